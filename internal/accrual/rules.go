@@ -0,0 +1,83 @@
+// Package accrual считает начисления по позициям чека без внешней системы
+// расчёта.
+package accrual
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+var ErrInvalidRule = errors.New("некорректное правило начисления")
+
+func Validate(r models.AccrualRule) error {
+	switch {
+	case strings.TrimSpace(r.Name) == "":
+		return fmt.Errorf("%w: не задано название", ErrInvalidRule)
+	case r.Percent < 0 || r.Percent > 100:
+		return fmt.Errorf("%w %q: процент вне диапазона 0–100", ErrInvalidRule, r.Name)
+	case r.Bonus < 0:
+		return fmt.Errorf("%w %q: отрицательный бонус", ErrInvalidRule, r.Name)
+	case r.Percent == 0 && r.Bonus == 0:
+		return fmt.Errorf("%w %q: правило ничего не начисляет", ErrInvalidRule, r.Name)
+	}
+	return nil
+}
+
+// LoadRules читает правила из JSON-файла с массивом правил.
+func LoadRules(path string) ([]models.AccrualRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать правила начислений: %w", err)
+	}
+	var rules []models.AccrualRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("неверный формат правил начислений: %w", err)
+	}
+	for _, r := range rules {
+		if err := Validate(r); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+// Calculate возвращает начисление за заказ. Проценты разных правил не
+// складываются: к позиции применяется наибольший из подходящих. Бонусы
+// всех сработавших правил суммируются.
+func Calculate(rules []models.AccrualRule, items []models.OrderItem) float64 {
+	var total float64
+	matched := make([]bool, len(rules))
+	for _, item := range items {
+		var percent float64
+		for i, r := range rules {
+			if !Matches(r, item) {
+				continue
+			}
+			matched[i] = true
+			percent = max(percent, r.Percent)
+		}
+		total += item.Amount() * percent / 100
+	}
+	for i, r := range rules {
+		if matched[i] {
+			total += r.Bonus
+		}
+	}
+	return math.Round(total*100) / 100
+}
+
+func Matches(r models.AccrualRule, item models.OrderItem) bool {
+	if r.Category != "" && !strings.EqualFold(r.Category, item.Category) {
+		return false
+	}
+	if r.Brand != "" && !strings.EqualFold(r.Brand, item.Brand) {
+		return false
+	}
+	return true
+}
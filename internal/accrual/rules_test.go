@@ -0,0 +1,71 @@
+package accrual
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestCalculate(t *testing.T) {
+	rules := []models.AccrualRule{
+		{Name: "База", Percent: 1},
+		{Name: "Молочка", Category: "dairy", Percent: 5},
+		{Name: "Бренд", Brand: "Acme", Percent: 10, Bonus: 50},
+	}
+
+	tests := []struct {
+		name  string
+		items []models.OrderItem
+		want  float64
+	}{
+		{
+			name:  "базовый процент",
+			items: []models.OrderItem{{Name: "Хлеб", Price: 100}},
+			want:  1,
+		},
+		{
+			name:  "наибольший процент и количество",
+			items: []models.OrderItem{{Name: "Молоко", Category: "Dairy", Price: 80, Quantity: 2}},
+			want:  8,
+		},
+		{
+			name: "бонус начисляется один раз за заказ",
+			items: []models.OrderItem{
+				{Name: "Йогурт", Category: "dairy", Brand: "acme", Price: 40},
+				{Name: "Сок", Brand: "ACME", Price: 60},
+			},
+			want: 60,
+		},
+		{
+			name: "без позиций",
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.InDelta(t, tt.want, Calculate(rules, tt.items), 0.001)
+		})
+	}
+}
+func TestLoadRules(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[
+		{"name": "База", "percent": 1},
+		{"name": "Бренд", "brand": "Acme", "bonus": 50}
+	]`), 0o600))
+
+	rules, err := LoadRules(path)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	require.Equal(t, "Acme", rules[1].Brand)
+
+	require.NoError(t, os.WriteFile(path, []byte(`[{"name": "Пусто"}]`), 0o600))
+	_, err = LoadRules(path)
+	require.ErrorIs(t, err, ErrInvalidRule)
+}
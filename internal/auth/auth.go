@@ -1,71 +1,143 @@
 package auth
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"math/big"
 	"strconv"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/scoring-service/pkg/logger"
 	"github.com/scoring-service/pkg/models"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type Claims struct {
-	UserID int `json:"user_id"`
-	jwt.StandardClaims
+	UserID      int      `json:"user_id"`
+	Role        string   `json:"role,omitempty"`
+	Permissions []string `json:"perms,omitempty"`
+	SessionID   int      `json:"sid,omitempty"`
+	jwt.RegisteredClaims
+}
+
+func (c *Claims) Principal() *Principal {
+	return &Principal{
+		UserID:      c.UserID,
+		Role:        c.Role,
+		Permissions: c.Permissions,
+		SessionID:   c.SessionID,
+		TokenID:     c.ID,
+		ExpiresAt:   c.ExpiresAt.Time,
+	}
 }
 
 type contextKey string
 
-const UserIDKey contextKey = "userID"
+// RevocationChecker сообщает, отозван ли токен (по jti) или сессия, в рамках
+// которой он выпущен.
+type RevocationChecker interface {
+	IsTokenRevoked(ctx context.Context, jti string, sessionID int) (bool, error)
+}
+
+const AccessTokenTTL = time.Hour
 
-var secretKey string
+var (
+	// Пока ключ не задан, токены не выпускаются и не принимаются.
+	keyring           = NewKeyring()
+	revocationChecker RevocationChecker
+)
 
+// SetSecretKey задаёт единственный ключ HS256. Для ротации ключей и
+// асимметричных алгоритмов используется SetKeyring.
 func SetSecretKey(key string) {
-	secretKey = key
+	keyring = NewKeyring(NewHMACKey(DefaultKeyID, []byte(key)))
+}
+
+func SetKeyring(k *Keyring) {
+	keyring = k
+}
+
+func JWKS() JWKSet {
+	return keyring.JWKS()
+}
+
+func SetRevocationChecker(checker RevocationChecker) {
+	revocationChecker = checker
 }
 
 func ValidateJWT(tokenString string) (int, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secretKey), nil
-	})
+	claims, err := ParseJWT(context.Background(), tokenString)
+	if err != nil {
+		return 0, err
+	}
+	return claims.UserID, nil
+}
+
+func ParseJWT(ctx context.Context, tokenString string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, keyring.verificationKey,
+		jwt.WithValidMethods(keyring.methods()), jwt.WithExpirationRequired())
 
 	if err != nil || !token.Valid {
 		logger.Log.Error("неверный или просроченный токен")
-		return 0, fmt.Errorf("неверный или просроченный токен")
+		return nil, fmt.Errorf("неверный или просроченный токен")
 	}
 
 	claims, ok := token.Claims.(*Claims)
 	if !ok || claims.UserID == 0 {
 		logger.Log.Error("не удалось извлечь данные из токена")
-		return 0, fmt.Errorf("не удалось извлечь данные из токена")
+		return nil, fmt.Errorf("не удалось извлечь данные из токена")
 	}
-
-	return claims.UserID, nil
-}
-func HashPassword(password string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		logger.Log.Error(err.Error())
-		return "", fmt.Errorf("ошибка хеширования пароля: %v", err)
+	if claims.Role == "" {
+		claims.Role = models.RoleCustomer
+	}
+	if revocationChecker != nil {
+		revoked, err := revocationChecker.IsTokenRevoked(ctx, claims.ID, claims.SessionID)
+		if err != nil {
+			logger.Log.Error(err.Error())
+			return nil, fmt.Errorf("не удалось проверить отзыв токена: %w", err)
+		}
+		if revoked {
+			logger.Log.Error("токен отозван")
+			return nil, fmt.Errorf("токен отозван")
+		}
 	}
-	return string(hash), nil
-}
 
+	return claims, nil
+}
 func GenerateJWT(user *models.User) (string, error) {
 	if user == nil {
 		logger.Log.Error("передан пустой указатель")
 		return "", fmt.Errorf("не удалось сгенерировать токен")
 
 	}
-	claims := jwt.MapClaims{
-		"user_id": user.ID,
-		"exp":     time.Now().Add(time.Hour).Unix(),
+	role := user.Role
+	if role == "" {
+		role = models.RoleCustomer
+	}
+	jti, err := randomToken(16)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return "", fmt.Errorf("не удалось сгенерировать токен: %v", err)
+	}
+	now := time.Now()
+	claims := &Claims{
+		UserID:      user.ID,
+		Role:        role,
+		Permissions: user.Permissions,
+		SessionID:   user.SessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenTTL)),
+		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(secretKey))
+	tokenString, err := keyring.sign(claims)
 	if err != nil {
 		logger.Log.Error(err.Error())
 		return "", fmt.Errorf("не удалось сгенерировать токен: %v", err)
@@ -73,10 +145,87 @@ func GenerateJWT(user *models.User) (string, error) {
 
 	return tokenString, nil
 }
-func CheckPasswordHash(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+
+// GenerateRefreshToken возвращает случайный refresh-токен; в базе хранится
+// только его хеш.
+func GenerateRefreshToken() (string, error) {
+	return randomToken(32)
+}
+
+// GenerateResetToken возвращает одноразовый токен сброса пароля; в базе,
+// как и для refresh-токенов, хранится только хеш.
+func GenerateResetToken() (string, error) {
+	return randomToken(32)
+}
+
+// GenerateChallengeToken возвращает токен второго шага входа.
+func GenerateChallengeToken() (string, error) {
+	return randomToken(32)
+}
+
+// GenerateCustomerToken возвращает одноразовый токен, которым покупатель
+// разрешает мерчанту загрузить заказ на свой счёт.
+func GenerateCustomerToken() (string, error) {
+	return randomToken(32)
+}
+
+// GenerateNonce возвращает случайное значение заголовка X-Nonce для
+// подписываемого запроса.
+func GenerateNonce() (string, error) {
+	return randomToken(16)
+}
+
+// GenerateAPIKey возвращает API-ключ мерчанта. Префикс помогает узнать
+// ключ в логах и сканерах утечек.
+func GenerateAPIKey() (string, error) {
+	key, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	return "gmk_" + key, nil
+}
+
+// GenerateSigningSecret возвращает секрет для подписи запросов мерчанта.
+func GenerateSigningSecret() (string, error) {
+	return randomToken(32)
+}
+
+// GenerateNumericCode возвращает случайный код из digits цифр, удобный для
+// ввода на кассе и для QR-кода.
+func GenerateNumericCode(digits int) (string, error) {
+	buf := make([]byte, digits)
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		buf[i] = byte('0' + n.Int64())
+	}
+	return string(buf), nil
 }
+
+// HashCode считает HMAC-SHA256 короткого кода с секретом сервера. Кодов из
+// восьми цифр всего 10^8, и простой хеш из утёкшей базы перебирается
+// мгновенно.
+func HashCode(secret []byte, code string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
 func IsValidLuhn(number string) bool {
 	var sum int
 	alt := false
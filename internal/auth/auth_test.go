@@ -1,13 +1,21 @@
 package auth
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/scoring-service/pkg/models"
 )
 
+var testSecret = []byte("test-secret")
+
+func init() {
+	SetSecretKey(string(testSecret))
+}
+
 func TestIsValidLuhn(t *testing.T) {
 	tests := []struct {
 		number string
@@ -84,7 +92,7 @@ func TestGenerateJWT(t *testing.T) {
 
 			if token != "" {
 				parsedToken, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
-					return []byte(secretKey), nil
+					return testSecret, nil
 				})
 				if err != nil || !parsedToken.Valid {
 					t.Errorf("token is invalid: %v", err)
@@ -123,12 +131,12 @@ func TestValidateJWT(t *testing.T) {
 			tokenString: func() string {
 				claims := &Claims{
 					UserID: 123,
-					StandardClaims: jwt.StandardClaims{
-						ExpiresAt: time.Now().Add(time.Hour).Unix(),
+					RegisteredClaims: jwt.RegisteredClaims{
+						ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
 					},
 				}
 				token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-				tokenString, _ := token.SignedString([]byte(secretKey))
+				tokenString, _ := token.SignedString(testSecret)
 				return tokenString
 			}(),
 			expectErr:    false,
@@ -140,12 +148,12 @@ func TestValidateJWT(t *testing.T) {
 
 				claims := &Claims{
 					UserID: 123,
-					StandardClaims: jwt.StandardClaims{
-						ExpiresAt: time.Now().Add(-time.Hour).Unix(),
+					RegisteredClaims: jwt.RegisteredClaims{
+						ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
 					},
 				}
 				token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-				tokenString, _ := token.SignedString([]byte(secretKey))
+				tokenString, _ := token.SignedString(testSecret)
 				return tokenString
 			}(),
 			expectErr:    true,
@@ -161,7 +169,7 @@ func TestValidateJWT(t *testing.T) {
 			name: "Token with missing claims",
 			tokenString: func() string {
 				token := jwt.New(jwt.SigningMethodHS256)
-				tokenString, _ := token.SignedString([]byte(secretKey))
+				tokenString, _ := token.SignedString(testSecret)
 				return tokenString
 			}(),
 			expectErr:    true,
@@ -185,3 +193,77 @@ func TestValidateJWT(t *testing.T) {
 		})
 	}
 }
+func TestParseJWTRole(t *testing.T) {
+	token, err := GenerateJWT(&models.User{ID: 7, Role: models.RoleSupport})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	claims, err := ParseJWT(context.Background(), token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.UserID != 7 || claims.Role != models.RoleSupport {
+		t.Errorf("expected user 7 with role %q, got %d/%q", models.RoleSupport, claims.UserID, claims.Role)
+	}
+
+	token, _ = GenerateJWT(&models.User{ID: 8})
+	claims, err = ParseJWT(context.Background(), token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.Role != models.RoleCustomer {
+		t.Errorf("expected default role %q, got %q", models.RoleCustomer, claims.Role)
+	}
+}
+
+type revocationStub struct {
+	revoked map[string]bool
+}
+
+func (s revocationStub) IsTokenRevoked(_ context.Context, jti string, _ int) (bool, error) {
+	return s.revoked[jti], nil
+}
+
+func TestValidateJWTRevoked(t *testing.T) {
+	token, err := GenerateJWT(&models.User{ID: 5, SessionID: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	claims, err := ParseJWT(context.Background(), token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.ID == "" || claims.SessionID != 3 {
+		t.Fatalf("expected jti and sid in claims, got %q/%d", claims.ID, claims.SessionID)
+	}
+
+	SetRevocationChecker(revocationStub{revoked: map[string]bool{claims.ID: true}})
+	defer SetRevocationChecker(nil)
+
+	if _, err := ValidateJWT(token); err == nil {
+		t.Error("expected revoked token to be rejected")
+	}
+}
+
+func TestGenerateNumericCode(t *testing.T) {
+	code, err := GenerateNumericCode(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(code) != 8 || strings.Trim(code, "0123456789") != "" {
+		t.Errorf("ожидался код из 8 цифр, получено %q", code)
+	}
+}
+
+func TestHashCode(t *testing.T) {
+	hash := HashCode([]byte("secret"), "12345678")
+	if len(hash) != 64 {
+		t.Errorf("ожидался hex HMAC-SHA256, получено %q", hash)
+	}
+	if hash != HashCode([]byte("secret"), "12345678") {
+		t.Error("хеш одного кода должен совпадать")
+	}
+	if hash == HashCode([]byte("other"), "12345678") || hash == HashToken("12345678") {
+		t.Error("хеш должен зависеть от секрета")
+	}
+}
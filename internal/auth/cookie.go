@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"time"
+)
+
+const (
+	AccessCookie  = "access_token"
+	RefreshCookie = "refresh_token"
+	CSRFCookie    = "csrf_token"
+	CSRFHeader    = "X-CSRF-Token"
+
+	refreshCookiePath = "/api/user/token"
+)
+
+// CookiePolicy задаёт, выдаются ли токены в cookie и с какими атрибутами.
+type CookiePolicy struct {
+	Enabled    bool
+	Secure     bool
+	SameSite   http.SameSite
+	Domain     string
+	RefreshTTL time.Duration
+}
+
+var cookiePolicy = CookiePolicy{
+	Enabled:    true,
+	Secure:     true,
+	SameSite:   http.SameSiteStrictMode,
+	RefreshTTL: 30 * 24 * time.Hour,
+}
+
+func SetCookiePolicy(policy CookiePolicy) {
+	cookiePolicy = policy
+}
+
+func CookiesEnabled() bool {
+	return cookiePolicy.Enabled
+}
+
+// SetAuthCookies кладёт access- и refresh-токены в HttpOnly cookie и выдаёт
+// новый CSRF-токен, который фронтенд должен вернуть в заголовке X-CSRF-Token.
+func SetAuthCookies(w http.ResponseWriter, accessToken, refreshToken string) error {
+	if !cookiePolicy.Enabled {
+		return nil
+	}
+	csrfToken, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+	refreshAge := int(cookiePolicy.RefreshTTL.Seconds())
+	http.SetCookie(w, newCookie(AccessCookie, accessToken, "/", int(AccessTokenTTL.Seconds()), true))
+	http.SetCookie(w, newCookie(RefreshCookie, refreshToken, refreshCookiePath, refreshAge, true))
+	http.SetCookie(w, newCookie(CSRFCookie, csrfToken, "/", refreshAge, false))
+	return nil
+}
+
+func ClearAuthCookies(w http.ResponseWriter) {
+	if !cookiePolicy.Enabled {
+		return
+	}
+	http.SetCookie(w, newCookie(AccessCookie, "", "/", -1, true))
+	http.SetCookie(w, newCookie(RefreshCookie, "", refreshCookiePath, -1, true))
+	http.SetCookie(w, newCookie(CSRFCookie, "", "/", -1, false))
+}
+
+// CheckCSRF сверяет CSRF-токен из заголовка со значением cookie
+// (double-submit).
+func CheckCSRF(r *http.Request) bool {
+	cookie, err := r.Cookie(CSRFCookie)
+	if err != nil || cookie.Value == "" {
+		return false
+	}
+	header := r.Header.Get(CSRFHeader)
+	return subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(header)) == 1
+}
+
+func newCookie(name, value, path string, maxAge int, httpOnly bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   cookiePolicy.Domain,
+		MaxAge:   maxAge,
+		Secure:   cookiePolicy.Secure,
+		HttpOnly: httpOnly,
+		SameSite: cookiePolicy.SameSite,
+	}
+}
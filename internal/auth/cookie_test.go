@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetAuthCookies(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := SetAuthCookies(w, "access", "refresh"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cookies := map[string]*http.Cookie{}
+	for _, c := range w.Result().Cookies() {
+		cookies[c.Name] = c
+	}
+	access, ok := cookies[AccessCookie]
+	if !ok || access.Value != "access" || !access.HttpOnly || !access.Secure || access.SameSite != http.SameSiteStrictMode {
+		t.Errorf("unexpected access cookie: %+v", access)
+	}
+	refresh, ok := cookies[RefreshCookie]
+	if !ok || refresh.Value != "refresh" || !refresh.HttpOnly || refresh.Path != refreshCookiePath {
+		t.Errorf("unexpected refresh cookie: %+v", refresh)
+	}
+	csrf, ok := cookies[CSRFCookie]
+	if !ok || csrf.Value == "" || csrf.HttpOnly {
+		t.Errorf("csrf cookie must be readable by the frontend: %+v", csrf)
+	}
+}
+func TestCheckCSRF(t *testing.T) {
+	tests := []struct {
+		name   string
+		cookie string
+		header string
+		valid  bool
+	}{
+		{name: "matching token", cookie: "abc", header: "abc", valid: true},
+		{name: "mismatched token", cookie: "abc", header: "abd", valid: false},
+		{name: "missing header", cookie: "abc", valid: false},
+		{name: "missing cookie", header: "abc", valid: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/user/orders", nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: CSRFCookie, Value: tt.cookie})
+			}
+			if tt.header != "" {
+				req.Header.Set(CSRFHeader, tt.header)
+			}
+			if got := CheckCSRF(req); got != tt.valid {
+				t.Errorf("CheckCSRF() = %v; want %v", got, tt.valid)
+			}
+		})
+	}
+}
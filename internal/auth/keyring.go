@@ -0,0 +1,285 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultKeyID — идентификатор ключа из SECRET_KEY. Им же проверяются токены,
+// выпущенные до появления заголовка kid.
+const DefaultKeyID = "default"
+
+// SigningKey — ключ подписи токенов. Ключ без приватной части годится
+// только для проверки подписи.
+type SigningKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Retired bool
+	sign    any
+	verify  any
+}
+
+func NewHMACKey(id string, secret []byte) *SigningKey {
+	return &SigningKey{ID: id, Method: jwt.SigningMethodHS256, sign: secret, verify: secret}
+}
+
+func NewRSAKey(id string, private *rsa.PrivateKey) *SigningKey {
+	return &SigningKey{ID: id, Method: jwt.SigningMethodRS256, sign: private, verify: &private.PublicKey}
+}
+
+func NewEd25519Key(id string, private ed25519.PrivateKey) *SigningKey {
+	return &SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, sign: private, verify: private.Public()}
+}
+
+// NewVerificationKey создаёт ключ только для проверки подписи — например,
+// когда приватная часть уже удалена, а выпущенные ею токены ещё действуют.
+func NewVerificationKey(id string, public crypto.PublicKey) (*SigningKey, error) {
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodRS256, verify: pub}, nil
+	case ed25519.PublicKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, verify: pub}, nil
+	}
+	return nil, fmt.Errorf("ключ %s: неподдерживаемый тип открытого ключа %T", id, public)
+}
+
+// canSign проверяет, что у ключа есть приватная часть. Пустой секрет HMAC
+// ключом не считается: подписанные им токены мог бы выпустить кто угодно.
+func (k *SigningKey) canSign() bool {
+	return isKeyMaterial(k.sign)
+}
+
+func isKeyMaterial(key any) bool {
+	switch key := key.(type) {
+	case nil:
+		return false
+	case []byte:
+		return len(key) > 0
+	case ed25519.PrivateKey:
+		return len(key) > 0
+	case *rsa.PrivateKey:
+		return key != nil
+	}
+	return true
+}
+
+// Keyring хранит набор ключей: активным подписываются новые токены, все
+// невыведенные из оборота принимаются при проверке.
+type Keyring struct {
+	keys   map[string]*SigningKey
+	active string
+}
+
+func NewKeyring(keys ...*SigningKey) *Keyring {
+	k := &Keyring{keys: make(map[string]*SigningKey)}
+	for _, key := range keys {
+		k.Add(key)
+	}
+	return k
+}
+
+// Add добавляет ключ; первый добавленный ключ с приватной частью становится
+// активным.
+func (k *Keyring) Add(key *SigningKey) {
+	k.keys[key.ID] = key
+	if k.active == "" && key.canSign() && !key.Retired {
+		k.active = key.ID
+	}
+}
+
+func (k *Keyring) SetActive(id string) error {
+	key, ok := k.keys[id]
+	if !ok {
+		return fmt.Errorf("ключ %s не найден", id)
+	}
+	if !key.canSign() || key.Retired {
+		return fmt.Errorf("ключ %s не может использоваться для подписи", id)
+	}
+	k.active = id
+	return nil
+}
+
+func (k *Keyring) Retire(id string) error {
+	key, ok := k.keys[id]
+	if !ok {
+		return fmt.Errorf("ключ %s не найден", id)
+	}
+	if id == k.active {
+		return fmt.Errorf("нельзя вывести из оборота активный ключ %s", id)
+	}
+	key.Retired = true
+	return nil
+}
+
+func (k *Keyring) Active() *SigningKey {
+	return k.keys[k.active]
+}
+
+func (k *Keyring) sign(claims jwt.Claims) (string, error) {
+	key := k.Active()
+	if key == nil {
+		return "", fmt.Errorf("не задан ключ подписи")
+	}
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.sign)
+}
+
+func (k *Keyring) methods() []string {
+	var algs []string
+	for _, key := range k.keys {
+		if !key.Retired {
+			algs = append(algs, key.Method.Alg())
+		}
+	}
+	return algs
+}
+
+func (k *Keyring) verificationKey(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		kid = DefaultKeyID
+	}
+	key, ok := k.keys[kid]
+	if !ok || key.Retired {
+		return nil, fmt.Errorf("неизвестный или выведенный из оборота ключ %q", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("алгоритм %s не соответствует ключу %q", token.Method.Alg(), kid)
+	}
+	if !isKeyMaterial(key.verify) {
+		return nil, fmt.Errorf("у ключа %q нет секрета для проверки подписи", kid)
+	}
+	return key.verify, nil
+}
+
+// JWK — открытый ключ в формате RFC 7517.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS возвращает открытые части всех действующих асимметричных ключей.
+// Симметричные ключи наружу не публикуются.
+func (k *Keyring) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range k.keys {
+		if key.Retired {
+			continue
+		}
+		switch pub := key.verify.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "RSA",
+				Kid: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "OKP",
+				Kid: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
+// LoadKeyDir читает ключи из каталога: *.pem — приватные (PKCS#8, PKCS#1)
+// или открытые (PKIX) ключи RSA и Ed25519, *.key — секреты HS256.
+// Идентификатор ключа — имя файла без расширения.
+func LoadKeyDir(dir string) ([]*SigningKey, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать каталог ключей: %w", err)
+	}
+	var keys []*SigningKey
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(entry.Name())
+		id := strings.TrimSuffix(entry.Name(), ext)
+		if ext != ".pem" && ext != ".key" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var key *SigningKey
+		if ext == ".key" {
+			secret := strings.TrimSpace(string(data))
+			if secret == "" {
+				return nil, fmt.Errorf("ключ %s: пустой секрет", id)
+			}
+			key = NewHMACKey(id, []byte(secret))
+		} else if key, err = parsePEMKey(id, data); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func parsePEMKey(id string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("ключ %s: не найден PEM-блок", id)
+	}
+	switch block.Type {
+	case "PRIVATE KEY":
+		private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("ключ %s: %w", id, err)
+		}
+		switch private := private.(type) {
+		case *rsa.PrivateKey:
+			return NewRSAKey(id, private), nil
+		case ed25519.PrivateKey:
+			return NewEd25519Key(id, private), nil
+		}
+		return nil, fmt.Errorf("ключ %s: неподдерживаемый тип ключа %T", id, private)
+	case "RSA PRIVATE KEY":
+		private, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("ключ %s: %w", id, err)
+		}
+		return NewRSAKey(id, private), nil
+	case "PUBLIC KEY":
+		public, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("ключ %s: %w", id, err)
+		}
+		return NewVerificationKey(id, public)
+	}
+	return nil, fmt.Errorf("ключ %s: неподдерживаемый PEM-блок %s", id, block.Type)
+}
@@ -0,0 +1,200 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func newTestKeys(t *testing.T) (*rsa.PrivateKey, ed25519.PrivateKey) {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return rsaKey, edKey
+}
+
+func withKeyring(t *testing.T, k *Keyring) {
+	t.Helper()
+	previous := keyring
+	SetKeyring(k)
+	t.Cleanup(func() { SetKeyring(previous) })
+}
+
+func TestKeyringRotation(t *testing.T) {
+	rsaKey, edKey := newTestKeys(t)
+	ring := NewKeyring(
+		NewHMACKey(DefaultKeyID, testSecret),
+		NewRSAKey("rsa-1", rsaKey),
+		NewEd25519Key("ed-1", edKey),
+	)
+	withKeyring(t, ring)
+
+	tokens := map[string]string{}
+	for _, kid := range []string{DefaultKeyID, "rsa-1", "ed-1"} {
+		if err := ring.SetActive(kid); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		token, err := GenerateJWT(&models.User{ID: 1})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		tokens[kid] = token
+	}
+
+	for kid, token := range tokens {
+		parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if parsed.Header["kid"] != kid {
+			t.Errorf("expected kid %q, got %v", kid, parsed.Header["kid"])
+		}
+		if _, err := ValidateJWT(token); err != nil {
+			t.Errorf("token signed by %s rejected: %v", kid, err)
+		}
+	}
+
+	if err := ring.Retire("rsa-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := ValidateJWT(tokens["rsa-1"]); err == nil {
+		t.Error("token signed by retired key must be rejected")
+	}
+	if err := ring.Retire("ed-1"); err == nil {
+		t.Error("active key must not be retired")
+	}
+}
+func TestKeyringRejectsForeignTokens(t *testing.T) {
+	rsaKey, _ := newTestKeys(t)
+	withKeyring(t, NewKeyring(NewRSAKey("rsa-1", rsaKey)))
+
+	claims := &Claims{
+		UserID:           1,
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	}
+
+	unknown := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	unknown.Header["kid"] = "other"
+	token, _ := unknown.SignedString(testSecret)
+	if _, err := ParseJWT(context.Background(), token); err == nil {
+		t.Error("token with unknown kid must be rejected")
+	}
+
+	// HS256 с открытым ключом RSA в роли секрета — классическая подмена алгоритма.
+	pub := x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)
+	confused := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	confused.Header["kid"] = "rsa-1"
+	token, _ = confused.SignedString(pub)
+	if _, err := ParseJWT(context.Background(), token); err == nil {
+		t.Error("token with mismatched algorithm must be rejected")
+	}
+}
+func TestKeyringRejectsEmptySecret(t *testing.T) {
+	ring := NewKeyring(NewHMACKey(DefaultKeyID, nil))
+	if ring.Active() != nil {
+		t.Fatal("key without a secret must not become active")
+	}
+	if err := ring.SetActive(DefaultKeyID); err == nil {
+		t.Error("key without a secret must not be activated")
+	}
+	withKeyring(t, ring)
+
+	if _, err := GenerateJWT(&models.User{ID: 1}); err == nil {
+		t.Error("token must not be issued without a secret")
+	}
+
+	claims := &Claims{
+		UserID:           1,
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	}
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte{})
+	if _, err := ParseJWT(context.Background(), token); err == nil {
+		t.Error("token signed with an empty secret must be rejected")
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	rsaKey, edKey := newTestKeys(t)
+	ring := NewKeyring(
+		NewHMACKey(DefaultKeyID, testSecret),
+		NewRSAKey("rsa-1", rsaKey),
+		NewEd25519Key("ed-1", edKey),
+	)
+	if err := ring.Retire("rsa-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	set := ring.JWKS()
+	if len(set.Keys) != 1 {
+		t.Fatalf("expected only the Ed25519 key, got %+v", set.Keys)
+	}
+	key := set.Keys[0]
+	if key.Kid != "ed-1" || key.Kty != "OKP" || key.Crv != "Ed25519" || key.Alg != "EdDSA" || key.X == "" {
+		t.Errorf("unexpected jwk: %+v", key)
+	}
+}
+func TestLoadKeyDir(t *testing.T) {
+	rsaKey, edKey := newTestKeys(t)
+	dir := t.TempDir()
+
+	edDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files := map[string][]byte{
+		"rsa-1.pem":  pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}),
+		"ed-1.pem":   pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edDER}),
+		"rsa-0.pem":  pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}),
+		"hs-1.key":   []byte("secret\n"),
+		"README.txt": []byte("ignored"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	keys, err := LoadKeyDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	algs := map[string]string{}
+	for _, key := range keys {
+		algs[key.ID] = key.Method.Alg()
+	}
+	want := map[string]string{"rsa-1": "RS256", "ed-1": "EdDSA", "rsa-0": "RS256", "hs-1": "HS256"}
+	if len(algs) != len(want) {
+		t.Fatalf("expected %v, got %v", want, algs)
+	}
+	for id, alg := range want {
+		if algs[id] != alg {
+			t.Errorf("key %s: expected %s, got %s", id, alg, algs[id])
+		}
+	}
+
+	ring := NewKeyring(keys...)
+	if err := ring.SetActive("rsa-0"); err == nil {
+		t.Error("public-only key must not become active")
+	}
+}
@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/scoring-service/pkg/logger"
+)
+
+// PasswordHasher хеширует пароли одним алгоритмом. Алгоритм сохранённого
+// хеша определяется по его префиксу, поэтому хеши разных алгоритмов могут
+// храниться в базе одновременно.
+type PasswordHasher interface {
+	// Matches сообщает, что хеш получен этим алгоритмом.
+	Matches(hash string) bool
+	Hash(password string) (string, error)
+	Verify(password, hash string) bool
+	// NeedsRehash сообщает, что хеш получен с устаревшими параметрами.
+	NeedsRehash(hash string) bool
+}
+
+// BcryptMaxPasswordBytes — длина пароля, которую учитывает bcrypt; более
+// длинные пароли он отвергает.
+const BcryptMaxPasswordBytes = 72
+
+type BcryptHasher struct {
+	Cost int
+}
+
+func (h BcryptHasher) Matches(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func (h BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	return string(hash), err
+}
+
+func (h BcryptHasher) Verify(password, hash string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+func (h BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.Cost
+}
+
+const argon2idPrefix = "$argon2id$"
+
+// Argon2idHasher хранит хеш в формате PHC:
+// $argon2id$v=19$m=65536,t=1,p=4$<соль>$<хеш>.
+type Argon2idHasher struct {
+	Memory  uint32
+	Time    uint32
+	Threads uint8
+	SaltLen uint32
+	KeyLen  uint32
+}
+
+// DefaultArgon2id — параметры, рекомендованные RFC 9106.
+var DefaultArgon2id = Argon2idHasher{Memory: 64 * 1024, Time: 1, Threads: 4, SaltLen: 16, KeyLen: 32}
+
+func (h Argon2idHasher) Matches(hash string) bool {
+	return strings.HasPrefix(hash, argon2idPrefix)
+}
+
+func (h Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Time, h.Memory, h.Threads, h.KeyLen)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version,
+		h.Memory, h.Time, h.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (h Argon2idHasher) Verify(password, hash string) bool {
+	params, salt, key, err := parseArgon2id(hash)
+	if err != nil {
+		return false
+	}
+	actual := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(actual, key) == 1
+}
+
+func (h Argon2idHasher) NeedsRehash(hash string) bool {
+	params, salt, key, err := parseArgon2id(hash)
+	if err != nil {
+		return true
+	}
+	return params.Memory != h.Memory || params.Time != h.Time || params.Threads != h.Threads ||
+		uint32(len(salt)) != h.SaltLen || uint32(len(key)) != h.KeyLen
+}
+
+func parseArgon2id(hash string) (Argon2idHasher, []byte, []byte, error) {
+	var params Argon2idHasher
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, fmt.Errorf("неверный формат хеша argon2id")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("неподдерживаемая версия argon2id")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil {
+		return params, nil, nil, fmt.Errorf("неверные параметры argon2id: %w", err)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	return params, salt, key, nil
+}
+
+var (
+	passwordHasher PasswordHasher = DefaultArgon2id
+	// knownHashers проверяют хеши, созданные не текущим алгоритмом.
+	knownHashers = []PasswordHasher{DefaultArgon2id, BcryptHasher{Cost: bcrypt.DefaultCost}}
+)
+
+// SetPasswordHasher задаёт алгоритм для новых хешей. Хеши остальных
+// известных алгоритмов по-прежнему проверяются и обновляются при входе.
+func SetPasswordHasher(h PasswordHasher) {
+	passwordHasher = h
+}
+
+func hasherFor(hash string) PasswordHasher {
+	if passwordHasher.Matches(hash) {
+		return passwordHasher
+	}
+	for _, h := range knownHashers {
+		if h.Matches(hash) {
+			return h
+		}
+	}
+	return nil
+}
+
+func HashPassword(password string) (string, error) {
+	hash, err := passwordHasher.Hash(password)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return "", fmt.Errorf("ошибка хеширования пароля: %v", err)
+	}
+	return hash, nil
+}
+
+func CheckPasswordHash(password, hash string) bool {
+	h := hasherFor(hash)
+	return h != nil && h.Verify(password, hash)
+}
+
+// PasswordNeedsRehash сообщает, что хеш получен не текущим алгоритмом или
+// с устаревшими параметрами и его стоит пересчитать при следующем входе.
+func PasswordNeedsRehash(hash string) bool {
+	return !passwordHasher.Matches(hash) || passwordHasher.NeedsRehash(hash)
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestArgon2idHasher(t *testing.T) {
+	hasher := Argon2idHasher{Memory: 1024, Time: 1, Threads: 1, SaltLen: 16, KeyLen: 32}
+
+	hash, err := hasher.Hash("correct horse")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	assert.True(t, hasher.Matches(hash))
+	assert.True(t, hasher.Verify("correct horse", hash))
+	assert.False(t, hasher.Verify("battery staple", hash))
+	assert.False(t, hasher.NeedsRehash(hash))
+
+	stronger := hasher
+	stronger.Time = 2
+	assert.True(t, stronger.NeedsRehash(hash))
+	assert.True(t, stronger.Verify("correct horse", hash))
+
+	assert.False(t, hasher.Verify("correct horse", "$argon2id$v=19$broken"))
+}
+func TestCheckPasswordHashLegacy(t *testing.T) {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	assert.True(t, CheckPasswordHash("secret", string(legacy)))
+	assert.False(t, CheckPasswordHash("wrong", string(legacy)))
+	assert.True(t, PasswordNeedsRehash(string(legacy)))
+	assert.False(t, CheckPasswordHash("secret", "plain-text"))
+}
+func TestHashPasswordDefault(t *testing.T) {
+	hash, err := HashPassword("secret")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$"))
+	assert.True(t, CheckPasswordHash("secret", hash))
+	assert.False(t, PasswordNeedsRehash(hash))
+}
+func TestSetPasswordHasher(t *testing.T) {
+	SetPasswordHasher(BcryptHasher{Cost: bcrypt.MinCost})
+	defer SetPasswordHasher(DefaultArgon2id)
+
+	hash, err := HashPassword("secret")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$2a$"))
+	assert.False(t, PasswordNeedsRehash(hash))
+
+	argonHash, err := DefaultArgon2id.Hash("secret")
+	require.NoError(t, err)
+	assert.True(t, CheckPasswordHash("secret", argonHash))
+	assert.True(t, PasswordNeedsRehash(argonHash))
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// Principal описывает аутентифицированного пользователя текущего запроса.
+type Principal struct {
+	UserID      int
+	Role        string
+	Permissions []string
+	SessionID   int
+	TokenID     string
+	ExpiresAt   time.Time
+	// MerchantID задан, если запрос аутентифицирован API-ключом мерчанта.
+	MerchantID int
+}
+
+const principalKey contextKey = "principal"
+
+func (p *Principal) Can(permission string) bool {
+	return p != nil && slices.Contains(p.Permissions, permission)
+}
+
+func (p *Principal) HasRole(roles ...string) bool {
+	return p != nil && slices.Contains(roles, p.Role)
+}
+
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey, p)
+}
+
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey).(*Principal)
+	return p, ok && p != nil
+}
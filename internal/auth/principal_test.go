@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestPrincipalFromJWT(t *testing.T) {
+	token, err := GenerateJWT(&models.User{
+		ID:          9,
+		Role:        models.RoleSupport,
+		Permissions: []string{models.PermUsersRead, models.PermOrdersRepoll},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	claims, err := ParseJWT(context.Background(), token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := WithPrincipal(context.Background(), claims.Principal())
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		t.Fatal("principal not found in context")
+	}
+	if principal.UserID != 9 {
+		t.Errorf("expected user 9, got %d", principal.UserID)
+	}
+	if !principal.Can(models.PermOrdersRepoll) || principal.Can(models.PermBalanceAdjust) {
+		t.Errorf("unexpected permissions %v", principal.Permissions)
+	}
+	if !principal.HasRole(models.RoleSupport, models.RoleAdmin) || principal.HasRole(models.RoleAdmin) {
+		t.Errorf("unexpected role %q", principal.Role)
+	}
+}
+func TestPrincipalFromEmptyContext(t *testing.T) {
+	if _, ok := PrincipalFromContext(context.Background()); ok {
+		t.Error("expected no principal in empty context")
+	}
+	var p *Principal
+	if p.Can(models.PermUsersRead) {
+		t.Error("nil principal must not have permissions")
+	}
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// SignatureTolerance — на сколько метка времени подписанного запроса может
+// расходиться с часами сервера.
+const SignatureTolerance = 5 * time.Minute
+
+// MaxNonceLength ограничивает длину X-Nonce: одноразовые значения хранятся
+// в базе, пока не истечёт окно подписи.
+const MaxNonceLength = 64
+
+var (
+	ErrSignatureMissing = errors.New("запрос не подписан")
+	ErrSignatureExpired = errors.New("метка времени подписи вне допустимого окна")
+	ErrSignatureInvalid = errors.New("неверная подпись запроса")
+)
+
+// SignedRequest — части запроса, которые покрывает подпись. Nonce уникален
+// для каждого запроса, по нему получатель отбрасывает повторы.
+type SignedRequest struct {
+	Method    string
+	Path      string
+	Timestamp string
+	Nonce     string
+	Body      []byte
+}
+
+// SignRequest считает HMAC-SHA256 от
+// "<timestamp>\n<nonce>\n<METHOD>\n<path>\n<тело запроса>" и возвращает его в
+// hex. timestamp — Unix-время в секундах.
+func SignRequest(secret []byte, req SignedRequest) string {
+	mac := hmac.New(sha256.New, secret)
+	for _, part := range []string{req.Timestamp, req.Nonce, req.Method, req.Path} {
+		mac.Write([]byte(part))
+		mac.Write([]byte("\n"))
+	}
+	mac.Write(req.Body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyRequestSignature проверяет подпись и метку времени. Повтор nonce
+// отслеживает вызывающий: подпись сама по себе от повтора не защищает.
+func VerifyRequestSignature(secret []byte, req SignedRequest, signature string, now time.Time) error {
+	if req.Timestamp == "" || req.Nonce == "" || signature == "" {
+		return ErrSignatureMissing
+	}
+	if len(req.Nonce) > MaxNonceLength {
+		return ErrSignatureInvalid
+	}
+	unix, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		return ErrSignatureExpired
+	}
+	if diff := now.Sub(time.Unix(unix, 0)); diff > SignatureTolerance || diff < -SignatureTolerance {
+		return ErrSignatureExpired
+	}
+	expected := SignRequest(secret, req)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrSignatureInvalid
+	}
+	return nil
+}
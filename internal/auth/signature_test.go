@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyRequestSignature(t *testing.T) {
+	secret := []byte("merchant-secret")
+	now := time.Unix(1750000000, 0)
+	req := SignedRequest{
+		Method:    "POST",
+		Path:      "/api/merchant/orders",
+		Timestamp: strconv.FormatInt(now.Unix(), 10),
+		Nonce:     "nonce-1",
+		Body:      []byte(`{"order":"79927398713"}`),
+	}
+	signature := SignRequest(secret, req)
+
+	assert.NoError(t, VerifyRequestSignature(secret, req, signature, now))
+	assert.NoError(t, VerifyRequestSignature(secret, req, signature, now.Add(4*time.Minute)))
+
+	assert.ErrorIs(t, VerifyRequestSignature(secret, req, signature, now.Add(10*time.Minute)), ErrSignatureExpired)
+	assert.ErrorIs(t, VerifyRequestSignature([]byte("other"), req, signature, now), ErrSignatureInvalid)
+
+	tampered := req
+	tampered.Body = []byte(`{"order":"1"}`)
+	assert.ErrorIs(t, VerifyRequestSignature(secret, tampered, signature, now), ErrSignatureInvalid)
+	tampered = req
+	tampered.Method = "DELETE"
+	assert.ErrorIs(t, VerifyRequestSignature(secret, tampered, signature, now), ErrSignatureInvalid)
+	tampered = req
+	tampered.Path = "/api/merchant/checkout"
+	assert.ErrorIs(t, VerifyRequestSignature(secret, tampered, signature, now), ErrSignatureInvalid)
+	tampered = req
+	tampered.Nonce = "nonce-2"
+	assert.ErrorIs(t, VerifyRequestSignature(secret, tampered, signature, now), ErrSignatureInvalid)
+	tampered = req
+	tampered.Nonce = strings.Repeat("n", MaxNonceLength+1)
+	assert.ErrorIs(t, VerifyRequestSignature(secret, tampered, signature, now), ErrSignatureInvalid)
+
+	missing := req
+	missing.Timestamp = ""
+	assert.ErrorIs(t, VerifyRequestSignature(secret, missing, signature, now), ErrSignatureMissing)
+	missing = req
+	missing.Nonce = ""
+	assert.ErrorIs(t, VerifyRequestSignature(secret, missing, signature, now), ErrSignatureMissing)
+	missing = req
+	missing.Timestamp = "yesterday"
+	assert.ErrorIs(t, VerifyRequestSignature(secret, missing, signature, now), ErrSignatureExpired)
+}
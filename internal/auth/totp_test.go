@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Секрет из тестовых векторов RFC 6238 ("12345678901234567890").
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		code, err := TOTPCode(rfcSecret, TOTPStep(time.Unix(tt.unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, code)
+	}
+}
+func TestVerifyTOTP(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+
+	step, ok := VerifyTOTP(rfcSecret, "081804", now, 0)
+	require.True(t, ok)
+	assert.Equal(t, TOTPStep(now), step)
+
+	_, ok = VerifyTOTP(rfcSecret, "081804", now.Add(30*time.Second), 0)
+	assert.True(t, ok, "код предыдущего шага принимается")
+
+	_, ok = VerifyTOTP(rfcSecret, "081804", now.Add(2*time.Minute), 0)
+	assert.False(t, ok, "устаревший код отклоняется")
+
+	_, ok = VerifyTOTP(rfcSecret, "081804", now, step)
+	assert.False(t, ok, "повторное использование кода отклоняется")
+
+	_, ok = VerifyTOTP(rfcSecret, "12345", now, 0)
+	assert.False(t, ok)
+}
+func TestTOTPURI(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	require.NoError(t, err)
+
+	uri, err := url.Parse(TOTPURI("Gophermart", "alice", secret))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Gophermart:alice", uri.Path)
+	assert.Equal(t, secret, uri.Query().Get("secret"))
+	assert.Equal(t, "Gophermart", uri.Query().Get("issuer"))
+}
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	require.NoError(t, err)
+	require.Len(t, codes, 10)
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		assert.Regexp(t, `^[a-z2-7]{4}-[a-z2-7]{4}$`, code)
+		assert.False(t, seen[code])
+		seen[code] = true
+	}
+	assert.Equal(t, HashRecoveryCode(codes[0]), HashRecoveryCode(strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))))
+}
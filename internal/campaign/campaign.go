@@ -0,0 +1,65 @@
+package campaign
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+var ErrInvalidCampaign = errors.New("некорректные параметры акции")
+
+type OrderFacts struct {
+	UserID         int
+	Number         string
+	Merchant       string
+	Accrual        float64
+	ProcessedAt    time.Time
+	FirstProcessed bool
+}
+
+func Validate(c models.Campaign) error {
+	switch {
+	case strings.TrimSpace(c.Name) == "":
+		return fmt.Errorf("%w: не задано название", ErrInvalidCampaign)
+	case c.StartsAt.IsZero():
+		return fmt.Errorf("%w: не задано время начала", ErrInvalidCampaign)
+	case c.EndsAt != nil && !c.EndsAt.After(c.StartsAt):
+		return fmt.Errorf("%w: время окончания раньше начала", ErrInvalidCampaign)
+	case c.BonusPoints < 0 || c.Multiplier < 0:
+		return fmt.Errorf("%w: отрицательное вознаграждение", ErrInvalidCampaign)
+	case c.BonusPoints == 0 && c.Multiplier <= 1:
+		return fmt.Errorf("%w: акция не начисляет бонусов", ErrInvalidCampaign)
+	}
+	return nil
+}
+
+// Evaluate возвращает бонус, который акция начисляет за обработанный заказ.
+// Множитель применяется к исходному начислению, бонус сверх него
+// складывается с фиксированной суммой.
+func Evaluate(c models.Campaign, order OrderFacts) float64 {
+	if !c.Active || order.ProcessedAt.Before(c.StartsAt) {
+		return 0
+	}
+	if c.EndsAt != nil && !order.ProcessedAt.Before(*c.EndsAt) {
+		return 0
+	}
+	if c.FirstOrderOnly && !order.FirstProcessed {
+		return 0
+	}
+	if c.Merchant != "" && c.Merchant != order.Merchant {
+		return 0
+	}
+	if c.OrderPrefix != "" && !strings.HasPrefix(order.Number, c.OrderPrefix) {
+		return 0
+	}
+
+	bonus := c.BonusPoints
+	if c.Multiplier > 1 {
+		bonus += order.Accrual * (c.Multiplier - 1)
+	}
+	return math.Round(bonus*100) / 100
+}
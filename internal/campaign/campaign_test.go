@@ -0,0 +1,95 @@
+package campaign
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestEvaluate(t *testing.T) {
+	start := time.Date(2025, 5, 3, 0, 0, 0, 0, time.UTC)
+	end := start.Add(48 * time.Hour)
+	during := start.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		campaign models.Campaign
+		order    OrderFacts
+		expected float64
+	}{
+		{
+			name:     "двойные баллы в выходные",
+			campaign: models.Campaign{Active: true, StartsAt: start, EndsAt: &end, Multiplier: 2},
+			order:    OrderFacts{Number: "12345678903", Accrual: 150.5, ProcessedAt: during},
+			expected: 150.5,
+		},
+		{
+			name:     "заказ после окончания акции",
+			campaign: models.Campaign{Active: true, StartsAt: start, EndsAt: &end, Multiplier: 2},
+			order:    OrderFacts{Number: "12345678903", Accrual: 150, ProcessedAt: end},
+			expected: 0,
+		},
+		{
+			name:     "бонус за первый заказ",
+			campaign: models.Campaign{Active: true, StartsAt: start, FirstOrderOnly: true, BonusPoints: 100},
+			order:    OrderFacts{Number: "12345678903", ProcessedAt: during, FirstProcessed: true},
+			expected: 100,
+		},
+		{
+			name:     "не первый заказ",
+			campaign: models.Campaign{Active: true, StartsAt: start, FirstOrderOnly: true, BonusPoints: 100},
+			order:    OrderFacts{Number: "12345678903", ProcessedAt: during},
+			expected: 0,
+		},
+		{
+			name:     "префикс магазина совпал",
+			campaign: models.Campaign{Active: true, StartsAt: start, OrderPrefix: "1234", BonusPoints: 25, Multiplier: 1.1},
+			order:    OrderFacts{Number: "12345678903", Accrual: 100, ProcessedAt: during},
+			expected: 35,
+		},
+		{
+			name:     "префикс магазина не совпал",
+			campaign: models.Campaign{Active: true, StartsAt: start, OrderPrefix: "9999", BonusPoints: 25},
+			order:    OrderFacts{Number: "12345678903", Accrual: 100, ProcessedAt: during},
+			expected: 0,
+		},
+		{
+			name:     "заказ от магазина акции",
+			campaign: models.Campaign{Active: true, StartsAt: start, Merchant: "acme", BonusPoints: 25},
+			order:    OrderFacts{Number: "12345678903", Merchant: "acme", ProcessedAt: during},
+			expected: 25,
+		},
+		{
+			name:     "заказ от другого магазина",
+			campaign: models.Campaign{Active: true, StartsAt: start, Merchant: "acme", BonusPoints: 25},
+			order:    OrderFacts{Number: "12345678903", Merchant: "other", ProcessedAt: during},
+			expected: 0,
+		},
+		{
+			name:     "акция выключена",
+			campaign: models.Campaign{StartsAt: start, BonusPoints: 25},
+			order:    OrderFacts{Number: "12345678903", ProcessedAt: during},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Evaluate(tt.campaign, tt.order))
+		})
+	}
+}
+func TestValidate(t *testing.T) {
+	start := time.Now()
+	before := start.Add(-time.Hour)
+
+	require.NoError(t, Validate(models.Campaign{Name: "weekend", StartsAt: start, Multiplier: 2}))
+	require.ErrorIs(t, Validate(models.Campaign{StartsAt: start, Multiplier: 2}), ErrInvalidCampaign)
+	require.ErrorIs(t, Validate(models.Campaign{Name: "weekend", Multiplier: 2}), ErrInvalidCampaign)
+	require.ErrorIs(t, Validate(models.Campaign{Name: "weekend", StartsAt: start, EndsAt: &before, Multiplier: 2}), ErrInvalidCampaign)
+	require.ErrorIs(t, Validate(models.Campaign{Name: "weekend", StartsAt: start, Multiplier: 1}), ErrInvalidCampaign)
+}
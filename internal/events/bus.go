@@ -0,0 +1,111 @@
+// Package events доставляет доменные события из outbox подписчикам внутри
+// процесса и, при необходимости, во внешний брокер сообщений.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+// Handler получает событие в том виде, в каком оно хранится в outbox.
+// Доставка «хотя бы один раз»: при сбое событие придёт повторно, поэтому
+// обработчики должны быть идемпотентны.
+type Handler func(ctx context.Context, event models.OutboxEvent) error
+
+// Broker публикует события во внешнюю систему под темой, равной типу
+// события. Подключается к шине через Bus.Attach.
+type Broker interface {
+	Publish(ctx context.Context, event models.OutboxEvent) error
+}
+
+// Ack запоминает, что получатель subscriber принял событие.
+type Ack func(ctx context.Context, subscriber string) error
+
+type subscriber struct {
+	name    string
+	handler Handler
+}
+
+// Bus различает получателей по имени: по нему запоминается, кто уже принял
+// событие, поэтому имя должно быть уникальным и не меняться между запусками.
+type Bus struct {
+	mu       sync.RWMutex
+	names    map[string]bool
+	handlers map[string][]subscriber
+	brokers  []subscriber
+}
+
+func NewBus() *Bus {
+	return &Bus{names: make(map[string]bool), handlers: make(map[string][]subscriber)}
+}
+
+func (b *Bus) register(name string) {
+	if name == "" || strings.Contains(name, ",") {
+		panic(fmt.Sprintf("events: недопустимое имя получателя %q", name))
+	}
+	if b.names[name] {
+		panic(fmt.Sprintf("events: получатель %q уже подключён", name))
+	}
+	b.names[name] = true
+}
+
+func (b *Bus) Handle(name, eventType string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.register(name)
+	b.handlers[eventType] = append(b.handlers[eventType], subscriber{name: name, handler: handler})
+}
+
+// Attach подключает внешний брокер; он получает все события.
+func (b *Bus) Attach(name string, broker Broker) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.register(name)
+	b.brokers = append(b.brokers, subscriber{name: name, handler: broker.Publish})
+}
+
+// Subscribe подписывает типизированный обработчик на события типа T.
+func Subscribe[T models.DomainEvent](b *Bus, name string, handler func(ctx context.Context, event T) error) {
+	var zero T
+	b.Handle(name, zero.EventType(), func(ctx context.Context, e models.OutboxEvent) error {
+		var payload T
+		if err := json.Unmarshal(e.Payload, &payload); err != nil {
+			return fmt.Errorf("не удалось разобрать событие %s #%d: %w", e.Type, e.ID, err)
+		}
+		return handler(ctx, payload)
+	})
+}
+
+// Publish передаёт событие всем подписчикам его типа и всем брокерам, кроме
+// уже принявших его (event.Delivered). Каждого принявшего получателя ack
+// запоминает сразу, чтобы при повторе он не получил событие ещё раз.
+// Ошибка одного получателя не мешает остальным; ошибки возвращаются вместе.
+func (b *Bus) Publish(ctx context.Context, event models.OutboxEvent, ack Ack) error {
+	b.mu.RLock()
+	subscribers := append(slices.Clone(b.handlers[event.Type]), b.brokers...)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, sub := range subscribers {
+		if slices.Contains(event.Delivered, sub.name) {
+			continue
+		}
+		if err := sub.handler(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sub.name, err))
+			continue
+		}
+		if ack != nil {
+			if err := ack(ctx, sub.name); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
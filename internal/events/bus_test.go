@@ -0,0 +1,101 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+type recordingBroker struct {
+	events []models.OutboxEvent
+	err    error
+}
+
+func (b *recordingBroker) Publish(ctx context.Context, event models.OutboxEvent) error {
+	b.events = append(b.events, event)
+	return b.err
+}
+
+func TestSubscribe(t *testing.T) {
+	bus := NewBus()
+	var withdrawn []models.PointsWithdrawnEvent
+	Subscribe(bus, "withdrawn", func(ctx context.Context, e models.PointsWithdrawnEvent) error {
+		withdrawn = append(withdrawn, e)
+		return nil
+	})
+	registered := 0
+	Subscribe(bus, "registered", func(ctx context.Context, e models.UserRegisteredEvent) error {
+		registered++
+		return nil
+	})
+
+	require.NoError(t, bus.Publish(context.Background(), models.OutboxEvent{
+		ID:      1,
+		Type:    models.EventPointsWithdrawn,
+		Payload: []byte(`{"user_id":1,"order":"12345678903","sum":100,"merchant_id":3}`),
+	}, nil))
+	require.Equal(t, []models.PointsWithdrawnEvent{{UserID: 1, Order: "12345678903", Sum: 100, MerchantID: 3}}, withdrawn)
+	require.Zero(t, registered)
+
+	err := bus.Publish(context.Background(), models.OutboxEvent{ID: 2, Type: models.EventPointsWithdrawn, Payload: []byte(`[]`)}, nil)
+	require.Error(t, err)
+}
+
+func TestPublishReachesEveryone(t *testing.T) {
+	bus := NewBus()
+	failing := errors.New("handler failed")
+	calls := 0
+	bus.Handle("failing", models.EventUserRegistered, func(ctx context.Context, e models.OutboxEvent) error {
+		calls++
+		return failing
+	})
+	bus.Handle("ok", models.EventUserRegistered, func(ctx context.Context, e models.OutboxEvent) error {
+		calls++
+		return nil
+	})
+	broker := &recordingBroker{}
+	bus.Attach("broker", broker)
+
+	var acked []string
+	ack := func(ctx context.Context, subscriber string) error {
+		acked = append(acked, subscriber)
+		return nil
+	}
+	event := models.OutboxEvent{ID: 1, Type: models.EventUserRegistered, Payload: []byte(`{}`), CreatedAt: time.Now()}
+	err := bus.Publish(context.Background(), event, ack)
+	require.ErrorIs(t, err, failing)
+	require.Equal(t, 2, calls)
+	require.Equal(t, []models.OutboxEvent{event}, broker.events)
+	require.Equal(t, []string{"ok", "broker"}, acked)
+}
+
+func TestPublishSkipsDelivered(t *testing.T) {
+	bus := NewBus()
+	calls := map[string]int{}
+	for _, name := range []string{"first", "second"} {
+		bus.Handle(name, models.EventUserRegistered, func(ctx context.Context, e models.OutboxEvent) error {
+			calls[name]++
+			return nil
+		})
+	}
+	broker := &recordingBroker{}
+	bus.Attach("broker", broker)
+
+	event := models.OutboxEvent{ID: 1, Type: models.EventUserRegistered, Payload: []byte(`{}`), Delivered: []string{"first", "broker"}}
+	require.NoError(t, bus.Publish(context.Background(), event, nil))
+	require.Equal(t, map[string]int{"second": 1}, calls)
+	require.Empty(t, broker.events)
+}
+
+func TestDuplicateSubscriber(t *testing.T) {
+	bus := NewBus()
+	bus.Handle("notify", models.EventUserRegistered, func(ctx context.Context, e models.OutboxEvent) error { return nil })
+	require.Panics(t, func() {
+		bus.Attach("notify", &recordingBroker{})
+	})
+}
@@ -0,0 +1,85 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+const (
+	relayBatch = 100
+	// relayLease — через сколько неопубликованное событие выбирается снова.
+	relayLease = time.Minute
+)
+
+type OutboxStore interface {
+	ClaimOutbox(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEvent, error)
+	MarkOutboxDelivered(ctx context.Context, id int64, subscriber string) error
+	MarkOutboxPublished(ctx context.Context, id int64) error
+	FailOutboxEvent(ctx context.Context, id int64, lastError string, dead bool) error
+}
+
+// Relay переносит события из outbox в шину. Событие отмечается
+// опубликованным, только когда его приняли все получатели; иначе оно будет
+// выбрано повторно после relayLease, и получат его только те, кто ещё не
+// принял. После maxAttempts неудачных попыток событие переходит в dead.
+type Relay struct {
+	store       OutboxStore
+	bus         *Bus
+	maxAttempts int
+}
+
+func NewRelay(store OutboxStore, bus *Bus, maxAttempts int) *Relay {
+	return &Relay{store: store, bus: bus, maxAttempts: maxAttempts}
+}
+
+// RelayOnce публикует очередную пачку событий и возвращает число
+// опубликованных.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	events, err := r.store.ClaimOutbox(ctx, relayBatch, relayLease)
+	if err != nil {
+		return 0, err
+	}
+	published := 0
+	for _, event := range events {
+		ack := func(ctx context.Context, subscriber string) error {
+			return r.store.MarkOutboxDelivered(ctx, event.ID, subscriber)
+		}
+		if err := r.bus.Publish(ctx, event, ack); err != nil {
+			dead := event.Attempts+1 >= r.maxAttempts
+			logger.Log.Error("Ошибка публикации события", zap.Int64("id", event.ID),
+				zap.String("type", event.Type), zap.Bool("dead", dead), zap.Error(err))
+			if err := r.store.FailOutboxEvent(ctx, event.ID, err.Error(), dead); err != nil {
+				return published, err
+			}
+			continue
+		}
+		if err := r.store.MarkOutboxPublished(ctx, event.ID); err != nil {
+			return published, err
+		}
+		published++
+	}
+	return published, nil
+}
+
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			jobCtx, cancel := context.WithTimeout(ctx, time.Minute)
+			if _, err := r.RelayOnce(jobCtx); err != nil {
+				logger.Log.Error("Ошибка чтения outbox", zap.Error(err))
+			}
+			cancel()
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+type fakeOutbox struct {
+	pending   []models.OutboxEvent
+	published []int64
+	delivered map[int64][]string
+	failed    map[int64]bool
+}
+
+func (f *fakeOutbox) ClaimOutbox(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEvent, error) {
+	return f.pending, nil
+}
+
+func (f *fakeOutbox) MarkOutboxDelivered(ctx context.Context, id int64, subscriber string) error {
+	f.delivered[id] = append(f.delivered[id], subscriber)
+	return nil
+}
+
+func (f *fakeOutbox) MarkOutboxPublished(ctx context.Context, id int64) error {
+	f.published = append(f.published, id)
+	return nil
+}
+
+func (f *fakeOutbox) FailOutboxEvent(ctx context.Context, id int64, lastError string, dead bool) error {
+	f.failed[id] = dead
+	return nil
+}
+
+func TestRelayOnce(t *testing.T) {
+	store := &fakeOutbox{
+		pending: []models.OutboxEvent{
+			{ID: 1, Type: models.EventUserRegistered, Payload: []byte(`{"user_id":1,"login":"alice"}`)},
+			{ID: 2, Type: models.EventOrderProcessed, Payload: []byte(`{"user_id":1,"order":"12345678903","accrual":10}`)},
+			{ID: 3, Type: models.EventUserRegistered, Payload: []byte(`{"user_id":2,"login":"bob"}`), Attempts: 1},
+			{ID: 4, Type: models.EventUserRegistered, Payload: []byte(`{"user_id":3,"login":"bob"}`), Attempts: 2},
+			{ID: 5, Type: models.EventUserRegistered, Payload: []byte(`{"user_id":4,"login":"carol"}`), Delivered: []string{"welcome"}},
+		},
+		delivered: map[int64][]string{},
+		failed:    map[int64]bool{},
+	}
+	bus := NewBus()
+	var logins []string
+	Subscribe(bus, "welcome", func(ctx context.Context, e models.UserRegisteredEvent) error {
+		if e.Login == "bob" {
+			return errors.New("temporary failure")
+		}
+		logins = append(logins, e.Login)
+		return nil
+	})
+	audited := 0
+	Subscribe(bus, "audit", func(ctx context.Context, e models.UserRegisteredEvent) error {
+		audited++
+		return nil
+	})
+
+	published, err := NewRelay(store, bus, 3).RelayOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, published)
+	require.Equal(t, []int64{1, 2, 5}, store.published)
+	require.Equal(t, []string{"alice"}, logins)
+	require.Equal(t, 4, audited)
+	require.Equal(t, map[int64][]string{
+		1: {"welcome", "audit"},
+		3: {"audit"},
+		4: {"audit"},
+		5: {"audit"},
+	}, store.delivered)
+	require.Equal(t, map[int64]bool{3: false, 4: true}, store.failed)
+}
@@ -0,0 +1,248 @@
+// Package ingest загружает заказы из CSV-файлов, которые партнёры кладут в
+// каталог приёма. Строка файла — логин покупателя и номер заказа.
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+const (
+	DoneDir   = "done"
+	FailedDir = "failed"
+	// settleTime — файл, изменённый позже, может ещё дописываться, и его
+	// разбор откладывается до следующего прохода.
+	settleTime = 5 * time.Second
+)
+
+type Importer interface {
+	StartIngestionRun(ctx context.Context, fileName, checksum string) (int, error)
+	IngestOrders(ctx context.Context, lines []models.IngestionLine) error
+	FinishIngestionRun(ctx context.Context, run models.IngestionRun) error
+}
+
+// Watcher периодически просматривает каталог приёма. Загруженные файлы
+// переносятся в done, файлы с ошибками — в failed вместе с отчётом
+// <файл>.errors.csv по каждой неудачной строке.
+type Watcher struct {
+	dir      string
+	importer Importer
+}
+
+func NewWatcher(dir string, importer Importer) (*Watcher, error) {
+	for _, sub := range []string{DoneDir, FailedDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o750); err != nil {
+			return nil, fmt.Errorf("не удалось создать каталог %s: %w", sub, err)
+		}
+	}
+	return &Watcher{dir: dir, importer: importer}, nil
+}
+
+func (w *Watcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		w.Scan(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scan загружает все готовые CSV-файлы каталога. Файл, который не удалось
+// обработать из-за ошибки базы, остаётся на месте до следующего прохода.
+func (w *Watcher) Scan(ctx context.Context) {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		logger.Log.Error("Не удалось прочитать каталог приёма", zap.String("dir", w.dir), zap.Error(err))
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".csv") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < settleTime {
+			continue
+		}
+		if err := w.processFile(ctx, entry.Name()); err != nil {
+			logger.Log.Error("Ошибка загрузки файла заказов", zap.String("file", entry.Name()), zap.Error(err))
+		}
+	}
+}
+
+func (w *Watcher) processFile(ctx context.Context, name string) error {
+	path := filepath.Join(w.dir, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	run := models.IngestionRun{FileName: name, Checksum: hex.EncodeToString(sum[:])}
+
+	run.ID, err = w.importer.StartIngestionRun(ctx, name, run.Checksum)
+	if errors.Is(err, models.ErrAlreadyIngested) {
+		logger.Log.Info("Файл заказов уже загружен", zap.String("file", name))
+		_, err = moveFile(path, filepath.Join(w.dir, DoneDir))
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	lines, err := parseLines(data)
+	if err != nil {
+		run.Status = models.IngestionFailed
+		run.Error = err.Error()
+		if err := w.importer.FinishIngestionRun(ctx, run); err != nil {
+			return err
+		}
+		return w.fail(path, []models.IngestionLine{{Error: run.Error}})
+	}
+
+	var valid []models.IngestionLine
+	for _, line := range lines {
+		if line.Error == "" {
+			valid = append(valid, line)
+		}
+	}
+	if err := w.importer.IngestOrders(ctx, valid); err != nil {
+		run.Status = models.IngestionFailed
+		run.Error = err.Error()
+		// Файл остаётся в каталоге и будет разобран снова; незакрытый запуск
+		// важен только для журнала, поэтому обе ошибки уходят наверх.
+		return errors.Join(err, w.importer.FinishIngestionRun(ctx, run))
+	}
+
+	var failed []models.IngestionLine
+	results := make(map[int]models.IngestionLine, len(valid))
+	for _, line := range valid {
+		results[line.Line] = line
+	}
+	for _, line := range lines {
+		if res, ok := results[line.Line]; ok {
+			line = res
+		}
+		switch {
+		case line.Error != "":
+			run.Failed++
+			failed = append(failed, line)
+		case line.Status == models.BatchOrderAccepted:
+			run.Imported++
+		default:
+			run.Skipped++
+		}
+	}
+	run.Lines = len(lines)
+	run.Status = models.IngestionDone
+	if run.Failed > 0 {
+		run.Status = models.IngestionFailed
+	}
+	if err := w.importer.FinishIngestionRun(ctx, run); err != nil {
+		return err
+	}
+
+	if len(failed) > 0 {
+		return w.fail(path, failed)
+	}
+	_, err = moveFile(path, filepath.Join(w.dir, DoneDir))
+	return err
+}
+
+// fail переносит файл в failed и кладёт рядом отчёт об ошибочных строках.
+func (w *Watcher) fail(path string, failed []models.IngestionLine) error {
+	dst, err := moveFile(path, filepath.Join(w.dir, FailedDir))
+	if err != nil {
+		return err
+	}
+	report, err := os.Create(dst + ".errors.csv")
+	if err != nil {
+		return err
+	}
+	defer report.Close()
+
+	out := csv.NewWriter(report)
+	out.Write([]string{"line", "login", "order", "error"})
+	for _, line := range failed {
+		out.Write([]string{strconv.Itoa(line.Line), line.Login, line.Order, line.Error})
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// parseLines разбирает файл целиком. Синтаксическая ошибка CSV делает
+// файл непригодным; строка без двух столбцов помечается ошибкой.
+func parseLines(data []byte) ([]models.IngestionLine, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var lines []models.IngestionLine
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("неверный формат CSV: %w", err)
+		}
+		lineNum, _ := reader.FieldPos(0)
+		if first && len(record) >= 2 && isHeader(record[1]) {
+			continue
+		}
+		line := models.IngestionLine{Line: lineNum}
+		if len(record) < 2 {
+			line.Error = "ожидаются два столбца: логин и номер заказа"
+			lines = append(lines, line)
+			continue
+		}
+		line.Login = strings.TrimSpace(record[0])
+		line.Order = strings.TrimSpace(record[1])
+		if line.Login == "" {
+			line.Error = "не указан логин покупателя"
+		}
+		lines = append(lines, line)
+	}
+}
+
+func isHeader(cell string) bool {
+	switch strings.ToLower(strings.TrimSpace(cell)) {
+	case "order", "number", "order_number":
+		return true
+	}
+	return false
+}
+
+// moveFile переносит файл в каталог dir, не затирая файл с тем же именем,
+// и возвращает новый путь.
+func moveFile(path, dir string) (string, error) {
+	name := filepath.Base(path)
+	dst := filepath.Join(dir, name)
+	if _, err := os.Stat(dst); err == nil {
+		ext := filepath.Ext(name)
+		dst = filepath.Join(dir, fmt.Sprintf("%s-%d%s", strings.TrimSuffix(name, ext), time.Now().UnixNano(), ext))
+	}
+	if err := os.Rename(path, dst); err != nil {
+		return "", err
+	}
+	return dst, nil
+}
@@ -0,0 +1,152 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+// fakeImporter помнит контрольные суммы загруженных файлов и принимает
+// заказы только покупателя alice.
+type fakeImporter struct {
+	done      map[string]bool
+	runs      []models.IngestionRun
+	ingested  int
+	ingestErr error
+	finishErr error
+}
+
+func (f *fakeImporter) StartIngestionRun(ctx context.Context, fileName, checksum string) (int, error) {
+	if f.done[checksum] {
+		return 0, models.ErrAlreadyIngested
+	}
+	return len(f.runs) + 1, nil
+}
+
+func (f *fakeImporter) IngestOrders(ctx context.Context, lines []models.IngestionLine) error {
+	f.ingested++
+	if f.ingestErr != nil {
+		return f.ingestErr
+	}
+	for i := range lines {
+		switch {
+		case lines[i].Login != "alice":
+			lines[i].Status = models.BatchOrderInvalid
+			lines[i].Error = "покупатель не найден"
+		case lines[i].Order == "79927398713":
+			lines[i].Status = models.BatchOrderAlreadyUploaded
+		default:
+			lines[i].Status = models.BatchOrderAccepted
+		}
+	}
+	return nil
+}
+
+func (f *fakeImporter) FinishIngestionRun(ctx context.Context, run models.IngestionRun) error {
+	f.runs = append(f.runs, run)
+	if f.finishErr != nil {
+		return f.finishErr
+	}
+	if run.Status == models.IngestionDone {
+		f.done[run.Checksum] = true
+	}
+	return nil
+}
+
+func writeDropFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	old := time.Now().Add(-time.Minute)
+	require.NoError(t, os.Chtimes(path, old, old))
+}
+
+func TestWatcherScan(t *testing.T) {
+	dir := t.TempDir()
+	importer := &fakeImporter{done: make(map[string]bool)}
+	watcher, err := NewWatcher(dir, importer)
+	require.NoError(t, err)
+
+	const good = "login,order\nalice,12345678903\nalice,79927398713\n"
+	writeDropFile(t, dir, "good.csv", good)
+	writeDropFile(t, dir, "bad.csv", "alice,12345678903\nbob,4539578763621486\nbroken\n")
+	writeDropFile(t, dir, "notes.txt", "не CSV")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fresh.csv"), []byte(good), 0o600))
+
+	watcher.Scan(context.Background())
+
+	require.FileExists(t, filepath.Join(dir, DoneDir, "good.csv"))
+	require.FileExists(t, filepath.Join(dir, FailedDir, "bad.csv"))
+	require.FileExists(t, filepath.Join(dir, "notes.txt"))
+	require.FileExists(t, filepath.Join(dir, "fresh.csv"), "недописанный файл не трогается")
+
+	report, err := os.ReadFile(filepath.Join(dir, FailedDir, "bad.csv.errors.csv"))
+	require.NoError(t, err)
+	require.Equal(t, "line,login,order,error\n"+
+		"2,bob,4539578763621486,покупатель не найден\n"+
+		"3,,,ожидаются два столбца: логин и номер заказа\n", string(report))
+
+	require.Len(t, importer.runs, 2)
+	byName := map[string]models.IngestionRun{}
+	for _, run := range importer.runs {
+		byName[run.FileName] = run
+	}
+	require.Equal(t, models.IngestionDone, byName["good.csv"].Status)
+	require.Equal(t, 2, byName["good.csv"].Lines)
+	require.Equal(t, 1, byName["good.csv"].Imported)
+	require.Equal(t, 1, byName["good.csv"].Skipped)
+	require.Equal(t, models.IngestionFailed, byName["bad.csv"].Status)
+	require.Equal(t, 1, byName["bad.csv"].Imported)
+	require.Equal(t, 2, byName["bad.csv"].Failed)
+
+	// Повторно доставленный файл не загружается, но и не затирает прежний.
+	writeDropFile(t, dir, "good.csv", good)
+	ingested := importer.ingested
+	watcher.Scan(context.Background())
+
+	require.Equal(t, ingested, importer.ingested)
+	require.NoFileExists(t, filepath.Join(dir, "good.csv"))
+	matches, err := filepath.Glob(filepath.Join(dir, DoneDir, "good*.csv"))
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+}
+func TestWatcherMalformedFile(t *testing.T) {
+	dir := t.TempDir()
+	importer := &fakeImporter{done: make(map[string]bool)}
+	watcher, err := NewWatcher(dir, importer)
+	require.NoError(t, err)
+
+	writeDropFile(t, dir, "broken.csv", "alice,\"12345678903\nalice,79927398713\n")
+	watcher.Scan(context.Background())
+
+	require.FileExists(t, filepath.Join(dir, FailedDir, "broken.csv"))
+	require.FileExists(t, filepath.Join(dir, FailedDir, "broken.csv.errors.csv"))
+	require.Zero(t, importer.ingested)
+	require.Len(t, importer.runs, 1)
+	require.Equal(t, models.IngestionFailed, importer.runs[0].Status)
+	require.Contains(t, importer.runs[0].Error, "неверный формат CSV")
+}
+func TestWatcherIngestError(t *testing.T) {
+	dir := t.TempDir()
+	ingestErr := errors.New("база недоступна")
+	finishErr := errors.New("не удалось записать запуск")
+	importer := &fakeImporter{done: make(map[string]bool), ingestErr: ingestErr, finishErr: finishErr}
+	watcher, err := NewWatcher(dir, importer)
+	require.NoError(t, err)
+
+	writeDropFile(t, dir, "orders.csv", "alice,12345678903\n")
+	err = watcher.processFile(context.Background(), "orders.csv")
+
+	require.ErrorIs(t, err, ingestErr)
+	require.ErrorIs(t, err, finishErr)
+	require.FileExists(t, filepath.Join(dir, "orders.csv"))
+	require.Len(t, importer.runs, 1)
+	require.Equal(t, models.IngestionFailed, importer.runs[0].Status)
+}
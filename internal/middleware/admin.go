@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/scoring-service/internal/auth"
+)
+
+// RequirePermission проверяет права, записанные в access-токен при его
+// выпуске. Изменённые администратором права вступают в силу после
+// обновления токена, то есть не позднее чем через auth.AccessTokenTTL.
+func RequirePermission(permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.PrincipalFromContext(r.Context())
+			if !ok || !principal.Can(permission) {
+				http.Error(w, "Доступ запрещён", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -10,38 +10,56 @@ import (
 	"github.com/scoring-service/pkg/logger"
 )
 
+type AccountChecker interface {
+	IsUserBlocked(ctx context.Context, userID int) (bool, error)
+}
+
+var accountChecker AccountChecker
+
+func SetAccountChecker(checker AccountChecker) {
+	accountChecker = checker
+}
+
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
-		tokenString, err := getTokenFromRequest(r)
+		tokenString, fromCookie, err := getTokenFromRequest(r)
 		if err != nil {
 			logger.Log.Error(err.Error())
 			http.Error(w, "Пользователь не авторизован", http.StatusUnauthorized)
 			return
 		}
+		if fromCookie && !isSafeMethod(r.Method) && !auth.CheckCSRF(r) {
+			logger.Log.Error("CSRF-токен отсутствует или не совпадает")
+			http.Error(w, "Неверный CSRF-токен", http.StatusForbidden)
+			return
+		}
 
-		userID, err := auth.ValidateJWT(tokenString)
+		claims, err := auth.ParseJWT(r.Context(), tokenString)
 		if err != nil {
 			logger.Log.Error(err.Error())
 			http.Error(w, "Неудачная аутентификация", http.StatusUnauthorized)
 			return
 		}
-		ctx := context.WithValue(r.Context(), auth.UserIDKey, userID)
-
-		next.ServeHTTP(w, r.WithContext(ctx))
+		if accountChecker != nil {
+			blocked, err := accountChecker.IsUserBlocked(r.Context(), claims.UserID)
+			if err != nil {
+				logger.Log.Error(err.Error())
+				http.Error(w, "Ошибка проверки учётной записи", http.StatusInternalServerError)
+				return
+			}
+			if blocked {
+				http.Error(w, "Учётная запись заблокирована", http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), claims.Principal())))
 	})
 }
 
-func GetUserIDFromContext(ctx context.Context) (int, error) {
-	userID, ok := ctx.Value(auth.UserIDKey).(int)
-	if !ok {
-		logger.Log.Error("не удалось извлечь userID из контекста")
-		return 0, fmt.Errorf("не удалось извлечь userID из контекста")
-	}
-	return userID, nil
-}
-
-func getTokenFromRequest(r *http.Request) (string, error) {
+// getTokenFromRequest берёт токен из заголовка Authorization, а при его
+// отсутствии — из cookie. Второй результат сообщает, что токен пришёл в cookie.
+func getTokenFromRequest(r *http.Request) (string, bool, error) {
 
 	authHeader := r.Header.Get("Authorization")
 	if authHeader != "" {
@@ -49,10 +67,23 @@ func getTokenFromRequest(r *http.Request) (string, error) {
 		split := strings.Split(authHeader, "Bearer ")
 		if len(split) != 2 {
 			logger.Log.Error("неверный формат заголовка Authorization")
-			return "", fmt.Errorf("неверный формат заголовка Authorization")
+			return "", false, fmt.Errorf("неверный формат заголовка Authorization")
+		}
+		return split[1], false, nil
+	}
+	if auth.CookiesEnabled() {
+		if cookie, err := r.Cookie(auth.AccessCookie); err == nil && cookie.Value != "" {
+			return cookie.Value, true, nil
 		}
-		return split[1], nil
 	}
 	logger.Log.Error("не найден токен авторизации")
-	return "", fmt.Errorf("не найден токен авторизации")
+	return "", false, fmt.Errorf("не найден токен авторизации")
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
 }
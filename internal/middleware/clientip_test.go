@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientIP(t *testing.T) {
+	require.NoError(t, SetTrustedProxies("10.0.0.0/8, 192.168.1.1"))
+	t.Cleanup(func() { trustedProxies = nil })
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		want       string
+	}{
+		{
+			name:       "прямое подключение",
+			remoteAddr: "203.0.113.7:5000",
+			forwarded:  "198.51.100.1",
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "через доверенный прокси",
+			remoteAddr: "10.0.0.5:5000",
+			forwarded:  "198.51.100.1",
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "подделанная левая часть заголовка",
+			remoteAddr: "10.0.0.5:5000",
+			forwarded:  "1.2.3.4, 198.51.100.1, 192.168.1.1",
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "прокси без заголовка",
+			remoteAddr: "192.168.1.1:5000",
+			want:       "192.168.1.1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			assert.Equal(t, tt.want, ClientIP(r))
+		})
+	}
+
+	require.Error(t, SetTrustedProxies("not-an-ip"))
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+type MerchantAuthenticator interface {
+	AuthenticateMerchant(ctx context.Context, apiKey string) (*models.Merchant, error)
+	RegisterRequestNonce(ctx context.Context, merchantID int, nonce string) error
+}
+
+var (
+	merchantAuthenticator MerchantAuthenticator
+	merchantLimiter       = newRateLimiter(time.Minute)
+)
+
+func SetMerchantAuthenticator(authenticator MerchantAuthenticator) {
+	merchantAuthenticator = authenticator
+}
+
+// MerchantAuthMiddleware аутентифицирует мерчанта по заголовку X-API-Key.
+// Если мерчанту выдан секрет подписи, запрос должен быть подписан
+// (заголовки X-Timestamp, X-Nonce и X-Signature), а X-Nonce не должен
+// повторяться.
+func MerchantAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if merchantAuthenticator == nil {
+			http.Error(w, "Аутентификация мерчантов не настроена", http.StatusInternalServerError)
+			return
+		}
+		merchant, err := merchantAuthenticator.AuthenticateMerchant(r.Context(), r.Header.Get("X-API-Key"))
+		if errors.Is(err, models.ErrInvalidAPIKey) {
+			http.Error(w, "Неверный API-ключ", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			logger.Log.Error(err.Error())
+			http.Error(w, "Ошибка проверки API-ключа", http.StatusInternalServerError)
+			return
+		}
+
+		if merchant.HMACSecret != "" {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, "Не удалось прочитать тело запроса", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			signed := auth.SignedRequest{
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Timestamp: r.Header.Get("X-Timestamp"),
+				Nonce:     r.Header.Get("X-Nonce"),
+				Body:      body,
+			}
+			err = auth.VerifyRequestSignature([]byte(merchant.HMACSecret), signed, r.Header.Get("X-Signature"), time.Now())
+			if err != nil {
+				logger.Log.Error(err.Error())
+				http.Error(w, "Неверная подпись запроса", http.StatusUnauthorized)
+				return
+			}
+			err = merchantAuthenticator.RegisterRequestNonce(r.Context(), merchant.ID, signed.Nonce)
+			if errors.Is(err, models.ErrRequestReplayed) {
+				http.Error(w, "Повтор подписанного запроса", http.StatusUnauthorized)
+				return
+			}
+			if err != nil {
+				logger.Log.Error(err.Error())
+				http.Error(w, "Ошибка проверки подписи", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		if ok, retryAfter := merchantLimiter.allow(merchant.ID, merchant.RateLimit, time.Now()); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			http.Error(w, "Превышен лимит запросов", http.StatusTooManyRequests)
+			return
+		}
+
+		principal := &auth.Principal{
+			UserID:      merchant.UserID,
+			Role:        models.RoleMerchant,
+			Permissions: merchant.Permissions,
+			MerchantID:  merchant.ID,
+		}
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	})
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+// nonceAuthenticator — мерчант с секретом подписи и хранилище принятых
+// X-Nonce в памяти.
+type nonceAuthenticator struct {
+	seen map[string]bool
+}
+
+func (a *nonceAuthenticator) AuthenticateMerchant(_ context.Context, apiKey string) (*models.Merchant, error) {
+	if apiKey != "gmk_key" {
+		return nil, models.ErrInvalidAPIKey
+	}
+	return &models.Merchant{ID: 3, UserID: 7, HMACSecret: "secret"}, nil
+}
+
+func (a *nonceAuthenticator) RegisterRequestNonce(_ context.Context, _ int, nonce string) error {
+	if a.seen[nonce] {
+		return models.ErrRequestReplayed
+	}
+	a.seen[nonce] = true
+	return nil
+}
+
+func TestMerchantAuthMiddlewareSignature(t *testing.T) {
+	SetMerchantAuthenticator(&nonceAuthenticator{seen: map[string]bool{}})
+	t.Cleanup(func() { SetMerchantAuthenticator(nil) })
+
+	handler := MerchantAuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	body := `{"order":"12345678903","customer_login":"alice"}`
+	signed := auth.SignedRequest{
+		Method:    http.MethodPost,
+		Path:      "/api/merchant/orders",
+		Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+		Nonce:     "nonce-1",
+		Body:      []byte(body),
+	}
+	signature := auth.SignRequest([]byte("secret"), signed)
+
+	send := func(method, path string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("X-API-Key", "gmk_key")
+		req.Header.Set("X-Timestamp", signed.Timestamp)
+		req.Header.Set("X-Nonce", signed.Nonce)
+		req.Header.Set("X-Signature", signature)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusUnauthorized, send(http.MethodPost, "/api/merchant/checkout"), "подпись не переносится на другой путь")
+	require.Equal(t, http.StatusOK, send(http.MethodPost, "/api/merchant/orders"))
+	require.Equal(t, http.StatusUnauthorized, send(http.MethodPost, "/api/merchant/orders"), "повтор отклоняется")
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter ограничивает число запросов на ключ в фиксированном окне.
+// Счётчики живут в памяти процесса, поэтому при нескольких репликах лимит
+// действует на каждую реплику отдельно.
+type rateLimiter struct {
+	mu      sync.Mutex
+	window  time.Duration
+	windows map[int]*rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+func newRateLimiter(window time.Duration) *rateLimiter {
+	return &rateLimiter{window: window, windows: make(map[int]*rateWindow)}
+}
+
+// allow учитывает запрос и сообщает, укладывается ли он в limit. Нулевой
+// limit снимает ограничение. Второй результат — время до начала нового окна.
+func (l *rateLimiter) allow(key, limit int, now time.Time) (bool, time.Duration) {
+	if limit <= 0 {
+		return true, 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &rateWindow{start: now}
+		l.windows[key] = w
+	}
+	if w.count >= limit {
+		return false, w.start.Add(l.window).Sub(now)
+	}
+	w.count++
+	return true, 0
+}
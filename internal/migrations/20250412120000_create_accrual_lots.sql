@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS accrual_lots (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id),
+    order_number VARCHAR(20),
+    source VARCHAR(20) NOT NULL,
+    amount NUMERIC(10,2) NOT NULL,
+    remaining NUMERIC(10,2) NOT NULL,
+    expired_amount NUMERIC(10,2) DEFAULT 0,
+    accrued_at TIMESTAMP DEFAULT now(),
+    expires_at TIMESTAMP,
+    expired_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS accrual_lots_user_id_idx ON accrual_lots (user_id, accrued_at) WHERE remaining > 0;
+CREATE INDEX IF NOT EXISTS accrual_lots_expires_at_idx ON accrual_lots (expires_at) WHERE remaining > 0;
+
+INSERT INTO accrual_lots (user_id, source, amount, remaining, accrued_at)
+SELECT id, 'LEGACY', current_balance, current_balance, now()
+FROM users
+WHERE current_balance > 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS accrual_lots;
+-- +goose StatementEnd
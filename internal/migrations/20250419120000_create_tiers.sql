@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS tiers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) UNIQUE NOT NULL,
+    min_points NUMERIC(10,2) NOT NULL,
+    multiplier NUMERIC(4,2) NOT NULL DEFAULT 1
+);
+
+INSERT INTO tiers (name, min_points, multiplier) VALUES
+    ('Bronze', 0, 1.00),
+    ('Silver', 1000, 1.25),
+    ('Gold', 5000, 1.50)
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tier_id INT REFERENCES tiers(id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS tier_id;
+DROP TABLE IF EXISTS tiers;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS campaigns (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    merchant VARCHAR(255) NOT NULL DEFAULT '',
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP,
+    first_order_only BOOLEAN NOT NULL DEFAULT FALSE,
+    order_prefix VARCHAR(20) NOT NULL DEFAULT '',
+    bonus_points NUMERIC(10,2) NOT NULL DEFAULT 0,
+    multiplier NUMERIC(4,2) NOT NULL DEFAULT 1,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS campaign_bonuses (
+    id SERIAL PRIMARY KEY,
+    campaign_id INT NOT NULL REFERENCES campaigns(id),
+    user_id INT NOT NULL REFERENCES users(id),
+    order_number VARCHAR(20) NOT NULL,
+    amount NUMERIC(10,2) NOT NULL,
+    created_at TIMESTAMP DEFAULT now(),
+    UNIQUE (campaign_id, order_number)
+);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS processed_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN IF EXISTS processed_at;
+DROP TABLE IF EXISTS campaign_bonuses;
+DROP TABLE IF EXISTS campaigns;
+-- +goose StatementEnd
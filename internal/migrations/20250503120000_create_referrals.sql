@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN IF NOT EXISTS referral_code VARCHAR(16) UNIQUE;
+
+UPDATE users
+SET referral_code = upper(substr(md5(random()::text || id::text), 1, 8))
+WHERE referral_code IS NULL;
+
+CREATE TABLE IF NOT EXISTS referrals (
+    id SERIAL PRIMARY KEY,
+    referrer_id INT NOT NULL REFERENCES users(id),
+    referee_id INT NOT NULL UNIQUE REFERENCES users(id),
+    status VARCHAR(20) NOT NULL,
+    referrer_bonus NUMERIC(10,2) DEFAULT 0,
+    referee_bonus NUMERIC(10,2) DEFAULT 0,
+    created_at TIMESTAMP DEFAULT now(),
+    rewarded_at TIMESTAMP,
+    CHECK (referrer_id <> referee_id)
+);
+
+CREATE INDEX IF NOT EXISTS referrals_referrer_id_idx ON referrals (referrer_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS referrals;
+ALTER TABLE users DROP COLUMN IF EXISTS referral_code;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'customer';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS blocked_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS balance_adjustments (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id),
+    admin_id INT NOT NULL REFERENCES users(id),
+    amount NUMERIC(10,2) NOT NULL,
+    reason TEXT NOT NULL CHECK (reason <> ''),
+    created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS admin_actions (
+    id SERIAL PRIMARY KEY,
+    admin_id INT NOT NULL REFERENCES users(id),
+    action VARCHAR(50) NOT NULL,
+    target_user_id INT REFERENCES users(id),
+    target_order VARCHAR(20),
+    details TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS balance_adjustments_user_id_idx ON balance_adjustments (user_id, created_at);
+CREATE INDEX IF NOT EXISTS admin_actions_admin_id_idx ON admin_actions (admin_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS admin_actions;
+DROP TABLE IF EXISTS balance_adjustments;
+ALTER TABLE users DROP COLUMN IF EXISTS blocked_at;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(20) NOT NULL,
+    permission VARCHAR(50) NOT NULL,
+    PRIMARY KEY (role, permission)
+);
+
+CREATE TABLE IF NOT EXISTS user_permissions (
+    user_id INT NOT NULL REFERENCES users(id),
+    permission VARCHAR(50) NOT NULL,
+    granted_at TIMESTAMP DEFAULT now(),
+    PRIMARY KEY (user_id, permission)
+);
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('support', 'users:read'),
+    ('support', 'users:block'),
+    ('support', 'orders:repoll'),
+    ('admin', 'users:read'),
+    ('admin', 'users:block'),
+    ('admin', 'users:manage'),
+    ('admin', 'orders:repoll'),
+    ('admin', 'balance:adjust'),
+    ('admin', 'campaigns:manage'),
+    ('merchant', 'orders:submit')
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_permissions;
+DROP TABLE IF EXISTS role_permissions;
+-- +goose StatementEnd
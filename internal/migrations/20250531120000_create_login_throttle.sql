@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS login_throttle (
+    scope VARCHAR(10) NOT NULL,
+    key TEXT NOT NULL,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP NOT NULL DEFAULT now(),
+    locked_until TIMESTAMP,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE TABLE IF NOT EXISTS login_audit (
+    id SERIAL PRIMARY KEY,
+    login TEXT NOT NULL,
+    ip VARCHAR(64) NOT NULL DEFAULT '',
+    success BOOLEAN NOT NULL,
+    reason VARCHAR(30) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS login_audit_login_idx ON login_audit (login, created_at);
+CREATE INDEX IF NOT EXISTS login_audit_ip_idx ON login_audit (ip, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS login_audit;
+DROP TABLE IF EXISTS login_throttle;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS merchants (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL UNIQUE REFERENCES users(id),
+    name VARCHAR(255) NOT NULL,
+    rate_limit INT NOT NULL DEFAULT 0,
+    hmac_secret TEXT,
+    created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS merchant_api_keys (
+    id SERIAL PRIMARY KEY,
+    merchant_id INT NOT NULL REFERENCES merchants(id),
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT now(),
+    revoked_at TIMESTAMP
+);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS merchant_id INT REFERENCES merchants(id);
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'merchants:manage')
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM role_permissions WHERE permission = 'merchants:manage';
+ALTER TABLE orders DROP COLUMN IF EXISTS merchant_id;
+DROP TABLE IF EXISTS merchant_api_keys;
+DROP TABLE IF EXISTS merchants;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS order_items (
+    id SERIAL PRIMARY KEY,
+    order_number VARCHAR(20) NOT NULL REFERENCES orders(number) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    category VARCHAR(255) NOT NULL DEFAULT '',
+    brand VARCHAR(255) NOT NULL DEFAULT '',
+    price NUMERIC(10,2) NOT NULL,
+    quantity INT NOT NULL DEFAULT 1
+);
+
+CREATE INDEX IF NOT EXISTS order_items_order_idx ON order_items (order_number);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_items;
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS reconciliation_runs (
+    id SERIAL PRIMARY KEY,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NOT NULL,
+    auto_correct BOOLEAN NOT NULL DEFAULT FALSE,
+    users_checked INT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS balance_drifts (
+    id SERIAL PRIMARY KEY,
+    run_id INT NOT NULL REFERENCES reconciliation_runs(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id),
+    balance NUMERIC(10,2) NOT NULL,
+    expected_balance NUMERIC(10,2) NOT NULL,
+    withdrawn NUMERIC(10,2) NOT NULL,
+    expected_withdrawn NUMERIC(10,2) NOT NULL,
+    corrected BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE IF NOT EXISTS balance_corrections (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id),
+    balance_before NUMERIC(10,2) NOT NULL,
+    balance_after NUMERIC(10,2) NOT NULL,
+    withdrawn_before NUMERIC(10,2) NOT NULL,
+    withdrawn_after NUMERIC(10,2) NOT NULL,
+    created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS balance_drifts_run_id_idx ON balance_drifts (run_id);
+CREATE INDEX IF NOT EXISTS balance_corrections_user_id_idx ON balance_corrections (user_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS balance_corrections;
+DROP TABLE IF EXISTS balance_drifts;
+DROP TABLE IF EXISTS reconciliation_runs;
+-- +goose StatementEnd
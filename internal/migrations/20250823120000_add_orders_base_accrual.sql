@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- base_accrual — начисление, рассчитанное системой начислений, до
+-- множителя уровня. Для уже обработанных заказов исходная сумма не
+-- сохранилась, поэтому за неё принимается зачисленная.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS base_accrual NUMERIC(10,2);
+
+UPDATE orders SET base_accrual = accrual
+WHERE status = 'PROCESSED' AND base_accrual IS NULL;
+
+UPDATE orders o SET processed_at = l.accrued_at
+FROM accrual_lots l
+WHERE o.processed_at IS NULL AND o.status = 'PROCESSED'
+    AND l.order_number = o.number AND l.source = 'ORDER';
+
+CREATE INDEX IF NOT EXISTS orders_user_id_processed_at_idx ON orders (user_id, processed_at) WHERE status = 'PROCESSED';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS orders_user_id_processed_at_idx;
+ALTER TABLE orders DROP COLUMN IF EXISTS base_accrual;
+-- +goose StatementEnd
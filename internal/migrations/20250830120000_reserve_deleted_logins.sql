@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Служебные логины удалённых учётных записей переводятся на префикс, который
+-- нельзя зарегистрировать.
+UPDATE users SET login = '#deleted-' || id WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE users u SET login = 'deleted-' || u.id
+WHERE u.deleted_at IS NOT NULL
+    AND NOT EXISTS (SELECT 1 FROM users o WHERE o.login = 'deleted-' || u.id);
+-- +goose StatementEnd
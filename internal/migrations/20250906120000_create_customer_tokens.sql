@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS customer_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id),
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    merchant_id INT REFERENCES merchants(id)
+);
+
+CREATE TABLE IF NOT EXISTS merchant_request_nonces (
+    merchant_id INT NOT NULL REFERENCES merchants(id),
+    nonce VARCHAR(64) NOT NULL,
+    received_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (merchant_id, nonce)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS merchant_request_nonces;
+DROP TABLE IF EXISTS customer_tokens;
+-- +goose StatementEnd
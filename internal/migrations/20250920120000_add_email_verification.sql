@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE notification_preferences
+    ADD COLUMN IF NOT EXISTS pending_email TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS email_token_hash CHAR(64),
+    ADD COLUMN IF NOT EXISTS email_token_expires_at TIMESTAMP;
+
+-- Адреса, сохранённые до появления подтверждения, не проверялись: письма на
+-- них уйдут только после того, как пользователь укажет адрес заново и
+-- подтвердит его.
+UPDATE notification_preferences SET pending_email = email, email = '' WHERE email <> '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE notification_preferences SET email = pending_email WHERE email = '' AND pending_email <> '';
+ALTER TABLE notification_preferences
+    DROP COLUMN IF EXISTS email_token_expires_at,
+    DROP COLUMN IF EXISTS email_token_hash,
+    DROP COLUMN IF EXISTS pending_email;
+-- +goose StatementEnd
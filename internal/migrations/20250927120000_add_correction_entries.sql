@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE reconciliation_runs ALTER COLUMN finished_at DROP NOT NULL;
+
+ALTER TABLE balance_corrections
+    ADD COLUMN IF NOT EXISTS run_id INT REFERENCES reconciliation_runs(id),
+    ADD COLUMN IF NOT EXISTS amount NUMERIC(10,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS reason TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE balance_corrections
+    DROP COLUMN IF EXISTS reason,
+    DROP COLUMN IF EXISTS amount,
+    DROP COLUMN IF EXISTS run_id;
+
+DELETE FROM reconciliation_runs WHERE finished_at IS NULL;
+ALTER TABLE reconciliation_runs ALTER COLUMN finished_at SET NOT NULL;
+-- +goose StatementEnd
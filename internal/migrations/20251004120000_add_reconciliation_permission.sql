@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'reconciliation:read')
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM user_permissions WHERE permission = 'reconciliation:read';
+DELETE FROM role_permissions WHERE permission = 'reconciliation:read';
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Акции привязываются к магазину по названию, поэтому оно должно быть
+-- уникальным. Совпавшие названия, кроме самого раннего, получают номер
+-- мерчанта, чтобы индекс создался; акции остаются за первым магазином.
+UPDATE merchants m SET name = m.name || ' #' || m.id
+WHERE EXISTS (SELECT 1 FROM merchants p WHERE p.name = m.name AND p.id < m.id);
+
+ALTER TABLE merchants ADD CONSTRAINT merchants_name_key UNIQUE (name);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE merchants DROP CONSTRAINT IF EXISTS merchants_name_key;
+-- +goose StatementEnd
@@ -0,0 +1,69 @@
+// Package notify содержит способы доставки служебных сообщений
+// пользователям: письма через SMTP, а для локальной разработки и
+// отладки — журнал сервиса и файл.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// LogNotifier пишет сообщения в журнал сервиса. Токены сброса пароля и
+// подтверждения адреса в журнал не попадают: кто читает журнал, завладел бы
+// по ним учётной записью. Поэтому для сценариев с токеном он не годится и
+// нужен только для отладки остальных уведомлений.
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, n models.Notification) error {
+	logger.Log.Info("Уведомление пользователю",
+		zap.String("kind", n.Kind),
+		zap.Int("user", n.UserID),
+		zap.String("login", n.Login),
+		zap.String("email", n.Email),
+		zap.Bool("has_token", n.Token != ""),
+		zap.Time("expires_at", n.ExpiresAt),
+		zap.String("order", n.Order),
+		zap.Float64("amount", n.Amount),
+	)
+	return nil
+}
+
+// FileNotifier дописывает сообщения в файл по одному JSON-объекту на строку.
+// Токены пишутся открытым текстом, поэтому он предназначен только для
+// локальной разработки.
+type FileNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+func (f *FileNotifier) Notify(ctx context.Context, n models.Notification) error {
+	line, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("не удалось открыть файл уведомлений: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("не удалось записать уведомление: %w", err)
+	}
+	return nil
+}
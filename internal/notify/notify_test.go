@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestFileNotifier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.log")
+	notifier := NewFileNotifier(path)
+	expires := time.Date(2025, 6, 7, 13, 0, 0, 0, time.UTC)
+
+	for _, token := range []string{"first", "second"} {
+		require.NoError(t, notifier.Notify(context.Background(), models.Notification{
+			Kind:      models.NotificationPasswordReset,
+			UserID:    1,
+			Login:     "alice",
+			Token:     token,
+			ExpiresAt: expires,
+		}))
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var n models.Notification
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &n))
+	require.Equal(t, "second", n.Token)
+	require.Equal(t, expires, n.ExpiresAt)
+}
+
+// Токен сброса пароля даёт доступ к учётной записи, поэтому в журнал он
+// попадать не должен.
+func TestLogNotifierHidesToken(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	prev := logger.Log
+	logger.Log = zap.New(core)
+	defer func() { logger.Log = prev }()
+
+	require.NoError(t, LogNotifier{}.Notify(context.Background(), models.Notification{
+		Kind:   models.NotificationPasswordReset,
+		UserID: 1,
+		Login:  "alice",
+		Token:  "secret-reset-token",
+	}))
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	require.Equal(t, true, fields["has_token"])
+	for _, v := range fields {
+		require.NotEqual(t, "secret-reset-token", v)
+	}
+}
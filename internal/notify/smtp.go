@@ -0,0 +1,174 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// SMTPNotifier отправляет уведомления письмами за одну попытку. Временную
+// ошибку сервера или сети он возвращает с методом Temporary: уведомления о
+// баллах повторяет outbox, письма с токенами — сервис. Отказ сервера с
+// кодом 5xx не повторяется: письмо отбрасывается с записью в журнал.
+// Попытка ограничена Timeout и контекстом, чтобы зависший сервер не держал
+// отправителя.
+type SMTPNotifier struct {
+	Addr    string
+	From    string
+	Auth    smtp.Auth
+	Timeout time.Duration
+}
+
+// defaultSMTPTimeout ограничивает попытку, если Timeout не задан.
+const defaultSMTPTimeout = 30 * time.Second
+
+func NewSMTPNotifier(addr, from, user, password string, timeout time.Duration) *SMTPNotifier {
+	n := &SMTPNotifier{Addr: addr, From: from, Timeout: timeout}
+	if user != "" {
+		host, _, _ := strings.Cut(addr, ":")
+		n.Auth = smtp.PlainAuth("", user, password, host)
+	}
+	return n
+}
+
+func (s *SMTPNotifier) Notify(ctx context.Context, n models.Notification) error {
+	if n.Email == "" {
+		logger.Log.Warn("Уведомление не отправлено: у пользователя нет адреса почты",
+			zap.String("kind", n.Kind), zap.Int("user", n.UserID))
+		return nil
+	}
+	msg, err := s.compose(n)
+	if err != nil {
+		return err
+	}
+
+	err = s.send(ctx, n.Email, msg)
+	if permanent(err) {
+		logger.Log.Error("Сервер отклонил письмо", zap.String("kind", n.Kind),
+			zap.Int("user", n.UserID), zap.Error(err))
+		return nil
+	}
+	if err != nil {
+		return &temporaryError{err: err}
+	}
+	return nil
+}
+
+// temporaryError — временная ошибка отправки: ответ сервера 4xx или сбой
+// сети. Такую отправку можно повторить.
+type temporaryError struct {
+	err error
+}
+
+func (e *temporaryError) Error() string {
+	return "не удалось отправить письмо: " + e.err.Error()
+}
+
+func (e *temporaryError) Unwrap() error   { return e.err }
+func (e *temporaryError) Temporary() bool { return true }
+
+// send повторяет smtp.SendMail, но соединяется с учётом контекста и
+// ставит соединению крайний срок: SendMail не принимает контекст и может
+// ждать ответа сервера бесконечно.
+func (s *SMTPNotifier) send(ctx context.Context, to string, msg []byte) error {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = defaultSMTPTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	// Отмена контекста прерывает и уже начатый обмен.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	host, _, _ := strings.Cut(s.Addr, ":")
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if err := c.Hello("localhost"); err != nil {
+		return err
+	}
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if s.Auth != nil {
+		if err := c.Auth(s.Auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func (s *SMTPNotifier) compose(n models.Notification) ([]byte, error) {
+	subject, body, err := Render(n)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", n.Email)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	w := quotedprintable.NewWriter(&buf)
+	if _, err := w.Write([]byte(body)); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// permanent сообщает, что сервер окончательно отклонил письмо.
+func permanent(err error) bool {
+	var tpErr *textproto.Error
+	return errors.As(err, &tpErr) && tpErr.Code >= 500
+}
@@ -0,0 +1,137 @@
+package notify
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/notify/smtptest"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestSMTPNotifier(t *testing.T) {
+	t.Run("письмо на языке пользователя", func(t *testing.T) {
+		server := smtptest.NewServer(t, 0)
+		notifier := &SMTPNotifier{Addr: server.Addr, From: "noreply@gophermart.local"}
+
+		require.NoError(t, notifier.Notify(context.Background(), models.Notification{
+			Kind:   models.NotificationPointsCredited,
+			UserID: 1,
+			Login:  "alice",
+			Email:  "alice@example.com",
+			Locale: models.LocaleRU,
+			Order:  "12345678903",
+			Amount: 729.98,
+		}))
+
+		messages := server.Messages()
+		require.Len(t, messages, 1)
+		require.Equal(t, "noreply@gophermart.local", messages[0].From)
+		require.Equal(t, []string{"alice@example.com"}, messages[0].To)
+		require.Equal(t, "Начислено 729.98 баллов", messages[0].Subject)
+		require.Contains(t, messages[0].Body, "За заказ 12345678903 вам начислено 729.98 баллов.")
+	})
+
+	t.Run("временная ошибка возвращается", func(t *testing.T) {
+		server := smtptest.NewServer(t, 1)
+		notifier := &SMTPNotifier{Addr: server.Addr, From: "noreply@gophermart.local"}
+		n := models.Notification{
+			Kind:   models.NotificationPointsWithdrawn,
+			Email:  "bob@example.com",
+			Locale: models.LocaleEN,
+			Order:  "2377225624",
+			Amount: 100,
+		}
+
+		require.Error(t, notifier.Notify(context.Background(), n))
+		require.Empty(t, server.Messages())
+
+		require.NoError(t, notifier.Notify(context.Background(), n))
+		messages := server.Messages()
+		require.Len(t, messages, 1)
+		require.Equal(t, "100.00 points withdrawn", messages[0].Subject)
+	})
+
+	t.Run("постоянный отказ не повторяется", func(t *testing.T) {
+		server := smtptest.NewServer(t, 0)
+		notifier := &SMTPNotifier{Addr: server.Addr, From: "noreply@gophermart.local"}
+
+		require.NoError(t, notifier.Notify(context.Background(), models.Notification{
+			Kind:  models.NotificationPointsWithdrawn,
+			Email: "bob@example.invalid",
+		}))
+		require.Empty(t, server.Messages())
+	})
+
+	t.Run("зависший сервер не держит отправителя", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer ln.Close()
+		go func() {
+			// Соединение принимается, но приветствие так и не приходит.
+			for {
+				conn, err := ln.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+			}
+		}()
+		n := models.Notification{Kind: models.NotificationPointsCredited, Email: "alice@example.com"}
+
+		notifier := &SMTPNotifier{Addr: ln.Addr().String(), From: "noreply@gophermart.local", Timeout: 100 * time.Millisecond}
+		started := time.Now()
+		require.Error(t, notifier.Notify(context.Background(), n))
+		require.Less(t, time.Since(started), 5*time.Second)
+
+		notifier.Timeout = time.Minute
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		started = time.Now()
+		require.Error(t, notifier.Notify(ctx, n))
+		require.Less(t, time.Since(started), 5*time.Second)
+	})
+
+	t.Run("без адреса письмо не отправляется", func(t *testing.T) {
+		server := smtptest.NewServer(t, 0)
+		notifier := &SMTPNotifier{Addr: server.Addr, From: "noreply@gophermart.local"}
+
+		require.NoError(t, notifier.Notify(context.Background(), models.Notification{Kind: models.NotificationPointsCredited}))
+		require.Empty(t, server.Messages())
+	})
+}
+
+func TestRender(t *testing.T) {
+	expires := time.Date(2025, 6, 7, 13, 0, 0, 0, time.UTC)
+	subject, body, err := Render(models.Notification{
+		Kind:      models.NotificationPasswordReset,
+		Login:     "alice",
+		Locale:    models.LocaleEN,
+		Token:     "token",
+		ExpiresAt: expires,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Password reset", subject)
+	require.Contains(t, body, "The token is valid until Jun 7, 2025 13:00 UTC.")
+
+	subject, body, err = Render(models.Notification{
+		Kind:      models.NotificationEmailVerification,
+		Login:     "alice",
+		Locale:    models.LocaleRU,
+		Token:     "token",
+		ExpiresAt: expires,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Подтверждение адреса почты", subject)
+	require.Contains(t, body, "подтвердите его токеном: token")
+
+	subject, _, err = Render(models.Notification{Kind: models.NotificationPasswordReset, Locale: "de"})
+	require.NoError(t, err)
+	require.Equal(t, "Сброс пароля", subject)
+
+	_, _, err = Render(models.Notification{Kind: "UNKNOWN"})
+	require.Error(t, err)
+}
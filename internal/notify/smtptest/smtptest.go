@@ -0,0 +1,157 @@
+// Package smtptest поднимает в тестах минимальный SMTP-сервер, который
+// принимает письма и сохраняет их в памяти.
+package smtptest
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Message — принятое сервером письмо с раскодированными темой и текстом.
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	Body    string
+	Header  mail.Header
+}
+
+type Server struct {
+	Addr string
+
+	mu        sync.Mutex
+	messages  []Message
+	failFirst int
+	ln        net.Listener
+}
+
+// NewServer запускает сервер на свободном порту localhost. Первые
+// failFirst соединений отклоняются временной ошибкой 421, получатели в
+// домене .invalid — постоянной ошибкой 550. Сервер останавливается по
+// завершении теста.
+func NewServer(t testing.TB, failFirst int) *Server {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("smtptest: %v", err)
+	}
+	s := &Server{Addr: ln.Addr().String(), failFirst: failFirst, ln: ln}
+	go s.serve()
+	t.Cleanup(func() { ln.Close() })
+	return s
+}
+
+// Messages возвращает принятые письма в порядке получения.
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+
+	s.mu.Lock()
+	reject := s.failFirst > 0
+	if reject {
+		s.failFirst--
+	}
+	s.mu.Unlock()
+	if reject {
+		tp.PrintfLine("421 service not available")
+		return
+	}
+
+	tp.PrintfLine("220 smtptest ready")
+	var msg Message
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(cmd) {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250 smtptest")
+		case "MAIL":
+			msg = Message{From: address(arg)}
+			tp.PrintfLine("250 OK")
+		case "RCPT":
+			to := address(arg)
+			if strings.HasSuffix(to, ".invalid") {
+				tp.PrintfLine("550 mailbox unavailable")
+				continue
+			}
+			msg.To = append(msg.To, to)
+			tp.PrintfLine("250 OK")
+		case "DATA":
+			tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			if err := parse(&msg, data); err != nil {
+				tp.PrintfLine("554 %s", err)
+				continue
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			tp.PrintfLine("250 OK")
+		case "RSET", "NOOP":
+			tp.PrintfLine("250 OK")
+		case "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("502 command not implemented")
+		}
+	}
+}
+
+func parse(msg *Message, data []byte) error {
+	m, err := mail.ReadMessage(bufio.NewReader(strings.NewReader(string(data))))
+	if err != nil {
+		return err
+	}
+	msg.Header = m.Header
+	if msg.Subject, err = new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject")); err != nil {
+		return err
+	}
+	body := io.Reader(m.Body)
+	if strings.EqualFold(m.Header.Get("Content-Transfer-Encoding"), "quoted-printable") {
+		body = quotedprintable.NewReader(body)
+	}
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	msg.Body = strings.ReplaceAll(string(b), "\r\n", "\n")
+	return nil
+}
+
+// address извлекает адрес из аргумента вида "FROM:<a@b>".
+func address(arg string) string {
+	_, addr, _ := strings.Cut(arg, ":")
+	addr, _, _ = strings.Cut(strings.TrimSpace(addr), " ")
+	return strings.Trim(addr, "<>")
+}
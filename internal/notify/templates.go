@@ -0,0 +1,129 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+type message struct {
+	subject string
+	body    string
+}
+
+// messages — тексты писем по языку и виду уведомления. Язык, для которого
+// нет перевода, заменяется русским.
+var messages = map[string]map[string]message{
+	models.LocaleRU: {
+		models.NotificationPasswordReset: {
+			subject: "Сброс пароля",
+			body: `Здравствуйте, {{.Login}}!
+
+Для сброса пароля используйте токен: {{.Token}}
+Токен действует до {{.ExpiresAt.Format "02.01.2006 15:04 MST"}}.
+
+Если вы не запрашивали сброс пароля, просто проигнорируйте это письмо.
+`,
+		},
+		models.NotificationEmailVerification: {
+			subject: "Подтверждение адреса почты",
+			body: `Здравствуйте, {{.Login}}!
+
+Чтобы получать уведомления на этот адрес, подтвердите его токеном: {{.Token}}
+Токен действует до {{.ExpiresAt.Format "02.01.2006 15:04 MST"}}.
+
+Если вы не указывали этот адрес, просто проигнорируйте это письмо.
+`,
+		},
+		models.NotificationPointsCredited: {
+			subject: "Начислено {{amount .Amount}} баллов",
+			body: `Здравствуйте, {{.Login}}!
+
+За заказ {{.Order}} вам начислено {{amount .Amount}} баллов.
+`,
+		},
+		models.NotificationPointsWithdrawn: {
+			subject: "Списано {{amount .Amount}} баллов",
+			body: `Здравствуйте, {{.Login}}!
+
+В счёт заказа {{.Order}} списано {{amount .Amount}} баллов.
+`,
+		},
+	},
+	models.LocaleEN: {
+		models.NotificationPasswordReset: {
+			subject: "Password reset",
+			body: `Hello, {{.Login}}!
+
+Use this token to reset your password: {{.Token}}
+The token is valid until {{.ExpiresAt.Format "Jan 2, 2006 15:04 MST"}}.
+
+If you did not request a password reset, please ignore this email.
+`,
+		},
+		models.NotificationEmailVerification: {
+			subject: "Confirm your email address",
+			body: `Hello, {{.Login}}!
+
+To receive notifications at this address, confirm it with this token: {{.Token}}
+The token is valid until {{.ExpiresAt.Format "Jan 2, 2006 15:04 MST"}}.
+
+If you did not enter this address, please ignore this email.
+`,
+		},
+		models.NotificationPointsCredited: {
+			subject: "{{amount .Amount}} points credited",
+			body: `Hello, {{.Login}}!
+
+You have earned {{amount .Amount}} points for order {{.Order}}.
+`,
+		},
+		models.NotificationPointsWithdrawn: {
+			subject: "{{amount .Amount}} points withdrawn",
+			body: `Hello, {{.Login}}!
+
+{{amount .Amount}} points were withdrawn for order {{.Order}}.
+`,
+		},
+	},
+}
+
+var funcs = template.FuncMap{
+	"amount": func(v float64) string { return fmt.Sprintf("%.2f", v) },
+}
+
+// Render возвращает тему и текст письма для уведомления на языке
+// получателя.
+func Render(n models.Notification) (string, string, error) {
+	byKind, ok := messages[n.Locale]
+	if !ok {
+		byKind = messages[models.LocaleRU]
+	}
+	msg, ok := byKind[n.Kind]
+	if !ok {
+		return "", "", fmt.Errorf("нет шаблона для уведомления %s", n.Kind)
+	}
+	subject, err := execute(msg.subject, n)
+	if err != nil {
+		return "", "", err
+	}
+	body, err := execute(msg.body, n)
+	if err != nil {
+		return "", "", err
+	}
+	return subject, body, nil
+}
+
+func execute(text string, n models.Notification) (string, error) {
+	tmpl, err := template.New("").Funcs(funcs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, n); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
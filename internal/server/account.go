@@ -0,0 +1,101 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+// ChangePassword меняет пароль текущего пользователя; остальные его сессии
+// завершаются.
+func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	var req models.PasswordChange
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		http.Error(w, "Не указан текущий или новый пароль", http.StatusBadRequest)
+		return
+	}
+	if err := h.serv.ChangePassword(r.Context(), principal, req); err != nil {
+		accountError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// RequestPasswordReset всегда отвечает 202, чтобы не раскрывать, какие
+// логины зарегистрированы.
+func (h *Handler) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var req models.PasswordResetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Login == "" {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	if err := h.serv.RequestPasswordReset(r.Context(), req.Login); err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.PasswordReset
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	if req.Token == "" || req.NewPassword == "" {
+		http.Error(w, "Не указан токен или новый пароль", http.StatusBadRequest)
+		return
+	}
+	if err := h.serv.ResetPassword(r.Context(), req); err != nil {
+		accountError(w, err)
+		return
+	}
+	auth.ClearAuthCookies(w)
+	w.WriteHeader(http.StatusOK)
+}
+
+// DeleteAccount закрывает учётную запись текущего пользователя. Для
+// подтверждения требуется пароль.
+func (h *Handler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	var req models.AccountDeletion
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Password == "" {
+		http.Error(w, "Для удаления учётной записи укажите пароль", http.StatusBadRequest)
+		return
+	}
+	if err := h.serv.DeleteAccount(r.Context(), principal.UserID, req.Password); err != nil {
+		accountError(w, err)
+		return
+	}
+	auth.ClearAuthCookies(w)
+	w.WriteHeader(http.StatusOK)
+}
+
+func accountError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, models.ErrWeakPassword):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, models.ErrInvalidResetToken):
+		http.Error(w, "Недействительный или просроченный токен", http.StatusBadRequest)
+	case errors.Is(err, models.ErrInvalidCredentials):
+		http.Error(w, "Неверный пароль", http.StatusForbidden)
+	case errors.Is(err, models.ErrUserNotFound):
+		http.Error(w, "Пользователь не найден", http.StatusNotFound)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
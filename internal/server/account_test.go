@@ -0,0 +1,122 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestChangePassword(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		mockSetup func(serv *MockService)
+		code      int
+	}{
+		{
+			name: "password changed",
+			body: `{"current_password": "old", "new_password": "new-secret"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("ChangePassword", mock.Anything, mock.Anything, models.PasswordChange{
+					CurrentPassword: "old",
+					NewPassword:     "new-secret",
+				}).Return(nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:      "missing current password",
+			body:      `{"new_password": "new-secret"}`,
+			mockSetup: func(serv *MockService) {},
+			code:      http.StatusBadRequest,
+		},
+		{
+			name: "wrong current password",
+			body: `{"current_password": "guess", "new_password": "new-secret"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("ChangePassword", mock.Anything, mock.Anything, mock.Anything).Return(models.ErrInvalidCredentials)
+			},
+			code: http.StatusForbidden,
+		},
+		{
+			name: "weak new password",
+			body: `{"current_password": "old", "new_password": "123"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("ChangePassword", mock.Anything, mock.Anything, mock.Anything).
+					Return(fmt.Errorf("%w: длина должна быть не меньше 8 символов", models.ErrWeakPassword))
+			},
+			code: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockService(t)
+			tt.mockSetup(mockService)
+			h := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/user/password", strings.NewReader(tt.body))
+			req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1, SessionID: 2}))
+			w := httptest.NewRecorder()
+
+			h.ChangePassword(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+			require.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
+func TestRequestPasswordReset(t *testing.T) {
+	mockService := NewMockService(t)
+	mockService.On("RequestPasswordReset", mock.Anything, "alice").Return(nil)
+	h := NewHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/password/reset", strings.NewReader(`{"login": "alice"}`))
+	w := httptest.NewRecorder()
+
+	h.RequestPasswordReset(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+}
+func TestResetPassword(t *testing.T) {
+	mockService := NewMockService(t)
+	mockService.On("ResetPassword", mock.Anything, models.PasswordReset{Token: "used", NewPassword: "new-secret"}).
+		Return(models.ErrInvalidResetToken)
+	h := NewHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/password/reset/confirm",
+		strings.NewReader(`{"token": "used", "new_password": "new-secret"}`))
+	w := httptest.NewRecorder()
+
+	h.ResetPassword(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+func TestDeleteAccount(t *testing.T) {
+	mockService := NewMockService(t)
+	mockService.On("DeleteAccount", mock.Anything, 1, "secret").Return(nil)
+	h := NewHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/user", strings.NewReader(`{"password": "secret"}`))
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+	w := httptest.NewRecorder()
+
+	h.DeleteAccount(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
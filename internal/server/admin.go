@@ -0,0 +1,188 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"github.com/scoring-service/internal/service"
+	"github.com/scoring-service/pkg/models"
+)
+
+func (h *Handler) SearchUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := h.serv.SearchUsers(r.Context(), r.URL.Query().Get("q"))
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if len(users) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, users)
+}
+
+func (h *Handler) GetUserView(w http.ResponseWriter, r *http.Request) {
+	userID, ok := targetUserID(w, r)
+	if !ok {
+		return
+	}
+	view, err := h.serv.GetUserView(r.Context(), userID)
+	if err != nil {
+		adminError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, view)
+}
+
+// GetLastReconciliation отдаёт итог последней сверки балансов.
+func (h *Handler) GetLastReconciliation(w http.ResponseWriter, r *http.Request) {
+	run, err := h.serv.GetLastReconciliation(r.Context())
+	if err != nil {
+		adminError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, run)
+}
+
+func (h *Handler) AdjustBalance(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := targetUserID(w, r)
+	if !ok {
+		return
+	}
+	var req models.Adjustment
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+	if err := h.serv.AdjustBalance(r.Context(), principal.UserID, userID, req); err != nil {
+		adminError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) BlockUser(w http.ResponseWriter, r *http.Request) {
+	h.setUserBlocked(w, r, true)
+}
+
+func (h *Handler) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	h.setUserBlocked(w, r, false)
+}
+
+func (h *Handler) setUserBlocked(w http.ResponseWriter, r *http.Request, blocked bool) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := targetUserID(w, r)
+	if !ok {
+		return
+	}
+	var req models.BlockRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request format", http.StatusBadRequest)
+			return
+		}
+	}
+	if err := h.serv.SetUserBlocked(r.Context(), principal.UserID, userID, blocked, req.Reason); err != nil {
+		adminError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) RepollOrder(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	if err := h.serv.RepollOrder(r.Context(), principal.UserID, chi.URLParam(r, "number")); err != nil {
+		adminError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *Handler) SetUserAccess(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := targetUserID(w, r)
+	if !ok {
+		return
+	}
+	var req models.UserAccess
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+	if err := h.serv.SetUserAccess(r.Context(), principal.UserID, userID, req); err != nil {
+		adminError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) UnlockLogin(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := targetUserID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.serv.UnlockLogin(r.Context(), principal.UserID, userID); err != nil {
+		adminError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func targetUserID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		http.Error(w, "invalid user id", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func adminError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, models.ErrUserNotFound):
+		http.Error(w, "user not found", http.StatusNotFound)
+	case errors.Is(err, models.ErrOrderNotFound):
+		http.Error(w, "order not found", http.StatusNotFound)
+	case errors.Is(err, models.ErrMerchantNotFound):
+		http.Error(w, "merchant not found", http.StatusNotFound)
+	case errors.Is(err, models.ErrNoReconciliation):
+		http.Error(w, "no reconciliation has run yet", http.StatusNotFound)
+	case errors.Is(err, models.ErrInvalidAPIKey):
+		http.Error(w, "api key not found", http.StatusNotFound)
+	case errors.Is(err, models.ErrLoginTaken):
+		http.Error(w, "login already taken", http.StatusConflict)
+	case errors.Is(err, models.ErrMerchantNameTaken):
+		http.Error(w, "merchant name already taken", http.StatusConflict)
+	case errors.Is(err, service.ErrReasonRequired), errors.Is(err, service.ErrInvalidAmount),
+		errors.Is(err, service.ErrUnknownRole), errors.Is(err, service.ErrUnknownPerm),
+		errors.Is(err, service.ErrInvalidMerchant):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, models.ErrInsufficientFunds):
+		http.Error(w, "insufficient funds", http.StatusConflict)
+	case errors.Is(err, service.ErrQueueNotReady), errors.Is(err, service.ErrQueueFull):
+		http.Error(w, "order queue unavailable", http.StatusServiceUnavailable)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
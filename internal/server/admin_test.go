@@ -0,0 +1,226 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/internal/service"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestAdjustBalance(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        string
+		body      string
+		mockSetup func(serv *MockService)
+		code      int
+	}{
+		{
+			name: "adjustment applied",
+			id:   "5",
+			body: `{"amount":-30,"reason":"double credit"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("AdjustBalance", mock.Anything, 1, 5, models.Adjustment{Amount: -30, Reason: "double credit"}).Return(nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "missing reason",
+			id:   "5",
+			body: `{"amount":10}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("AdjustBalance", mock.Anything, 1, 5, models.Adjustment{Amount: 10}).Return(service.ErrReasonRequired)
+			},
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "user not found",
+			id:   "6",
+			body: `{"amount":10,"reason":"goodwill"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("AdjustBalance", mock.Anything, 1, 6, mock.Anything).Return(models.ErrUserNotFound)
+			},
+			code: http.StatusNotFound,
+		},
+		{
+			name: "insufficient funds",
+			id:   "5",
+			body: `{"amount":-1000,"reason":"chargeback"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("AdjustBalance", mock.Anything, 1, 5, mock.Anything).Return(models.ErrInsufficientFunds)
+			},
+			code: http.StatusConflict,
+		},
+		{
+			name:      "invalid user id",
+			id:        "abc",
+			body:      `{"amount":10,"reason":"goodwill"}`,
+			mockSetup: func(serv *MockService) {},
+			code:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockService(t)
+			tt.mockSetup(mockService)
+
+			h := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/users/"+tt.id+"/adjustments", strings.NewReader(tt.body))
+			req = withURLParam(req, "id", tt.id)
+			req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+			w := httptest.NewRecorder()
+
+			h.AdjustBalance(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			require.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
+func TestBlockUser(t *testing.T) {
+	mockService := NewMockService(t)
+	mockService.On("SetUserBlocked", mock.Anything, 1, 5, true, "fraud").Return(nil)
+
+	h := NewHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/users/5/block", strings.NewReader(`{"reason":"fraud"}`))
+	req = withURLParam(req, "id", "5")
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+	w := httptest.NewRecorder()
+
+	h.BlockUser(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+func TestRepollOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "order enqueued", code: http.StatusAccepted},
+		{name: "order not found", err: models.ErrOrderNotFound, code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockService(t)
+			mockService.On("RepollOrder", mock.Anything, 1, "79927398713").Return(tt.err)
+
+			h := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/orders/79927398713/repoll", nil)
+			req = withURLParam(req, "number", "79927398713")
+			req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+			w := httptest.NewRecorder()
+
+			h.RepollOrder(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			require.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
+func TestSetUserAccess(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "access updated", code: http.StatusOK},
+		{name: "unknown role", err: service.ErrUnknownRole, code: http.StatusBadRequest},
+		{name: "user not found", err: models.ErrUserNotFound, code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockService(t)
+			mockService.On("SetUserAccess", mock.Anything, 1, 5, models.UserAccess{
+				Role:        models.RoleSupport,
+				Permissions: []string{models.PermBalanceAdjust},
+			}).Return(tt.err)
+
+			h := NewHandler(mockService)
+
+			body := `{"role":"support","permissions":["balance:adjust"]}`
+			req := httptest.NewRequest(http.MethodPut, "/api/admin/users/5/access", strings.NewReader(body))
+			req = withURLParam(req, "id", "5")
+			req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+			w := httptest.NewRecorder()
+
+			h.SetUserAccess(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			require.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
+func TestUnlockLogin(t *testing.T) {
+	mockService := NewMockService(t)
+	mockService.On("UnlockLogin", mock.Anything, 1, 5).Return(nil)
+
+	h := NewHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/users/5/unlock", nil)
+	req = withURLParam(req, "id", "5")
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+	w := httptest.NewRecorder()
+
+	h.UnlockLogin(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestGetLastReconciliation(t *testing.T) {
+	tests := []struct {
+		name string
+		run  models.ReconciliationRun
+		err  error
+		code int
+	}{
+		{
+			name: "last run",
+			run: models.ReconciliationRun{ID: 7, UsersChecked: 42, Drifts: []models.BalanceDrift{
+				{UserID: 3, Login: "alice", Balance: 1000, ExpectedBalance: 500},
+			}},
+			code: http.StatusOK,
+		},
+		{name: "never run", err: models.ErrNoReconciliation, code: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockService(t)
+			mockService.On("GetLastReconciliation", mock.Anything).Return(tt.run, tt.err)
+			h := NewHandler(mockService)
+
+			w := httptest.NewRecorder()
+			h.GetLastReconciliation(w, httptest.NewRequest(http.MethodGet, "/api/admin/reconciliation", nil))
+
+			require.Equal(t, tt.code, w.Code)
+			if tt.code == http.StatusOK {
+				require.Contains(t, w.Body.String(), `"expected_balance":500`)
+			}
+		})
+	}
+}
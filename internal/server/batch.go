@@ -0,0 +1,127 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+// maxBatchBody ограничивает тело пакетной загрузки заказов.
+const maxBatchBody = 10 << 20
+
+func (h *Handler) PostOrdersBatch(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	numbers, err := parseOrderBatch(r.Header.Get("Content-Type"), http.MaxBytesReader(w, r.Body, maxBatchBody))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(numbers) == 0 {
+		http.Error(w, "empty batch", http.StatusBadRequest)
+		return
+	}
+
+	results, err := h.serv.CreateOrders(r.Context(), principal.UserID, numbers)
+	if errors.Is(err, models.ErrBatchTooLarge) {
+		http.Error(w, "too many orders in batch", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// parseOrderBatch разбирает номера заказов из JSON-массива, NDJSON или CSV.
+// Элементом JSON может быть строка с номером или объект {"order": "..."};
+// в CSV номер берётся из первой колонки, строка заголовка пропускается.
+func parseOrderBatch(contentType string, body io.Reader) ([]string, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/json", "":
+		var items []json.RawMessage
+		if err := json.NewDecoder(body).Decode(&items); err != nil {
+			return nil, fmt.Errorf("invalid JSON array: %w", err)
+		}
+		numbers := make([]string, len(items))
+		for i, item := range items {
+			number, err := parseBatchItem(item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i+1, err)
+			}
+			numbers[i] = number
+		}
+		return numbers, nil
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		var numbers []string
+		scanner := bufio.NewScanner(body)
+		for line := 1; scanner.Scan(); line++ {
+			item := bytes.TrimSpace(scanner.Bytes())
+			if len(item) == 0 {
+				continue
+			}
+			number, err := parseBatchItem(item)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			numbers = append(numbers, number)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("invalid NDJSON: %w", err)
+		}
+		return numbers, nil
+	case "text/csv":
+		reader := csv.NewReader(body)
+		reader.FieldsPerRecord = -1
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		var numbers []string
+		for i, record := range records {
+			number := strings.TrimSpace(record[0])
+			if number == "" || i == 0 && isBatchHeader(number) {
+				continue
+			}
+			numbers = append(numbers, number)
+		}
+		return numbers, nil
+	default:
+		return nil, fmt.Errorf("unsupported content type %q", mediaType)
+	}
+}
+
+func parseBatchItem(item json.RawMessage) (string, error) {
+	var number string
+	if err := json.Unmarshal(item, &number); err == nil {
+		return number, nil
+	}
+	var obj struct {
+		Order string `json:"order"`
+	}
+	if err := json.Unmarshal(item, &obj); err != nil || obj.Order == "" {
+		return "", errors.New(`expected order number string or {"order": "..."}`)
+	}
+	return obj.Order, nil
+}
+
+func isBatchHeader(cell string) bool {
+	switch strings.ToLower(strings.TrimPrefix(cell, "\ufeff")) {
+	case "order", "number", "order_number":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestParseOrderBatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        []string
+		wantErr     bool
+	}{
+		{
+			name:        "json array",
+			contentType: "application/json",
+			body:        `["12345678903", {"order": "79927398713"}]`,
+			want:        []string{"12345678903", "79927398713"},
+		},
+		{
+			name:        "ndjson",
+			contentType: "application/x-ndjson",
+			body:        "\"12345678903\"\n\n{\"order\": \"79927398713\"}\n",
+			want:        []string{"12345678903", "79927398713"},
+		},
+		{
+			name:        "csv with header",
+			contentType: "text/csv; charset=utf-8",
+			body:        "order,amount\n12345678903,100\n79927398713\n",
+			want:        []string{"12345678903", "79927398713"},
+		},
+		{
+			name:        "json numbers are rejected",
+			contentType: "application/json",
+			body:        `[12345678903]`,
+			wantErr:     true,
+		},
+		{
+			name:        "unsupported type",
+			contentType: "text/plain",
+			body:        "12345678903",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOrderBatch(tt.contentType, strings.NewReader(tt.body))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+func TestPostOrdersBatch(t *testing.T) {
+	mockService := NewMockService(t)
+	mockService.On("CreateOrders", mock.Anything, 1, []string{"12345678903", "123"}).Return([]models.BatchOrderResult{
+		{Order: "12345678903", Status: models.BatchOrderAccepted},
+		{Order: "123", Status: models.BatchOrderInvalid},
+	}, nil)
+	mockService.On("CreateOrders", mock.Anything, 1, mock.Anything).Return(nil, models.ErrBatchTooLarge)
+	h := NewHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/orders/batch", strings.NewReader(`["12345678903", "123"]`))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+	w := httptest.NewRecorder()
+	h.PostOrdersBatch(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var results []models.BatchOrderResult
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&results))
+	require.Len(t, results, 2)
+	require.Equal(t, models.BatchOrderInvalid, results[1].Status)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/user/orders/batch", strings.NewReader("12345678903\n79927398713\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+	w = httptest.NewRecorder()
+	h.PostOrdersBatch(w, req)
+	require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
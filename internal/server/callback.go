@@ -0,0 +1,83 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+// maxCallbackBody ограничивает тело уведомления о начислениях.
+const maxCallbackBody = 1 << 20
+
+// AccrualCallback принимает результаты расчёта от системы начислений: один
+// объект AccrualResponse или их массив. Запрос подписывается так же, как
+// запросы мерчантов, — заголовками X-Timestamp, X-Nonce и X-Signature.
+func (h *Handler) AccrualCallback(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCallbackBody))
+	if err != nil {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	signature := r.Header.Get("X-Signature")
+	err = h.serv.VerifyAccrualCallback(r.Context(), signedRequest(r, body), signature)
+	switch {
+	case errors.Is(err, models.ErrCallbackDisabled):
+		http.NotFound(w, r)
+		return
+	case errors.Is(err, auth.ErrSignatureMissing), errors.Is(err, auth.ErrSignatureExpired), errors.Is(err, auth.ErrSignatureInvalid):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case err != nil:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	accruals, err := parseAccruals(body)
+	if err != nil || len(accruals) == 0 {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	err = h.serv.ApplyAccruals(r.Context(), signature, accruals)
+	switch {
+	case errors.Is(err, models.ErrInvalidAccrual):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, models.ErrCallbackReplayed):
+		http.Error(w, "callback already received", http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parseAccruals(body []byte) ([]models.AccrualResponse, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var accruals []models.AccrualResponse
+		err := json.Unmarshal(body, &accruals)
+		return accruals, err
+	}
+	var accrual models.AccrualResponse
+	if err := json.Unmarshal(body, &accrual); err != nil {
+		return nil, err
+	}
+	return []models.AccrualResponse{accrual}, nil
+}
+
+// signedRequest собирает подписываемые части входящего запроса.
+func signedRequest(r *http.Request, body []byte) auth.SignedRequest {
+	return auth.SignedRequest{
+		Method:    r.Method,
+		Path:      r.URL.Path,
+		Timestamp: r.Header.Get("X-Timestamp"),
+		Nonce:     r.Header.Get("X-Nonce"),
+		Body:      body,
+	}
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestAccrualCallback(t *testing.T) {
+	single := `{"order": "12345678903", "status": "PROCESSED", "accrual": 10}`
+	processed := models.AccrualResponse{Order: "12345678903", Status: models.OrderProcessed, Accrual: 10}
+
+	tests := []struct {
+		name      string
+		body      string
+		verifyErr error
+		applied   []models.AccrualResponse
+		applyErr  error
+		want      int
+	}{
+		{name: "один объект", body: single, applied: []models.AccrualResponse{processed}, want: http.StatusNoContent},
+		{
+			name:    "массив",
+			body:    `[{"order": "12345678903", "status": "PROCESSED", "accrual": 10}, {"order": "79927398713", "status": "INVALID"}]`,
+			applied: []models.AccrualResponse{processed, {Order: "79927398713", Status: models.OrderInvalid}},
+			want:    http.StatusNoContent,
+		},
+		{name: "приём выключен", body: single, verifyErr: models.ErrCallbackDisabled, want: http.StatusNotFound},
+		{name: "неверная подпись", body: single, verifyErr: auth.ErrSignatureInvalid, want: http.StatusUnauthorized},
+		{name: "просроченная метка", body: single, verifyErr: auth.ErrSignatureExpired, want: http.StatusUnauthorized},
+		{name: "повтор", body: single, applied: []models.AccrualResponse{processed}, applyErr: models.ErrCallbackReplayed, want: http.StatusConflict},
+		{name: "битый JSON", body: `{"order":`, want: http.StatusBadRequest},
+		{name: "пустой массив", body: `[]`, want: http.StatusBadRequest},
+		{name: "неверные данные", body: single, applied: []models.AccrualResponse{processed}, applyErr: models.ErrInvalidAccrual, want: http.StatusBadRequest},
+		{name: "ошибка записи", body: single, applied: []models.AccrualResponse{processed}, applyErr: errors.New("db"), want: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockService(t)
+			signed := auth.SignedRequest{
+				Method:    http.MethodPost,
+				Path:      "/api/internal/accrual-callback",
+				Timestamp: "1700000000",
+				Nonce:     "nonce",
+				Body:      []byte(tt.body),
+			}
+			mockService.On("VerifyAccrualCallback", mock.Anything, signed, "sig").Return(tt.verifyErr)
+			if tt.applied != nil {
+				mockService.On("ApplyAccruals", mock.Anything, "sig", tt.applied).Return(tt.applyErr)
+			}
+			h := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/internal/accrual-callback", strings.NewReader(tt.body))
+			req.Header.Set("X-Timestamp", "1700000000")
+			req.Header.Set("X-Nonce", "nonce")
+			req.Header.Set("X-Signature", "sig")
+			w := httptest.NewRecorder()
+			h.AccrualCallback(w, req)
+			require.Equal(t, tt.want, w.Code)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	"github.com/scoring-service/internal/campaign"
+	"github.com/scoring-service/pkg/models"
+)
+
+func (h *Handler) ListCampaigns(w http.ResponseWriter, r *http.Request) {
+	campaigns, err := h.serv.ListCampaigns(r.Context())
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if len(campaigns) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, campaigns)
+}
+
+func (h *Handler) GetCampaign(w http.ResponseWriter, r *http.Request) {
+	id, ok := campaignID(w, r)
+	if !ok {
+		return
+	}
+	c, err := h.serv.GetCampaign(r.Context(), id)
+	if err != nil {
+		campaignError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+func (h *Handler) CreateCampaign(w http.ResponseWriter, r *http.Request) {
+	var c models.Campaign
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+	if err := h.serv.CreateCampaign(r.Context(), &c); err != nil {
+		campaignError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, c)
+}
+
+func (h *Handler) UpdateCampaign(w http.ResponseWriter, r *http.Request) {
+	id, ok := campaignID(w, r)
+	if !ok {
+		return
+	}
+	var c models.Campaign
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+	c.ID = id
+	if err := h.serv.UpdateCampaign(r.Context(), &c); err != nil {
+		campaignError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+func (h *Handler) DeleteCampaign(w http.ResponseWriter, r *http.Request) {
+	id, ok := campaignID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.serv.DeleteCampaign(r.Context(), id); err != nil {
+		campaignError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) DryRunCampaign(w http.ResponseWriter, r *http.Request) {
+	id, ok := campaignID(w, r)
+	if !ok {
+		return
+	}
+	from, err := parseTimeParam(r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, "invalid from parameter", http.StatusBadRequest)
+		return
+	}
+	to, err := parseTimeParam(r.URL.Query().Get("to"))
+	if err != nil {
+		http.Error(w, "invalid to parameter", http.StatusBadRequest)
+		return
+	}
+	result, err := h.serv.DryRunCampaign(r.Context(), id, from, to)
+	if err != nil {
+		campaignError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func campaignID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		http.Error(w, "invalid campaign id", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func campaignError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, models.ErrCampaignNotFound):
+		http.Error(w, "campaign not found", http.StatusNotFound)
+	case errors.Is(err, campaign.ErrInvalidCampaign):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+// parseTimeParam принимает дату в формате RFC 3339 или YYYY-MM-DD.
+// Пустое значение возвращает нулевое время.
+func parseTimeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/campaign"
+	"github.com/scoring-service/pkg/models"
+)
+
+func withURLParam(req *http.Request, key, value string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add(key, value)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestCreateCampaign(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		mockSetup func(serv *MockService)
+		code      int
+	}{
+		{
+			name: "campaign created",
+			body: `{"name":"weekend","starts_at":"2025-05-03T00:00:00Z","multiplier":2,"active":true}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("CreateCampaign", mock.Anything, mock.Anything).Return(nil)
+			},
+			code: http.StatusCreated,
+		},
+		{
+			name: "invalid campaign",
+			body: `{"name":"","starts_at":"2025-05-03T00:00:00Z","multiplier":2}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("CreateCampaign", mock.Anything, mock.Anything).
+					Return(fmt.Errorf("%w: не задано название", campaign.ErrInvalidCampaign))
+			},
+			code: http.StatusBadRequest,
+		},
+		{
+			name:      "bad json",
+			body:      `{bad json}`,
+			mockSetup: func(serv *MockService) {},
+			code:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockService(t)
+			tt.mockSetup(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/campaigns", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			NewHandler(mockService).CreateCampaign(w, req)
+
+			require.Equal(t, tt.code, w.Code)
+		})
+	}
+}
+func TestDryRunCampaign(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        string
+		query     string
+		mockSetup func(serv *MockService)
+		code      int
+	}{
+		{
+			name:  "dry run with range",
+			id:    "7",
+			query: "?from=2025-05-01&to=2025-06-01",
+			mockSetup: func(serv *MockService) {
+				from := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+				to := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+				serv.On("DryRunCampaign", mock.Anything, 7, from, to).
+					Return(models.CampaignDryRun{CampaignID: 7, Evaluated: 3, Total: 200}, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "campaign not found",
+			id:   "8",
+			mockSetup: func(serv *MockService) {
+				serv.On("DryRunCampaign", mock.Anything, 8, time.Time{}, time.Time{}).
+					Return(models.CampaignDryRun{}, models.ErrCampaignNotFound)
+			},
+			code: http.StatusNotFound,
+		},
+		{
+			name:      "invalid range",
+			id:        "7",
+			query:     "?from=yesterday",
+			mockSetup: func(serv *MockService) {},
+			code:      http.StatusBadRequest,
+		},
+		{
+			name:      "invalid id",
+			id:        "abc",
+			mockSetup: func(serv *MockService) {},
+			code:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockService(t)
+			tt.mockSetup(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/campaigns/"+tt.id+"/dry-run"+tt.query, nil)
+			req = withURLParam(req, "id", tt.id)
+			w := httptest.NewRecorder()
+
+			NewHandler(mockService).DryRunCampaign(w, req)
+
+			require.Equal(t, tt.code, w.Code)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/scoring-service/internal/service"
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+func (h *Handler) IssueCheckoutCode(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	code, err := h.serv.IssueCheckoutCode(r.Context(), principal.UserID)
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusCreated, code)
+}
+
+// IssueCustomerToken выдаёт покупателю одноразовый токен для загрузки заказа
+// магазином через /api/merchant/orders.
+func (h *Handler) IssueCustomerToken(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	token, err := h.serv.IssueCustomerToken(r.Context(), principal.UserID)
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusCreated, token)
+}
+
+func (h *Handler) RedeemCheckoutCode(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requireMerchant(w, r)
+	if !ok {
+		return
+	}
+	var req models.CheckoutRedeem
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+	req.Code = strings.TrimSpace(req.Code)
+	req.Order = strings.TrimSpace(req.Order)
+	if req.Code == "" {
+		http.Error(w, "code is required", http.StatusBadRequest)
+		return
+	}
+	if !validItems(req.Items) {
+		http.Error(w, "invalid items", http.StatusBadRequest)
+		return
+	}
+
+	wait, err := h.serv.CheckCheckoutThrottle(r.Context(), principal.MerchantID)
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if wait > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		http.Error(w, "too many invalid codes, try again later", http.StatusTooManyRequests)
+		return
+	}
+
+	switch status := h.serv.RedeemCheckoutCode(r.Context(), principal.MerchantID, req); status {
+	case service.StatusOK:
+		if req.Sum > 0 {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusAccepted)
+		}
+	case service.StatusAlreadyExist:
+		w.WriteHeader(http.StatusOK)
+	case service.StatusNotFound:
+		http.Error(w, "invalid or expired code", http.StatusNotFound)
+	case service.StatusConflict:
+		if req.Sum > 0 {
+			http.Error(w, "insufficient funds", http.StatusPaymentRequired)
+		} else {
+			http.Error(w, "order already exists for another user", http.StatusConflict)
+		}
+	case service.StatusInvalid:
+		http.Error(w, "invalid order number format", http.StatusUnprocessableEntity)
+	case service.StatusTOTPRequired:
+		http.Error(w, "two-factor code required", http.StatusForbidden)
+	case service.StatusThrottled:
+		http.Error(w, "too many invalid two-factor codes, try again later", http.StatusTooManyRequests)
+	case service.StatusError:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	default:
+		logger.Log.Sugar().Error("Unknown status ", status)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
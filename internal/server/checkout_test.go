@@ -0,0 +1,120 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/internal/service"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestIssueCheckoutCode(t *testing.T) {
+	mockService := NewMockService(t)
+	mockService.On("IssueCheckoutCode", mock.Anything, 1).
+		Return(models.CheckoutCode{Code: "12345678", ExpiresAt: time.Now().Add(5 * time.Minute)}, nil)
+	h := NewHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/checkout-code", nil)
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+	w := httptest.NewRecorder()
+
+	h.IssueCheckoutCode(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+
+	var code models.CheckoutCode
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&code))
+	require.Equal(t, "12345678", code.Code)
+}
+func TestRedeemCheckoutCode(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		mockSetup  func(serv *MockService)
+		code       int
+		retryAfter string
+	}{
+		{
+			name: "order attached",
+			body: `{"code": "12345678", "order": "12345678903"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("CheckCheckoutThrottle", mock.Anything, 3).Return(time.Duration(0), nil)
+				serv.On("RedeemCheckoutCode", mock.Anything, 3,
+					models.CheckoutRedeem{Code: "12345678", Order: "12345678903"}).Return(service.StatusOK)
+			},
+			code: http.StatusAccepted,
+		},
+		{
+			name: "points captured",
+			body: `{"code": "12345678", "order": "12345678903", "sum": 100}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("CheckCheckoutThrottle", mock.Anything, 3).Return(time.Duration(0), nil)
+				serv.On("RedeemCheckoutCode", mock.Anything, 3, mock.Anything).Return(service.StatusOK)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "insufficient funds",
+			body: `{"code": "12345678", "order": "12345678903", "sum": 100}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("CheckCheckoutThrottle", mock.Anything, 3).Return(time.Duration(0), nil)
+				serv.On("RedeemCheckoutCode", mock.Anything, 3, mock.Anything).Return(service.StatusConflict)
+			},
+			code: http.StatusPaymentRequired,
+		},
+		{
+			name: "wrong code",
+			body: `{"code": "00000000", "order": "12345678903"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("CheckCheckoutThrottle", mock.Anything, 3).Return(time.Duration(0), nil)
+				serv.On("RedeemCheckoutCode", mock.Anything, 3, mock.Anything).Return(service.StatusNotFound)
+			},
+			code: http.StatusNotFound,
+		},
+		{
+			name: "merchant locked out",
+			body: `{"code": "00000000", "order": "12345678903"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("CheckCheckoutThrottle", mock.Anything, 3).Return(90*time.Second, nil)
+			},
+			code:       http.StatusTooManyRequests,
+			retryAfter: "90",
+		},
+		{
+			name:      "missing code",
+			body:      `{"order": "12345678903"}`,
+			mockSetup: func(serv *MockService) {},
+			code:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockService(t)
+			tt.mockSetup(mockService)
+			h := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/merchant/checkout", strings.NewReader(tt.body))
+			req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 7, MerchantID: 3}))
+			w := httptest.NewRecorder()
+
+			h.RedeemCheckoutCode(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+			require.Equal(t, tt.code, resp.StatusCode)
+			require.Equal(t, tt.retryAfter, resp.Header.Get("Retry-After"))
+		})
+	}
+}
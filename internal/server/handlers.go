@@ -3,10 +3,14 @@ package server
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"io"
+	"math"
+	"mime"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/scoring-service/internal/auth"
 	"github.com/scoring-service/internal/service"
@@ -24,6 +28,63 @@ type Service interface {
 	GetUserBalance(ctx context.Context, id int) (models.Balance, error)
 	CreateOrder(ctx context.Context, userID int, orderNum string) service.CreateStatus
 	CreateWithdraw(ctx context.Context, userID int, withdraw models.Withdraw) service.CreateStatus
+	CreateTransfer(ctx context.Context, userID int, transfer models.TransferRequest) service.CreateStatus
+	GetUserTransfers(ctx context.Context, id int) ([]models.Transfer, error)
+	GetUserTier(ctx context.Context, userID int) (models.TierStatus, error)
+	ListCampaigns(ctx context.Context) ([]models.Campaign, error)
+	GetCampaign(ctx context.Context, id int) (models.Campaign, error)
+	CreateCampaign(ctx context.Context, c *models.Campaign) error
+	UpdateCampaign(ctx context.Context, c *models.Campaign) error
+	DeleteCampaign(ctx context.Context, id int) error
+	DryRunCampaign(ctx context.Context, id int, from, to time.Time) (models.CampaignDryRun, error)
+	GetUserReferrals(ctx context.Context, userID int) (models.ReferralSummary, error)
+	SearchUsers(ctx context.Context, query string) ([]models.AdminUser, error)
+	GetUserView(ctx context.Context, userID int) (models.AdminUserView, error)
+	AdjustBalance(ctx context.Context, adminID, userID int, adjustment models.Adjustment) error
+	SetUserBlocked(ctx context.Context, adminID, userID int, blocked bool, reason string) error
+	RepollOrder(ctx context.Context, adminID int, orderNum string) error
+	SetUserAccess(ctx context.Context, adminID, userID int, access models.UserAccess) error
+	StartSession(ctx context.Context, user *models.User, meta models.SessionMeta) (string, error)
+	RefreshSession(ctx context.Context, refreshToken string) (*models.User, string, error)
+	Logout(ctx context.Context, principal *auth.Principal) error
+	GetUserSessions(ctx context.Context, userID, currentSessionID int) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID int) error
+	RevokeOtherSessions(ctx context.Context, userID, keepSessionID int) error
+	CheckLoginThrottle(ctx context.Context, login, ip string) (time.Duration, error)
+	RecordLoginAttempt(ctx context.Context, login, ip string, authErr error) error
+	UnlockLogin(ctx context.Context, adminID, userID int) error
+	ChangePassword(ctx context.Context, principal *auth.Principal, change models.PasswordChange) error
+	RequestPasswordReset(ctx context.Context, login string) error
+	ResetPassword(ctx context.Context, reset models.PasswordReset) error
+	DeleteAccount(ctx context.Context, userID int, password string) error
+	EnrollTOTP(ctx context.Context, userID int) (models.TOTPEnrollment, error)
+	ConfirmTOTP(ctx context.Context, userID int, code string) ([]string, error)
+	DisableTOTP(ctx context.Context, userID int, code string) error
+	SetTwoFactorSettings(ctx context.Context, userID int, settings models.TwoFactorSettings) error
+	StartLoginChallenge(ctx context.Context, user *models.User) (models.LoginChallenge, error)
+	CompleteLoginChallenge(ctx context.Context, challengeToken, code, ip string) (*models.User, error)
+	CreateMerchant(ctx context.Context, adminID int, req models.MerchantCreate) (models.MerchantCredentials, error)
+	IssueMerchantAPIKey(ctx context.Context, adminID, merchantID int) (models.MerchantCredentials, error)
+	RevokeMerchantAPIKey(ctx context.Context, adminID, merchantID, keyID int) error
+	SubmitMerchantOrder(ctx context.Context, merchantID int, req models.MerchantOrder) service.CreateStatus
+	IssueCheckoutCode(ctx context.Context, userID int) (models.CheckoutCode, error)
+	IssueCustomerToken(ctx context.Context, userID int) (models.CustomerToken, error)
+	CheckCheckoutThrottle(ctx context.Context, merchantID int) (time.Duration, error)
+	RedeemCheckoutCode(ctx context.Context, merchantID int, req models.CheckoutRedeem) service.CreateStatus
+	CreateOrders(ctx context.Context, userID int, numbers []string) ([]models.BatchOrderResult, error)
+	VerifyAccrualCallback(ctx context.Context, req auth.SignedRequest, signature string) error
+	ApplyAccruals(ctx context.Context, signature string, accruals []models.AccrualResponse) error
+	CreateWebhookSubscription(ctx context.Context, merchantID int, req models.WebhookSubscription) (models.WebhookSubscription, error)
+	GetWebhookSubscriptions(ctx context.Context, merchantID int) ([]models.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, merchantID, subscriptionID int) error
+	GetWebhookDeliveries(ctx context.Context, merchantID int, status string) ([]models.WebhookDelivery, error)
+	RedeliverWebhook(ctx context.Context, merchantID, deliveryID int) error
+	GetNotificationPreferences(ctx context.Context, userID int) (models.NotificationPreferences, error)
+	ConfirmNotificationEmail(ctx context.Context, userID int, token string) error
+	UpdateNotificationPreferences(ctx context.Context, userID int, prefs models.NotificationPreferences) (models.NotificationPreferences, error)
+	StreamStatement(ctx context.Context, userID int, from, to time.Time, fn func(models.StatementEntry) error) error
+	GetBalanceAt(ctx context.Context, userID int, at time.Time) (models.Balance, error)
+	GetLastReconciliation(ctx context.Context) (models.ReconciliationRun, error)
 }
 
 type Handler struct {
@@ -33,6 +94,28 @@ type Handler struct {
 func NewHandler(service Service) *Handler {
 	return &Handler{serv: service}
 }
+
+// requirePrincipal возвращает пользователя, которого AuthMiddleware положил в
+// контекст, и отвечает 401, если его там нет.
+func requirePrincipal(w http.ResponseWriter, r *http.Request) (*auth.Principal, bool) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}
+	return principal, ok
+}
+
+// requireMerchant то же, что requirePrincipal, но пропускает только запросы
+// от имени мерчанта.
+func requireMerchant(w http.ResponseWriter, r *http.Request) (*auth.Principal, bool) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok || principal.MerchantID == 0 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+	return principal, true
+}
+
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	var newUser models.User
 	if err := json.NewDecoder(r.Body).Decode(&newUser); err != nil {
@@ -54,18 +137,32 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.serv.ReagisterUser(r.Context(), &newUser); err != nil {
+		if errors.Is(err, models.ErrInvalidReferralCode) {
+			http.Error(w, "Неверный реферальный код", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, models.ErrWeakPassword) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, models.ErrReservedLogin) {
+			http.Error(w, "Недопустимый логин", http.StatusBadRequest)
+			return
+		}
 		http.Error(w, "Ошибка регистрации клиента", http.StatusInternalServerError)
 		return
 	}
 
-	token, err := auth.GenerateJWT(&newUser)
+	refreshToken, err := h.serv.StartSession(r.Context(), &newUser, sessionMeta(r))
 	if err != nil {
+		http.Error(w, "Ошибка при создании сессии", http.StatusInternalServerError)
+		return
+	}
+	if _, err := writeTokens(w, &newUser, refreshToken); err != nil {
 		http.Error(w, "Ошибка при генерации токена", http.StatusInternalServerError)
 		return
 	}
 
-	w.Header().Set("Authorization", fmt.Sprintf("Bearer %s", token))
-
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("Пользователь успешно зарегистрирован и аутентифицирован"))
 }
@@ -79,30 +176,60 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Невалидный логин или пароль", http.StatusBadRequest)
 		return
 	}
-	if err := h.serv.AuthorizeUser(r.Context(), &req); err != nil {
+	meta := sessionMeta(r)
+	wait, err := h.serv.CheckLoginThrottle(r.Context(), req.Login, meta.IP)
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if wait > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		http.Error(w, "Слишком много попыток входа, повторите позже", http.StatusTooManyRequests)
+		return
+	}
+	authErr := h.serv.AuthorizeUser(r.Context(), &req)
+	if err := h.serv.RecordLoginAttempt(r.Context(), req.Login, meta.IP, authErr); err != nil {
+		logger.Log.Error(err.Error())
+	}
+	if authErr != nil {
+		if errors.Is(authErr, models.ErrUserBlocked) {
+			http.Error(w, "Учётная запись заблокирована", http.StatusForbidden)
+			return
+		}
 		http.Error(w, "Неверная пара логин/пароль", http.StatusUnauthorized)
 		return
 	}
+	challenge, err := h.serv.StartLoginChallenge(r.Context(), &req)
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if challenge.ChallengeToken != "" {
+		writeJSON(w, http.StatusAccepted, challenge)
+		return
+	}
 
-	token, err := auth.GenerateJWT(&req)
+	refreshToken, err := h.serv.StartSession(r.Context(), &req, meta)
 	if err != nil {
+		http.Error(w, "Ошибка при создании сессии", http.StatusInternalServerError)
+		return
+	}
+	if _, err := writeTokens(w, &req, refreshToken); err != nil {
 		http.Error(w, "Ошибка при генерации токена", http.StatusInternalServerError)
 		return
 	}
-	w.Header().Set("Authorization", fmt.Sprintf("Bearer %s", token))
 
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("Пользователь успешно аутентифицирован"))
 }
 func (h *Handler) GetUserOrders(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	userID, ok := ctx.Value(auth.UserIDKey).(int)
+	principal, ok := requirePrincipal(w, r)
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
 		return
 	}
 
-	orders, err := h.serv.GetUserOrders(ctx, userID)
+	orders, err := h.serv.GetUserOrders(ctx, principal.UserID)
 	if err != nil {
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
@@ -120,13 +247,12 @@ func (h *Handler) GetUserOrders(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) GetUserWithdrawals(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	userID, ok := ctx.Value(auth.UserIDKey).(int)
+	principal, ok := requirePrincipal(w, r)
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
 		return
 	}
 
-	withdrawals, err := h.serv.GetUserWithdrawals(ctx, userID)
+	withdrawals, err := h.serv.GetUserWithdrawals(ctx, principal.UserID)
 	if err != nil {
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
@@ -144,13 +270,16 @@ func (h *Handler) GetUserWithdrawals(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) GetUserBalance(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	userID, ok := ctx.Value(auth.UserIDKey).(int)
+	principal, ok := requirePrincipal(w, r)
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if at := r.URL.Query().Get("at"); at != "" {
+		h.getBalanceAt(w, r, principal.UserID, at)
 		return
 	}
 
-	balance, err := h.serv.GetUserBalance(ctx, userID)
+	balance, err := h.serv.GetUserBalance(ctx, principal.UserID)
 	if err != nil {
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
@@ -161,10 +290,8 @@ func (h *Handler) GetUserBalance(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(balance)
 }
 func (h *Handler) PostOrder(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	userID, ok := ctx.Value(auth.UserIDKey).(int)
+	principal, ok := requirePrincipal(w, r)
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
 		return
 	}
 	body, err := io.ReadAll(r.Body)
@@ -172,9 +299,17 @@ func (h *Handler) PostOrder(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "invalid request body", http.StatusBadRequest)
 		return
 	}
-	orderNum := strings.TrimSpace(string(body))
 
-	status := h.serv.CreateOrder(r.Context(), userID, orderNum)
+	orderNum := string(body)
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/json" {
+		var order models.OrderSubmission
+		if err := json.Unmarshal(body, &order); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		orderNum = order.Order
+	}
+	status := h.serv.CreateOrder(r.Context(), principal.UserID, strings.TrimSpace(orderNum))
 
 	switch status {
 	case service.StatusOK:
@@ -200,9 +335,8 @@ func (h *Handler) PostOrder(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) Withdraw(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	userID, ok := ctx.Value(auth.UserIDKey).(int)
+	principal, ok := requirePrincipal(w, r)
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
 		return
 	}
 
@@ -216,7 +350,7 @@ func (h *Handler) Withdraw(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	status := h.serv.CreateWithdraw(ctx, userID, req)
+	status := h.serv.CreateWithdraw(ctx, principal.UserID, req)
 	switch status {
 	case service.StatusOK:
 		w.WriteHeader(http.StatusOK)
@@ -228,6 +362,12 @@ func (h *Handler) Withdraw(w http.ResponseWriter, r *http.Request) {
 	case service.StatusInvalid:
 		http.Error(w, "invalid order number format", http.StatusUnprocessableEntity)
 		return
+	case service.StatusTOTPRequired:
+		http.Error(w, "two-factor code required", http.StatusForbidden)
+		return
+	case service.StatusThrottled:
+		http.Error(w, "too many invalid two-factor codes, try again later", http.StatusTooManyRequests)
+		return
 	case service.StatusError:
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
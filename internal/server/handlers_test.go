@@ -1,13 +1,14 @@
 package server
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -17,6 +18,14 @@ import (
 	"github.com/scoring-service/pkg/models"
 )
 
+// testSecretKey подписывает токены, которые выдают обработчики в тестах.
+const testSecretKey = "test-secret"
+
+func TestMain(m *testing.M) {
+	auth.SetSecretKey(testSecretKey)
+	os.Exit(m.Run())
+}
+
 func TestRegister(t *testing.T) {
 	type want struct {
 		code       int
@@ -35,6 +44,7 @@ func TestRegister(t *testing.T) {
 			mockSetup: func(serv *MockService) {
 				serv.On("UserExist", mock.Anything, "test").Return(false, nil)
 				serv.On("ReagisterUser", mock.Anything, mock.Anything).Return(nil)
+				serv.On("StartSession", mock.Anything, mock.Anything, mock.Anything).Return("refresh", nil)
 			},
 			want: want{code: http.StatusOK, authHeader: true},
 		},
@@ -52,6 +62,27 @@ func TestRegister(t *testing.T) {
 			mockSetup: func(serv *MockService) {},
 			want:      want{code: http.StatusBadRequest},
 		},
+		{
+			name: "unknown referral code",
+			body: `{"login": "test", "password": "12345", "referral_code": "NOPE"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("UserExist", mock.Anything, "test").Return(false, nil)
+				serv.On("ReagisterUser", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+					return u.InvitedWith == "NOPE"
+				})).Return(models.ErrInvalidReferralCode)
+			},
+			want: want{code: http.StatusBadRequest},
+		},
+		{
+			name: "weak password",
+			body: `{"login": "test", "password": "qwerty"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("UserExist", mock.Anything, "test").Return(false, nil)
+				serv.On("ReagisterUser", mock.Anything, mock.Anything).
+					Return(fmt.Errorf("%w: пароль входит в список распространённых", models.ErrWeakPassword))
+			},
+			want: want{code: http.StatusBadRequest},
+		},
 	}
 
 	for _, tt := range tests {
@@ -72,6 +103,7 @@ func TestRegister(t *testing.T) {
 			require.Equal(t, tt.want.code, resp.StatusCode)
 			if tt.want.authHeader {
 				require.NotEmpty(t, resp.Header.Get("Authorization"))
+				require.Empty(t, resp.Header.Get(refreshTokenHeader))
 			}
 		})
 	}
@@ -80,6 +112,7 @@ func TestLogin(t *testing.T) {
 	type want struct {
 		code       int
 		authHeader bool
+		retryAfter string
 	}
 	tests := []struct {
 		name      string
@@ -91,18 +124,54 @@ func TestLogin(t *testing.T) {
 			name: "valid login",
 			body: `{"login": "test", "password": "12345"}`,
 			mockSetup: func(serv *MockService) {
+				serv.On("CheckLoginThrottle", mock.Anything, "test", "192.0.2.1").Return(time.Duration(0), nil)
 				serv.On("AuthorizeUser", mock.Anything, mock.Anything).Return(nil)
+				serv.On("RecordLoginAttempt", mock.Anything, "test", "192.0.2.1", nil).Return(nil)
+				serv.On("StartLoginChallenge", mock.Anything, mock.Anything).Return(models.LoginChallenge{}, nil)
+				serv.On("StartSession", mock.Anything, mock.Anything, mock.Anything).Return("refresh", nil)
 			},
 			want: want{code: http.StatusOK, authHeader: true},
 		},
+		{
+			name: "two-factor challenge",
+			body: `{"login": "test", "password": "12345"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("CheckLoginThrottle", mock.Anything, "test", "192.0.2.1").Return(time.Duration(0), nil)
+				serv.On("AuthorizeUser", mock.Anything, mock.Anything).Return(nil)
+				serv.On("RecordLoginAttempt", mock.Anything, "test", "192.0.2.1", nil).Return(nil)
+				serv.On("StartLoginChallenge", mock.Anything, mock.Anything).
+					Return(models.LoginChallenge{ChallengeToken: "challenge", ExpiresIn: 300}, nil)
+			},
+			want: want{code: http.StatusAccepted},
+		},
+		{
+			name: "blocked account",
+			body: `{"login": "test", "password": "12345"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("CheckLoginThrottle", mock.Anything, "test", "192.0.2.1").Return(time.Duration(0), nil)
+				serv.On("AuthorizeUser", mock.Anything, mock.Anything).Return(models.ErrUserBlocked)
+				serv.On("RecordLoginAttempt", mock.Anything, "test", "192.0.2.1", models.ErrUserBlocked).Return(nil)
+			},
+			want: want{code: http.StatusForbidden},
+		},
 		{
 			name: "invalid login",
 			body: `{"login": "test", "password": "wrong"}`,
 			mockSetup: func(serv *MockService) {
+				serv.On("CheckLoginThrottle", mock.Anything, "test", "192.0.2.1").Return(time.Duration(0), nil)
 				serv.On("AuthorizeUser", mock.Anything, mock.Anything).Return(errors.New("bad credentials"))
+				serv.On("RecordLoginAttempt", mock.Anything, "test", "192.0.2.1", mock.Anything).Return(nil)
 			},
 			want: want{code: http.StatusUnauthorized},
 		},
+		{
+			name: "too many attempts",
+			body: `{"login": "test", "password": "wrong"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("CheckLoginThrottle", mock.Anything, "test", "192.0.2.1").Return(1500*time.Millisecond, nil)
+			},
+			want: want{code: http.StatusTooManyRequests, retryAfter: "2"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -120,8 +189,11 @@ func TestLogin(t *testing.T) {
 			defer resp.Body.Close()
 
 			require.Equal(t, tt.want.code, resp.StatusCode)
+			require.Equal(t, tt.want.retryAfter, resp.Header.Get("Retry-After"))
 			if tt.want.authHeader {
 				require.NotEmpty(t, resp.Header.Get("Authorization"))
+				require.Empty(t, resp.Header.Get(refreshTokenHeader))
+				require.NotEmpty(t, resp.Cookies())
 			}
 		})
 	}
@@ -165,7 +237,7 @@ func TestPostOrder(t *testing.T) {
 			h := NewHandler(mockService)
 
 			req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(tt.body))
-			req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, tt.userID))
+			req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: tt.userID}))
 			w := httptest.NewRecorder()
 
 			h.PostOrder(w, req)
@@ -177,6 +249,28 @@ func TestPostOrder(t *testing.T) {
 		})
 	}
 }
+
+// TestPostOrderJSON проверяет, что позиции чека от покупателя
+// игнорируются: цены в них задавал бы сам покупатель.
+func TestPostOrderJSON(t *testing.T) {
+	mockService := NewMockService(t)
+	mockService.On("CreateOrder", mock.Anything, 42, "12345678903").Return(service.StatusOK)
+	h := NewHandler(mockService)
+
+	for body, code := range map[string]int{
+		`{"order": "12345678903"}`: http.StatusAccepted,
+		`{"order": "12345678903", "items": [{"name": "Молоко", "category": "dairy", "price": 1000000, "quantity": 2}]}`: http.StatusAccepted,
+		`{"order": `: http.StatusBadRequest,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 42}))
+		w := httptest.NewRecorder()
+
+		h.PostOrder(w, req)
+		require.Equal(t, code, w.Code, body)
+	}
+}
 func TestWithdraw(t *testing.T) {
 	type want struct {
 		code int
@@ -280,7 +374,7 @@ func TestWithdraw(t *testing.T) {
 
 			req := httptest.NewRequest(http.MethodPost, "/api/user/balance/withdraw", strings.NewReader(tt.body))
 			if tt.userID != nil {
-				req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, tt.userID))
+				req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: tt.userID.(int)}))
 			}
 			w := httptest.NewRecorder()
 
@@ -347,7 +441,7 @@ func TestGetUserWithdrawals(t *testing.T) {
 
 			req := httptest.NewRequest(http.MethodGet, "/api/user/withdrawals", nil)
 			if tt.userID != nil {
-				req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, tt.userID))
+				req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: tt.userID.(int)}))
 			}
 			w := httptest.NewRecorder()
 
@@ -367,6 +461,7 @@ func TestGetUserBalance(t *testing.T) {
 	tests := []struct {
 		name      string
 		userID    any
+		query     string
 		mockSetup func(serv *MockService)
 		want      want
 	}{
@@ -392,6 +487,32 @@ func TestGetUserBalance(t *testing.T) {
 			},
 			want: want{code: http.StatusInternalServerError},
 		},
+		{
+			name:   "balance at the end of a day",
+			userID: 1,
+			query:  "?at=2025-03-31",
+			mockSetup: func(serv *MockService) {
+				serv.On("GetBalanceAt", mock.Anything, 1, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)).
+					Return(models.Balance{Current: 600, Withdrawn: 100}, nil)
+			},
+			want: want{code: http.StatusOK},
+		},
+		{
+			name:      "invalid at",
+			userID:    1,
+			query:     "?at=31.03.2025",
+			mockSetup: func(serv *MockService) {},
+			want:      want{code: http.StatusBadRequest},
+		},
+		{
+			name:   "balance in the future",
+			userID: 1,
+			query:  "?at=2999-01-01",
+			mockSetup: func(serv *MockService) {
+				serv.On("GetBalanceAt", mock.Anything, 1, mock.Anything).Return(models.Balance{}, models.ErrInvalidPeriod)
+			},
+			want: want{code: http.StatusBadRequest},
+		},
 	}
 
 	for _, tt := range tests {
@@ -401,9 +522,9 @@ func TestGetUserBalance(t *testing.T) {
 
 			h := NewHandler(mockService)
 
-			req := httptest.NewRequest(http.MethodGet, "/api/user/balance", nil)
+			req := httptest.NewRequest(http.MethodGet, "/api/user/balance"+tt.query, nil)
 			if tt.userID != nil {
-				req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, tt.userID))
+				req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: tt.userID.(int)}))
 			}
 			w := httptest.NewRecorder()
 
@@ -0,0 +1,13 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/scoring-service/internal/auth"
+)
+
+// JWKS публикует открытые ключи, которыми другие сервисы проверяют наши токены.
+func (h *Handler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	writeJSON(w, http.StatusOK, auth.JWKS())
+}
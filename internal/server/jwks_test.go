@@ -0,0 +1,37 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+)
+
+func TestJWKS(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	auth.SetKeyring(auth.NewKeyring(auth.NewEd25519Key("ed-1", key)))
+	t.Cleanup(func() { auth.SetSecretKey(testSecretKey) })
+
+	h := NewHandler(NewMockService(t))
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+
+	h.JWKS(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var set auth.JWKSet
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&set))
+	require.Len(t, set.Keys, 1)
+	require.Equal(t, "ed-1", set.Keys[0].Kid)
+}
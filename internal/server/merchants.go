@@ -0,0 +1,128 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+
+	"github.com/scoring-service/internal/service"
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+func (h *Handler) SubmitMerchantOrder(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requireMerchant(w, r)
+	if !ok {
+		return
+	}
+	var req models.MerchantOrder
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+	req.Order = strings.TrimSpace(req.Order)
+	if (req.CustomerLogin == "") == (req.CustomerToken == "") {
+		http.Error(w, "exactly one of customer_login and customer_token is required", http.StatusBadRequest)
+		return
+	}
+	if !validItems(req.Items) {
+		http.Error(w, "invalid items", http.StatusBadRequest)
+		return
+	}
+
+	switch status := h.serv.SubmitMerchantOrder(r.Context(), principal.MerchantID, req); status {
+	case service.StatusOK:
+		w.WriteHeader(http.StatusAccepted)
+	case service.StatusAlreadyExist:
+		w.WriteHeader(http.StatusOK)
+	case service.StatusNotFound:
+		http.Error(w, "customer not found", http.StatusNotFound)
+	case service.StatusConflict:
+		http.Error(w, "order already exists for another user", http.StatusConflict)
+	case service.StatusInvalid:
+		http.Error(w, "invalid order number format", http.StatusUnprocessableEntity)
+	case service.StatusError:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	default:
+		logger.Log.Sugar().Error("Unknown status ", status)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) CreateMerchant(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	var req models.MerchantCreate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+	creds, err := h.serv.CreateMerchant(r.Context(), principal.UserID, req)
+	if err != nil {
+		adminError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, creds)
+}
+
+func (h *Handler) IssueMerchantAPIKey(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	merchantID, ok := merchantID(w, r)
+	if !ok {
+		return
+	}
+	creds, err := h.serv.IssueMerchantAPIKey(r.Context(), principal.UserID, merchantID)
+	if err != nil {
+		adminError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, creds)
+}
+
+func (h *Handler) RevokeMerchantAPIKey(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	merchantID, ok := merchantID(w, r)
+	if !ok {
+		return
+	}
+	keyID, err := strconv.Atoi(chi.URLParam(r, "keyID"))
+	if err != nil || keyID <= 0 {
+		http.Error(w, "invalid key id", http.StatusBadRequest)
+		return
+	}
+	if err := h.serv.RevokeMerchantAPIKey(r.Context(), principal.UserID, merchantID, keyID); err != nil {
+		adminError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func merchantID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		http.Error(w, "invalid merchant id", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// validItems отсекает позиции с отрицательной ценой или количеством.
+func validItems(items []models.OrderItem) bool {
+	for _, item := range items {
+		if item.Price < 0 || item.Quantity < 0 {
+			return false
+		}
+	}
+	return true
+}
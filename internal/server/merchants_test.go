@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/internal/service"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestSubmitMerchantOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		mockSetup func(serv *MockService)
+		code      int
+	}{
+		{
+			name: "new order",
+			body: `{"order": "12345678903", "customer_login": "alice"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("SubmitMerchantOrder", mock.Anything, 3,
+					models.MerchantOrder{Order: "12345678903", CustomerLogin: "alice"}).Return(service.StatusOK)
+			},
+			code: http.StatusAccepted,
+		},
+		{
+			name: "already uploaded",
+			body: `{"order": "12345678903", "customer_token": "token"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("SubmitMerchantOrder", mock.Anything, 3, mock.Anything).Return(service.StatusAlreadyExist)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "unknown customer",
+			body: `{"order": "12345678903", "customer_login": "bob"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("SubmitMerchantOrder", mock.Anything, 3, mock.Anything).Return(service.StatusNotFound)
+			},
+			code: http.StatusNotFound,
+		},
+		{
+			name: "invalid number",
+			body: `{"order": "123", "customer_login": "alice"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("SubmitMerchantOrder", mock.Anything, 3, mock.Anything).Return(service.StatusInvalid)
+			},
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name:      "no customer",
+			body:      `{"order": "12345678903"}`,
+			mockSetup: func(serv *MockService) {},
+			code:      http.StatusBadRequest,
+		},
+		{
+			name: "with items",
+			body: `{"order": "12345678903", "customer_login": "alice", "items": [{"name": "Молоко", "price": 80, "quantity": 2}]}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("SubmitMerchantOrder", mock.Anything, 3, models.MerchantOrder{
+					Order:         "12345678903",
+					CustomerLogin: "alice",
+					Items:         []models.OrderItem{{Name: "Молоко", Price: 80, Quantity: 2}},
+				}).Return(service.StatusOK)
+			},
+			code: http.StatusAccepted,
+		},
+		{
+			name:      "negative price",
+			body:      `{"order": "12345678903", "customer_login": "alice", "items": [{"name": "Молоко", "price": -1}]}`,
+			mockSetup: func(serv *MockService) {},
+			code:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockService(t)
+			tt.mockSetup(mockService)
+			h := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/merchant/orders", strings.NewReader(tt.body))
+			req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 7, MerchantID: 3}))
+			w := httptest.NewRecorder()
+
+			h.SubmitMerchantOrder(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+			require.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
+func TestCreateMerchant(t *testing.T) {
+	mockService := NewMockService(t)
+	mockService.On("CreateMerchant", mock.Anything, 1, models.MerchantCreate{Name: "Shop", Login: "shop", RateLimit: 60}).
+		Return(models.MerchantCredentials{MerchantID: 3, KeyID: 11, APIKey: "gmk_key"}, nil)
+	mockService.On("CreateMerchant", mock.Anything, 1, models.MerchantCreate{Name: "Shop", Login: "taken"}).
+		Return(models.MerchantCredentials{}, models.ErrLoginTaken)
+	h := NewHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/merchants",
+		strings.NewReader(`{"name": "Shop", "login": "shop", "rate_limit": 60}`))
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+	w := httptest.NewRecorder()
+	h.CreateMerchant(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var creds models.MerchantCredentials
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&creds))
+	require.Equal(t, "gmk_key", creds.APIKey)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/admin/merchants",
+		strings.NewReader(`{"name": "Shop", "login": "taken"}`))
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+	w = httptest.NewRecorder()
+	h.CreateMerchant(w, req)
+	require.Equal(t, http.StatusConflict, w.Code)
+}
+func TestRevokeMerchantAPIKey(t *testing.T) {
+	mockService := NewMockService(t)
+	mockService.On("RevokeMerchantAPIKey", mock.Anything, 1, 3, 11).Return(nil)
+	mockService.On("RevokeMerchantAPIKey", mock.Anything, 1, 3, 12).Return(models.ErrInvalidAPIKey)
+	h := NewHandler(mockService)
+
+	for keyID, code := range map[string]int{"11": http.StatusNoContent, "12": http.StatusNotFound, "x": http.StatusBadRequest} {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "3")
+		rctx.URLParams.Add("keyID", keyID)
+		req := httptest.NewRequest(http.MethodDelete, "/api/admin/merchants/3/keys/"+keyID, nil)
+		ctx := auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1})
+		req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+
+		h.RevokeMerchantAPIKey(w, req)
+		require.Equal(t, code, w.Code, keyID)
+	}
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func (h *Handler) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	prefs, err := h.serv.GetNotificationPreferences(r.Context(), principal.UserID)
+	if err != nil {
+		notificationsError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, prefs)
+}
+
+// UpdateNotificationPreferences заменяет настройки уведомлений целиком;
+// не указанные в запросе виды уведомлений остаются включёнными.
+func (h *Handler) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	req := models.NotificationPreferences{PointsCredited: true, PointsWithdrawn: true}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+	prefs, err := h.serv.UpdateNotificationPreferences(r.Context(), principal.UserID, req)
+	if err != nil {
+		notificationsError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, prefs)
+}
+
+// ConfirmNotificationEmail подтверждает новый адрес токеном из письма; после
+// этого уведомления уходят на него.
+func (h *Handler) ConfirmNotificationEmail(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	var req models.EmailConfirmation
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+	if err := h.serv.ConfirmNotificationEmail(r.Context(), principal.UserID, req.Token); err != nil {
+		notificationsError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func notificationsError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidPreferences):
+		http.Error(w, "invalid email or locale", http.StatusBadRequest)
+	case errors.Is(err, models.ErrInvalidEmailToken):
+		http.Error(w, "invalid or expired token", http.StatusBadRequest)
+	case errors.Is(err, models.ErrUserNotFound):
+		http.Error(w, "user not found", http.StatusNotFound)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestGetNotificationPreferences(t *testing.T) {
+	mockService := NewMockService(t)
+	mockService.On("GetNotificationPreferences", mock.Anything, 1).Return(models.NotificationPreferences{
+		Login: "alice", Email: "alice@example.com", Locale: models.LocaleEN, PointsCredited: true,
+	}, nil)
+	h := NewHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user/notifications", nil)
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+	w := httptest.NewRecorder()
+	h.GetNotificationPreferences(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"email":"alice@example.com","locale":"en","points_credited":true,"points_withdrawn":false}`, w.Body.String())
+}
+
+func TestUpdateNotificationPreferences(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want models.NotificationPreferences
+		err  error
+		code int
+	}{
+		{
+			name: "omitted kinds stay enabled",
+			body: `{"email":"alice@example.com","locale":"en"}`,
+			want: models.NotificationPreferences{Email: "alice@example.com", Locale: models.LocaleEN, PointsCredited: true, PointsWithdrawn: true},
+			code: http.StatusOK,
+		},
+		{
+			name: "invalid email",
+			body: `{"email":"alice","points_withdrawn":false}`,
+			want: models.NotificationPreferences{Email: "alice", PointsCredited: true},
+			err:  models.ErrInvalidPreferences,
+			code: http.StatusBadRequest,
+		},
+		{name: "malformed body", body: `{`, code: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockService(t)
+			if tt.code != http.StatusBadRequest || tt.err != nil {
+				mockService.On("UpdateNotificationPreferences", mock.Anything, 1, tt.want).Return(tt.want, tt.err)
+			}
+			h := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/user/notifications", strings.NewReader(tt.body))
+			req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+			w := httptest.NewRecorder()
+			h.UpdateNotificationPreferences(w, req)
+
+			require.Equal(t, tt.code, w.Code)
+			if tt.code == http.StatusOK {
+				var prefs models.NotificationPreferences
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&prefs))
+				require.Equal(t, tt.want, prefs)
+			}
+		})
+	}
+}
+
+func TestConfirmNotificationEmail(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+	}{
+		{name: "confirmed", body: `{"token":"token"}`, code: http.StatusNoContent},
+		{name: "stale token", body: `{"token":"token"}`, err: models.ErrInvalidEmailToken, code: http.StatusBadRequest},
+		{name: "malformed body", body: `{`, code: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockService(t)
+			if tt.body != `{` {
+				mockService.On("ConfirmNotificationEmail", mock.Anything, 1, "token").Return(tt.err)
+			}
+			h := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/user/notifications/email/confirm", strings.NewReader(tt.body))
+			req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+			w := httptest.NewRecorder()
+			h.ConfirmNotificationEmail(w, req)
+
+			require.Equal(t, tt.code, w.Code)
+		})
+	}
+}
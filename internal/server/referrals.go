@@ -0,0 +1,22 @@
+package server
+
+import (
+	"net/http"
+)
+
+func (h *Handler) GetUserReferrals(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+
+	referrals, err := h.serv.GetUserReferrals(ctx, principal.UserID)
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, referrals)
+}
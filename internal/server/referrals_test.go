@@ -0,0 +1,68 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestGetUserReferrals(t *testing.T) {
+	t.Run("referrals found", func(t *testing.T) {
+		mockService := NewMockService(t)
+		summary := models.ReferralSummary{
+			Code:   "ABCD2345",
+			Earned: 100,
+			Invitees: []models.Referral{
+				{Login: "bob", Status: models.ReferralRewarded, Bonus: 100, RegisteredAt: time.Date(2025, 5, 3, 0, 0, 0, 0, time.UTC)},
+			},
+		}
+		mockService.On("GetUserReferrals", mock.Anything, 1).Return(summary, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/user/referrals", nil)
+		req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+		w := httptest.NewRecorder()
+
+		NewHandler(mockService).GetUserReferrals(w, req)
+
+		res := w.Result()
+		defer res.Body.Close()
+
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var got models.ReferralSummary
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		require.Equal(t, summary, got)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		mockService := NewMockService(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/user/referrals", nil)
+		w := httptest.NewRecorder()
+
+		NewHandler(mockService).GetUserReferrals(w, req)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockService := NewMockService(t)
+		mockService.On("GetUserReferrals", mock.Anything, 1).Return(models.ReferralSummary{}, errors.New("db error"))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/user/referrals", nil)
+		req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+		w := httptest.NewRecorder()
+
+		NewHandler(mockService).GetUserReferrals(w, req)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
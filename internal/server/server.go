@@ -6,6 +6,7 @@ import (
 	"github.com/go-chi/chi"
 
 	"github.com/scoring-service/internal/middleware"
+	"github.com/scoring-service/pkg/models"
 )
 
 func Init(address string, service Service) error {
@@ -15,17 +16,89 @@ func Init(address string, service Service) error {
 	r.Group(func(r chi.Router) {
 		r.Post("/api/user/register", h.Register)
 		r.Post("/api/user/login", h.Login)
+		r.Post("/api/user/login/2fa", h.CompleteLogin)
+		r.Post("/api/user/token/refresh", h.RefreshToken)
+		r.Post("/api/user/password/reset", h.RequestPasswordReset)
+		r.Post("/api/user/password/reset/confirm", h.ResetPassword)
+		r.Get("/.well-known/jwks.json", h.JWKS)
+		r.Post("/api/internal/accrual-callback", h.AccrualCallback)
 	})
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.AuthMiddleware)
 		r.Use(middleware.GzipMiddleware)
 		r.Get("/api/user/orders", h.GetUserOrders)
 		r.Post("/api/user/orders", h.PostOrder)
+		r.Post("/api/user/orders/batch", h.PostOrdersBatch)
 		r.Get("/api/user/withdrawals", h.GetUserWithdrawals)
 		r.Get("/api/user/balance", h.GetUserBalance)
+		r.Get("/api/user/statement", h.GetUserStatement)
 		r.Post("/api/user/balance/withdraw", h.Withdraw)
+		r.Post("/api/user/balance/transfer", h.Transfer)
+		r.Get("/api/user/transfers", h.GetUserTransfers)
+		r.Get("/api/user/tier", h.GetUserTier)
+		r.Get("/api/user/referrals", h.GetUserReferrals)
+		r.Post("/api/user/logout", h.Logout)
+		r.Get("/api/user/sessions", h.GetUserSessions)
+		r.Delete("/api/user/sessions", h.DeleteUserSessions)
+		r.Delete("/api/user/sessions/{id}", h.DeleteUserSession)
+		r.Post("/api/user/password", h.ChangePassword)
+		r.Delete("/api/user", h.DeleteAccount)
+		r.Post("/api/user/2fa/enroll", h.EnrollTOTP)
+		r.Post("/api/user/2fa/confirm", h.ConfirmTOTP)
+		r.Put("/api/user/2fa/settings", h.UpdateTwoFactorSettings)
+		r.Delete("/api/user/2fa", h.DisableTOTP)
+		r.Post("/api/user/checkout-code", h.IssueCheckoutCode)
+		r.Post("/api/user/customer-token", h.IssueCustomerToken)
+		r.Get("/api/user/notifications", h.GetNotificationPreferences)
+		r.Put("/api/user/notifications", h.UpdateNotificationPreferences)
+		r.Post("/api/user/notifications/email/confirm", h.ConfirmNotificationEmail)
 
 	})
+	r.Route("/api/admin", func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+		r.Use(middleware.GzipMiddleware)
+		r.With(middleware.RequirePermission(models.PermUsersRead)).Get("/users", h.SearchUsers)
+		r.With(middleware.RequirePermission(models.PermUsersRead)).Get("/users/{id}", h.GetUserView)
+		r.With(middleware.RequirePermission(models.PermBalanceAdjust)).Post("/users/{id}/adjustments", h.AdjustBalance)
+		r.With(middleware.RequirePermission(models.PermUsersBlock)).Post("/users/{id}/block", h.BlockUser)
+		r.With(middleware.RequirePermission(models.PermUsersBlock)).Post("/users/{id}/unblock", h.UnblockUser)
+		r.With(middleware.RequirePermission(models.PermUsersBlock)).Post("/users/{id}/unlock", h.UnlockLogin)
+		r.With(middleware.RequirePermission(models.PermUsersManage)).Put("/users/{id}/access", h.SetUserAccess)
+		r.With(middleware.RequirePermission(models.PermOrdersRepoll)).Post("/orders/{number}/repoll", h.RepollOrder)
+		r.With(middleware.RequirePermission(models.PermReconciliationRead)).Get("/reconciliation", h.GetLastReconciliation)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequirePermission(models.PermCampaignsManage))
+			r.Get("/campaigns", h.ListCampaigns)
+			r.Post("/campaigns", h.CreateCampaign)
+			r.Get("/campaigns/{id}", h.GetCampaign)
+			r.Put("/campaigns/{id}", h.UpdateCampaign)
+			r.Delete("/campaigns/{id}", h.DeleteCampaign)
+			r.Post("/campaigns/{id}/dry-run", h.DryRunCampaign)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequirePermission(models.PermMerchantsManage))
+			r.Post("/merchants", h.CreateMerchant)
+			r.Post("/merchants/{id}/keys", h.IssueMerchantAPIKey)
+			r.Delete("/merchants/{id}/keys/{keyID}", h.RevokeMerchantAPIKey)
+		})
+	})
+
+	r.Route("/api/merchant", func(r chi.Router) {
+		r.Use(middleware.MerchantAuthMiddleware)
+		r.Use(middleware.GzipMiddleware)
+		r.With(middleware.RequirePermission(models.PermOrdersSubmit)).Post("/orders", h.SubmitMerchantOrder)
+		r.With(middleware.RequirePermission(models.PermOrdersSubmit)).Post("/checkout", h.RedeemCheckoutCode)
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Use(middleware.RequirePermission(models.PermWebhooksManage))
+			r.Post("/", h.CreateWebhookSubscription)
+			r.Get("/", h.GetWebhookSubscriptions)
+			r.Delete("/{id}", h.DeleteWebhookSubscription)
+			r.Get("/deliveries", h.GetWebhookDeliveries)
+			r.Post("/deliveries/{id}/redeliver", h.RedeliverWebhook)
+		})
+	})
 
 	return http.ListenAndServe(address, r)
 }
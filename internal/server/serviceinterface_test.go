@@ -4,11 +4,12 @@ package server
 
 import (
 	context "context"
+	time "time"
 
+	auth "github.com/scoring-service/internal/auth"
+	service "github.com/scoring-service/internal/service"
 	models "github.com/scoring-service/pkg/models"
 	mock "github.com/stretchr/testify/mock"
-
-	service "github.com/scoring-service/internal/service"
 )
 
 // MockService is an autogenerated mock type for the Service type
@@ -24,6 +25,103 @@ func (_m *MockService) EXPECT() *MockService_Expecter {
 	return &MockService_Expecter{mock: &_m.Mock}
 }
 
+// AdjustBalance provides a mock function with given fields: ctx, adminID, userID, adjustment
+func (_m *MockService) AdjustBalance(ctx context.Context, adminID int, userID int, adjustment models.Adjustment) error {
+	ret := _m.Called(ctx, adminID, userID, adjustment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdjustBalance")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, models.Adjustment) error); ok {
+		r0 = rf(ctx, adminID, userID, adjustment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_AdjustBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdjustBalance'
+type MockService_AdjustBalance_Call struct {
+	*mock.Call
+}
+
+// AdjustBalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - adminID int
+//   - userID int
+//   - adjustment models.Adjustment
+func (_e *MockService_Expecter) AdjustBalance(ctx interface{}, adminID interface{}, userID interface{}, adjustment interface{}) *MockService_AdjustBalance_Call {
+	return &MockService_AdjustBalance_Call{Call: _e.mock.On("AdjustBalance", ctx, adminID, userID, adjustment)}
+}
+
+func (_c *MockService_AdjustBalance_Call) Run(run func(ctx context.Context, adminID int, userID int, adjustment models.Adjustment)) *MockService_AdjustBalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(models.Adjustment))
+	})
+	return _c
+}
+
+func (_c *MockService_AdjustBalance_Call) Return(_a0 error) *MockService_AdjustBalance_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_AdjustBalance_Call) RunAndReturn(run func(context.Context, int, int, models.Adjustment) error) *MockService_AdjustBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ApplyAccruals provides a mock function with given fields: ctx, signature, accruals
+func (_m *MockService) ApplyAccruals(ctx context.Context, signature string, accruals []models.AccrualResponse) error {
+	ret := _m.Called(ctx, signature, accruals)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyAccruals")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []models.AccrualResponse) error); ok {
+		r0 = rf(ctx, signature, accruals)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_ApplyAccruals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApplyAccruals'
+type MockService_ApplyAccruals_Call struct {
+	*mock.Call
+}
+
+// ApplyAccruals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - signature string
+//   - accruals []models.AccrualResponse
+func (_e *MockService_Expecter) ApplyAccruals(ctx interface{}, signature interface{}, accruals interface{}) *MockService_ApplyAccruals_Call {
+	return &MockService_ApplyAccruals_Call{Call: _e.mock.On("ApplyAccruals", ctx, signature, accruals)}
+}
+
+func (_c *MockService_ApplyAccruals_Call) Run(run func(ctx context.Context, signature string, accruals []models.AccrualResponse)) *MockService_ApplyAccruals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]models.AccrualResponse))
+	})
+	return _c
+}
+
+func (_c *MockService_ApplyAccruals_Call) Return(_a0 error) *MockService_ApplyAccruals_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_ApplyAccruals_Call) RunAndReturn(run func(context.Context, string, []models.AccrualResponse) error) *MockService_ApplyAccruals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // AuthorizeUser provides a mock function with given fields: ctx, user
 func (_m *MockService) AuthorizeUser(ctx context.Context, user *models.User) error {
 	ret := _m.Called(ctx, user)
@@ -71,123 +169,132 @@ func (_c *MockService_AuthorizeUser_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
-// CreateOrder provides a mock function with given fields: ctx, userID, orderNum
-func (_m *MockService) CreateOrder(ctx context.Context, userID int, orderNum string) service.CreateStatus {
-	ret := _m.Called(ctx, userID, orderNum)
+// ChangePassword provides a mock function with given fields: ctx, principal, change
+func (_m *MockService) ChangePassword(ctx context.Context, principal *auth.Principal, change models.PasswordChange) error {
+	ret := _m.Called(ctx, principal, change)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateOrder")
+		panic("no return value specified for ChangePassword")
 	}
 
-	var r0 service.CreateStatus
-	if rf, ok := ret.Get(0).(func(context.Context, int, string) service.CreateStatus); ok {
-		r0 = rf(ctx, userID, orderNum)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *auth.Principal, models.PasswordChange) error); ok {
+		r0 = rf(ctx, principal, change)
 	} else {
-		r0 = ret.Get(0).(service.CreateStatus)
+		r0 = ret.Error(0)
 	}
 
 	return r0
 }
 
-// MockService_CreateOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateOrder'
-type MockService_CreateOrder_Call struct {
+// MockService_ChangePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangePassword'
+type MockService_ChangePassword_Call struct {
 	*mock.Call
 }
 
-// CreateOrder is a helper method to define mock.On call
+// ChangePassword is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID int
-//   - orderNum string
-func (_e *MockService_Expecter) CreateOrder(ctx interface{}, userID interface{}, orderNum interface{}) *MockService_CreateOrder_Call {
-	return &MockService_CreateOrder_Call{Call: _e.mock.On("CreateOrder", ctx, userID, orderNum)}
+//   - principal *auth.Principal
+//   - change models.PasswordChange
+func (_e *MockService_Expecter) ChangePassword(ctx interface{}, principal interface{}, change interface{}) *MockService_ChangePassword_Call {
+	return &MockService_ChangePassword_Call{Call: _e.mock.On("ChangePassword", ctx, principal, change)}
 }
 
-func (_c *MockService_CreateOrder_Call) Run(run func(ctx context.Context, userID int, orderNum string)) *MockService_CreateOrder_Call {
+func (_c *MockService_ChangePassword_Call) Run(run func(ctx context.Context, principal *auth.Principal, change models.PasswordChange)) *MockService_ChangePassword_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(string))
+		run(args[0].(context.Context), args[1].(*auth.Principal), args[2].(models.PasswordChange))
 	})
 	return _c
 }
 
-func (_c *MockService_CreateOrder_Call) Return(_a0 service.CreateStatus) *MockService_CreateOrder_Call {
+func (_c *MockService_ChangePassword_Call) Return(_a0 error) *MockService_ChangePassword_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockService_CreateOrder_Call) RunAndReturn(run func(context.Context, int, string) service.CreateStatus) *MockService_CreateOrder_Call {
+func (_c *MockService_ChangePassword_Call) RunAndReturn(run func(context.Context, *auth.Principal, models.PasswordChange) error) *MockService_ChangePassword_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateWithdraw provides a mock function with given fields: ctx, userID, withdraw
-func (_m *MockService) CreateWithdraw(ctx context.Context, userID int, withdraw models.Withdraw) service.CreateStatus {
-	ret := _m.Called(ctx, userID, withdraw)
+// CheckCheckoutThrottle provides a mock function with given fields: ctx, merchantID
+func (_m *MockService) CheckCheckoutThrottle(ctx context.Context, merchantID int) (time.Duration, error) {
+	ret := _m.Called(ctx, merchantID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateWithdraw")
+		panic("no return value specified for CheckCheckoutThrottle")
 	}
 
-	var r0 service.CreateStatus
-	if rf, ok := ret.Get(0).(func(context.Context, int, models.Withdraw) service.CreateStatus); ok {
-		r0 = rf(ctx, userID, withdraw)
+	var r0 time.Duration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (time.Duration, error)); ok {
+		return rf(ctx, merchantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) time.Duration); ok {
+		r0 = rf(ctx, merchantID)
 	} else {
-		r0 = ret.Get(0).(service.CreateStatus)
+		r0 = ret.Get(0).(time.Duration)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, merchantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// MockService_CreateWithdraw_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateWithdraw'
-type MockService_CreateWithdraw_Call struct {
+// MockService_CheckCheckoutThrottle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckCheckoutThrottle'
+type MockService_CheckCheckoutThrottle_Call struct {
 	*mock.Call
 }
 
-// CreateWithdraw is a helper method to define mock.On call
+// CheckCheckoutThrottle is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID int
-//   - withdraw models.Withdraw
-func (_e *MockService_Expecter) CreateWithdraw(ctx interface{}, userID interface{}, withdraw interface{}) *MockService_CreateWithdraw_Call {
-	return &MockService_CreateWithdraw_Call{Call: _e.mock.On("CreateWithdraw", ctx, userID, withdraw)}
+//   - merchantID int
+func (_e *MockService_Expecter) CheckCheckoutThrottle(ctx interface{}, merchantID interface{}) *MockService_CheckCheckoutThrottle_Call {
+	return &MockService_CheckCheckoutThrottle_Call{Call: _e.mock.On("CheckCheckoutThrottle", ctx, merchantID)}
 }
 
-func (_c *MockService_CreateWithdraw_Call) Run(run func(ctx context.Context, userID int, withdraw models.Withdraw)) *MockService_CreateWithdraw_Call {
+func (_c *MockService_CheckCheckoutThrottle_Call) Run(run func(ctx context.Context, merchantID int)) *MockService_CheckCheckoutThrottle_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(models.Withdraw))
+		run(args[0].(context.Context), args[1].(int))
 	})
 	return _c
 }
 
-func (_c *MockService_CreateWithdraw_Call) Return(_a0 service.CreateStatus) *MockService_CreateWithdraw_Call {
-	_c.Call.Return(_a0)
+func (_c *MockService_CheckCheckoutThrottle_Call) Return(_a0 time.Duration, _a1 error) *MockService_CheckCheckoutThrottle_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockService_CreateWithdraw_Call) RunAndReturn(run func(context.Context, int, models.Withdraw) service.CreateStatus) *MockService_CreateWithdraw_Call {
+func (_c *MockService_CheckCheckoutThrottle_Call) RunAndReturn(run func(context.Context, int) (time.Duration, error)) *MockService_CheckCheckoutThrottle_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserBalance provides a mock function with given fields: ctx, id
-func (_m *MockService) GetUserBalance(ctx context.Context, id int) (models.Balance, error) {
-	ret := _m.Called(ctx, id)
+// CheckLoginThrottle provides a mock function with given fields: ctx, login, ip
+func (_m *MockService) CheckLoginThrottle(ctx context.Context, login string, ip string) (time.Duration, error) {
+	ret := _m.Called(ctx, login, ip)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserBalance")
+		panic("no return value specified for CheckLoginThrottle")
 	}
 
-	var r0 models.Balance
+	var r0 time.Duration
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) (models.Balance, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (time.Duration, error)); ok {
+		return rf(ctx, login, ip)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) models.Balance); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) time.Duration); ok {
+		r0 = rf(ctx, login, ip)
 	} else {
-		r0 = ret.Get(0).(models.Balance)
+		r0 = ret.Get(0).(time.Duration)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, login, ip)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -195,58 +302,59 @@ func (_m *MockService) GetUserBalance(ctx context.Context, id int) (models.Balan
 	return r0, r1
 }
 
-// MockService_GetUserBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserBalance'
-type MockService_GetUserBalance_Call struct {
+// MockService_CheckLoginThrottle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckLoginThrottle'
+type MockService_CheckLoginThrottle_Call struct {
 	*mock.Call
 }
 
-// GetUserBalance is a helper method to define mock.On call
+// CheckLoginThrottle is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id int
-func (_e *MockService_Expecter) GetUserBalance(ctx interface{}, id interface{}) *MockService_GetUserBalance_Call {
-	return &MockService_GetUserBalance_Call{Call: _e.mock.On("GetUserBalance", ctx, id)}
+//   - login string
+//   - ip string
+func (_e *MockService_Expecter) CheckLoginThrottle(ctx interface{}, login interface{}, ip interface{}) *MockService_CheckLoginThrottle_Call {
+	return &MockService_CheckLoginThrottle_Call{Call: _e.mock.On("CheckLoginThrottle", ctx, login, ip)}
 }
 
-func (_c *MockService_GetUserBalance_Call) Run(run func(ctx context.Context, id int)) *MockService_GetUserBalance_Call {
+func (_c *MockService_CheckLoginThrottle_Call) Run(run func(ctx context.Context, login string, ip string)) *MockService_CheckLoginThrottle_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int))
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockService_GetUserBalance_Call) Return(_a0 models.Balance, _a1 error) *MockService_GetUserBalance_Call {
+func (_c *MockService_CheckLoginThrottle_Call) Return(_a0 time.Duration, _a1 error) *MockService_CheckLoginThrottle_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockService_GetUserBalance_Call) RunAndReturn(run func(context.Context, int) (models.Balance, error)) *MockService_GetUserBalance_Call {
+func (_c *MockService_CheckLoginThrottle_Call) RunAndReturn(run func(context.Context, string, string) (time.Duration, error)) *MockService_CheckLoginThrottle_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserOrders provides a mock function with given fields: ctx, id
-func (_m *MockService) GetUserOrders(ctx context.Context, id int) ([]models.Order, error) {
-	ret := _m.Called(ctx, id)
+// CompleteLoginChallenge provides a mock function with given fields: ctx, challengeToken, code, ip
+func (_m *MockService) CompleteLoginChallenge(ctx context.Context, challengeToken string, code string, ip string) (*models.User, error) {
+	ret := _m.Called(ctx, challengeToken, code, ip)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserOrders")
+		panic("no return value specified for CompleteLoginChallenge")
 	}
 
-	var r0 []models.Order
+	var r0 *models.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.Order, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*models.User, error)); ok {
+		return rf(ctx, challengeToken, code, ip)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) []models.Order); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *models.User); ok {
+		r0 = rf(ctx, challengeToken, code, ip)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Order)
+			r0 = ret.Get(0).(*models.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, challengeToken, code, ip)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -254,58 +362,108 @@ func (_m *MockService) GetUserOrders(ctx context.Context, id int) ([]models.Orde
 	return r0, r1
 }
 
-// MockService_GetUserOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserOrders'
-type MockService_GetUserOrders_Call struct {
+// MockService_CompleteLoginChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompleteLoginChallenge'
+type MockService_CompleteLoginChallenge_Call struct {
 	*mock.Call
 }
 
-// GetUserOrders is a helper method to define mock.On call
+// CompleteLoginChallenge is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id int
-func (_e *MockService_Expecter) GetUserOrders(ctx interface{}, id interface{}) *MockService_GetUserOrders_Call {
-	return &MockService_GetUserOrders_Call{Call: _e.mock.On("GetUserOrders", ctx, id)}
+//   - challengeToken string
+//   - code string
+//   - ip string
+func (_e *MockService_Expecter) CompleteLoginChallenge(ctx interface{}, challengeToken interface{}, code interface{}, ip interface{}) *MockService_CompleteLoginChallenge_Call {
+	return &MockService_CompleteLoginChallenge_Call{Call: _e.mock.On("CompleteLoginChallenge", ctx, challengeToken, code, ip)}
 }
 
-func (_c *MockService_GetUserOrders_Call) Run(run func(ctx context.Context, id int)) *MockService_GetUserOrders_Call {
+func (_c *MockService_CompleteLoginChallenge_Call) Run(run func(ctx context.Context, challengeToken string, code string, ip string)) *MockService_CompleteLoginChallenge_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
 	})
 	return _c
 }
 
-func (_c *MockService_GetUserOrders_Call) Return(_a0 []models.Order, _a1 error) *MockService_GetUserOrders_Call {
+func (_c *MockService_CompleteLoginChallenge_Call) Return(_a0 *models.User, _a1 error) *MockService_CompleteLoginChallenge_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockService_GetUserOrders_Call) RunAndReturn(run func(context.Context, int) ([]models.Order, error)) *MockService_GetUserOrders_Call {
+func (_c *MockService_CompleteLoginChallenge_Call) RunAndReturn(run func(context.Context, string, string, string) (*models.User, error)) *MockService_CompleteLoginChallenge_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserWithdrawals provides a mock function with given fields: ctx, id
-func (_m *MockService) GetUserWithdrawals(ctx context.Context, id int) ([]models.Withdrawal, error) {
-	ret := _m.Called(ctx, id)
+// ConfirmNotificationEmail provides a mock function with given fields: ctx, userID, token
+func (_m *MockService) ConfirmNotificationEmail(ctx context.Context, userID int, token string) error {
+	ret := _m.Called(ctx, userID, token)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserWithdrawals")
+		panic("no return value specified for ConfirmNotificationEmail")
 	}
 
-	var r0 []models.Withdrawal
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, userID, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_ConfirmNotificationEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConfirmNotificationEmail'
+type MockService_ConfirmNotificationEmail_Call struct {
+	*mock.Call
+}
+
+// ConfirmNotificationEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - token string
+func (_e *MockService_Expecter) ConfirmNotificationEmail(ctx interface{}, userID interface{}, token interface{}) *MockService_ConfirmNotificationEmail_Call {
+	return &MockService_ConfirmNotificationEmail_Call{Call: _e.mock.On("ConfirmNotificationEmail", ctx, userID, token)}
+}
+
+func (_c *MockService_ConfirmNotificationEmail_Call) Run(run func(ctx context.Context, userID int, token string)) *MockService_ConfirmNotificationEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockService_ConfirmNotificationEmail_Call) Return(_a0 error) *MockService_ConfirmNotificationEmail_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_ConfirmNotificationEmail_Call) RunAndReturn(run func(context.Context, int, string) error) *MockService_ConfirmNotificationEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ConfirmTOTP provides a mock function with given fields: ctx, userID, code
+func (_m *MockService) ConfirmTOTP(ctx context.Context, userID int, code string) ([]string, error) {
+	ret := _m.Called(ctx, userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmTOTP")
+	}
+
+	var r0 []string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.Withdrawal, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) ([]string, error)); ok {
+		return rf(ctx, userID, code)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) []models.Withdrawal); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) []string); ok {
+		r0 = rf(ctx, userID, code)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Withdrawal)
+			r0 = ret.Get(0).([]string)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, userID, code)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -313,46 +471,47 @@ func (_m *MockService) GetUserWithdrawals(ctx context.Context, id int) ([]models
 	return r0, r1
 }
 
-// MockService_GetUserWithdrawals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserWithdrawals'
-type MockService_GetUserWithdrawals_Call struct {
+// MockService_ConfirmTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConfirmTOTP'
+type MockService_ConfirmTOTP_Call struct {
 	*mock.Call
 }
 
-// GetUserWithdrawals is a helper method to define mock.On call
+// ConfirmTOTP is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id int
-func (_e *MockService_Expecter) GetUserWithdrawals(ctx interface{}, id interface{}) *MockService_GetUserWithdrawals_Call {
-	return &MockService_GetUserWithdrawals_Call{Call: _e.mock.On("GetUserWithdrawals", ctx, id)}
+//   - userID int
+//   - code string
+func (_e *MockService_Expecter) ConfirmTOTP(ctx interface{}, userID interface{}, code interface{}) *MockService_ConfirmTOTP_Call {
+	return &MockService_ConfirmTOTP_Call{Call: _e.mock.On("ConfirmTOTP", ctx, userID, code)}
 }
 
-func (_c *MockService_GetUserWithdrawals_Call) Run(run func(ctx context.Context, id int)) *MockService_GetUserWithdrawals_Call {
+func (_c *MockService_ConfirmTOTP_Call) Run(run func(ctx context.Context, userID int, code string)) *MockService_ConfirmTOTP_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int))
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockService_GetUserWithdrawals_Call) Return(_a0 []models.Withdrawal, _a1 error) *MockService_GetUserWithdrawals_Call {
+func (_c *MockService_ConfirmTOTP_Call) Return(_a0 []string, _a1 error) *MockService_ConfirmTOTP_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockService_GetUserWithdrawals_Call) RunAndReturn(run func(context.Context, int) ([]models.Withdrawal, error)) *MockService_GetUserWithdrawals_Call {
+func (_c *MockService_ConfirmTOTP_Call) RunAndReturn(run func(context.Context, int, string) ([]string, error)) *MockService_ConfirmTOTP_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ReagisterUser provides a mock function with given fields: ctx, user
-func (_m *MockService) ReagisterUser(ctx context.Context, user *models.User) error {
-	ret := _m.Called(ctx, user)
+// CreateCampaign provides a mock function with given fields: ctx, c
+func (_m *MockService) CreateCampaign(ctx context.Context, c *models.Campaign) error {
+	ret := _m.Called(ctx, c)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ReagisterUser")
+		panic("no return value specified for CreateCampaign")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
-		r0 = rf(ctx, user)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Campaign) error); ok {
+		r0 = rf(ctx, c)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -360,56 +519,56 @@ func (_m *MockService) ReagisterUser(ctx context.Context, user *models.User) err
 	return r0
 }
 
-// MockService_ReagisterUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReagisterUser'
-type MockService_ReagisterUser_Call struct {
+// MockService_CreateCampaign_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateCampaign'
+type MockService_CreateCampaign_Call struct {
 	*mock.Call
 }
 
-// ReagisterUser is a helper method to define mock.On call
+// CreateCampaign is a helper method to define mock.On call
 //   - ctx context.Context
-//   - user *models.User
-func (_e *MockService_Expecter) ReagisterUser(ctx interface{}, user interface{}) *MockService_ReagisterUser_Call {
-	return &MockService_ReagisterUser_Call{Call: _e.mock.On("ReagisterUser", ctx, user)}
+//   - c *models.Campaign
+func (_e *MockService_Expecter) CreateCampaign(ctx interface{}, c interface{}) *MockService_CreateCampaign_Call {
+	return &MockService_CreateCampaign_Call{Call: _e.mock.On("CreateCampaign", ctx, c)}
 }
 
-func (_c *MockService_ReagisterUser_Call) Run(run func(ctx context.Context, user *models.User)) *MockService_ReagisterUser_Call {
+func (_c *MockService_CreateCampaign_Call) Run(run func(ctx context.Context, c *models.Campaign)) *MockService_CreateCampaign_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*models.User))
+		run(args[0].(context.Context), args[1].(*models.Campaign))
 	})
 	return _c
 }
 
-func (_c *MockService_ReagisterUser_Call) Return(_a0 error) *MockService_ReagisterUser_Call {
+func (_c *MockService_CreateCampaign_Call) Return(_a0 error) *MockService_CreateCampaign_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockService_ReagisterUser_Call) RunAndReturn(run func(context.Context, *models.User) error) *MockService_ReagisterUser_Call {
+func (_c *MockService_CreateCampaign_Call) RunAndReturn(run func(context.Context, *models.Campaign) error) *MockService_CreateCampaign_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UserExist provides a mock function with given fields: ctx, login
-func (_m *MockService) UserExist(ctx context.Context, login string) (bool, error) {
-	ret := _m.Called(ctx, login)
+// CreateMerchant provides a mock function with given fields: ctx, adminID, req
+func (_m *MockService) CreateMerchant(ctx context.Context, adminID int, req models.MerchantCreate) (models.MerchantCredentials, error) {
+	ret := _m.Called(ctx, adminID, req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UserExist")
+		panic("no return value specified for CreateMerchant")
 	}
 
-	var r0 bool
+	var r0 models.MerchantCredentials
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
-		return rf(ctx, login)
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.MerchantCreate) (models.MerchantCredentials, error)); ok {
+		return rf(ctx, adminID, req)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
-		r0 = rf(ctx, login)
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.MerchantCreate) models.MerchantCredentials); ok {
+		r0 = rf(ctx, adminID, req)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Get(0).(models.MerchantCredentials)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, login)
+	if rf, ok := ret.Get(1).(func(context.Context, int, models.MerchantCreate) error); ok {
+		r1 = rf(ctx, adminID, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -417,21 +576,2854 @@ func (_m *MockService) UserExist(ctx context.Context, login string) (bool, error
 	return r0, r1
 }
 
-// MockService_UserExist_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UserExist'
-type MockService_UserExist_Call struct {
+// MockService_CreateMerchant_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateMerchant'
+type MockService_CreateMerchant_Call struct {
 	*mock.Call
 }
 
-// UserExist is a helper method to define mock.On call
+// CreateMerchant is a helper method to define mock.On call
 //   - ctx context.Context
-//   - login string
-func (_e *MockService_Expecter) UserExist(ctx interface{}, login interface{}) *MockService_UserExist_Call {
-	return &MockService_UserExist_Call{Call: _e.mock.On("UserExist", ctx, login)}
+//   - adminID int
+//   - req models.MerchantCreate
+func (_e *MockService_Expecter) CreateMerchant(ctx interface{}, adminID interface{}, req interface{}) *MockService_CreateMerchant_Call {
+	return &MockService_CreateMerchant_Call{Call: _e.mock.On("CreateMerchant", ctx, adminID, req)}
 }
 
-func (_c *MockService_UserExist_Call) Run(run func(ctx context.Context, login string)) *MockService_UserExist_Call {
+func (_c *MockService_CreateMerchant_Call) Run(run func(ctx context.Context, adminID int, req models.MerchantCreate)) *MockService_CreateMerchant_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(int), args[2].(models.MerchantCreate))
+	})
+	return _c
+}
+
+func (_c *MockService_CreateMerchant_Call) Return(_a0 models.MerchantCredentials, _a1 error) *MockService_CreateMerchant_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_CreateMerchant_Call) RunAndReturn(run func(context.Context, int, models.MerchantCreate) (models.MerchantCredentials, error)) *MockService_CreateMerchant_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateOrder provides a mock function with given fields: ctx, userID, orderNum
+func (_m *MockService) CreateOrder(ctx context.Context, userID int, orderNum string) service.CreateStatus {
+	ret := _m.Called(ctx, userID, orderNum)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOrder")
+	}
+
+	var r0 service.CreateStatus
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) service.CreateStatus); ok {
+		r0 = rf(ctx, userID, orderNum)
+	} else {
+		r0 = ret.Get(0).(service.CreateStatus)
+	}
+
+	return r0
+}
+
+// MockService_CreateOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateOrder'
+type MockService_CreateOrder_Call struct {
+	*mock.Call
+}
+
+// CreateOrder is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - orderNum string
+func (_e *MockService_Expecter) CreateOrder(ctx interface{}, userID interface{}, orderNum interface{}) *MockService_CreateOrder_Call {
+	return &MockService_CreateOrder_Call{Call: _e.mock.On("CreateOrder", ctx, userID, orderNum)}
+}
+
+func (_c *MockService_CreateOrder_Call) Run(run func(ctx context.Context, userID int, orderNum string)) *MockService_CreateOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockService_CreateOrder_Call) Return(_a0 service.CreateStatus) *MockService_CreateOrder_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_CreateOrder_Call) RunAndReturn(run func(context.Context, int, string) service.CreateStatus) *MockService_CreateOrder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateOrders provides a mock function with given fields: ctx, userID, numbers
+func (_m *MockService) CreateOrders(ctx context.Context, userID int, numbers []string) ([]models.BatchOrderResult, error) {
+	ret := _m.Called(ctx, userID, numbers)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOrders")
+	}
+
+	var r0 []models.BatchOrderResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []string) ([]models.BatchOrderResult, error)); ok {
+		return rf(ctx, userID, numbers)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, []string) []models.BatchOrderResult); ok {
+		r0 = rf(ctx, userID, numbers)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.BatchOrderResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, []string) error); ok {
+		r1 = rf(ctx, userID, numbers)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_CreateOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateOrders'
+type MockService_CreateOrders_Call struct {
+	*mock.Call
+}
+
+// CreateOrders is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - numbers []string
+func (_e *MockService_Expecter) CreateOrders(ctx interface{}, userID interface{}, numbers interface{}) *MockService_CreateOrders_Call {
+	return &MockService_CreateOrders_Call{Call: _e.mock.On("CreateOrders", ctx, userID, numbers)}
+}
+
+func (_c *MockService_CreateOrders_Call) Run(run func(ctx context.Context, userID int, numbers []string)) *MockService_CreateOrders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *MockService_CreateOrders_Call) Return(_a0 []models.BatchOrderResult, _a1 error) *MockService_CreateOrders_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_CreateOrders_Call) RunAndReturn(run func(context.Context, int, []string) ([]models.BatchOrderResult, error)) *MockService_CreateOrders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateTransfer provides a mock function with given fields: ctx, userID, transfer
+func (_m *MockService) CreateTransfer(ctx context.Context, userID int, transfer models.TransferRequest) service.CreateStatus {
+	ret := _m.Called(ctx, userID, transfer)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTransfer")
+	}
+
+	var r0 service.CreateStatus
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.TransferRequest) service.CreateStatus); ok {
+		r0 = rf(ctx, userID, transfer)
+	} else {
+		r0 = ret.Get(0).(service.CreateStatus)
+	}
+
+	return r0
+}
+
+// MockService_CreateTransfer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTransfer'
+type MockService_CreateTransfer_Call struct {
+	*mock.Call
+}
+
+// CreateTransfer is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - transfer models.TransferRequest
+func (_e *MockService_Expecter) CreateTransfer(ctx interface{}, userID interface{}, transfer interface{}) *MockService_CreateTransfer_Call {
+	return &MockService_CreateTransfer_Call{Call: _e.mock.On("CreateTransfer", ctx, userID, transfer)}
+}
+
+func (_c *MockService_CreateTransfer_Call) Run(run func(ctx context.Context, userID int, transfer models.TransferRequest)) *MockService_CreateTransfer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.TransferRequest))
+	})
+	return _c
+}
+
+func (_c *MockService_CreateTransfer_Call) Return(_a0 service.CreateStatus) *MockService_CreateTransfer_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_CreateTransfer_Call) RunAndReturn(run func(context.Context, int, models.TransferRequest) service.CreateStatus) *MockService_CreateTransfer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateWebhookSubscription provides a mock function with given fields: ctx, merchantID, req
+func (_m *MockService) CreateWebhookSubscription(ctx context.Context, merchantID int, req models.WebhookSubscription) (models.WebhookSubscription, error) {
+	ret := _m.Called(ctx, merchantID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWebhookSubscription")
+	}
+
+	var r0 models.WebhookSubscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.WebhookSubscription) (models.WebhookSubscription, error)); ok {
+		return rf(ctx, merchantID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.WebhookSubscription) models.WebhookSubscription); ok {
+		r0 = rf(ctx, merchantID, req)
+	} else {
+		r0 = ret.Get(0).(models.WebhookSubscription)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, models.WebhookSubscription) error); ok {
+		r1 = rf(ctx, merchantID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_CreateWebhookSubscription_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateWebhookSubscription'
+type MockService_CreateWebhookSubscription_Call struct {
+	*mock.Call
+}
+
+// CreateWebhookSubscription is a helper method to define mock.On call
+//   - ctx context.Context
+//   - merchantID int
+//   - req models.WebhookSubscription
+func (_e *MockService_Expecter) CreateWebhookSubscription(ctx interface{}, merchantID interface{}, req interface{}) *MockService_CreateWebhookSubscription_Call {
+	return &MockService_CreateWebhookSubscription_Call{Call: _e.mock.On("CreateWebhookSubscription", ctx, merchantID, req)}
+}
+
+func (_c *MockService_CreateWebhookSubscription_Call) Run(run func(ctx context.Context, merchantID int, req models.WebhookSubscription)) *MockService_CreateWebhookSubscription_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.WebhookSubscription))
+	})
+	return _c
+}
+
+func (_c *MockService_CreateWebhookSubscription_Call) Return(_a0 models.WebhookSubscription, _a1 error) *MockService_CreateWebhookSubscription_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_CreateWebhookSubscription_Call) RunAndReturn(run func(context.Context, int, models.WebhookSubscription) (models.WebhookSubscription, error)) *MockService_CreateWebhookSubscription_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateWithdraw provides a mock function with given fields: ctx, userID, withdraw
+func (_m *MockService) CreateWithdraw(ctx context.Context, userID int, withdraw models.Withdraw) service.CreateStatus {
+	ret := _m.Called(ctx, userID, withdraw)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWithdraw")
+	}
+
+	var r0 service.CreateStatus
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.Withdraw) service.CreateStatus); ok {
+		r0 = rf(ctx, userID, withdraw)
+	} else {
+		r0 = ret.Get(0).(service.CreateStatus)
+	}
+
+	return r0
+}
+
+// MockService_CreateWithdraw_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateWithdraw'
+type MockService_CreateWithdraw_Call struct {
+	*mock.Call
+}
+
+// CreateWithdraw is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - withdraw models.Withdraw
+func (_e *MockService_Expecter) CreateWithdraw(ctx interface{}, userID interface{}, withdraw interface{}) *MockService_CreateWithdraw_Call {
+	return &MockService_CreateWithdraw_Call{Call: _e.mock.On("CreateWithdraw", ctx, userID, withdraw)}
+}
+
+func (_c *MockService_CreateWithdraw_Call) Run(run func(ctx context.Context, userID int, withdraw models.Withdraw)) *MockService_CreateWithdraw_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.Withdraw))
+	})
+	return _c
+}
+
+func (_c *MockService_CreateWithdraw_Call) Return(_a0 service.CreateStatus) *MockService_CreateWithdraw_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_CreateWithdraw_Call) RunAndReturn(run func(context.Context, int, models.Withdraw) service.CreateStatus) *MockService_CreateWithdraw_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteAccount provides a mock function with given fields: ctx, userID, password
+func (_m *MockService) DeleteAccount(ctx context.Context, userID int, password string) error {
+	ret := _m.Called(ctx, userID, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAccount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, userID, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_DeleteAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAccount'
+type MockService_DeleteAccount_Call struct {
+	*mock.Call
+}
+
+// DeleteAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - password string
+func (_e *MockService_Expecter) DeleteAccount(ctx interface{}, userID interface{}, password interface{}) *MockService_DeleteAccount_Call {
+	return &MockService_DeleteAccount_Call{Call: _e.mock.On("DeleteAccount", ctx, userID, password)}
+}
+
+func (_c *MockService_DeleteAccount_Call) Run(run func(ctx context.Context, userID int, password string)) *MockService_DeleteAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockService_DeleteAccount_Call) Return(_a0 error) *MockService_DeleteAccount_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_DeleteAccount_Call) RunAndReturn(run func(context.Context, int, string) error) *MockService_DeleteAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteCampaign provides a mock function with given fields: ctx, id
+func (_m *MockService) DeleteCampaign(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCampaign")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_DeleteCampaign_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteCampaign'
+type MockService_DeleteCampaign_Call struct {
+	*mock.Call
+}
+
+// DeleteCampaign is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockService_Expecter) DeleteCampaign(ctx interface{}, id interface{}) *MockService_DeleteCampaign_Call {
+	return &MockService_DeleteCampaign_Call{Call: _e.mock.On("DeleteCampaign", ctx, id)}
+}
+
+func (_c *MockService_DeleteCampaign_Call) Run(run func(ctx context.Context, id int)) *MockService_DeleteCampaign_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_DeleteCampaign_Call) Return(_a0 error) *MockService_DeleteCampaign_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_DeleteCampaign_Call) RunAndReturn(run func(context.Context, int) error) *MockService_DeleteCampaign_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteWebhookSubscription provides a mock function with given fields: ctx, merchantID, subscriptionID
+func (_m *MockService) DeleteWebhookSubscription(ctx context.Context, merchantID int, subscriptionID int) error {
+	ret := _m.Called(ctx, merchantID, subscriptionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebhookSubscription")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, merchantID, subscriptionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_DeleteWebhookSubscription_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteWebhookSubscription'
+type MockService_DeleteWebhookSubscription_Call struct {
+	*mock.Call
+}
+
+// DeleteWebhookSubscription is a helper method to define mock.On call
+//   - ctx context.Context
+//   - merchantID int
+//   - subscriptionID int
+func (_e *MockService_Expecter) DeleteWebhookSubscription(ctx interface{}, merchantID interface{}, subscriptionID interface{}) *MockService_DeleteWebhookSubscription_Call {
+	return &MockService_DeleteWebhookSubscription_Call{Call: _e.mock.On("DeleteWebhookSubscription", ctx, merchantID, subscriptionID)}
+}
+
+func (_c *MockService_DeleteWebhookSubscription_Call) Run(run func(ctx context.Context, merchantID int, subscriptionID int)) *MockService_DeleteWebhookSubscription_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_DeleteWebhookSubscription_Call) Return(_a0 error) *MockService_DeleteWebhookSubscription_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_DeleteWebhookSubscription_Call) RunAndReturn(run func(context.Context, int, int) error) *MockService_DeleteWebhookSubscription_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DisableTOTP provides a mock function with given fields: ctx, userID, code
+func (_m *MockService) DisableTOTP(ctx context.Context, userID int, code string) error {
+	ret := _m.Called(ctx, userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableTOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, userID, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_DisableTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DisableTOTP'
+type MockService_DisableTOTP_Call struct {
+	*mock.Call
+}
+
+// DisableTOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - code string
+func (_e *MockService_Expecter) DisableTOTP(ctx interface{}, userID interface{}, code interface{}) *MockService_DisableTOTP_Call {
+	return &MockService_DisableTOTP_Call{Call: _e.mock.On("DisableTOTP", ctx, userID, code)}
+}
+
+func (_c *MockService_DisableTOTP_Call) Run(run func(ctx context.Context, userID int, code string)) *MockService_DisableTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockService_DisableTOTP_Call) Return(_a0 error) *MockService_DisableTOTP_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_DisableTOTP_Call) RunAndReturn(run func(context.Context, int, string) error) *MockService_DisableTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DryRunCampaign provides a mock function with given fields: ctx, id, from, to
+func (_m *MockService) DryRunCampaign(ctx context.Context, id int, from time.Time, to time.Time) (models.CampaignDryRun, error) {
+	ret := _m.Called(ctx, id, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DryRunCampaign")
+	}
+
+	var r0 models.CampaignDryRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, time.Time) (models.CampaignDryRun, error)); ok {
+		return rf(ctx, id, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, time.Time) models.CampaignDryRun); ok {
+		r0 = rf(ctx, id, from, to)
+	} else {
+		r0 = ret.Get(0).(models.CampaignDryRun)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, id, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_DryRunCampaign_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DryRunCampaign'
+type MockService_DryRunCampaign_Call struct {
+	*mock.Call
+}
+
+// DryRunCampaign is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - from time.Time
+//   - to time.Time
+func (_e *MockService_Expecter) DryRunCampaign(ctx interface{}, id interface{}, from interface{}, to interface{}) *MockService_DryRunCampaign_Call {
+	return &MockService_DryRunCampaign_Call{Call: _e.mock.On("DryRunCampaign", ctx, id, from, to)}
+}
+
+func (_c *MockService_DryRunCampaign_Call) Run(run func(ctx context.Context, id int, from time.Time, to time.Time)) *MockService_DryRunCampaign_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockService_DryRunCampaign_Call) Return(_a0 models.CampaignDryRun, _a1 error) *MockService_DryRunCampaign_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_DryRunCampaign_Call) RunAndReturn(run func(context.Context, int, time.Time, time.Time) (models.CampaignDryRun, error)) *MockService_DryRunCampaign_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EnrollTOTP provides a mock function with given fields: ctx, userID
+func (_m *MockService) EnrollTOTP(ctx context.Context, userID int) (models.TOTPEnrollment, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnrollTOTP")
+	}
+
+	var r0 models.TOTPEnrollment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.TOTPEnrollment, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.TOTPEnrollment); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(models.TOTPEnrollment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_EnrollTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnrollTOTP'
+type MockService_EnrollTOTP_Call struct {
+	*mock.Call
+}
+
+// EnrollTOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockService_Expecter) EnrollTOTP(ctx interface{}, userID interface{}) *MockService_EnrollTOTP_Call {
+	return &MockService_EnrollTOTP_Call{Call: _e.mock.On("EnrollTOTP", ctx, userID)}
+}
+
+func (_c *MockService_EnrollTOTP_Call) Run(run func(ctx context.Context, userID int)) *MockService_EnrollTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_EnrollTOTP_Call) Return(_a0 models.TOTPEnrollment, _a1 error) *MockService_EnrollTOTP_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_EnrollTOTP_Call) RunAndReturn(run func(context.Context, int) (models.TOTPEnrollment, error)) *MockService_EnrollTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBalanceAt provides a mock function with given fields: ctx, userID, at
+func (_m *MockService) GetBalanceAt(ctx context.Context, userID int, at time.Time) (models.Balance, error) {
+	ret := _m.Called(ctx, userID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalanceAt")
+	}
+
+	var r0 models.Balance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) (models.Balance, error)); ok {
+		return rf(ctx, userID, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) models.Balance); ok {
+		r0 = rf(ctx, userID, at)
+	} else {
+		r0 = ret.Get(0).(models.Balance)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time) error); ok {
+		r1 = rf(ctx, userID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_GetBalanceAt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBalanceAt'
+type MockService_GetBalanceAt_Call struct {
+	*mock.Call
+}
+
+// GetBalanceAt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - at time.Time
+func (_e *MockService_Expecter) GetBalanceAt(ctx interface{}, userID interface{}, at interface{}) *MockService_GetBalanceAt_Call {
+	return &MockService_GetBalanceAt_Call{Call: _e.mock.On("GetBalanceAt", ctx, userID, at)}
+}
+
+func (_c *MockService_GetBalanceAt_Call) Run(run func(ctx context.Context, userID int, at time.Time)) *MockService_GetBalanceAt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockService_GetBalanceAt_Call) Return(_a0 models.Balance, _a1 error) *MockService_GetBalanceAt_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_GetBalanceAt_Call) RunAndReturn(run func(context.Context, int, time.Time) (models.Balance, error)) *MockService_GetBalanceAt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCampaign provides a mock function with given fields: ctx, id
+func (_m *MockService) GetCampaign(ctx context.Context, id int) (models.Campaign, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCampaign")
+	}
+
+	var r0 models.Campaign
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.Campaign, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.Campaign); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Campaign)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_GetCampaign_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCampaign'
+type MockService_GetCampaign_Call struct {
+	*mock.Call
+}
+
+// GetCampaign is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockService_Expecter) GetCampaign(ctx interface{}, id interface{}) *MockService_GetCampaign_Call {
+	return &MockService_GetCampaign_Call{Call: _e.mock.On("GetCampaign", ctx, id)}
+}
+
+func (_c *MockService_GetCampaign_Call) Run(run func(ctx context.Context, id int)) *MockService_GetCampaign_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_GetCampaign_Call) Return(_a0 models.Campaign, _a1 error) *MockService_GetCampaign_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_GetCampaign_Call) RunAndReturn(run func(context.Context, int) (models.Campaign, error)) *MockService_GetCampaign_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLastReconciliation provides a mock function with given fields: ctx
+func (_m *MockService) GetLastReconciliation(ctx context.Context) (models.ReconciliationRun, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastReconciliation")
+	}
+
+	var r0 models.ReconciliationRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (models.ReconciliationRun, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) models.ReconciliationRun); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(models.ReconciliationRun)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_GetLastReconciliation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLastReconciliation'
+type MockService_GetLastReconciliation_Call struct {
+	*mock.Call
+}
+
+// GetLastReconciliation is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockService_Expecter) GetLastReconciliation(ctx interface{}) *MockService_GetLastReconciliation_Call {
+	return &MockService_GetLastReconciliation_Call{Call: _e.mock.On("GetLastReconciliation", ctx)}
+}
+
+func (_c *MockService_GetLastReconciliation_Call) Run(run func(ctx context.Context)) *MockService_GetLastReconciliation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockService_GetLastReconciliation_Call) Return(_a0 models.ReconciliationRun, _a1 error) *MockService_GetLastReconciliation_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_GetLastReconciliation_Call) RunAndReturn(run func(context.Context) (models.ReconciliationRun, error)) *MockService_GetLastReconciliation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetNotificationPreferences provides a mock function with given fields: ctx, userID
+func (_m *MockService) GetNotificationPreferences(ctx context.Context, userID int) (models.NotificationPreferences, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNotificationPreferences")
+	}
+
+	var r0 models.NotificationPreferences
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.NotificationPreferences, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.NotificationPreferences); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(models.NotificationPreferences)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_GetNotificationPreferences_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNotificationPreferences'
+type MockService_GetNotificationPreferences_Call struct {
+	*mock.Call
+}
+
+// GetNotificationPreferences is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockService_Expecter) GetNotificationPreferences(ctx interface{}, userID interface{}) *MockService_GetNotificationPreferences_Call {
+	return &MockService_GetNotificationPreferences_Call{Call: _e.mock.On("GetNotificationPreferences", ctx, userID)}
+}
+
+func (_c *MockService_GetNotificationPreferences_Call) Run(run func(ctx context.Context, userID int)) *MockService_GetNotificationPreferences_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_GetNotificationPreferences_Call) Return(_a0 models.NotificationPreferences, _a1 error) *MockService_GetNotificationPreferences_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_GetNotificationPreferences_Call) RunAndReturn(run func(context.Context, int) (models.NotificationPreferences, error)) *MockService_GetNotificationPreferences_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserBalance provides a mock function with given fields: ctx, id
+func (_m *MockService) GetUserBalance(ctx context.Context, id int) (models.Balance, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserBalance")
+	}
+
+	var r0 models.Balance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.Balance, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.Balance); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Balance)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_GetUserBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserBalance'
+type MockService_GetUserBalance_Call struct {
+	*mock.Call
+}
+
+// GetUserBalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockService_Expecter) GetUserBalance(ctx interface{}, id interface{}) *MockService_GetUserBalance_Call {
+	return &MockService_GetUserBalance_Call{Call: _e.mock.On("GetUserBalance", ctx, id)}
+}
+
+func (_c *MockService_GetUserBalance_Call) Run(run func(ctx context.Context, id int)) *MockService_GetUserBalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_GetUserBalance_Call) Return(_a0 models.Balance, _a1 error) *MockService_GetUserBalance_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_GetUserBalance_Call) RunAndReturn(run func(context.Context, int) (models.Balance, error)) *MockService_GetUserBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserOrders provides a mock function with given fields: ctx, id
+func (_m *MockService) GetUserOrders(ctx context.Context, id int) ([]models.Order, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserOrders")
+	}
+
+	var r0 []models.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.Order, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.Order); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_GetUserOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserOrders'
+type MockService_GetUserOrders_Call struct {
+	*mock.Call
+}
+
+// GetUserOrders is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockService_Expecter) GetUserOrders(ctx interface{}, id interface{}) *MockService_GetUserOrders_Call {
+	return &MockService_GetUserOrders_Call{Call: _e.mock.On("GetUserOrders", ctx, id)}
+}
+
+func (_c *MockService_GetUserOrders_Call) Run(run func(ctx context.Context, id int)) *MockService_GetUserOrders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_GetUserOrders_Call) Return(_a0 []models.Order, _a1 error) *MockService_GetUserOrders_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_GetUserOrders_Call) RunAndReturn(run func(context.Context, int) ([]models.Order, error)) *MockService_GetUserOrders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserReferrals provides a mock function with given fields: ctx, userID
+func (_m *MockService) GetUserReferrals(ctx context.Context, userID int) (models.ReferralSummary, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserReferrals")
+	}
+
+	var r0 models.ReferralSummary
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.ReferralSummary, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.ReferralSummary); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(models.ReferralSummary)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_GetUserReferrals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserReferrals'
+type MockService_GetUserReferrals_Call struct {
+	*mock.Call
+}
+
+// GetUserReferrals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockService_Expecter) GetUserReferrals(ctx interface{}, userID interface{}) *MockService_GetUserReferrals_Call {
+	return &MockService_GetUserReferrals_Call{Call: _e.mock.On("GetUserReferrals", ctx, userID)}
+}
+
+func (_c *MockService_GetUserReferrals_Call) Run(run func(ctx context.Context, userID int)) *MockService_GetUserReferrals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_GetUserReferrals_Call) Return(_a0 models.ReferralSummary, _a1 error) *MockService_GetUserReferrals_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_GetUserReferrals_Call) RunAndReturn(run func(context.Context, int) (models.ReferralSummary, error)) *MockService_GetUserReferrals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserSessions provides a mock function with given fields: ctx, userID, currentSessionID
+func (_m *MockService) GetUserSessions(ctx context.Context, userID int, currentSessionID int) ([]models.Session, error) {
+	ret := _m.Called(ctx, userID, currentSessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserSessions")
+	}
+
+	var r0 []models.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]models.Session, error)); ok {
+		return rf(ctx, userID, currentSessionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []models.Session); ok {
+		r0 = rf(ctx, userID, currentSessionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, userID, currentSessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_GetUserSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserSessions'
+type MockService_GetUserSessions_Call struct {
+	*mock.Call
+}
+
+// GetUserSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - currentSessionID int
+func (_e *MockService_Expecter) GetUserSessions(ctx interface{}, userID interface{}, currentSessionID interface{}) *MockService_GetUserSessions_Call {
+	return &MockService_GetUserSessions_Call{Call: _e.mock.On("GetUserSessions", ctx, userID, currentSessionID)}
+}
+
+func (_c *MockService_GetUserSessions_Call) Run(run func(ctx context.Context, userID int, currentSessionID int)) *MockService_GetUserSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_GetUserSessions_Call) Return(_a0 []models.Session, _a1 error) *MockService_GetUserSessions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_GetUserSessions_Call) RunAndReturn(run func(context.Context, int, int) ([]models.Session, error)) *MockService_GetUserSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserTier provides a mock function with given fields: ctx, userID
+func (_m *MockService) GetUserTier(ctx context.Context, userID int) (models.TierStatus, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserTier")
+	}
+
+	var r0 models.TierStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.TierStatus, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.TierStatus); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(models.TierStatus)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_GetUserTier_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserTier'
+type MockService_GetUserTier_Call struct {
+	*mock.Call
+}
+
+// GetUserTier is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockService_Expecter) GetUserTier(ctx interface{}, userID interface{}) *MockService_GetUserTier_Call {
+	return &MockService_GetUserTier_Call{Call: _e.mock.On("GetUserTier", ctx, userID)}
+}
+
+func (_c *MockService_GetUserTier_Call) Run(run func(ctx context.Context, userID int)) *MockService_GetUserTier_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_GetUserTier_Call) Return(_a0 models.TierStatus, _a1 error) *MockService_GetUserTier_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_GetUserTier_Call) RunAndReturn(run func(context.Context, int) (models.TierStatus, error)) *MockService_GetUserTier_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserTransfers provides a mock function with given fields: ctx, id
+func (_m *MockService) GetUserTransfers(ctx context.Context, id int) ([]models.Transfer, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserTransfers")
+	}
+
+	var r0 []models.Transfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.Transfer, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.Transfer); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Transfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_GetUserTransfers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserTransfers'
+type MockService_GetUserTransfers_Call struct {
+	*mock.Call
+}
+
+// GetUserTransfers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockService_Expecter) GetUserTransfers(ctx interface{}, id interface{}) *MockService_GetUserTransfers_Call {
+	return &MockService_GetUserTransfers_Call{Call: _e.mock.On("GetUserTransfers", ctx, id)}
+}
+
+func (_c *MockService_GetUserTransfers_Call) Run(run func(ctx context.Context, id int)) *MockService_GetUserTransfers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_GetUserTransfers_Call) Return(_a0 []models.Transfer, _a1 error) *MockService_GetUserTransfers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_GetUserTransfers_Call) RunAndReturn(run func(context.Context, int) ([]models.Transfer, error)) *MockService_GetUserTransfers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserView provides a mock function with given fields: ctx, userID
+func (_m *MockService) GetUserView(ctx context.Context, userID int) (models.AdminUserView, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserView")
+	}
+
+	var r0 models.AdminUserView
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.AdminUserView, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.AdminUserView); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(models.AdminUserView)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_GetUserView_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserView'
+type MockService_GetUserView_Call struct {
+	*mock.Call
+}
+
+// GetUserView is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockService_Expecter) GetUserView(ctx interface{}, userID interface{}) *MockService_GetUserView_Call {
+	return &MockService_GetUserView_Call{Call: _e.mock.On("GetUserView", ctx, userID)}
+}
+
+func (_c *MockService_GetUserView_Call) Run(run func(ctx context.Context, userID int)) *MockService_GetUserView_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_GetUserView_Call) Return(_a0 models.AdminUserView, _a1 error) *MockService_GetUserView_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_GetUserView_Call) RunAndReturn(run func(context.Context, int) (models.AdminUserView, error)) *MockService_GetUserView_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserWithdrawals provides a mock function with given fields: ctx, id
+func (_m *MockService) GetUserWithdrawals(ctx context.Context, id int) ([]models.Withdrawal, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserWithdrawals")
+	}
+
+	var r0 []models.Withdrawal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.Withdrawal, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.Withdrawal); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Withdrawal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_GetUserWithdrawals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserWithdrawals'
+type MockService_GetUserWithdrawals_Call struct {
+	*mock.Call
+}
+
+// GetUserWithdrawals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockService_Expecter) GetUserWithdrawals(ctx interface{}, id interface{}) *MockService_GetUserWithdrawals_Call {
+	return &MockService_GetUserWithdrawals_Call{Call: _e.mock.On("GetUserWithdrawals", ctx, id)}
+}
+
+func (_c *MockService_GetUserWithdrawals_Call) Run(run func(ctx context.Context, id int)) *MockService_GetUserWithdrawals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_GetUserWithdrawals_Call) Return(_a0 []models.Withdrawal, _a1 error) *MockService_GetUserWithdrawals_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_GetUserWithdrawals_Call) RunAndReturn(run func(context.Context, int) ([]models.Withdrawal, error)) *MockService_GetUserWithdrawals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWebhookDeliveries provides a mock function with given fields: ctx, merchantID, status
+func (_m *MockService) GetWebhookDeliveries(ctx context.Context, merchantID int, status string) ([]models.WebhookDelivery, error) {
+	ret := _m.Called(ctx, merchantID, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhookDeliveries")
+	}
+
+	var r0 []models.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) ([]models.WebhookDelivery, error)); ok {
+		return rf(ctx, merchantID, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) []models.WebhookDelivery); ok {
+		r0 = rf(ctx, merchantID, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, merchantID, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_GetWebhookDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWebhookDeliveries'
+type MockService_GetWebhookDeliveries_Call struct {
+	*mock.Call
+}
+
+// GetWebhookDeliveries is a helper method to define mock.On call
+//   - ctx context.Context
+//   - merchantID int
+//   - status string
+func (_e *MockService_Expecter) GetWebhookDeliveries(ctx interface{}, merchantID interface{}, status interface{}) *MockService_GetWebhookDeliveries_Call {
+	return &MockService_GetWebhookDeliveries_Call{Call: _e.mock.On("GetWebhookDeliveries", ctx, merchantID, status)}
+}
+
+func (_c *MockService_GetWebhookDeliveries_Call) Run(run func(ctx context.Context, merchantID int, status string)) *MockService_GetWebhookDeliveries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockService_GetWebhookDeliveries_Call) Return(_a0 []models.WebhookDelivery, _a1 error) *MockService_GetWebhookDeliveries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_GetWebhookDeliveries_Call) RunAndReturn(run func(context.Context, int, string) ([]models.WebhookDelivery, error)) *MockService_GetWebhookDeliveries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWebhookSubscriptions provides a mock function with given fields: ctx, merchantID
+func (_m *MockService) GetWebhookSubscriptions(ctx context.Context, merchantID int) ([]models.WebhookSubscription, error) {
+	ret := _m.Called(ctx, merchantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhookSubscriptions")
+	}
+
+	var r0 []models.WebhookSubscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.WebhookSubscription, error)); ok {
+		return rf(ctx, merchantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.WebhookSubscription); ok {
+		r0 = rf(ctx, merchantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.WebhookSubscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, merchantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_GetWebhookSubscriptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWebhookSubscriptions'
+type MockService_GetWebhookSubscriptions_Call struct {
+	*mock.Call
+}
+
+// GetWebhookSubscriptions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - merchantID int
+func (_e *MockService_Expecter) GetWebhookSubscriptions(ctx interface{}, merchantID interface{}) *MockService_GetWebhookSubscriptions_Call {
+	return &MockService_GetWebhookSubscriptions_Call{Call: _e.mock.On("GetWebhookSubscriptions", ctx, merchantID)}
+}
+
+func (_c *MockService_GetWebhookSubscriptions_Call) Run(run func(ctx context.Context, merchantID int)) *MockService_GetWebhookSubscriptions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_GetWebhookSubscriptions_Call) Return(_a0 []models.WebhookSubscription, _a1 error) *MockService_GetWebhookSubscriptions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_GetWebhookSubscriptions_Call) RunAndReturn(run func(context.Context, int) ([]models.WebhookSubscription, error)) *MockService_GetWebhookSubscriptions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IssueCheckoutCode provides a mock function with given fields: ctx, userID
+func (_m *MockService) IssueCheckoutCode(ctx context.Context, userID int) (models.CheckoutCode, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueCheckoutCode")
+	}
+
+	var r0 models.CheckoutCode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.CheckoutCode, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.CheckoutCode); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(models.CheckoutCode)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_IssueCheckoutCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IssueCheckoutCode'
+type MockService_IssueCheckoutCode_Call struct {
+	*mock.Call
+}
+
+// IssueCheckoutCode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockService_Expecter) IssueCheckoutCode(ctx interface{}, userID interface{}) *MockService_IssueCheckoutCode_Call {
+	return &MockService_IssueCheckoutCode_Call{Call: _e.mock.On("IssueCheckoutCode", ctx, userID)}
+}
+
+func (_c *MockService_IssueCheckoutCode_Call) Run(run func(ctx context.Context, userID int)) *MockService_IssueCheckoutCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_IssueCheckoutCode_Call) Return(_a0 models.CheckoutCode, _a1 error) *MockService_IssueCheckoutCode_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_IssueCheckoutCode_Call) RunAndReturn(run func(context.Context, int) (models.CheckoutCode, error)) *MockService_IssueCheckoutCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IssueCustomerToken provides a mock function with given fields: ctx, userID
+func (_m *MockService) IssueCustomerToken(ctx context.Context, userID int) (models.CustomerToken, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueCustomerToken")
+	}
+
+	var r0 models.CustomerToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.CustomerToken, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.CustomerToken); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(models.CustomerToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_IssueCustomerToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IssueCustomerToken'
+type MockService_IssueCustomerToken_Call struct {
+	*mock.Call
+}
+
+// IssueCustomerToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockService_Expecter) IssueCustomerToken(ctx interface{}, userID interface{}) *MockService_IssueCustomerToken_Call {
+	return &MockService_IssueCustomerToken_Call{Call: _e.mock.On("IssueCustomerToken", ctx, userID)}
+}
+
+func (_c *MockService_IssueCustomerToken_Call) Run(run func(ctx context.Context, userID int)) *MockService_IssueCustomerToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_IssueCustomerToken_Call) Return(_a0 models.CustomerToken, _a1 error) *MockService_IssueCustomerToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_IssueCustomerToken_Call) RunAndReturn(run func(context.Context, int) (models.CustomerToken, error)) *MockService_IssueCustomerToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IssueMerchantAPIKey provides a mock function with given fields: ctx, adminID, merchantID
+func (_m *MockService) IssueMerchantAPIKey(ctx context.Context, adminID int, merchantID int) (models.MerchantCredentials, error) {
+	ret := _m.Called(ctx, adminID, merchantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueMerchantAPIKey")
+	}
+
+	var r0 models.MerchantCredentials
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (models.MerchantCredentials, error)); ok {
+		return rf(ctx, adminID, merchantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) models.MerchantCredentials); ok {
+		r0 = rf(ctx, adminID, merchantID)
+	} else {
+		r0 = ret.Get(0).(models.MerchantCredentials)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, adminID, merchantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_IssueMerchantAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IssueMerchantAPIKey'
+type MockService_IssueMerchantAPIKey_Call struct {
+	*mock.Call
+}
+
+// IssueMerchantAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - adminID int
+//   - merchantID int
+func (_e *MockService_Expecter) IssueMerchantAPIKey(ctx interface{}, adminID interface{}, merchantID interface{}) *MockService_IssueMerchantAPIKey_Call {
+	return &MockService_IssueMerchantAPIKey_Call{Call: _e.mock.On("IssueMerchantAPIKey", ctx, adminID, merchantID)}
+}
+
+func (_c *MockService_IssueMerchantAPIKey_Call) Run(run func(ctx context.Context, adminID int, merchantID int)) *MockService_IssueMerchantAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_IssueMerchantAPIKey_Call) Return(_a0 models.MerchantCredentials, _a1 error) *MockService_IssueMerchantAPIKey_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_IssueMerchantAPIKey_Call) RunAndReturn(run func(context.Context, int, int) (models.MerchantCredentials, error)) *MockService_IssueMerchantAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListCampaigns provides a mock function with given fields: ctx
+func (_m *MockService) ListCampaigns(ctx context.Context) ([]models.Campaign, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCampaigns")
+	}
+
+	var r0 []models.Campaign
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Campaign, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Campaign); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Campaign)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_ListCampaigns_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListCampaigns'
+type MockService_ListCampaigns_Call struct {
+	*mock.Call
+}
+
+// ListCampaigns is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockService_Expecter) ListCampaigns(ctx interface{}) *MockService_ListCampaigns_Call {
+	return &MockService_ListCampaigns_Call{Call: _e.mock.On("ListCampaigns", ctx)}
+}
+
+func (_c *MockService_ListCampaigns_Call) Run(run func(ctx context.Context)) *MockService_ListCampaigns_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockService_ListCampaigns_Call) Return(_a0 []models.Campaign, _a1 error) *MockService_ListCampaigns_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_ListCampaigns_Call) RunAndReturn(run func(context.Context) ([]models.Campaign, error)) *MockService_ListCampaigns_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Logout provides a mock function with given fields: ctx, principal
+func (_m *MockService) Logout(ctx context.Context, principal *auth.Principal) error {
+	ret := _m.Called(ctx, principal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *auth.Principal) error); ok {
+		r0 = rf(ctx, principal)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_Logout_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Logout'
+type MockService_Logout_Call struct {
+	*mock.Call
+}
+
+// Logout is a helper method to define mock.On call
+//   - ctx context.Context
+//   - principal *auth.Principal
+func (_e *MockService_Expecter) Logout(ctx interface{}, principal interface{}) *MockService_Logout_Call {
+	return &MockService_Logout_Call{Call: _e.mock.On("Logout", ctx, principal)}
+}
+
+func (_c *MockService_Logout_Call) Run(run func(ctx context.Context, principal *auth.Principal)) *MockService_Logout_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*auth.Principal))
+	})
+	return _c
+}
+
+func (_c *MockService_Logout_Call) Return(_a0 error) *MockService_Logout_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_Logout_Call) RunAndReturn(run func(context.Context, *auth.Principal) error) *MockService_Logout_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReagisterUser provides a mock function with given fields: ctx, user
+func (_m *MockService) ReagisterUser(ctx context.Context, user *models.User) error {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReagisterUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_ReagisterUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReagisterUser'
+type MockService_ReagisterUser_Call struct {
+	*mock.Call
+}
+
+// ReagisterUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *models.User
+func (_e *MockService_Expecter) ReagisterUser(ctx interface{}, user interface{}) *MockService_ReagisterUser_Call {
+	return &MockService_ReagisterUser_Call{Call: _e.mock.On("ReagisterUser", ctx, user)}
+}
+
+func (_c *MockService_ReagisterUser_Call) Run(run func(ctx context.Context, user *models.User)) *MockService_ReagisterUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.User))
+	})
+	return _c
+}
+
+func (_c *MockService_ReagisterUser_Call) Return(_a0 error) *MockService_ReagisterUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_ReagisterUser_Call) RunAndReturn(run func(context.Context, *models.User) error) *MockService_ReagisterUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordLoginAttempt provides a mock function with given fields: ctx, login, ip, authErr
+func (_m *MockService) RecordLoginAttempt(ctx context.Context, login string, ip string, authErr error) error {
+	ret := _m.Called(ctx, login, ip, authErr)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordLoginAttempt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, error) error); ok {
+		r0 = rf(ctx, login, ip, authErr)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_RecordLoginAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordLoginAttempt'
+type MockService_RecordLoginAttempt_Call struct {
+	*mock.Call
+}
+
+// RecordLoginAttempt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login string
+//   - ip string
+//   - authErr error
+func (_e *MockService_Expecter) RecordLoginAttempt(ctx interface{}, login interface{}, ip interface{}, authErr interface{}) *MockService_RecordLoginAttempt_Call {
+	return &MockService_RecordLoginAttempt_Call{Call: _e.mock.On("RecordLoginAttempt", ctx, login, ip, authErr)}
+}
+
+func (_c *MockService_RecordLoginAttempt_Call) Run(run func(ctx context.Context, login string, ip string, authErr error)) *MockService_RecordLoginAttempt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(error))
+	})
+	return _c
+}
+
+func (_c *MockService_RecordLoginAttempt_Call) Return(_a0 error) *MockService_RecordLoginAttempt_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_RecordLoginAttempt_Call) RunAndReturn(run func(context.Context, string, string, error) error) *MockService_RecordLoginAttempt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RedeemCheckoutCode provides a mock function with given fields: ctx, merchantID, req
+func (_m *MockService) RedeemCheckoutCode(ctx context.Context, merchantID int, req models.CheckoutRedeem) service.CreateStatus {
+	ret := _m.Called(ctx, merchantID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedeemCheckoutCode")
+	}
+
+	var r0 service.CreateStatus
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.CheckoutRedeem) service.CreateStatus); ok {
+		r0 = rf(ctx, merchantID, req)
+	} else {
+		r0 = ret.Get(0).(service.CreateStatus)
+	}
+
+	return r0
+}
+
+// MockService_RedeemCheckoutCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RedeemCheckoutCode'
+type MockService_RedeemCheckoutCode_Call struct {
+	*mock.Call
+}
+
+// RedeemCheckoutCode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - merchantID int
+//   - req models.CheckoutRedeem
+func (_e *MockService_Expecter) RedeemCheckoutCode(ctx interface{}, merchantID interface{}, req interface{}) *MockService_RedeemCheckoutCode_Call {
+	return &MockService_RedeemCheckoutCode_Call{Call: _e.mock.On("RedeemCheckoutCode", ctx, merchantID, req)}
+}
+
+func (_c *MockService_RedeemCheckoutCode_Call) Run(run func(ctx context.Context, merchantID int, req models.CheckoutRedeem)) *MockService_RedeemCheckoutCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.CheckoutRedeem))
+	})
+	return _c
+}
+
+func (_c *MockService_RedeemCheckoutCode_Call) Return(_a0 service.CreateStatus) *MockService_RedeemCheckoutCode_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_RedeemCheckoutCode_Call) RunAndReturn(run func(context.Context, int, models.CheckoutRedeem) service.CreateStatus) *MockService_RedeemCheckoutCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RedeliverWebhook provides a mock function with given fields: ctx, merchantID, deliveryID
+func (_m *MockService) RedeliverWebhook(ctx context.Context, merchantID int, deliveryID int) error {
+	ret := _m.Called(ctx, merchantID, deliveryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedeliverWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, merchantID, deliveryID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_RedeliverWebhook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RedeliverWebhook'
+type MockService_RedeliverWebhook_Call struct {
+	*mock.Call
+}
+
+// RedeliverWebhook is a helper method to define mock.On call
+//   - ctx context.Context
+//   - merchantID int
+//   - deliveryID int
+func (_e *MockService_Expecter) RedeliverWebhook(ctx interface{}, merchantID interface{}, deliveryID interface{}) *MockService_RedeliverWebhook_Call {
+	return &MockService_RedeliverWebhook_Call{Call: _e.mock.On("RedeliverWebhook", ctx, merchantID, deliveryID)}
+}
+
+func (_c *MockService_RedeliverWebhook_Call) Run(run func(ctx context.Context, merchantID int, deliveryID int)) *MockService_RedeliverWebhook_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_RedeliverWebhook_Call) Return(_a0 error) *MockService_RedeliverWebhook_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_RedeliverWebhook_Call) RunAndReturn(run func(context.Context, int, int) error) *MockService_RedeliverWebhook_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RefreshSession provides a mock function with given fields: ctx, refreshToken
+func (_m *MockService) RefreshSession(ctx context.Context, refreshToken string) (*models.User, string, error) {
+	ret := _m.Called(ctx, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshSession")
+	}
+
+	var r0 *models.User
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, string, error)); ok {
+		return rf(ctx, refreshToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, refreshToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) string); ok {
+		r1 = rf(ctx, refreshToken)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, refreshToken)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockService_RefreshSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RefreshSession'
+type MockService_RefreshSession_Call struct {
+	*mock.Call
+}
+
+// RefreshSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - refreshToken string
+func (_e *MockService_Expecter) RefreshSession(ctx interface{}, refreshToken interface{}) *MockService_RefreshSession_Call {
+	return &MockService_RefreshSession_Call{Call: _e.mock.On("RefreshSession", ctx, refreshToken)}
+}
+
+func (_c *MockService_RefreshSession_Call) Run(run func(ctx context.Context, refreshToken string)) *MockService_RefreshSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockService_RefreshSession_Call) Return(_a0 *models.User, _a1 string, _a2 error) *MockService_RefreshSession_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockService_RefreshSession_Call) RunAndReturn(run func(context.Context, string) (*models.User, string, error)) *MockService_RefreshSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RepollOrder provides a mock function with given fields: ctx, adminID, orderNum
+func (_m *MockService) RepollOrder(ctx context.Context, adminID int, orderNum string) error {
+	ret := _m.Called(ctx, adminID, orderNum)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RepollOrder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, adminID, orderNum)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_RepollOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RepollOrder'
+type MockService_RepollOrder_Call struct {
+	*mock.Call
+}
+
+// RepollOrder is a helper method to define mock.On call
+//   - ctx context.Context
+//   - adminID int
+//   - orderNum string
+func (_e *MockService_Expecter) RepollOrder(ctx interface{}, adminID interface{}, orderNum interface{}) *MockService_RepollOrder_Call {
+	return &MockService_RepollOrder_Call{Call: _e.mock.On("RepollOrder", ctx, adminID, orderNum)}
+}
+
+func (_c *MockService_RepollOrder_Call) Run(run func(ctx context.Context, adminID int, orderNum string)) *MockService_RepollOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockService_RepollOrder_Call) Return(_a0 error) *MockService_RepollOrder_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_RepollOrder_Call) RunAndReturn(run func(context.Context, int, string) error) *MockService_RepollOrder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RequestPasswordReset provides a mock function with given fields: ctx, login
+func (_m *MockService) RequestPasswordReset(ctx context.Context, login string) error {
+	ret := _m.Called(ctx, login)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestPasswordReset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, login)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_RequestPasswordReset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RequestPasswordReset'
+type MockService_RequestPasswordReset_Call struct {
+	*mock.Call
+}
+
+// RequestPasswordReset is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login string
+func (_e *MockService_Expecter) RequestPasswordReset(ctx interface{}, login interface{}) *MockService_RequestPasswordReset_Call {
+	return &MockService_RequestPasswordReset_Call{Call: _e.mock.On("RequestPasswordReset", ctx, login)}
+}
+
+func (_c *MockService_RequestPasswordReset_Call) Run(run func(ctx context.Context, login string)) *MockService_RequestPasswordReset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockService_RequestPasswordReset_Call) Return(_a0 error) *MockService_RequestPasswordReset_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_RequestPasswordReset_Call) RunAndReturn(run func(context.Context, string) error) *MockService_RequestPasswordReset_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetPassword provides a mock function with given fields: ctx, reset
+func (_m *MockService) ResetPassword(ctx context.Context, reset models.PasswordReset) error {
+	ret := _m.Called(ctx, reset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.PasswordReset) error); ok {
+		r0 = rf(ctx, reset)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_ResetPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetPassword'
+type MockService_ResetPassword_Call struct {
+	*mock.Call
+}
+
+// ResetPassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - reset models.PasswordReset
+func (_e *MockService_Expecter) ResetPassword(ctx interface{}, reset interface{}) *MockService_ResetPassword_Call {
+	return &MockService_ResetPassword_Call{Call: _e.mock.On("ResetPassword", ctx, reset)}
+}
+
+func (_c *MockService_ResetPassword_Call) Run(run func(ctx context.Context, reset models.PasswordReset)) *MockService_ResetPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.PasswordReset))
+	})
+	return _c
+}
+
+func (_c *MockService_ResetPassword_Call) Return(_a0 error) *MockService_ResetPassword_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_ResetPassword_Call) RunAndReturn(run func(context.Context, models.PasswordReset) error) *MockService_ResetPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeMerchantAPIKey provides a mock function with given fields: ctx, adminID, merchantID, keyID
+func (_m *MockService) RevokeMerchantAPIKey(ctx context.Context, adminID int, merchantID int, keyID int) error {
+	ret := _m.Called(ctx, adminID, merchantID, keyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeMerchantAPIKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) error); ok {
+		r0 = rf(ctx, adminID, merchantID, keyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_RevokeMerchantAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeMerchantAPIKey'
+type MockService_RevokeMerchantAPIKey_Call struct {
+	*mock.Call
+}
+
+// RevokeMerchantAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - adminID int
+//   - merchantID int
+//   - keyID int
+func (_e *MockService_Expecter) RevokeMerchantAPIKey(ctx interface{}, adminID interface{}, merchantID interface{}, keyID interface{}) *MockService_RevokeMerchantAPIKey_Call {
+	return &MockService_RevokeMerchantAPIKey_Call{Call: _e.mock.On("RevokeMerchantAPIKey", ctx, adminID, merchantID, keyID)}
+}
+
+func (_c *MockService_RevokeMerchantAPIKey_Call) Run(run func(ctx context.Context, adminID int, merchantID int, keyID int)) *MockService_RevokeMerchantAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_RevokeMerchantAPIKey_Call) Return(_a0 error) *MockService_RevokeMerchantAPIKey_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_RevokeMerchantAPIKey_Call) RunAndReturn(run func(context.Context, int, int, int) error) *MockService_RevokeMerchantAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeOtherSessions provides a mock function with given fields: ctx, userID, keepSessionID
+func (_m *MockService) RevokeOtherSessions(ctx context.Context, userID int, keepSessionID int) error {
+	ret := _m.Called(ctx, userID, keepSessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeOtherSessions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, userID, keepSessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_RevokeOtherSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeOtherSessions'
+type MockService_RevokeOtherSessions_Call struct {
+	*mock.Call
+}
+
+// RevokeOtherSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - keepSessionID int
+func (_e *MockService_Expecter) RevokeOtherSessions(ctx interface{}, userID interface{}, keepSessionID interface{}) *MockService_RevokeOtherSessions_Call {
+	return &MockService_RevokeOtherSessions_Call{Call: _e.mock.On("RevokeOtherSessions", ctx, userID, keepSessionID)}
+}
+
+func (_c *MockService_RevokeOtherSessions_Call) Run(run func(ctx context.Context, userID int, keepSessionID int)) *MockService_RevokeOtherSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_RevokeOtherSessions_Call) Return(_a0 error) *MockService_RevokeOtherSessions_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_RevokeOtherSessions_Call) RunAndReturn(run func(context.Context, int, int) error) *MockService_RevokeOtherSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeSession provides a mock function with given fields: ctx, userID, sessionID
+func (_m *MockService) RevokeSession(ctx context.Context, userID int, sessionID int) error {
+	ret := _m.Called(ctx, userID, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, userID, sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_RevokeSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeSession'
+type MockService_RevokeSession_Call struct {
+	*mock.Call
+}
+
+// RevokeSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - sessionID int
+func (_e *MockService_Expecter) RevokeSession(ctx interface{}, userID interface{}, sessionID interface{}) *MockService_RevokeSession_Call {
+	return &MockService_RevokeSession_Call{Call: _e.mock.On("RevokeSession", ctx, userID, sessionID)}
+}
+
+func (_c *MockService_RevokeSession_Call) Run(run func(ctx context.Context, userID int, sessionID int)) *MockService_RevokeSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_RevokeSession_Call) Return(_a0 error) *MockService_RevokeSession_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_RevokeSession_Call) RunAndReturn(run func(context.Context, int, int) error) *MockService_RevokeSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SearchUsers provides a mock function with given fields: ctx, query
+func (_m *MockService) SearchUsers(ctx context.Context, query string) ([]models.AdminUser, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchUsers")
+	}
+
+	var r0 []models.AdminUser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]models.AdminUser, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []models.AdminUser); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AdminUser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_SearchUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SearchUsers'
+type MockService_SearchUsers_Call struct {
+	*mock.Call
+}
+
+// SearchUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query string
+func (_e *MockService_Expecter) SearchUsers(ctx interface{}, query interface{}) *MockService_SearchUsers_Call {
+	return &MockService_SearchUsers_Call{Call: _e.mock.On("SearchUsers", ctx, query)}
+}
+
+func (_c *MockService_SearchUsers_Call) Run(run func(ctx context.Context, query string)) *MockService_SearchUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockService_SearchUsers_Call) Return(_a0 []models.AdminUser, _a1 error) *MockService_SearchUsers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_SearchUsers_Call) RunAndReturn(run func(context.Context, string) ([]models.AdminUser, error)) *MockService_SearchUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetTwoFactorSettings provides a mock function with given fields: ctx, userID, settings
+func (_m *MockService) SetTwoFactorSettings(ctx context.Context, userID int, settings models.TwoFactorSettings) error {
+	ret := _m.Called(ctx, userID, settings)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTwoFactorSettings")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.TwoFactorSettings) error); ok {
+		r0 = rf(ctx, userID, settings)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_SetTwoFactorSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetTwoFactorSettings'
+type MockService_SetTwoFactorSettings_Call struct {
+	*mock.Call
+}
+
+// SetTwoFactorSettings is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - settings models.TwoFactorSettings
+func (_e *MockService_Expecter) SetTwoFactorSettings(ctx interface{}, userID interface{}, settings interface{}) *MockService_SetTwoFactorSettings_Call {
+	return &MockService_SetTwoFactorSettings_Call{Call: _e.mock.On("SetTwoFactorSettings", ctx, userID, settings)}
+}
+
+func (_c *MockService_SetTwoFactorSettings_Call) Run(run func(ctx context.Context, userID int, settings models.TwoFactorSettings)) *MockService_SetTwoFactorSettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.TwoFactorSettings))
+	})
+	return _c
+}
+
+func (_c *MockService_SetTwoFactorSettings_Call) Return(_a0 error) *MockService_SetTwoFactorSettings_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_SetTwoFactorSettings_Call) RunAndReturn(run func(context.Context, int, models.TwoFactorSettings) error) *MockService_SetTwoFactorSettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetUserAccess provides a mock function with given fields: ctx, adminID, userID, access
+func (_m *MockService) SetUserAccess(ctx context.Context, adminID int, userID int, access models.UserAccess) error {
+	ret := _m.Called(ctx, adminID, userID, access)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserAccess")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, models.UserAccess) error); ok {
+		r0 = rf(ctx, adminID, userID, access)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_SetUserAccess_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetUserAccess'
+type MockService_SetUserAccess_Call struct {
+	*mock.Call
+}
+
+// SetUserAccess is a helper method to define mock.On call
+//   - ctx context.Context
+//   - adminID int
+//   - userID int
+//   - access models.UserAccess
+func (_e *MockService_Expecter) SetUserAccess(ctx interface{}, adminID interface{}, userID interface{}, access interface{}) *MockService_SetUserAccess_Call {
+	return &MockService_SetUserAccess_Call{Call: _e.mock.On("SetUserAccess", ctx, adminID, userID, access)}
+}
+
+func (_c *MockService_SetUserAccess_Call) Run(run func(ctx context.Context, adminID int, userID int, access models.UserAccess)) *MockService_SetUserAccess_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(models.UserAccess))
+	})
+	return _c
+}
+
+func (_c *MockService_SetUserAccess_Call) Return(_a0 error) *MockService_SetUserAccess_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_SetUserAccess_Call) RunAndReturn(run func(context.Context, int, int, models.UserAccess) error) *MockService_SetUserAccess_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetUserBlocked provides a mock function with given fields: ctx, adminID, userID, blocked, reason
+func (_m *MockService) SetUserBlocked(ctx context.Context, adminID int, userID int, blocked bool, reason string) error {
+	ret := _m.Called(ctx, adminID, userID, blocked, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserBlocked")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, bool, string) error); ok {
+		r0 = rf(ctx, adminID, userID, blocked, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_SetUserBlocked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetUserBlocked'
+type MockService_SetUserBlocked_Call struct {
+	*mock.Call
+}
+
+// SetUserBlocked is a helper method to define mock.On call
+//   - ctx context.Context
+//   - adminID int
+//   - userID int
+//   - blocked bool
+//   - reason string
+func (_e *MockService_Expecter) SetUserBlocked(ctx interface{}, adminID interface{}, userID interface{}, blocked interface{}, reason interface{}) *MockService_SetUserBlocked_Call {
+	return &MockService_SetUserBlocked_Call{Call: _e.mock.On("SetUserBlocked", ctx, adminID, userID, blocked, reason)}
+}
+
+func (_c *MockService_SetUserBlocked_Call) Run(run func(ctx context.Context, adminID int, userID int, blocked bool, reason string)) *MockService_SetUserBlocked_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(bool), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockService_SetUserBlocked_Call) Return(_a0 error) *MockService_SetUserBlocked_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_SetUserBlocked_Call) RunAndReturn(run func(context.Context, int, int, bool, string) error) *MockService_SetUserBlocked_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StartLoginChallenge provides a mock function with given fields: ctx, user
+func (_m *MockService) StartLoginChallenge(ctx context.Context, user *models.User) (models.LoginChallenge, error) {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartLoginChallenge")
+	}
+
+	var r0 models.LoginChallenge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) (models.LoginChallenge, error)); ok {
+		return rf(ctx, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) models.LoginChallenge); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Get(0).(models.LoginChallenge)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.User) error); ok {
+		r1 = rf(ctx, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_StartLoginChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartLoginChallenge'
+type MockService_StartLoginChallenge_Call struct {
+	*mock.Call
+}
+
+// StartLoginChallenge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *models.User
+func (_e *MockService_Expecter) StartLoginChallenge(ctx interface{}, user interface{}) *MockService_StartLoginChallenge_Call {
+	return &MockService_StartLoginChallenge_Call{Call: _e.mock.On("StartLoginChallenge", ctx, user)}
+}
+
+func (_c *MockService_StartLoginChallenge_Call) Run(run func(ctx context.Context, user *models.User)) *MockService_StartLoginChallenge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.User))
+	})
+	return _c
+}
+
+func (_c *MockService_StartLoginChallenge_Call) Return(_a0 models.LoginChallenge, _a1 error) *MockService_StartLoginChallenge_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_StartLoginChallenge_Call) RunAndReturn(run func(context.Context, *models.User) (models.LoginChallenge, error)) *MockService_StartLoginChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StartSession provides a mock function with given fields: ctx, user, meta
+func (_m *MockService) StartSession(ctx context.Context, user *models.User, meta models.SessionMeta) (string, error) {
+	ret := _m.Called(ctx, user, meta)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartSession")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, models.SessionMeta) (string, error)); ok {
+		return rf(ctx, user, meta)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, models.SessionMeta) string); ok {
+		r0 = rf(ctx, user, meta)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.User, models.SessionMeta) error); ok {
+		r1 = rf(ctx, user, meta)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_StartSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartSession'
+type MockService_StartSession_Call struct {
+	*mock.Call
+}
+
+// StartSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *models.User
+//   - meta models.SessionMeta
+func (_e *MockService_Expecter) StartSession(ctx interface{}, user interface{}, meta interface{}) *MockService_StartSession_Call {
+	return &MockService_StartSession_Call{Call: _e.mock.On("StartSession", ctx, user, meta)}
+}
+
+func (_c *MockService_StartSession_Call) Run(run func(ctx context.Context, user *models.User, meta models.SessionMeta)) *MockService_StartSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.User), args[2].(models.SessionMeta))
+	})
+	return _c
+}
+
+func (_c *MockService_StartSession_Call) Return(_a0 string, _a1 error) *MockService_StartSession_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_StartSession_Call) RunAndReturn(run func(context.Context, *models.User, models.SessionMeta) (string, error)) *MockService_StartSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StreamStatement provides a mock function with given fields: ctx, userID, from, to, fn
+func (_m *MockService) StreamStatement(ctx context.Context, userID int, from time.Time, to time.Time, fn func(models.StatementEntry) error) error {
+	ret := _m.Called(ctx, userID, from, to, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamStatement")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, time.Time, func(models.StatementEntry) error) error); ok {
+		r0 = rf(ctx, userID, from, to, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_StreamStatement_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StreamStatement'
+type MockService_StreamStatement_Call struct {
+	*mock.Call
+}
+
+// StreamStatement is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - from time.Time
+//   - to time.Time
+//   - fn func(models.StatementEntry) error
+func (_e *MockService_Expecter) StreamStatement(ctx interface{}, userID interface{}, from interface{}, to interface{}, fn interface{}) *MockService_StreamStatement_Call {
+	return &MockService_StreamStatement_Call{Call: _e.mock.On("StreamStatement", ctx, userID, from, to, fn)}
+}
+
+func (_c *MockService_StreamStatement_Call) Run(run func(ctx context.Context, userID int, from time.Time, to time.Time, fn func(models.StatementEntry) error)) *MockService_StreamStatement_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time), args[3].(time.Time), args[4].(func(models.StatementEntry) error))
+	})
+	return _c
+}
+
+func (_c *MockService_StreamStatement_Call) Return(_a0 error) *MockService_StreamStatement_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_StreamStatement_Call) RunAndReturn(run func(context.Context, int, time.Time, time.Time, func(models.StatementEntry) error) error) *MockService_StreamStatement_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SubmitMerchantOrder provides a mock function with given fields: ctx, merchantID, req
+func (_m *MockService) SubmitMerchantOrder(ctx context.Context, merchantID int, req models.MerchantOrder) service.CreateStatus {
+	ret := _m.Called(ctx, merchantID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubmitMerchantOrder")
+	}
+
+	var r0 service.CreateStatus
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.MerchantOrder) service.CreateStatus); ok {
+		r0 = rf(ctx, merchantID, req)
+	} else {
+		r0 = ret.Get(0).(service.CreateStatus)
+	}
+
+	return r0
+}
+
+// MockService_SubmitMerchantOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SubmitMerchantOrder'
+type MockService_SubmitMerchantOrder_Call struct {
+	*mock.Call
+}
+
+// SubmitMerchantOrder is a helper method to define mock.On call
+//   - ctx context.Context
+//   - merchantID int
+//   - req models.MerchantOrder
+func (_e *MockService_Expecter) SubmitMerchantOrder(ctx interface{}, merchantID interface{}, req interface{}) *MockService_SubmitMerchantOrder_Call {
+	return &MockService_SubmitMerchantOrder_Call{Call: _e.mock.On("SubmitMerchantOrder", ctx, merchantID, req)}
+}
+
+func (_c *MockService_SubmitMerchantOrder_Call) Run(run func(ctx context.Context, merchantID int, req models.MerchantOrder)) *MockService_SubmitMerchantOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.MerchantOrder))
+	})
+	return _c
+}
+
+func (_c *MockService_SubmitMerchantOrder_Call) Return(_a0 service.CreateStatus) *MockService_SubmitMerchantOrder_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_SubmitMerchantOrder_Call) RunAndReturn(run func(context.Context, int, models.MerchantOrder) service.CreateStatus) *MockService_SubmitMerchantOrder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UnlockLogin provides a mock function with given fields: ctx, adminID, userID
+func (_m *MockService) UnlockLogin(ctx context.Context, adminID int, userID int) error {
+	ret := _m.Called(ctx, adminID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnlockLogin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, adminID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_UnlockLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnlockLogin'
+type MockService_UnlockLogin_Call struct {
+	*mock.Call
+}
+
+// UnlockLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - adminID int
+//   - userID int
+func (_e *MockService_Expecter) UnlockLogin(ctx interface{}, adminID interface{}, userID interface{}) *MockService_UnlockLogin_Call {
+	return &MockService_UnlockLogin_Call{Call: _e.mock.On("UnlockLogin", ctx, adminID, userID)}
+}
+
+func (_c *MockService_UnlockLogin_Call) Run(run func(ctx context.Context, adminID int, userID int)) *MockService_UnlockLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockService_UnlockLogin_Call) Return(_a0 error) *MockService_UnlockLogin_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_UnlockLogin_Call) RunAndReturn(run func(context.Context, int, int) error) *MockService_UnlockLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateCampaign provides a mock function with given fields: ctx, c
+func (_m *MockService) UpdateCampaign(ctx context.Context, c *models.Campaign) error {
+	ret := _m.Called(ctx, c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCampaign")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Campaign) error); ok {
+		r0 = rf(ctx, c)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_UpdateCampaign_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateCampaign'
+type MockService_UpdateCampaign_Call struct {
+	*mock.Call
+}
+
+// UpdateCampaign is a helper method to define mock.On call
+//   - ctx context.Context
+//   - c *models.Campaign
+func (_e *MockService_Expecter) UpdateCampaign(ctx interface{}, c interface{}) *MockService_UpdateCampaign_Call {
+	return &MockService_UpdateCampaign_Call{Call: _e.mock.On("UpdateCampaign", ctx, c)}
+}
+
+func (_c *MockService_UpdateCampaign_Call) Run(run func(ctx context.Context, c *models.Campaign)) *MockService_UpdateCampaign_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Campaign))
+	})
+	return _c
+}
+
+func (_c *MockService_UpdateCampaign_Call) Return(_a0 error) *MockService_UpdateCampaign_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_UpdateCampaign_Call) RunAndReturn(run func(context.Context, *models.Campaign) error) *MockService_UpdateCampaign_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateNotificationPreferences provides a mock function with given fields: ctx, userID, prefs
+func (_m *MockService) UpdateNotificationPreferences(ctx context.Context, userID int, prefs models.NotificationPreferences) (models.NotificationPreferences, error) {
+	ret := _m.Called(ctx, userID, prefs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateNotificationPreferences")
+	}
+
+	var r0 models.NotificationPreferences
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.NotificationPreferences) (models.NotificationPreferences, error)); ok {
+		return rf(ctx, userID, prefs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.NotificationPreferences) models.NotificationPreferences); ok {
+		r0 = rf(ctx, userID, prefs)
+	} else {
+		r0 = ret.Get(0).(models.NotificationPreferences)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, models.NotificationPreferences) error); ok {
+		r1 = rf(ctx, userID, prefs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_UpdateNotificationPreferences_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateNotificationPreferences'
+type MockService_UpdateNotificationPreferences_Call struct {
+	*mock.Call
+}
+
+// UpdateNotificationPreferences is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - prefs models.NotificationPreferences
+func (_e *MockService_Expecter) UpdateNotificationPreferences(ctx interface{}, userID interface{}, prefs interface{}) *MockService_UpdateNotificationPreferences_Call {
+	return &MockService_UpdateNotificationPreferences_Call{Call: _e.mock.On("UpdateNotificationPreferences", ctx, userID, prefs)}
+}
+
+func (_c *MockService_UpdateNotificationPreferences_Call) Run(run func(ctx context.Context, userID int, prefs models.NotificationPreferences)) *MockService_UpdateNotificationPreferences_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.NotificationPreferences))
+	})
+	return _c
+}
+
+func (_c *MockService_UpdateNotificationPreferences_Call) Return(_a0 models.NotificationPreferences, _a1 error) *MockService_UpdateNotificationPreferences_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_UpdateNotificationPreferences_Call) RunAndReturn(run func(context.Context, int, models.NotificationPreferences) (models.NotificationPreferences, error)) *MockService_UpdateNotificationPreferences_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UserExist provides a mock function with given fields: ctx, login
+func (_m *MockService) UserExist(ctx context.Context, login string) (bool, error) {
+	ret := _m.Called(ctx, login)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UserExist")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, login)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, login)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, login)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_UserExist_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UserExist'
+type MockService_UserExist_Call struct {
+	*mock.Call
+}
+
+// UserExist is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login string
+func (_e *MockService_Expecter) UserExist(ctx interface{}, login interface{}) *MockService_UserExist_Call {
+	return &MockService_UserExist_Call{Call: _e.mock.On("UserExist", ctx, login)}
+}
+
+func (_c *MockService_UserExist_Call) Run(run func(ctx context.Context, login string)) *MockService_UserExist_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
@@ -446,6 +3438,54 @@ func (_c *MockService_UserExist_Call) RunAndReturn(run func(context.Context, str
 	return _c
 }
 
+// VerifyAccrualCallback provides a mock function with given fields: ctx, req, signature
+func (_m *MockService) VerifyAccrualCallback(ctx context.Context, req auth.SignedRequest, signature string) error {
+	ret := _m.Called(ctx, req, signature)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyAccrualCallback")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, auth.SignedRequest, string) error); ok {
+		r0 = rf(ctx, req, signature)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockService_VerifyAccrualCallback_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyAccrualCallback'
+type MockService_VerifyAccrualCallback_Call struct {
+	*mock.Call
+}
+
+// VerifyAccrualCallback is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req auth.SignedRequest
+//   - signature string
+func (_e *MockService_Expecter) VerifyAccrualCallback(ctx interface{}, req interface{}, signature interface{}) *MockService_VerifyAccrualCallback_Call {
+	return &MockService_VerifyAccrualCallback_Call{Call: _e.mock.On("VerifyAccrualCallback", ctx, req, signature)}
+}
+
+func (_c *MockService_VerifyAccrualCallback_Call) Run(run func(ctx context.Context, req auth.SignedRequest, signature string)) *MockService_VerifyAccrualCallback_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(auth.SignedRequest), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockService_VerifyAccrualCallback_Call) Return(_a0 error) *MockService_VerifyAccrualCallback_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockService_VerifyAccrualCallback_Call) RunAndReturn(run func(context.Context, auth.SignedRequest, string) error) *MockService_VerifyAccrualCallback_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockService creates a new instance of MockService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockService(t interface {
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/internal/middleware"
+	"github.com/scoring-service/pkg/models"
+)
+
+const refreshTokenHeader = "X-Refresh-Token"
+
+func sessionMeta(r *http.Request) models.SessionMeta {
+	return models.SessionMeta{UserAgent: r.UserAgent(), IP: middleware.ClientIP(r)}
+}
+
+// writeTokens выпускает access-токен и отдаёт его в заголовке. Refresh-токен
+// при включённых cookie уходит только в HttpOnly cookie, чтобы скрипты
+// страницы не могли его прочитать, иначе — в заголовке X-Refresh-Token.
+func writeTokens(w http.ResponseWriter, user *models.User, refreshToken string) (string, error) {
+	token, err := auth.GenerateJWT(user)
+	if err != nil {
+		return "", err
+	}
+	if err := auth.SetAuthCookies(w, token, refreshToken); err != nil {
+		return "", err
+	}
+	w.Header().Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	if !auth.CookiesEnabled() {
+		w.Header().Set(refreshTokenHeader, refreshToken)
+	}
+	return token, nil
+}
+
+// RefreshToken принимает refresh-токен в теле запроса, а если тело пустое —
+// из cookie; во втором случае запрос должен нести CSRF-токен.
+func (h *Handler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+			return
+		}
+	} else if cookie, err := r.Cookie(auth.RefreshCookie); err == nil && auth.CookiesEnabled() {
+		if !auth.CheckCSRF(r) {
+			http.Error(w, "Неверный CSRF-токен", http.StatusForbidden)
+			return
+		}
+		req.RefreshToken = cookie.Value
+	}
+	user, refreshToken, err := h.serv.RefreshSession(r.Context(), req.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidRefreshToken), errors.Is(err, models.ErrRefreshTokenReused):
+			http.Error(w, "Недействительный refresh-токен", http.StatusUnauthorized)
+		case errors.Is(err, models.ErrUserBlocked):
+			http.Error(w, "Учётная запись заблокирована", http.StatusForbidden)
+		default:
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+	token, err := writeTokens(w, user, refreshToken)
+	if err != nil {
+		http.Error(w, "Ошибка при генерации токена", http.StatusInternalServerError)
+		return
+	}
+	pair := models.TokenPair{
+		AccessToken: token,
+		ExpiresIn:   int(auth.AccessTokenTTL.Seconds()),
+	}
+	if !auth.CookiesEnabled() {
+		pair.RefreshToken = refreshToken
+	}
+	writeJSON(w, http.StatusOK, pair)
+}
+
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	if err := h.serv.Logout(r.Context(), principal); err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	auth.ClearAuthCookies(w)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) GetUserSessions(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	sessions, err := h.serv.GetUserSessions(r.Context(), principal.UserID, principal.SessionID)
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if len(sessions) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, sessions)
+}
+
+// DeleteUserSessions завершает все сессии пользователя, кроме текущей.
+func (h *Handler) DeleteUserSessions(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	if err := h.serv.RevokeOtherSessions(r.Context(), principal.UserID, principal.SessionID); err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) DeleteUserSession(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	sessionID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || sessionID <= 0 {
+		http.Error(w, "invalid session id", http.StatusBadRequest)
+		return
+	}
+	if err := h.serv.RevokeSession(r.Context(), principal.UserID, sessionID); err != nil {
+		if errors.Is(err, models.ErrSessionNotFound) {
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
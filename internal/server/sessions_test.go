@@ -0,0 +1,201 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestRefreshToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		mockSetup func(serv *MockService)
+		code      int
+	}{
+		{
+			name: "token rotated",
+			body: `{"refresh_token":"old"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("RefreshSession", mock.Anything, "old").
+					Return(&models.User{ID: 1, SessionID: 11}, "new", nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "reused token",
+			body: `{"refresh_token":"old"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("RefreshSession", mock.Anything, "old").
+					Return(nil, "", models.ErrRefreshTokenReused)
+			},
+			code: http.StatusUnauthorized,
+		},
+		{
+			name:      "bad request",
+			body:      `{bad json}`,
+			mockSetup: func(serv *MockService) {},
+			code:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockService(t)
+			tt.mockSetup(mockService)
+
+			h := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/user/token/refresh", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			h.RefreshToken(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			require.Equal(t, tt.code, resp.StatusCode)
+			if tt.code == http.StatusOK {
+				var pair models.TokenPair
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&pair))
+				require.Empty(t, pair.RefreshToken)
+				require.Empty(t, resp.Header.Get(refreshTokenHeader))
+				require.NotEmpty(t, pair.AccessToken)
+				require.Equal(t, "Bearer "+pair.AccessToken, resp.Header.Get("Authorization"))
+			}
+		})
+	}
+}
+func TestRefreshTokenWithoutCookies(t *testing.T) {
+	auth.SetCookiePolicy(auth.CookiePolicy{})
+	t.Cleanup(func() {
+		auth.SetCookiePolicy(auth.CookiePolicy{
+			Enabled:    true,
+			Secure:     true,
+			SameSite:   http.SameSiteStrictMode,
+			RefreshTTL: 30 * 24 * time.Hour,
+		})
+	})
+
+	mockService := NewMockService(t)
+	mockService.On("RefreshSession", mock.Anything, "old").
+		Return(&models.User{ID: 1, SessionID: 11}, "new", nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/token/refresh", strings.NewReader(`{"refresh_token":"old"}`))
+	w := httptest.NewRecorder()
+
+	NewHandler(mockService).RefreshToken(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var pair models.TokenPair
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&pair))
+	require.Equal(t, "new", pair.RefreshToken)
+	require.Equal(t, "new", resp.Header.Get(refreshTokenHeader))
+	require.Empty(t, resp.Cookies())
+}
+func TestDeleteUserSession(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "session revoked", code: http.StatusOK},
+		{name: "session not found", err: models.ErrSessionNotFound, code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockService(t)
+			mockService.On("RevokeSession", mock.Anything, 1, 12).Return(tt.err)
+
+			h := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/user/sessions/12", nil)
+			req = withURLParam(req, "id", "12")
+			req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1, SessionID: 11}))
+			w := httptest.NewRecorder()
+
+			h.DeleteUserSession(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			require.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
+func TestLogout(t *testing.T) {
+	principal := &auth.Principal{UserID: 1, SessionID: 11, TokenID: "jti"}
+	mockService := NewMockService(t)
+	mockService.On("Logout", mock.Anything, principal).Return(nil)
+
+	h := NewHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/logout", nil)
+	req = req.WithContext(auth.WithPrincipal(req.Context(), principal))
+	w := httptest.NewRecorder()
+
+	h.Logout(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+func TestRefreshTokenFromCookie(t *testing.T) {
+	tests := []struct {
+		name string
+		csrf string
+		code int
+	}{
+		{name: "valid csrf token", csrf: "csrf", code: http.StatusOK},
+		{name: "missing csrf token", code: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockService(t)
+			if tt.code == http.StatusOK {
+				mockService.On("RefreshSession", mock.Anything, "old").
+					Return(&models.User{ID: 1, SessionID: 11}, "new", nil)
+			}
+
+			h := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/user/token/refresh", nil)
+			req.AddCookie(&http.Cookie{Name: auth.RefreshCookie, Value: "old"})
+			req.AddCookie(&http.Cookie{Name: auth.CSRFCookie, Value: "csrf"})
+			if tt.csrf != "" {
+				req.Header.Set(auth.CSRFHeader, tt.csrf)
+			}
+			w := httptest.NewRecorder()
+
+			h.RefreshToken(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			require.Equal(t, tt.code, resp.StatusCode)
+			if tt.code == http.StatusOK {
+				var names []string
+				for _, c := range resp.Cookies() {
+					names = append(names, c.Name)
+				}
+				require.ElementsMatch(t, []string{auth.AccessCookie, auth.RefreshCookie, auth.CSRFCookie}, names)
+				require.Empty(t, resp.Header.Get(refreshTokenHeader))
+			}
+		})
+	}
+}
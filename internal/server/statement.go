@@ -0,0 +1,178 @@
+package server
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// GetUserStatement отдаёт выписку за период from–to в JSON или CSV.
+// Выписка пишется в ответ по мере чтения из базы; если чтение прервалось
+// после начала ответа, клиент получит обрезанную выписку.
+func (h *Handler) GetUserStatement(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	var enc statementEncoder
+	switch query.Get("format") {
+	case "", "json":
+		enc = &jsonStatement{w: w}
+	case "csv":
+		enc = &csvStatement{w: w}
+	default:
+		http.Error(w, "format must be json or csv", http.StatusBadRequest)
+		return
+	}
+	from, err := parseStatementTime(query.Get("from"), false)
+	if err != nil {
+		http.Error(w, "invalid from", http.StatusBadRequest)
+		return
+	}
+	to := time.Now()
+	if query.Get("to") != "" {
+		if to, err = parseStatementTime(query.Get("to"), true); err != nil {
+			http.Error(w, "invalid to", http.StatusBadRequest)
+			return
+		}
+	}
+
+	started := false
+	err = h.serv.StreamStatement(r.Context(), principal.UserID, from, to, func(e models.StatementEntry) error {
+		if !started {
+			enc.start()
+			started = true
+		}
+		return enc.write(e)
+	})
+	if err != nil {
+		if !started {
+			statementError(w, err)
+			return
+		}
+		logger.Log.Error("Выписка прервана", zap.Int("user", principal.UserID), zap.Error(err))
+		return
+	}
+	if !started {
+		enc.start()
+	}
+	if err := enc.finish(); err != nil {
+		logger.Log.Error("Ошибка записи выписки", zap.Int("user", principal.UserID), zap.Error(err))
+	}
+}
+
+// getBalanceAt отдаёт баланс на момент из параметра at запроса баланса.
+func (h *Handler) getBalanceAt(w http.ResponseWriter, r *http.Request, userID int, value string) {
+	at, err := parseStatementTime(value, true)
+	if err != nil {
+		http.Error(w, "invalid at", http.StatusBadRequest)
+		return
+	}
+	balance, err := h.serv.GetBalanceAt(r.Context(), userID, at)
+	if err != nil {
+		statementError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, balance)
+}
+
+// parseStatementTime разбирает время в RFC 3339 или дату YYYY-MM-DD (UTC).
+// Дата как конец периода означает конец этого дня.
+func parseStatementTime(value string, end bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+func statementError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidPeriod):
+		http.Error(w, "invalid period", http.StatusBadRequest)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+type statementEncoder interface {
+	start()
+	write(e models.StatementEntry) error
+	finish() error
+}
+
+// jsonStatement пишет выписку JSON-массивом, по одной операции за раз.
+type jsonStatement struct {
+	w     http.ResponseWriter
+	comma bool
+}
+
+func (s *jsonStatement) start() {
+	s.w.Header().Set("Content-Type", "application/json")
+	s.w.WriteHeader(http.StatusOK)
+	s.w.Write([]byte("["))
+}
+
+func (s *jsonStatement) write(e models.StatementEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if s.comma {
+		data = append([]byte(","), data...)
+	}
+	s.comma = true
+	_, err = s.w.Write(data)
+	return err
+}
+
+func (s *jsonStatement) finish() error {
+	_, err := s.w.Write([]byte("]\n"))
+	return err
+}
+
+type csvStatement struct {
+	w   http.ResponseWriter
+	csv *csv.Writer
+}
+
+func (s *csvStatement) start() {
+	s.w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	s.w.Header().Set("Content-Disposition", `attachment; filename="statement.csv"`)
+	s.w.WriteHeader(http.StatusOK)
+	s.csv = csv.NewWriter(s.w)
+	s.csv.Write([]string{"processed_at", "type", "order", "amount", "balance"})
+}
+
+func (s *csvStatement) write(e models.StatementEntry) error {
+	return s.csv.Write([]string{
+		e.ProcessedAt.Format(time.RFC3339),
+		e.Type,
+		e.Order,
+		strconv.FormatFloat(e.Amount, 'f', 2, 64),
+		strconv.FormatFloat(e.Balance, 'f', 2, 64),
+	})
+}
+
+func (s *csvStatement) finish() error {
+	s.csv.Flush()
+	return s.csv.Error()
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestGetUserStatement(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	entries := []models.StatementEntry{
+		{Type: models.StatementAccrual, Order: "12345678903", Amount: 500, Balance: 700, ProcessedAt: from.Add(time.Hour)},
+		{Type: models.StatementWithdrawal, Order: "2377225624", Amount: -100, Balance: 600, ProcessedAt: from.Add(2 * time.Hour)},
+	}
+	stream := func(entries []models.StatementEntry, err error) func(serv *MockService) {
+		return func(serv *MockService) {
+			serv.On("StreamStatement", mock.Anything, 1, from, to, mock.Anything).
+				Run(func(args mock.Arguments) {
+					fn := args.Get(4).(func(models.StatementEntry) error)
+					for _, e := range entries {
+						fn(e)
+					}
+				}).Return(err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		mockSetup func(serv *MockService)
+		code      int
+		body      string
+	}{
+		{
+			name:      "json",
+			query:     "?from=2025-03-01&to=2025-03-31",
+			mockSetup: stream(entries, nil),
+			code:      http.StatusOK,
+			body: `[{"type":"ACCRUAL","order":"12345678903","amount":500,"balance":700,"processed_at":"2025-03-01T01:00:00Z"},` +
+				`{"type":"WITHDRAWAL","order":"2377225624","amount":-100,"balance":600,"processed_at":"2025-03-01T02:00:00Z"}]` + "\n",
+		},
+		{
+			name:      "csv",
+			query:     "?from=2025-03-01T00:00:00Z&to=2025-04-01T00:00:00Z&format=csv",
+			mockSetup: stream(entries, nil),
+			code:      http.StatusOK,
+			body: "processed_at,type,order,amount,balance\n" +
+				"2025-03-01T01:00:00Z,ACCRUAL,12345678903,500.00,700.00\n" +
+				"2025-03-01T02:00:00Z,WITHDRAWAL,2377225624,-100.00,600.00\n",
+		},
+		{
+			name:      "empty period",
+			query:     "?from=2025-03-01&to=2025-03-31",
+			mockSetup: stream(nil, nil),
+			code:      http.StatusOK,
+			body:      "[]\n",
+		},
+		{
+			name:      "error before first entry",
+			query:     "?from=2025-03-01&to=2025-03-31",
+			mockSetup: stream(nil, fmt.Errorf("db error")),
+			code:      http.StatusInternalServerError,
+		},
+		{
+			name:  "reversed period",
+			query: "?from=2025-04-01&to=2025-02-28",
+			mockSetup: func(serv *MockService) {
+				serv.On("StreamStatement", mock.Anything, 1, to, from, mock.Anything).Return(models.ErrInvalidPeriod)
+			},
+			code: http.StatusBadRequest,
+		},
+		{name: "unknown format", query: "?format=xml", mockSetup: func(serv *MockService) {}, code: http.StatusBadRequest},
+		{name: "invalid from", query: "?from=yesterday", mockSetup: func(serv *MockService) {}, code: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockService(t)
+			tt.mockSetup(mockService)
+			h := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/user/statement"+tt.query, nil)
+			req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+			w := httptest.NewRecorder()
+			h.GetUserStatement(w, req)
+
+			require.Equal(t, tt.code, w.Code)
+			if tt.body != "" {
+				require.Equal(t, tt.body, w.Body.String())
+			}
+		})
+	}
+}
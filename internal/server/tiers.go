@@ -0,0 +1,25 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func (h *Handler) GetUserTier(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+
+	tier, err := h.serv.GetUserTier(ctx, principal.UserID)
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(tier)
+}
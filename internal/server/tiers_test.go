@@ -0,0 +1,50 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestGetUserTier(t *testing.T) {
+	t.Run("tier found", func(t *testing.T) {
+		mockService := NewMockService(t)
+		status := models.TierStatus{Tier: "Silver", Multiplier: 1.25, Accrued: 3000, NextTier: "Gold", PointsToNext: 2000, Progress: 0.5}
+		mockService.On("GetUserTier", mock.Anything, 1).Return(status, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/user/tier", nil)
+		req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+		w := httptest.NewRecorder()
+
+		NewHandler(mockService).GetUserTier(w, req)
+
+		res := w.Result()
+		defer res.Body.Close()
+
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var got models.TierStatus
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		require.Equal(t, status, got)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockService := NewMockService(t)
+		mockService.On("GetUserTier", mock.Anything, 1).Return(models.TierStatus{}, errors.New("db error"))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/user/tier", nil)
+		req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+		w := httptest.NewRecorder()
+
+		NewHandler(mockService).GetUserTier(w, req)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/scoring-service/internal/service"
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+func (h *Handler) Transfer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.TransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+	if req.Recipient == "" {
+		http.Error(w, "recipient is required", http.StatusBadRequest)
+		return
+	}
+	if req.Sum <= 0 {
+		http.Error(w, "sum must be greater than zero", http.StatusBadRequest)
+		return
+	}
+
+	status := h.serv.CreateTransfer(ctx, principal.UserID, req)
+	switch status {
+	case service.StatusOK:
+		w.WriteHeader(http.StatusOK)
+	case service.StatusNotFound:
+		http.Error(w, "recipient not found", http.StatusNotFound)
+	case service.StatusInvalid:
+		http.Error(w, "cannot transfer to yourself", http.StatusUnprocessableEntity)
+	case service.StatusConflict:
+		http.Error(w, "insufficient funds", http.StatusPaymentRequired)
+	case service.StatusLimitExceeded:
+		http.Error(w, "daily transfer limit exceeded", http.StatusForbidden)
+	case service.StatusTOTPRequired:
+		http.Error(w, "two-factor code required", http.StatusForbidden)
+	case service.StatusThrottled:
+		http.Error(w, "too many invalid two-factor codes, try again later", http.StatusTooManyRequests)
+	case service.StatusError:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	default:
+		logger.Log.Sugar().Error("Unknown status ", status)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) GetUserTransfers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+
+	transfers, err := h.serv.GetUserTransfers(ctx, principal.UserID)
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if len(transfers) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(transfers)
+}
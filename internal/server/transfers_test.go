@@ -0,0 +1,160 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/internal/service"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestTransfer(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		userID    any
+		mockSetup func(serv *MockService)
+		code      int
+	}{
+		{
+			name:   "successful transfer",
+			body:   `{"recipient":"bob", "sum":50}`,
+			userID: 1,
+			mockSetup: func(serv *MockService) {
+				serv.On("CreateTransfer", mock.Anything, 1, models.TransferRequest{Recipient: "bob", Sum: 50}).
+					Return(service.StatusOK)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "recipient not found",
+			body:   `{"recipient":"nobody", "sum":50}`,
+			userID: 1,
+			mockSetup: func(serv *MockService) {
+				serv.On("CreateTransfer", mock.Anything, 1, mock.Anything).Return(service.StatusNotFound)
+			},
+			code: http.StatusNotFound,
+		},
+		{
+			name:   "insufficient funds",
+			body:   `{"recipient":"bob", "sum":5000}`,
+			userID: 1,
+			mockSetup: func(serv *MockService) {
+				serv.On("CreateTransfer", mock.Anything, 1, mock.Anything).Return(service.StatusConflict)
+			},
+			code: http.StatusPaymentRequired,
+		},
+		{
+			name:   "daily limit exceeded",
+			body:   `{"recipient":"bob", "sum":50}`,
+			userID: 1,
+			mockSetup: func(serv *MockService) {
+				serv.On("CreateTransfer", mock.Anything, 1, mock.Anything).Return(service.StatusLimitExceeded)
+			},
+			code: http.StatusForbidden,
+		},
+		{
+			name:   "self transfer",
+			body:   `{"recipient":"alice", "sum":50}`,
+			userID: 1,
+			mockSetup: func(serv *MockService) {
+				serv.On("CreateTransfer", mock.Anything, 1, mock.Anything).Return(service.StatusInvalid)
+			},
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name:      "missing recipient",
+			body:      `{"sum":50}`,
+			userID:    1,
+			mockSetup: func(serv *MockService) {},
+			code:      http.StatusBadRequest,
+		},
+		{
+			name:      "unauthorized user",
+			body:      `{"recipient":"bob", "sum":50}`,
+			mockSetup: func(serv *MockService) {},
+			code:      http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockService(t)
+			tt.mockSetup(mockService)
+
+			h := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/user/balance/transfer", strings.NewReader(tt.body))
+			if tt.userID != nil {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: tt.userID.(int)}))
+			}
+			w := httptest.NewRecorder()
+
+			h.Transfer(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			require.Equal(t, tt.code, res.StatusCode)
+		})
+	}
+}
+func TestGetUserTransfers(t *testing.T) {
+	tests := []struct {
+		name      string
+		mockSetup func(serv *MockService)
+		code      int
+	}{
+		{
+			name: "transfers found",
+			mockSetup: func(serv *MockService) {
+				serv.On("GetUserTransfers", mock.Anything, 1).Return([]models.Transfer{
+					{Direction: models.TransferOut, Counterparty: "bob", Sum: 50, ProcessedAt: time.Now()},
+				}, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "no transfers",
+			mockSetup: func(serv *MockService) {
+				serv.On("GetUserTransfers", mock.Anything, 1).Return(nil, nil)
+			},
+			code: http.StatusNoContent,
+		},
+		{
+			name: "service error",
+			mockSetup: func(serv *MockService) {
+				serv.On("GetUserTransfers", mock.Anything, 1).Return(nil, errors.New("db error"))
+			},
+			code: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockService(t)
+			tt.mockSetup(mockService)
+
+			h := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/user/transfers", nil)
+			req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+			w := httptest.NewRecorder()
+
+			h.GetUserTransfers(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			require.Equal(t, tt.code, res.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/scoring-service/internal/service"
+	"github.com/scoring-service/pkg/models"
+)
+
+func (h *Handler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	enrollment, err := h.serv.EnrollTOTP(r.Context(), principal.UserID)
+	if err != nil {
+		twoFactorError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, enrollment)
+}
+
+// ConfirmTOTP включает 2FA первым кодом из приложения и отдаёт коды
+// восстановления — больше они нигде не показываются.
+func (h *Handler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	var req models.TOTPCode
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		http.Error(w, "Не указан код подтверждения", http.StatusBadRequest)
+		return
+	}
+	codes, err := h.serv.ConfirmTOTP(r.Context(), principal.UserID, req.Code)
+	if err != nil {
+		twoFactorError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, models.RecoveryCodes{Codes: codes})
+}
+
+func (h *Handler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	var req models.TOTPCode
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		http.Error(w, "Не указан код подтверждения", http.StatusBadRequest)
+		return
+	}
+	if err := h.serv.DisableTOTP(r.Context(), principal.UserID, req.Code); err != nil {
+		twoFactorError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) UpdateTwoFactorSettings(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	var req models.TwoFactorSettings
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	if err := h.serv.SetTwoFactorSettings(r.Context(), principal.UserID, req); err != nil {
+		twoFactorError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// CompleteLogin — второй шаг входа: токен из ответа /api/user/login и код
+// TOTP или код восстановления.
+func (h *Handler) CompleteLogin(w http.ResponseWriter, r *http.Request) {
+	var req models.TwoFactorLogin
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+	if req.ChallengeToken == "" || req.Code == "" {
+		http.Error(w, "Не указан токен входа или код подтверждения", http.StatusBadRequest)
+		return
+	}
+	meta := sessionMeta(r)
+	user, err := h.serv.CompleteLoginChallenge(r.Context(), req.ChallengeToken, req.Code, meta.IP)
+	if err != nil {
+		var throttled *service.ThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.Wait.Seconds()))))
+			http.Error(w, "Слишком много попыток входа, повторите позже", http.StatusTooManyRequests)
+		case errors.Is(err, models.ErrInvalidChallenge):
+			http.Error(w, "Недействительный или просроченный токен входа", http.StatusUnauthorized)
+		case errors.Is(err, models.ErrInvalidTOTP):
+			http.Error(w, "Неверный код подтверждения", http.StatusUnauthorized)
+		case errors.Is(err, models.ErrUserBlocked):
+			http.Error(w, "Учётная запись заблокирована", http.StatusForbidden)
+		default:
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+	refreshToken, err := h.serv.StartSession(r.Context(), user, meta)
+	if err != nil {
+		http.Error(w, "Ошибка при создании сессии", http.StatusInternalServerError)
+		return
+	}
+	if _, err := writeTokens(w, user, refreshToken); err != nil {
+		http.Error(w, "Ошибка при генерации токена", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Пользователь успешно аутентифицирован"))
+}
+
+func twoFactorError(w http.ResponseWriter, err error) {
+	var throttled *service.ThrottledError
+	switch {
+	case errors.As(err, &throttled):
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.Wait.Seconds()))))
+		http.Error(w, "Слишком много неверных кодов, повторите позже", http.StatusTooManyRequests)
+	case errors.Is(err, models.ErrInvalidTOTP):
+		http.Error(w, "Неверный код подтверждения", http.StatusForbidden)
+	case errors.Is(err, models.ErrTOTPNotEnabled):
+		http.Error(w, "Двухфакторная аутентификация не подключена", http.StatusConflict)
+	case errors.Is(err, models.ErrTOTPAlreadyEnabled):
+		http.Error(w, "Двухфакторная аутентификация уже подключена", http.StatusConflict)
+	case errors.Is(err, service.ErrInvalidAmount):
+		http.Error(w, "Порог списания не может быть отрицательным", http.StatusBadRequest)
+	case errors.Is(err, models.ErrUserNotFound):
+		http.Error(w, "Пользователь не найден", http.StatusNotFound)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
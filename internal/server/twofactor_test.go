@@ -0,0 +1,142 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/internal/service"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestConfirmTOTP(t *testing.T) {
+	mockService := NewMockService(t)
+	mockService.On("ConfirmTOTP", mock.Anything, 1, "123456").Return([]string{"abcd-efgh"}, nil)
+	h := NewHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/2fa/confirm", strings.NewReader(`{"code": "123456"}`))
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+	w := httptest.NewRecorder()
+
+	h.ConfirmTOTP(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body models.RecoveryCodes
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Equal(t, []string{"abcd-efgh"}, body.Codes)
+}
+func TestCompleteLogin(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		mockSetup  func(serv *MockService)
+		code       int
+		authHeader bool
+	}{
+		{
+			name: "valid code",
+			body: `{"challenge_token": "challenge", "code": "123456"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("CompleteLoginChallenge", mock.Anything, "challenge", "123456", mock.Anything).
+					Return(&models.User{ID: 1, Login: "alice"}, nil)
+				serv.On("StartSession", mock.Anything, mock.Anything, mock.Anything).Return("refresh", nil)
+			},
+			code:       http.StatusOK,
+			authHeader: true,
+		},
+		{
+			name: "wrong code",
+			body: `{"challenge_token": "challenge", "code": "000000"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("CompleteLoginChallenge", mock.Anything, "challenge", "000000", mock.Anything).
+					Return(nil, models.ErrInvalidTOTP)
+			},
+			code: http.StatusUnauthorized,
+		},
+		{
+			name: "throttled",
+			body: `{"challenge_token": "challenge", "code": "000000"}`,
+			mockSetup: func(serv *MockService) {
+				serv.On("CompleteLoginChallenge", mock.Anything, "challenge", "000000", mock.Anything).
+					Return(nil, &service.ThrottledError{Wait: 30 * time.Second})
+			},
+			code: http.StatusTooManyRequests,
+		},
+		{
+			name:      "missing code",
+			body:      `{"challenge_token": "challenge"}`,
+			mockSetup: func(serv *MockService) {},
+			code:      http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockService(t)
+			tt.mockSetup(mockService)
+			h := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/user/login/2fa", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			h.CompleteLogin(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+			require.Equal(t, tt.code, resp.StatusCode)
+			if tt.authHeader {
+				require.NotEmpty(t, resp.Header.Get("Authorization"))
+				require.Empty(t, resp.Header.Get(refreshTokenHeader))
+			}
+		})
+	}
+}
+func TestWithdrawTOTPRequired(t *testing.T) {
+	mockService := NewMockService(t)
+	mockService.On("CreateWithdraw", mock.Anything, 1, models.Withdraw{Order: "79927398713", Sum: 600}).
+		Return(service.StatusTOTPRequired)
+	h := NewHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/balance/withdraw", strings.NewReader(`{"order": "79927398713", "sum": 600}`))
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+	w := httptest.NewRecorder()
+
+	h.Withdraw(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+func TestSecondFactorThrottled(t *testing.T) {
+	mockService := NewMockService(t)
+	mockService.On("CreateWithdraw", mock.Anything, 1, models.Withdraw{Order: "79927398713", Sum: 600, TOTP: "000000"}).
+		Return(service.StatusThrottled)
+	mockService.On("DisableTOTP", mock.Anything, 1, "000000").
+		Return(&service.ThrottledError{Wait: 90 * time.Second})
+	h := NewHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/balance/withdraw",
+		strings.NewReader(`{"order": "79927398713", "sum": 600, "totp_code": "000000"}`))
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+	w := httptest.NewRecorder()
+	h.Withdraw(w, req)
+	require.Equal(t, http.StatusTooManyRequests, w.Result().StatusCode)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/user/2fa", strings.NewReader(`{"code": "000000"}`))
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 1}))
+	w = httptest.NewRecorder()
+	h.DisableTOTP(w, req)
+	resp := w.Result()
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	require.Equal(t, "90", resp.Header.Get("Retry-After"))
+}
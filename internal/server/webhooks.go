@@ -0,0 +1,111 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func (h *Handler) CreateWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requireMerchant(w, r)
+	if !ok {
+		return
+	}
+	var req models.WebhookSubscription
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+	sub, err := h.serv.CreateWebhookSubscription(r.Context(), principal.MerchantID, req)
+	if err != nil {
+		webhookError(w, err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusCreated, sub)
+}
+
+func (h *Handler) GetWebhookSubscriptions(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requireMerchant(w, r)
+	if !ok {
+		return
+	}
+	subs, err := h.serv.GetWebhookSubscriptions(r.Context(), principal.MerchantID)
+	if err != nil {
+		webhookError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, subs)
+}
+
+func (h *Handler) DeleteWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requireMerchant(w, r)
+	if !ok {
+		return
+	}
+	id, ok := webhookID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.serv.DeleteWebhookSubscription(r.Context(), principal.MerchantID, id); err != nil {
+		webhookError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries отдаёт журнал доставок; ?status=DEAD показывает
+// доставки, которые нужно повторить вручную.
+func (h *Handler) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requireMerchant(w, r)
+	if !ok {
+		return
+	}
+	deliveries, err := h.serv.GetWebhookDeliveries(r.Context(), principal.MerchantID, r.URL.Query().Get("status"))
+	if err != nil {
+		webhookError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, deliveries)
+}
+
+func (h *Handler) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requireMerchant(w, r)
+	if !ok {
+		return
+	}
+	id, ok := webhookID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.serv.RedeliverWebhook(r.Context(), principal.MerchantID, id); err != nil {
+		webhookError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func webhookID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func webhookError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidWebhook):
+		http.Error(w, "invalid url, event type or status", http.StatusBadRequest)
+	case errors.Is(err, models.ErrWebhookNotFound):
+		http.Error(w, "not found", http.StatusNotFound)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+func merchantRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	return req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: 7, MerchantID: 3}))
+}
+
+func TestCreateWebhookSubscription(t *testing.T) {
+	mockService := NewMockService(t)
+	mockService.On("CreateWebhookSubscription", mock.Anything, 3, models.WebhookSubscription{
+		URL: "https://shop.example/hooks", Events: []string{models.WebhookOrderProcessed},
+	}).Return(models.WebhookSubscription{
+		ID: 5, URL: "https://shop.example/hooks", Events: []string{models.WebhookOrderProcessed}, Secret: "secret",
+	}, nil)
+	mockService.On("CreateWebhookSubscription", mock.Anything, 3, mock.Anything).
+		Return(models.WebhookSubscription{}, models.ErrInvalidWebhook)
+	h := NewHandler(mockService)
+
+	w := httptest.NewRecorder()
+	h.CreateWebhookSubscription(w, merchantRequest(http.MethodPost, "/api/merchant/webhooks",
+		`{"url": "https://shop.example/hooks", "events": ["order.processed"]}`))
+	require.Equal(t, http.StatusCreated, w.Code)
+	var sub models.WebhookSubscription
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&sub))
+	require.Equal(t, "secret", sub.Secret)
+
+	w = httptest.NewRecorder()
+	h.CreateWebhookSubscription(w, merchantRequest(http.MethodPost, "/api/merchant/webhooks",
+		`{"url": "ftp://shop.example", "events": ["order.created"]}`))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetWebhookDeliveries(t *testing.T) {
+	mockService := NewMockService(t)
+	mockService.On("GetWebhookDeliveries", mock.Anything, 3, models.DeliveryDead).
+		Return([]models.WebhookDelivery{{ID: 1, Event: models.WebhookOrderProcessed, Status: models.DeliveryDead, Attempts: 8}}, nil)
+	h := NewHandler(mockService)
+
+	w := httptest.NewRecorder()
+	h.GetWebhookDeliveries(w, merchantRequest(http.MethodGet, "/api/merchant/webhooks/deliveries?status=DEAD", ""))
+	require.Equal(t, http.StatusOK, w.Code)
+	var deliveries []models.WebhookDelivery
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&deliveries))
+	require.Equal(t, 8, deliveries[0].Attempts)
+}
+
+func TestRedeliverWebhook(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		err  error
+		code int
+	}{
+		{name: "queued", id: "5", code: http.StatusAccepted},
+		{name: "foreign delivery", id: "6", err: models.ErrWebhookNotFound, code: http.StatusNotFound},
+		{name: "bad id", id: "x", code: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockService(t)
+			if tt.code != http.StatusBadRequest {
+				mockService.On("RedeliverWebhook", mock.Anything, 3, mock.Anything).Return(tt.err)
+			}
+			h := NewHandler(mockService)
+
+			req := merchantRequest(http.MethodPost, "/api/merchant/webhooks/deliveries/"+tt.id+"/redeliver", "")
+			w := httptest.NewRecorder()
+			h.RedeliverWebhook(w, withURLParam(req, "id", tt.id))
+			require.Equal(t, tt.code, w.Code)
+		})
+	}
+}
+
+func TestDeleteWebhookSubscription(t *testing.T) {
+	mockService := NewMockService(t)
+	mockService.On("DeleteWebhookSubscription", mock.Anything, 3, 5).Return(nil)
+	h := NewHandler(mockService)
+
+	w := httptest.NewRecorder()
+	h.DeleteWebhookSubscription(w, withURLParam(merchantRequest(http.MethodDelete, "/api/merchant/webhooks/5", ""), "id", "5"))
+	require.Equal(t, http.StatusNoContent, w.Code)
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// Notifier доставляет пользователю служебные сообщения, например токен
+// сброса пароля.
+type Notifier interface {
+	Notify(ctx context.Context, n models.Notification) error
+}
+
+func (s *AccrualService) SetNotifier(notifier Notifier) {
+	s.notifier = notifier
+}
+
+// ChangePassword меняет пароль после проверки текущего и завершает все
+// сессии, кроме той, из которой пришёл запрос.
+func (s *AccrualService) ChangePassword(ctx context.Context, principal *auth.Principal, change models.PasswordChange) error {
+	hash, err := s.db.GetPasswordHash(ctx, principal.UserID)
+	if err != nil {
+		return err
+	}
+	if !auth.CheckPasswordHash(change.CurrentPassword, hash) {
+		return models.ErrInvalidCredentials
+	}
+	if change.NewPassword == change.CurrentPassword {
+		return fmt.Errorf("%w: новый пароль совпадает с текущим", models.ErrWeakPassword)
+	}
+	if err := s.validatePassword(change.NewPassword); err != nil {
+		return err
+	}
+	newHash, err := auth.HashPassword(change.NewPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.db.UpdatePasswordHash(ctx, principal.UserID, newHash); err != nil {
+		return err
+	}
+	return s.RevokeOtherSessions(ctx, principal.UserID, principal.SessionID)
+}
+
+// RequestPasswordReset выпускает токен сброса и отправляет его владельцу.
+// Для неизвестного или заблокированного логина ничего не происходит, чтобы
+// по ответу нельзя было судить о существовании учётной записи. Запросы сверх
+// PasswordResetThrottle молча отбрасываются.
+func (s *AccrualService) RequestPasswordReset(ctx context.Context, login string) error {
+	throttle, err := s.db.GetPasswordResetThrottle(ctx, login)
+	if err != nil {
+		return err
+	}
+	if throttleWait(throttle, s.cfg.PasswordResetThrottle, time.Now()) > 0 {
+		logger.Log.Warn("Слишком много запросов сброса пароля", zap.String("login", login))
+		return nil
+	}
+	if err := s.db.RecordPasswordResetRequest(ctx, login, s.cfg.PasswordResetThrottle); err != nil {
+		return err
+	}
+	user, err := s.db.GetUserByLogin(ctx, login)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.Blocked {
+		logger.Log.Info("Сброс пароля для неизвестного или заблокированного логина", zap.String("login", login))
+		return nil
+	}
+	token, err := auth.GenerateResetToken()
+	if err != nil {
+		return err
+	}
+	if err := s.db.CreatePasswordReset(ctx, user.ID, auth.HashToken(token), s.cfg.PasswordResetTTL); err != nil {
+		return err
+	}
+	if s.notifier == nil {
+		return fmt.Errorf("не настроена доставка уведомлений")
+	}
+	prefs, err := s.db.GetNotificationPreferences(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	return s.notifyNow(ctx, models.Notification{
+		Kind:      models.NotificationPasswordReset,
+		UserID:    user.ID,
+		Login:     user.Login,
+		Email:     prefs.Email,
+		Locale:    prefs.Locale,
+		Token:     token,
+		ExpiresAt: time.Now().Add(s.cfg.PasswordResetTTL),
+	})
+}
+
+// ResetPassword задаёт новый пароль по токену сброса. Токен одноразовый,
+// все сессии пользователя завершаются.
+func (s *AccrualService) ResetPassword(ctx context.Context, reset models.PasswordReset) error {
+	if reset.Token == "" {
+		return models.ErrInvalidResetToken
+	}
+	if err := s.validatePassword(reset.NewPassword); err != nil {
+		return err
+	}
+	hash, err := auth.HashPassword(reset.NewPassword)
+	if err != nil {
+		return err
+	}
+	userID, err := s.db.ResetPassword(ctx, auth.HashToken(reset.Token), hash)
+	if err != nil {
+		return err
+	}
+	logger.Log.Info("Пароль сброшен", zap.Int("user", userID))
+	return nil
+}
+
+// DeleteAccount закрывает учётную запись после подтверждения паролем.
+func (s *AccrualService) DeleteAccount(ctx context.Context, userID int, password string) error {
+	hash, err := s.db.GetPasswordHash(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !auth.CheckPasswordHash(password, hash) {
+		return models.ErrInvalidCredentials
+	}
+	if err := s.db.DeleteUser(ctx, userID); err != nil {
+		return err
+	}
+	s.blocked.forget(userID)
+	logger.Log.Info("Учётная запись удалена", zap.Int("user", userID))
+	return nil
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+type notifierStub struct {
+	sent []models.Notification
+}
+
+func (n *notifierStub) Notify(ctx context.Context, notification models.Notification) error {
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+func TestChangePassword(t *testing.T) {
+	hash, err := auth.HashPassword("old-secret")
+	require.NoError(t, err)
+	principal := &auth.Principal{UserID: 1, SessionID: 10}
+
+	t.Run("успешная смена", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB, cfg: Config{Password: models.PasswordPolicy{MinLength: 8}}}
+
+		mockDB.EXPECT().GetPasswordHash(mock.Anything, 1).Return(hash, nil).Once()
+		mockDB.EXPECT().UpdatePasswordHash(mock.Anything, 1, mock.MatchedBy(func(h string) bool {
+			return auth.CheckPasswordHash("new-secret", h)
+		})).Return(nil).Once()
+		mockDB.EXPECT().RevokeOtherSessions(mock.Anything, 1, 10).Return(nil).Once()
+
+		err := service.ChangePassword(context.Background(), principal, models.PasswordChange{
+			CurrentPassword: "old-secret",
+			NewPassword:     "new-secret",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("неверный текущий пароль", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		mockDB.EXPECT().GetPasswordHash(mock.Anything, 1).Return(hash, nil).Once()
+
+		err := service.ChangePassword(context.Background(), principal, models.PasswordChange{
+			CurrentPassword: "guess",
+			NewPassword:     "new-secret",
+		})
+		require.ErrorIs(t, err, models.ErrInvalidCredentials)
+	})
+
+	t.Run("новый пароль не проходит политику", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB, cfg: Config{Password: models.PasswordPolicy{MinLength: 12}}}
+
+		mockDB.EXPECT().GetPasswordHash(mock.Anything, 1).Return(hash, nil).Twice()
+
+		err := service.ChangePassword(context.Background(), principal, models.PasswordChange{
+			CurrentPassword: "old-secret",
+			NewPassword:     "short",
+		})
+		require.ErrorIs(t, err, models.ErrWeakPassword)
+
+		err = service.ChangePassword(context.Background(), principal, models.PasswordChange{
+			CurrentPassword: "old-secret",
+			NewPassword:     "old-secret",
+		})
+		require.ErrorIs(t, err, models.ErrWeakPassword)
+	})
+}
+func TestRequestPasswordReset(t *testing.T) {
+	t.Run("токен отправляется владельцу", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		notifier := &notifierStub{}
+		service := &AccrualService{db: mockDB, notifier: notifier, cfg: Config{PasswordResetTTL: time.Hour}}
+
+		var storedHash string
+		mockDB.EXPECT().GetPasswordResetThrottle(mock.Anything, "alice").Return(models.LoginThrottle{}, nil).Once()
+		mockDB.EXPECT().RecordPasswordResetRequest(mock.Anything, "alice", models.LoginPolicy{}).Return(nil).Once()
+		mockDB.EXPECT().GetUserByLogin(mock.Anything, "alice").
+			Return(&models.User{ID: 3, Login: "alice"}, nil).Once()
+		mockDB.EXPECT().CreatePasswordReset(mock.Anything, 3, mock.Anything, time.Hour).
+			Run(func(ctx context.Context, userID int, tokenHash string, ttl time.Duration) {
+				storedHash = tokenHash
+			}).Return(nil).Once()
+		mockDB.EXPECT().GetNotificationPreferences(mock.Anything, 3).
+			Return(models.NotificationPreferences{Login: "alice", Email: "alice@example.com", Locale: models.LocaleEN}, nil).Once()
+
+		require.NoError(t, service.RequestPasswordReset(context.Background(), "alice"))
+		require.Len(t, notifier.sent, 1)
+		require.Equal(t, models.NotificationPasswordReset, notifier.sent[0].Kind)
+		require.Equal(t, "alice@example.com", notifier.sent[0].Email)
+		require.Equal(t, models.LocaleEN, notifier.sent[0].Locale)
+		require.Equal(t, auth.HashToken(notifier.sent[0].Token), storedHash)
+	})
+
+	t.Run("неизвестный логин", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		notifier := &notifierStub{}
+		service := &AccrualService{db: mockDB, notifier: notifier}
+
+		mockDB.EXPECT().GetPasswordResetThrottle(mock.Anything, "ghost").Return(models.LoginThrottle{}, nil).Once()
+		mockDB.EXPECT().RecordPasswordResetRequest(mock.Anything, "ghost", models.LoginPolicy{}).Return(nil).Once()
+		mockDB.EXPECT().GetUserByLogin(mock.Anything, "ghost").Return(nil, nil).Once()
+
+		require.NoError(t, service.RequestPasswordReset(context.Background(), "ghost"))
+		require.Empty(t, notifier.sent)
+	})
+
+	t.Run("слишком много запросов", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		notifier := &notifierStub{}
+		policy := models.LoginPolicy{MaxFailures: 3, Lockout: time.Hour, Window: time.Hour}
+		service := &AccrualService{db: mockDB, notifier: notifier, cfg: Config{PasswordResetThrottle: policy}}
+
+		mockDB.EXPECT().GetPasswordResetThrottle(mock.Anything, "alice").
+			Return(models.LoginThrottle{Failures: 3, LastFailureAt: time.Now(), LockedUntil: time.Now().Add(time.Hour)}, nil).Once()
+
+		require.NoError(t, service.RequestPasswordReset(context.Background(), "alice"))
+		require.Empty(t, notifier.sent)
+	})
+}
+func TestRegisterReservedLogin(t *testing.T) {
+	service := &AccrualService{db: NewMockStorage(t)}
+	user := &models.User{Login: models.DeletedLoginPrefix + "3", Password: "secret"}
+	require.ErrorIs(t, service.ReagisterUser(context.Background(), user), models.ErrReservedLogin)
+}
+func TestResetPassword(t *testing.T) {
+	t.Run("успешный сброс", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		mockDB.EXPECT().ResetPassword(mock.Anything, auth.HashToken("token"), mock.MatchedBy(func(h string) bool {
+			return auth.CheckPasswordHash("brand-new", h)
+		})).Return(3, nil).Once()
+
+		require.NoError(t, service.ResetPassword(context.Background(), models.PasswordReset{Token: "token", NewPassword: "brand-new"}))
+	})
+
+	t.Run("слабый пароль не гасит токен", func(t *testing.T) {
+		service := &AccrualService{db: NewMockStorage(t), cfg: Config{Password: models.PasswordPolicy{MinLength: 8}}}
+
+		err := service.ResetPassword(context.Background(), models.PasswordReset{Token: "token", NewPassword: "short"})
+		require.ErrorIs(t, err, models.ErrWeakPassword)
+	})
+}
+func TestDeleteAccount(t *testing.T) {
+	hash, err := auth.HashPassword("secret")
+	require.NoError(t, err)
+
+	t.Run("успешное удаление", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		mockDB.EXPECT().GetPasswordHash(mock.Anything, 5).Return(hash, nil).Once()
+		mockDB.EXPECT().DeleteUser(mock.Anything, 5).Return(nil).Once()
+
+		require.NoError(t, service.DeleteAccount(context.Background(), 5, "secret"))
+	})
+
+	t.Run("неверный пароль", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		mockDB.EXPECT().GetPasswordHash(mock.Anything, 5).Return(hash, nil).Once()
+
+		require.ErrorIs(t, service.DeleteAccount(context.Background(), 5, "guess"), models.ErrInvalidCredentials)
+	})
+}
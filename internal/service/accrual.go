@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/scoring-service/internal/accrual"
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// AccrualProvider рассчитывает начисление за заказ. Результат записывается
+// через UpdateOrder одинаково для любого источника.
+type AccrualProvider interface {
+	GetAccrual(ctx context.Context, orderNumber string) (*models.AccrualResponse, error)
+}
+
+// SetAccrualProvider заменяет внешнюю систему расчёта начислений. Без
+// провайдера заказы опрашиваются по адресу ACCRUAL_SYSTEM_ADDRESS.
+func (s *AccrualService) SetAccrualProvider(provider AccrualProvider) {
+	s.provider = provider
+}
+
+// HTTPAccrualProvider опрашивает внешнюю систему расчёта начислений.
+type HTTPAccrualProvider struct {
+	Client *http.Client
+	URL    string
+}
+
+func (p *HTTPAccrualProvider) GetAccrual(ctx context.Context, orderNumber string) (*models.AccrualResponse, error) {
+	attempts := 0
+	maxAttempts := 5
+	backoff := time.Second
+	for {
+		if attempts >= maxAttempts {
+			logger.Log.Error("max retries reached")
+			return nil, errors.New("max retries reached")
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		u, err := url.Parse(p.URL)
+		if err != nil {
+			return nil, fmt.Errorf("неверный API URL: %w", err)
+		}
+		u.Path = path.Join(u.Path, "api/orders", orderNumber)
+
+		resp, err := p.Client.Get(u.String())
+		if err != nil {
+			logger.Log.Error(err.Error())
+
+			time.Sleep(backoff)
+			backoff *= 2
+			attempts++
+			continue
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusNoContent {
+			logger.Log.Error("order not registered")
+			return nil, errors.New("order not registered")
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			retryKey := resp.Header.Get("Retry-After")
+			if retryKey != "" {
+				retry, err := strconv.Atoi(retryKey)
+				if err == nil {
+					backoff = time.Second
+					time.Sleep(time.Second * time.Duration(retry))
+				}
+
+			}
+			continue
+		}
+
+		if resp.StatusCode == http.StatusInternalServerError {
+			logger.Log.Error("internal server error")
+			return nil, errors.New("internal server error")
+		}
+
+		var accrual models.AccrualResponse
+		if err := json.NewDecoder(resp.Body).Decode(&accrual); err != nil {
+			logger.Log.Error(err.Error())
+			return nil, err
+		}
+		return &accrual, nil
+	}
+}
+
+// LocalAccrualProvider считает начисления сам по позициям чека, сохранённым
+// вместе с заказом. Заказ без позиций считается недействительным.
+type LocalAccrualProvider struct {
+	db    Storage
+	rules []models.AccrualRule
+}
+
+func NewLocalAccrualProvider(db Storage, rules []models.AccrualRule) *LocalAccrualProvider {
+	return &LocalAccrualProvider{db: db, rules: rules}
+}
+
+func (p *LocalAccrualProvider) GetAccrual(ctx context.Context, orderNumber string) (*models.AccrualResponse, error) {
+	items, err := p.db.GetOrderItems(ctx, orderNumber)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return &models.AccrualResponse{Order: orderNumber, Status: models.OrderInvalid}, nil
+	}
+	return &models.AccrualResponse{
+		Order:   orderNumber,
+		Status:  models.OrderProcessed,
+		Accrual: accrual.Calculate(p.rules, items),
+	}, nil
+}
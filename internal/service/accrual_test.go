@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestLocalAccrualProvider(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	provider := NewLocalAccrualProvider(mockDB, []models.AccrualRule{
+		{Name: "База", Percent: 1},
+		{Name: "Молочка", Category: "dairy", Percent: 5, Bonus: 10},
+	})
+
+	mockDB.EXPECT().GetOrderItems(mock.Anything, "12345678903").Return([]models.OrderItem{
+		{Name: "Молоко", Category: "dairy", Price: 80, Quantity: 2},
+		{Name: "Хлеб", Price: 50},
+	}, nil).Once()
+	got, err := provider.GetAccrual(context.Background(), "12345678903")
+	require.NoError(t, err)
+	require.Equal(t, &models.AccrualResponse{Order: "12345678903", Status: models.OrderProcessed, Accrual: 18.5}, got)
+
+	mockDB.EXPECT().GetOrderItems(mock.Anything, "79927398713").Return(nil, nil).Once()
+	got, err = provider.GetAccrual(context.Background(), "79927398713")
+	require.NoError(t, err)
+	require.Equal(t, models.OrderInvalid, got.Status)
+}
+func TestFetchAccrualWithProvider(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB}
+	service.SetAccrualProvider(NewLocalAccrualProvider(mockDB, []models.AccrualRule{{Name: "База", Percent: 10}}))
+
+	mockDB.EXPECT().GetOrderItems(mock.Anything, "12345678903").
+		Return([]models.OrderItem{{Name: "Хлеб", Price: 50}}, nil).Once()
+	mockDB.EXPECT().UpdateOrder(mock.Anything,
+		&models.AccrualResponse{Order: "12345678903", Status: models.OrderProcessed, Accrual: 5}, mock.Anything).
+		Return(nil).Once()
+
+	require.NoError(t, service.FetchAccrual(context.Background(), "12345678903"))
+}
+func TestCreateOrderWithItems(t *testing.T) {
+	items := []models.OrderItem{{Name: "Хлеб", Price: 50}}
+	order := &models.Order{Number: "12345678903", Status: models.OrderNew}
+
+	for saveErr, want := range map[error]CreateStatus{
+		nil:                     StatusOK,
+		models.ErrOrderExists:   StatusAlreadyExist,
+		models.ErrOrderConflict: StatusConflict,
+	} {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		mockDB.EXPECT().IsOrderExists(mock.Anything, "12345678903").Return(0, nil).Once()
+		mockDB.EXPECT().SaveOrderWithItems(mock.Anything, 1, order, items).Return(saveErr).Once()
+
+		require.Equal(t, want, service.createOrder(context.Background(), 1, "12345678903", items))
+	}
+}
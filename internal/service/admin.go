@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+const adminSearchLimit = 50
+
+var (
+	ErrReasonRequired = errors.New("необходимо указать причину")
+	ErrInvalidAmount  = errors.New("некорректная сумма")
+	ErrQueueNotReady  = errors.New("очередь опроса заказов не настроена")
+	ErrUnknownRole    = errors.New("неизвестная роль")
+	ErrUnknownPerm    = errors.New("неизвестное право доступа")
+)
+
+func (s *AccrualService) SearchUsers(ctx context.Context, query string) ([]models.AdminUser, error) {
+	return s.db.SearchUsers(ctx, strings.TrimSpace(query), adminSearchLimit)
+}
+
+func (s *AccrualService) GetUserView(ctx context.Context, userID int) (models.AdminUserView, error) {
+	var view models.AdminUserView
+	user, err := s.db.GetAdminUser(ctx, userID)
+	if err != nil {
+		return view, err
+	}
+	view.User = user
+	if view.Permissions, err = s.db.GetUserPermissions(ctx, userID); err != nil {
+		return view, err
+	}
+	if view.Orders, err = s.db.GetUserOrders(ctx, userID); err != nil {
+		return view, err
+	}
+	if view.Withdrawals, err = s.db.GetUserWithdrawals(ctx, userID); err != nil {
+		return view, err
+	}
+	return view, nil
+}
+
+func (s *AccrualService) AdjustBalance(ctx context.Context, adminID, userID int, adjustment models.Adjustment) error {
+	adjustment.Reason = strings.TrimSpace(adjustment.Reason)
+	if adjustment.Reason == "" {
+		return ErrReasonRequired
+	}
+	if adjustment.Amount == 0 {
+		return ErrInvalidAmount
+	}
+	err := s.db.AdjustBalance(ctx, adminID, userID, adjustment, s.cfg.PointsLifetimeMonths)
+	if err != nil {
+		return err
+	}
+	logger.Log.Info("Ручная корректировка баланса",
+		zap.Int("admin", adminID), zap.Int("user", userID), zap.Float64("amount", adjustment.Amount))
+	return nil
+}
+
+func (s *AccrualService) SetUserBlocked(ctx context.Context, adminID, userID int, blocked bool, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if blocked && reason == "" {
+		return ErrReasonRequired
+	}
+	defer s.blocked.forget(userID)
+	return s.db.SetUserBlocked(ctx, adminID, userID, blocked, reason)
+}
+
+func (s *AccrualService) RepollOrder(ctx context.Context, adminID int, orderNum string) error {
+	if s.queue == nil {
+		return ErrQueueNotReady
+	}
+	userID, err := s.db.IsOrderExists(ctx, orderNum)
+	if err != nil {
+		return err
+	}
+	if userID == 0 {
+		return models.ErrOrderNotFound
+	}
+	if err := s.queue.EnqueueOrder(orderNum); err != nil {
+		return err
+	}
+	return s.db.LogAdminAction(ctx, models.AdminAction{
+		AdminID:      adminID,
+		Action:       models.AdminActionRepoll,
+		TargetUserID: userID,
+		TargetOrder:  orderNum,
+	})
+}
+
+// SetUserAccess меняет роль и права пользователя. Уже выданные access-токены
+// хранят прежние права до обновления по refresh-токену.
+func (s *AccrualService) SetUserAccess(ctx context.Context, adminID, userID int, access models.UserAccess) error {
+	if !slices.Contains(models.Roles, access.Role) {
+		return ErrUnknownRole
+	}
+	for _, permission := range access.Permissions {
+		if !slices.Contains(models.Permissions, permission) {
+			return fmt.Errorf("%w: %s", ErrUnknownPerm, permission)
+		}
+	}
+	slices.Sort(access.Permissions)
+	access.Permissions = slices.Compact(access.Permissions)
+	return s.db.SetUserAccess(ctx, adminID, userID, access)
+}
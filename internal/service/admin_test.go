@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+type queueStub struct {
+	orders chan string
+}
+
+func (q *queueStub) EnqueueOrder(orderNum string) error {
+	select {
+	case q.orders <- orderNum:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func TestAdjustBalance(t *testing.T) {
+	t.Run("без причины", func(t *testing.T) {
+		service := &AccrualService{db: NewMockStorage(t)}
+		err := service.AdjustBalance(context.Background(), 1, 2, models.Adjustment{Amount: 10, Reason: "  "})
+		require.ErrorIs(t, err, ErrReasonRequired)
+	})
+
+	t.Run("нулевая сумма", func(t *testing.T) {
+		service := &AccrualService{db: NewMockStorage(t)}
+		err := service.AdjustBalance(context.Background(), 1, 2, models.Adjustment{Reason: "goodwill"})
+		require.ErrorIs(t, err, ErrInvalidAmount)
+	})
+
+	t.Run("корректировка", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB, cfg: Config{PointsLifetimeMonths: 12}}
+
+		mockDB.EXPECT().AdjustBalance(mock.Anything, 1, 2, models.Adjustment{Amount: -5, Reason: "double credit"}, 12).
+			Return(nil).Once()
+
+		err := service.AdjustBalance(context.Background(), 1, 2, models.Adjustment{Amount: -5, Reason: " double credit "})
+		require.NoError(t, err)
+	})
+}
+func TestSetUserBlocked(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB}
+
+	require.ErrorIs(t, service.SetUserBlocked(context.Background(), 1, 2, true, ""), ErrReasonRequired)
+
+	mockDB.EXPECT().SetUserBlocked(mock.Anything, 1, 2, false, "").Return(nil).Once()
+	require.NoError(t, service.SetUserBlocked(context.Background(), 1, 2, false, ""))
+}
+func TestRepollOrder(t *testing.T) {
+	t.Run("заказ поставлен в очередь", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		queue := &queueStub{orders: make(chan string, 1)}
+		service := &AccrualService{db: mockDB, queue: queue}
+
+		mockDB.EXPECT().IsOrderExists(mock.Anything, "79927398713").Return(7, nil).Once()
+		mockDB.EXPECT().LogAdminAction(mock.Anything, models.AdminAction{
+			AdminID:      1,
+			Action:       models.AdminActionRepoll,
+			TargetUserID: 7,
+			TargetOrder:  "79927398713",
+		}).Return(nil).Once()
+
+		require.NoError(t, service.RepollOrder(context.Background(), 1, "79927398713"))
+		require.Equal(t, "79927398713", <-queue.orders)
+	})
+
+	t.Run("очередь переполнена", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB, queue: &queueStub{}}
+
+		mockDB.EXPECT().IsOrderExists(mock.Anything, "79927398713").Return(7, nil).Once()
+
+		require.ErrorIs(t, service.RepollOrder(context.Background(), 1, "79927398713"), ErrQueueFull)
+	})
+
+	t.Run("заказ не найден", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB, queue: &queueStub{}}
+
+		mockDB.EXPECT().IsOrderExists(mock.Anything, "79927398713").Return(0, nil).Once()
+
+		require.ErrorIs(t, service.RepollOrder(context.Background(), 1, "79927398713"), models.ErrOrderNotFound)
+	})
+}
+func TestAuthorizeBlockedUser(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB}
+
+	hash, err := auth.HashPassword("secret")
+	require.NoError(t, err)
+	mockDB.EXPECT().GetUserByLogin(mock.Anything, "bob").
+		Return(&models.User{ID: 2, Login: "bob", Password: hash, Blocked: true}, nil).Once()
+
+	err = service.AuthorizeUser(context.Background(), &models.User{Login: "bob", Password: "secret"})
+	require.ErrorIs(t, err, models.ErrUserBlocked)
+}
+func TestSetUserAccess(t *testing.T) {
+	t.Run("неизвестная роль", func(t *testing.T) {
+		service := &AccrualService{db: NewMockStorage(t)}
+		err := service.SetUserAccess(context.Background(), 1, 2, models.UserAccess{Role: "root"})
+		require.ErrorIs(t, err, ErrUnknownRole)
+	})
+
+	t.Run("неизвестное право", func(t *testing.T) {
+		service := &AccrualService{db: NewMockStorage(t)}
+		err := service.SetUserAccess(context.Background(), 1, 2, models.UserAccess{
+			Role:        models.RoleSupport,
+			Permissions: []string{"everything"},
+		})
+		require.ErrorIs(t, err, ErrUnknownPerm)
+	})
+
+	t.Run("права без дублей", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		mockDB.EXPECT().SetUserAccess(mock.Anything, 1, 2, models.UserAccess{
+			Role:        models.RoleSupport,
+			Permissions: []string{models.PermBalanceAdjust, models.PermUsersRead},
+		}).Return(nil).Once()
+
+		err := service.SetUserAccess(context.Background(), 1, 2, models.UserAccess{
+			Role:        models.RoleSupport,
+			Permissions: []string{models.PermUsersRead, models.PermBalanceAdjust, models.PermUsersRead},
+		})
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// CreateOrders загружает пачку заказов с теми же проверками, что и
+// CreateOrder, и возвращает результат по каждому номеру в исходном порядке.
+// Новые заказы вставляются одним запросом и сразу ставятся в очередь опроса.
+func (s *AccrualService) CreateOrders(ctx context.Context, userID int, numbers []string) ([]models.BatchOrderResult, error) {
+	if s.cfg.OrderBatchLimit > 0 && len(numbers) > s.cfg.OrderBatchLimit {
+		return nil, models.ErrBatchTooLarge
+	}
+
+	results := make([]models.BatchOrderResult, len(numbers))
+	seen := make(map[string]struct{}, len(numbers))
+	var candidates []string
+	for i, number := range numbers {
+		number = strings.TrimSpace(number)
+		results[i].Order = number
+		if !auth.IsValidLuhn(number) {
+			results[i].Status = models.BatchOrderInvalid
+			continue
+		}
+		if _, ok := seen[number]; ok {
+			results[i].Status = models.BatchOrderDuplicate
+			continue
+		}
+		seen[number] = struct{}{}
+		candidates = append(candidates, number)
+	}
+
+	owners, err := s.db.GetOrderOwners(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+	var fresh []string
+	for _, number := range candidates {
+		if _, ok := owners[number]; !ok {
+			fresh = append(fresh, number)
+		}
+	}
+	inserted, err := s.db.SaveOrders(ctx, userID, fresh)
+	if err != nil {
+		return nil, err
+	}
+	accepted := make(map[string]struct{}, len(inserted))
+	for _, number := range inserted {
+		accepted[number] = struct{}{}
+	}
+	if len(inserted) < len(fresh) {
+		// Часть номеров успели загрузить параллельно — узнаём, кем.
+		var raced []string
+		for _, number := range fresh {
+			if _, ok := accepted[number]; !ok {
+				raced = append(raced, number)
+			}
+		}
+		racedOwners, err := s.db.GetOrderOwners(ctx, raced)
+		if err != nil {
+			return nil, err
+		}
+		for number, owner := range racedOwners {
+			owners[number] = owner
+		}
+	}
+
+	for i := range results {
+		if results[i].Status != "" {
+			continue
+		}
+		number := results[i].Order
+		switch _, ok := accepted[number]; {
+		case ok:
+			results[i].Status = models.BatchOrderAccepted
+		case owners[number] == userID:
+			results[i].Status = models.BatchOrderAlreadyUploaded
+		default:
+			results[i].Status = models.BatchOrderConflict
+		}
+	}
+
+	logger.Log.Info("Пакетная загрузка заказов", zap.Int("user", userID),
+		zap.Int("total", len(numbers)), zap.Int("accepted", len(inserted)))
+	// При включённых уведомлениях результат придёт сам, а заказы без
+	// уведомления подберёт опрос по таймауту.
+	if s.queue != nil && len(inserted) > 0 && !s.callbacksEnabled() {
+		for _, number := range inserted {
+			if err := s.queue.EnqueueOrder(number); err != nil {
+				logger.Log.Warn("Заказ не поставлен в очередь опроса", zap.String("order", number), zap.Error(err))
+				break
+			}
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+type recordingQueue chan string
+
+func (q recordingQueue) EnqueueOrder(orderNum string) error {
+	q <- orderNum
+	return nil
+}
+
+func TestCreateOrders(t *testing.T) {
+	t.Run("результат по каждому номеру", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		queue := make(recordingQueue, 10)
+		service := &AccrualService{db: mockDB, queue: queue}
+
+		// 12345678903 — новый, 79927398713 — уже загружен этим пользователем,
+		// 4539578763621486 — другим, 6011000990139424 — загружен другим
+		// параллельно с этой пачкой.
+		mockDB.EXPECT().GetOrderOwners(mock.Anything,
+			[]string{"12345678903", "79927398713", "4539578763621486", "6011000990139424"}).
+			Return(map[string]int{"79927398713": 1, "4539578763621486": 2}, nil).Once()
+		mockDB.EXPECT().SaveOrders(mock.Anything, 1, []string{"12345678903", "6011000990139424"}).
+			Return([]string{"12345678903"}, nil).Once()
+		mockDB.EXPECT().GetOrderOwners(mock.Anything, []string{"6011000990139424"}).
+			Return(map[string]int{"6011000990139424": 3}, nil).Once()
+
+		results, err := service.CreateOrders(context.Background(), 1, []string{
+			"12345678903", "79927398713", "4539578763621486", "123", " 12345678903 ", "6011000990139424",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []models.BatchOrderResult{
+			{Order: "12345678903", Status: models.BatchOrderAccepted},
+			{Order: "79927398713", Status: models.BatchOrderAlreadyUploaded},
+			{Order: "4539578763621486", Status: models.BatchOrderConflict},
+			{Order: "123", Status: models.BatchOrderInvalid},
+			{Order: "12345678903", Status: models.BatchOrderDuplicate},
+			{Order: "6011000990139424", Status: models.BatchOrderConflict},
+		}, results)
+		require.Equal(t, "12345678903", <-queue)
+	})
+
+	t.Run("при уведомлениях заказы ждут их, а не опроса", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		queue := make(recordingQueue, 10)
+		service := &AccrualService{db: mockDB, queue: queue, cfg: Config{AccrualCallbackSecret: "secret"}}
+		mockDB.EXPECT().GetOrderOwners(mock.Anything, []string{"12345678903"}).Return(map[string]int{}, nil).Once()
+		mockDB.EXPECT().SaveOrders(mock.Anything, 1, []string{"12345678903"}).Return([]string{"12345678903"}, nil).Once()
+
+		_, err := service.CreateOrders(context.Background(), 1, []string{"12345678903"})
+		require.NoError(t, err)
+		require.Empty(t, queue)
+	})
+
+	t.Run("превышен размер пачки", func(t *testing.T) {
+		service := &AccrualService{db: NewMockStorage(t), cfg: Config{OrderBatchLimit: 1}}
+		_, err := service.CreateOrders(context.Background(), 1, []string{"12345678903", "79927398713"})
+		require.ErrorIs(t, err, models.ErrBatchTooLarge)
+	})
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// blockedCacheTTL ограничивает, как долго другой экземпляр сервиса может
+// пропускать запросы заблокированного пользователя.
+const blockedCacheTTL = 30 * time.Second
+
+type blockedEntry struct {
+	blocked bool
+	expires time.Time
+}
+
+// blockedCache избавляет AuthMiddleware от запроса в базу на каждый вызов.
+// Блокировка через этот экземпляр сбрасывает запись сразу.
+type blockedCache struct {
+	mu      sync.Mutex
+	entries map[int]blockedEntry
+}
+
+func (c *blockedCache) get(userID int) (bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[userID]
+	if !ok || time.Now().After(entry.expires) {
+		return false, false
+	}
+	return entry.blocked, true
+}
+
+func (c *blockedCache) set(userID int, blocked bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[int]blockedEntry)
+	}
+	c.entries[userID] = blockedEntry{blocked: blocked, expires: time.Now().Add(blockedCacheTTL)}
+}
+
+func (c *blockedCache) forget(userID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, userID)
+}
+
+func (s *AccrualService) IsUserBlocked(ctx context.Context, userID int) (bool, error) {
+	if blocked, ok := s.blocked.get(userID); ok {
+		return blocked, nil
+	}
+	blocked, err := s.db.IsUserBlocked(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	s.blocked.set(userID, blocked)
+	return blocked, nil
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsUserBlockedCached(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB}
+	ctx := context.Background()
+
+	mockDB.EXPECT().IsUserBlocked(mock.Anything, 7).Return(false, nil).Once()
+	for range 3 {
+		blocked, err := service.IsUserBlocked(ctx, 7)
+		require.NoError(t, err)
+		require.False(t, blocked)
+	}
+
+	// Блокировка сбрасывает запись, следующий запрос идёт в базу.
+	mockDB.EXPECT().SetUserBlocked(mock.Anything, 1, 7, true, "fraud").Return(nil).Once()
+	require.NoError(t, service.SetUserBlocked(ctx, 1, 7, true, "fraud"))
+
+	mockDB.EXPECT().IsUserBlocked(mock.Anything, 7).Return(true, nil).Once()
+	blocked, err := service.IsUserBlocked(ctx, 7)
+	require.NoError(t, err)
+	require.True(t, blocked)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+	"go.uber.org/zap"
+)
+
+func (s *AccrualService) callbacksEnabled() bool {
+	return s.cfg.AccrualCallbackSecret != ""
+}
+
+// pollDelay — сколько необработанный заказ ждёт уведомления, прежде чем
+// очередь начнёт опрашивать систему начислений сама.
+func (s *AccrualService) pollDelay() time.Duration {
+	if !s.callbacksEnabled() {
+		return 0
+	}
+	return s.cfg.AccrualCallbackTimeout
+}
+
+// VerifyAccrualCallback проверяет подпись уведомления системы начислений.
+// Повтор подписи отсекает ApplyAccruals, запоминая её вместе с результатами.
+func (s *AccrualService) VerifyAccrualCallback(ctx context.Context, req auth.SignedRequest, signature string) error {
+	if !s.callbacksEnabled() {
+		return models.ErrCallbackDisabled
+	}
+	return auth.VerifyRequestSignature([]byte(s.cfg.AccrualCallbackSecret), req, signature, time.Now())
+}
+
+// ApplyAccruals записывает результаты, присланные системой начислений, так
+// же, как результаты опроса. Пачка с неверной записью отклоняется целиком;
+// подпись сохраняется в той же транзакции, поэтому перехваченный запрос
+// нельзя повторить, пока его метка времени ещё в допустимом окне.
+func (s *AccrualService) ApplyAccruals(ctx context.Context, signature string, accruals []models.AccrualResponse) error {
+	for _, accrual := range accruals {
+		if err := validateAccrual(accrual); err != nil {
+			return err
+		}
+	}
+	for i := range accruals {
+		normalizeAccrual(&accruals[i])
+	}
+	if err := s.db.ApplyAccrualCallback(ctx, signature, 2*auth.SignatureTolerance, accruals, s.creditPolicy()); err != nil {
+		return err
+	}
+	logger.Log.Info("Приняты уведомления о начислениях", zap.Int("count", len(accruals)))
+	return nil
+}
+
+func validateAccrual(accrual models.AccrualResponse) error {
+	if !auth.IsValidLuhn(accrual.Order) {
+		return fmt.Errorf("%w: заказ %q", models.ErrInvalidAccrual, accrual.Order)
+	}
+	switch accrual.Status {
+	case models.AccrualRegistered, models.OrderProcessing, models.OrderInvalid, models.OrderProcessed:
+	default:
+		return fmt.Errorf("%w: статус %q", models.ErrInvalidAccrual, accrual.Status)
+	}
+	if accrual.Accrual < 0 || (accrual.Accrual > 0 && accrual.Status != models.OrderProcessed) {
+		return fmt.Errorf("%w: начисление %v", models.ErrInvalidAccrual, accrual.Accrual)
+	}
+	return nil
+}
+
+// normalizeAccrual приводит статус системы начислений к статусу заказа.
+func normalizeAccrual(accrual *models.AccrualResponse) {
+	if accrual.Status == models.AccrualRegistered {
+		accrual.Status = models.OrderProcessing
+	}
+}
+
+// applyAccrual записывает результат опроса системы начислений.
+func (s *AccrualService) applyAccrual(ctx context.Context, accrual *models.AccrualResponse) error {
+	normalizeAccrual(accrual)
+	if err := s.db.UpdateOrder(ctx, accrual, s.creditPolicy()); err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	return nil
+}
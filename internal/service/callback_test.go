@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestVerifyAccrualCallback(t *testing.T) {
+	body := []byte(`{"order":"12345678903","status":"PROCESSED","accrual":10}`)
+	req := auth.SignedRequest{
+		Method:    "POST",
+		Path:      "/api/internal/accrual-callback",
+		Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+		Nonce:     "nonce",
+		Body:      body,
+	}
+	sig := auth.SignRequest([]byte("secret"), req)
+
+	t.Run("приём выключен", func(t *testing.T) {
+		service := &AccrualService{db: NewMockStorage(t)}
+		err := service.VerifyAccrualCallback(context.Background(), req, sig)
+		require.ErrorIs(t, err, models.ErrCallbackDisabled)
+	})
+
+	t.Run("верная подпись", func(t *testing.T) {
+		service := &AccrualService{db: NewMockStorage(t), cfg: Config{AccrualCallbackSecret: "secret"}}
+		require.NoError(t, service.VerifyAccrualCallback(context.Background(), req, sig))
+	})
+
+	t.Run("неверная подпись", func(t *testing.T) {
+		service := &AccrualService{db: NewMockStorage(t), cfg: Config{AccrualCallbackSecret: "other"}}
+		err := service.VerifyAccrualCallback(context.Background(), req, sig)
+		require.ErrorIs(t, err, auth.ErrSignatureInvalid)
+	})
+}
+
+func TestApplyAccruals(t *testing.T) {
+	t.Run("записываются вместе с подписью", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB, cfg: Config{PointsLifetimeMonths: 12, TierWindowMonths: 12}}
+		policy := models.CreditPolicy{LifetimeMonths: 12, TierWindowMonths: 12}
+		mockDB.EXPECT().ApplyAccrualCallback(mock.Anything, "sig", 2*auth.SignatureTolerance, []models.AccrualResponse{
+			{Order: "12345678903", Status: models.OrderProcessed, Accrual: 10},
+			{Order: "79927398713", Status: models.OrderProcessing},
+		}, policy).Return(nil).Once()
+
+		err := service.ApplyAccruals(context.Background(), "sig", []models.AccrualResponse{
+			{Order: "12345678903", Status: models.OrderProcessed, Accrual: 10},
+			{Order: "79927398713", Status: models.AccrualRegistered},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("неверная запись отклоняет пачку", func(t *testing.T) {
+		service := &AccrualService{db: NewMockStorage(t)}
+		for _, accrual := range []models.AccrualResponse{
+			{Order: "123", Status: models.OrderProcessed},
+			{Order: "12345678903", Status: "DONE"},
+			{Order: "12345678903", Status: models.OrderProcessed, Accrual: -1},
+			{Order: "12345678903", Status: models.OrderInvalid, Accrual: 5},
+		} {
+			err := service.ApplyAccruals(context.Background(), "sig", []models.AccrualResponse{
+				{Order: "79927398713", Status: models.OrderProcessed, Accrual: 1}, accrual,
+			})
+			require.ErrorIs(t, err, models.ErrInvalidAccrual)
+		}
+	})
+
+	t.Run("повтор", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+		mockDB.EXPECT().ApplyAccrualCallback(mock.Anything, "sig", mock.Anything, mock.Anything, mock.Anything).
+			Return(models.ErrCallbackReplayed).Once()
+
+		err := service.ApplyAccruals(context.Background(), "sig", []models.AccrualResponse{
+			{Order: "12345678903", Status: models.OrderInvalid},
+		})
+		require.ErrorIs(t, err, models.ErrCallbackReplayed)
+	})
+
+	t.Run("ошибка базы", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+		mockDB.EXPECT().ApplyAccrualCallback(mock.Anything, "sig", mock.Anything, mock.Anything, mock.Anything).
+			Return(errors.New("db")).Once()
+
+		err := service.ApplyAccruals(context.Background(), "sig", []models.AccrualResponse{
+			{Order: "12345678903", Status: models.OrderInvalid},
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestPollDelay(t *testing.T) {
+	service := &AccrualService{cfg: Config{AccrualCallbackTimeout: time.Minute}}
+	require.Equal(t, time.Duration(0), service.pollDelay())
+	service.cfg.AccrualCallbackSecret = "secret"
+	require.Equal(t, time.Minute, service.pollDelay())
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/scoring-service/internal/campaign"
+	"github.com/scoring-service/pkg/models"
+)
+
+func (s *AccrualService) ListCampaigns(ctx context.Context) ([]models.Campaign, error) {
+	return s.db.ListCampaigns(ctx)
+}
+func (s *AccrualService) GetCampaign(ctx context.Context, id int) (models.Campaign, error) {
+	return s.db.GetCampaign(ctx, id)
+}
+func (s *AccrualService) CreateCampaign(ctx context.Context, c *models.Campaign) error {
+	if err := campaign.Validate(*c); err != nil {
+		return err
+	}
+	return s.db.CreateCampaign(ctx, c)
+}
+func (s *AccrualService) UpdateCampaign(ctx context.Context, c *models.Campaign) error {
+	if err := campaign.Validate(*c); err != nil {
+		return err
+	}
+	return s.db.UpdateCampaign(ctx, c)
+}
+func (s *AccrualService) DeleteCampaign(ctx context.Context, id int) error {
+	return s.db.DeactivateCampaign(ctx, id)
+}
+
+// DryRunCampaign оценивает, какие бонусы начислила бы акция по заказам,
+// обработанным в интервале [from, to). Нулевые границы берутся из периода
+// действия акции. Начисления не производятся.
+func (s *AccrualService) DryRunCampaign(ctx context.Context, id int, from, to time.Time) (models.CampaignDryRun, error) {
+	c, err := s.db.GetCampaign(ctx, id)
+	if err != nil {
+		return models.CampaignDryRun{}, err
+	}
+	if from.IsZero() {
+		from = c.StartsAt
+	}
+	if to.IsZero() {
+		to = time.Now()
+		if c.EndsAt != nil && c.EndsAt.Before(to) {
+			to = *c.EndsAt
+		}
+	}
+
+	orders, err := s.db.GetProcessedOrderFacts(ctx, from, to)
+	if err != nil {
+		return models.CampaignDryRun{}, err
+	}
+
+	// Пробный прогон оценивает правила акции даже для выключенной акции.
+	c.Active = true
+	result := models.CampaignDryRun{CampaignID: c.ID, Evaluated: len(orders), Bonuses: []models.CampaignBonus{}}
+	for _, order := range orders {
+		bonus := campaign.Evaluate(c, order)
+		if bonus <= 0 {
+			continue
+		}
+		result.Bonuses = append(result.Bonuses, models.CampaignBonus{
+			CampaignID: c.ID,
+			UserID:     order.UserID,
+			Order:      order.Number,
+			Amount:     bonus,
+		})
+		result.Total += bonus
+	}
+	return result, nil
+}
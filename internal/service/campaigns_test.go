@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/campaign"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestCreateCampaign(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB}
+
+	invalid := &models.Campaign{Name: "", StartsAt: time.Now(), Multiplier: 2}
+	require.ErrorIs(t, service.CreateCampaign(context.Background(), invalid), campaign.ErrInvalidCampaign)
+
+	valid := &models.Campaign{Name: "weekend", StartsAt: time.Now(), Multiplier: 2, Active: true}
+	mockDB.EXPECT().CreateCampaign(mock.Anything, valid).Return(nil).Once()
+	require.NoError(t, service.CreateCampaign(context.Background(), valid))
+}
+func TestDryRunCampaign(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB}
+
+	start := time.Date(2025, 5, 3, 0, 0, 0, 0, time.UTC)
+	end := start.Add(48 * time.Hour)
+	c := models.Campaign{ID: 7, Name: "welcome", StartsAt: start, EndsAt: &end, FirstOrderOnly: true, BonusPoints: 100}
+
+	mockDB.EXPECT().GetCampaign(mock.Anything, 7).Return(c, nil).Once()
+	mockDB.EXPECT().GetProcessedOrderFacts(mock.Anything, start, end).Return([]campaign.OrderFacts{
+		{UserID: 1, Number: "12345678903", ProcessedAt: start.Add(time.Hour), FirstProcessed: true},
+		{UserID: 1, Number: "79927398713", ProcessedAt: start.Add(2 * time.Hour)},
+		{UserID: 2, Number: "4561261212345467", ProcessedAt: start.Add(3 * time.Hour), FirstProcessed: true},
+	}, nil).Once()
+
+	result, err := service.DryRunCampaign(context.Background(), 7, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, 3, result.Evaluated)
+	require.Len(t, result.Bonuses, 2)
+	require.Equal(t, 200.0, result.Total)
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+const (
+	checkoutCodeDigits = 8
+	// checkoutCodeAttempts — попыток подобрать код, не совпадающий с
+	// действующими кодами других покупателей.
+	checkoutCodeAttempts = 3
+)
+
+func (s *AccrualService) IssueCheckoutCode(ctx context.Context, userID int) (models.CheckoutCode, error) {
+	for range checkoutCodeAttempts {
+		code, err := auth.GenerateNumericCode(checkoutCodeDigits)
+		if err != nil {
+			return models.CheckoutCode{}, err
+		}
+		expiresAt, err := s.db.CreateCheckoutCode(ctx, userID, s.checkoutCodeHash(code), s.cfg.CheckoutCodeTTL)
+		if errors.Is(err, models.ErrCheckoutCodeTaken) {
+			continue
+		}
+		if err != nil {
+			return models.CheckoutCode{}, err
+		}
+		return models.CheckoutCode{Code: code, ExpiresAt: expiresAt}, nil
+	}
+	return models.CheckoutCode{}, models.ErrCheckoutCodeTaken
+}
+
+// CheckCheckoutThrottle возвращает, сколько мерчанту ждать до следующей
+// попытки погасить код после серии неверных кодов.
+func (s *AccrualService) CheckCheckoutThrottle(ctx context.Context, merchantID int) (time.Duration, error) {
+	t, err := s.db.GetCheckoutThrottle(ctx, merchantID)
+	if err != nil {
+		return 0, err
+	}
+	return throttleWait(t, s.cfg.CheckoutThrottle, time.Now()), nil
+}
+
+// RedeemCheckoutCode гасит код покупателя и регистрирует заказ или списание.
+// Номер заказа проверяется до погашения, чтобы опечатка кассира не сжигала
+// код; если списание или заказ не прошли, код снова становится действующим.
+func (s *AccrualService) RedeemCheckoutCode(ctx context.Context, merchantID int, req models.CheckoutRedeem) CreateStatus {
+	if !auth.IsValidLuhn(req.Order) || req.Sum < 0 {
+		return StatusInvalid
+	}
+	codeHash := s.checkoutCodeHash(req.Code)
+	userID, err := s.db.RedeemCheckoutCode(ctx, merchantID, codeHash)
+	if errors.Is(err, models.ErrInvalidCheckoutCode) {
+		logger.Log.Warn("Неверный код оплаты", zap.Int("merchant", merchantID))
+		if err := s.db.RecordCheckoutFailure(ctx, merchantID, s.cfg.CheckoutThrottle); err != nil {
+			return StatusError
+		}
+		return StatusNotFound
+	}
+	if err != nil {
+		return StatusError
+	}
+
+	var status CreateStatus
+	if req.Sum > 0 {
+		status = s.createWithdraw(ctx, merchantID, userID, models.Withdraw{Order: req.Order, Sum: req.Sum, TOTP: req.TOTP})
+	} else {
+		status = s.createMerchantOrder(ctx, merchantID, userID, req.Order, req.Items)
+	}
+	// Неверный код 2FA покупателя считается и против кассы: код оплаты
+	// возвращается, и без этого касса могла бы перебирать коды бесконечно.
+	if status == StatusTOTPRequired && req.TOTP != "" {
+		if err := s.db.RecordCheckoutFailure(ctx, merchantID, s.cfg.CheckoutThrottle); err != nil {
+			logger.Log.Error("Не удалось учесть неверный код 2FA на кассе", zap.Int("merchant", merchantID), zap.Error(err))
+		}
+	}
+	if status != StatusOK && status != StatusAlreadyExist {
+		if err := s.db.ReleaseCheckoutCode(ctx, merchantID, codeHash); err != nil {
+			logger.Log.Error("Не удалось вернуть код оплаты", zap.Int("merchant", merchantID), zap.Error(err))
+		}
+	}
+	return status
+}
+
+func (s *AccrualService) checkoutCodeHash(code string) string {
+	return auth.HashCode([]byte(s.cfg.CheckoutCodeSecret), code)
+}
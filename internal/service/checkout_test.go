@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestIssueCheckoutCode(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB, cfg: Config{CheckoutCodeTTL: 5 * time.Minute, CheckoutCodeSecret: "code-secret"}}
+	expiresAt := time.Now().Add(5 * time.Minute)
+
+	var hashes []string
+	mockDB.EXPECT().CreateCheckoutCode(mock.Anything, 1, mock.Anything, 5*time.Minute).
+		RunAndReturn(func(_ context.Context, _ int, hash string, _ time.Duration) (time.Time, error) {
+			hashes = append(hashes, hash)
+			if len(hashes) == 1 {
+				return time.Time{}, models.ErrCheckoutCodeTaken
+			}
+			return expiresAt, nil
+		}).Twice()
+
+	code, err := service.IssueCheckoutCode(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, code.Code, checkoutCodeDigits)
+	require.Equal(t, expiresAt, code.ExpiresAt)
+	require.Equal(t, auth.HashCode([]byte("code-secret"), code.Code), hashes[1])
+}
+func TestCheckCheckoutThrottle(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	policy := models.LoginPolicy{MaxFailures: 10, Lockout: 15 * time.Minute, Window: time.Hour}
+	service := &AccrualService{db: mockDB, cfg: Config{CheckoutThrottle: policy}}
+
+	mockDB.EXPECT().GetCheckoutThrottle(mock.Anything, 3).
+		Return(models.LoginThrottle{Failures: 10, LastFailureAt: time.Now(), LockedUntil: time.Now().Add(10 * time.Minute)}, nil).Once()
+	wait, err := service.CheckCheckoutThrottle(context.Background(), 3)
+	require.NoError(t, err)
+	require.InDelta(t, (10 * time.Minute).Seconds(), wait.Seconds(), 1)
+
+	mockDB.EXPECT().GetCheckoutThrottle(mock.Anything, 4).
+		Return(models.LoginThrottle{Failures: 3, LastFailureAt: time.Now()}, nil).Once()
+	wait, err = service.CheckCheckoutThrottle(context.Background(), 4)
+	require.NoError(t, err)
+	require.Zero(t, wait)
+}
+func TestRedeemCheckoutCode(t *testing.T) {
+	const order = "12345678903"
+	codeHash := auth.HashCode([]byte("code-secret"), "12345678")
+	policy := models.LoginPolicy{MaxFailures: 10}
+
+	tests := []struct {
+		name      string
+		req       models.CheckoutRedeem
+		mockSetup func(db *MockStorage)
+		want      CreateStatus
+	}{
+		{
+			name: "привязка заказа",
+			req:  models.CheckoutRedeem{Code: "12345678", Order: order},
+			mockSetup: func(db *MockStorage) {
+				db.EXPECT().RedeemCheckoutCode(mock.Anything, 3, codeHash).Return(5, nil).Once()
+				db.EXPECT().IsOrderExists(mock.Anything, order).Return(0, nil).Once()
+				db.EXPECT().SaveOrder(mock.Anything, 5, mock.Anything).Return(nil).Once()
+				db.EXPECT().AttachOrderToMerchant(mock.Anything, 3, order, merchantAttachWindow).Return(true, nil).Once()
+			},
+			want: StatusOK,
+		},
+		{
+			name: "списание баллов",
+			req:  models.CheckoutRedeem{Code: "12345678", Order: order, Sum: 100},
+			mockSetup: func(db *MockStorage) {
+				db.EXPECT().RedeemCheckoutCode(mock.Anything, 3, codeHash).Return(5, nil).Once()
+				db.EXPECT().GetUserBalance(mock.Anything, 5).Return(models.Balance{Current: 500}, nil).Once()
+				db.EXPECT().GetTOTP(mock.Anything, 5).Return(models.TOTP{}, nil).Once()
+				db.EXPECT().WithdrawAtMerchant(mock.Anything, 3, 5, order, 100.0).Return(nil).Once()
+			},
+			want: StatusOK,
+		},
+		{
+			name: "нехватка баллов возвращает код",
+			req:  models.CheckoutRedeem{Code: "12345678", Order: order, Sum: 100},
+			mockSetup: func(db *MockStorage) {
+				db.EXPECT().RedeemCheckoutCode(mock.Anything, 3, codeHash).Return(5, nil).Once()
+				db.EXPECT().GetUserBalance(mock.Anything, 5).Return(models.Balance{Current: 50}, nil).Once()
+				db.EXPECT().ReleaseCheckoutCode(mock.Anything, 3, codeHash).Return(nil).Once()
+			},
+			want: StatusConflict,
+		},
+		{
+			name: "нужен код 2FA — код оплаты остаётся действующим",
+			req:  models.CheckoutRedeem{Code: "12345678", Order: order, Sum: 600},
+			mockSetup: func(db *MockStorage) {
+				db.EXPECT().RedeemCheckoutCode(mock.Anything, 3, codeHash).Return(5, nil).Once()
+				db.EXPECT().GetUserBalance(mock.Anything, 5).Return(models.Balance{Current: 1000}, nil).Once()
+				db.EXPECT().GetTOTP(mock.Anything, 5).
+					Return(models.TOTP{Secret: testTOTPSecret, Enabled: true, WithdrawThreshold: 500}, nil).Once()
+				db.EXPECT().GetTOTPThrottle(mock.Anything, 5).Return(models.LoginThrottle{}, nil).Once()
+				db.EXPECT().ReleaseCheckoutCode(mock.Anything, 3, codeHash).Return(nil).Once()
+			},
+			want: StatusTOTPRequired,
+		},
+		{
+			name: "неверный код 2FA считается против покупателя и кассы",
+			req:  models.CheckoutRedeem{Code: "12345678", Order: order, Sum: 600, TOTP: "000000"},
+			mockSetup: func(db *MockStorage) {
+				db.EXPECT().RedeemCheckoutCode(mock.Anything, 3, codeHash).Return(5, nil).Once()
+				db.EXPECT().GetUserBalance(mock.Anything, 5).Return(models.Balance{Current: 1000}, nil).Once()
+				db.EXPECT().GetTOTP(mock.Anything, 5).
+					Return(models.TOTP{Secret: testTOTPSecret, Enabled: true, WithdrawThreshold: 500}, nil).Once()
+				db.EXPECT().GetTOTPThrottle(mock.Anything, 5).Return(models.LoginThrottle{}, nil).Once()
+				db.EXPECT().RecordTOTPFailure(mock.Anything, 5, models.LoginPolicy{}).Return(nil).Once()
+				db.EXPECT().RecordCheckoutFailure(mock.Anything, 3, policy).Return(nil).Once()
+				db.EXPECT().ReleaseCheckoutCode(mock.Anything, 3, codeHash).Return(nil).Once()
+			},
+			want: StatusTOTPRequired,
+		},
+		{
+			name: "заказ другого покупателя возвращает код",
+			req:  models.CheckoutRedeem{Code: "12345678", Order: order},
+			mockSetup: func(db *MockStorage) {
+				db.EXPECT().RedeemCheckoutCode(mock.Anything, 3, codeHash).Return(5, nil).Once()
+				db.EXPECT().IsOrderExists(mock.Anything, order).Return(6, nil).Once()
+				db.EXPECT().ReleaseCheckoutCode(mock.Anything, 3, codeHash).Return(nil).Once()
+			},
+			want: StatusConflict,
+		},
+		{
+			name: "неверный код учитывается",
+			req:  models.CheckoutRedeem{Code: "12345678", Order: order},
+			mockSetup: func(db *MockStorage) {
+				db.EXPECT().RedeemCheckoutCode(mock.Anything, 3, codeHash).Return(0, models.ErrInvalidCheckoutCode).Once()
+				db.EXPECT().RecordCheckoutFailure(mock.Anything, 3, policy).Return(nil).Once()
+			},
+			want: StatusNotFound,
+		},
+		{
+			name:      "неверный номер заказа не гасит код",
+			req:       models.CheckoutRedeem{Code: "12345678", Order: "123"},
+			mockSetup: func(db *MockStorage) {},
+			want:      StatusInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := NewMockStorage(t)
+			service := &AccrualService{db: mockDB, cfg: Config{CheckoutThrottle: policy, CheckoutCodeSecret: "code-secret"}}
+			tt.mockSetup(mockDB)
+
+			require.Equal(t, tt.want, service.RedeemCheckoutCode(context.Background(), 3, tt.req))
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+func (s *AccrualService) creditPolicy() models.CreditPolicy {
+	return models.CreditPolicy{
+		LifetimeMonths:   s.cfg.PointsLifetimeMonths,
+		TierWindowMonths: s.cfg.TierWindowMonths,
+		Referral:         s.cfg.Referral,
+	}
+}
+
+func (s *AccrualService) ExpirePoints(ctx context.Context) error {
+	users, err := s.db.ExpirePoints(ctx)
+	if err != nil {
+		logger.Log.Error("Ошибка при сгорании баллов", zap.Error(err))
+		return err
+	}
+	if users > 0 {
+		logger.Log.Info("Сгорели просроченные баллы", zap.Int64("users", users))
+	}
+	return nil
+}
+
+func (s *AccrualService) RunExpiryJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			jobCtx, cancel := context.WithTimeout(ctx, time.Minute)
+			s.ExpirePoints(jobCtx)
+			cancel()
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestGetUserBalance(t *testing.T) {
+	t.Run("баланс с графиком сгорания", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		expiring := []models.ExpiringPoints{{Date: "2026-01-31", Amount: 50}}
+		mockDB.EXPECT().GetUserBalance(mock.Anything, 1).Return(models.Balance{Current: 100, Withdrawn: 10}, nil).Once()
+		mockDB.EXPECT().GetExpiringPoints(mock.Anything, 1).Return(expiring, nil).Once()
+
+		balance, err := service.GetUserBalance(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, 100.0, balance.Current)
+		require.Equal(t, expiring, balance.Expiring)
+	})
+
+	t.Run("ошибка при получении графика сгорания", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		mockDB.EXPECT().GetUserBalance(mock.Anything, 1).Return(models.Balance{Current: 100}, nil).Once()
+		mockDB.EXPECT().GetExpiringPoints(mock.Anything, 1).Return(nil, errors.New("db error")).Once()
+
+		_, err := service.GetUserBalance(context.Background(), 1)
+		require.Error(t, err)
+	})
+}
+func TestExpirePoints(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB}
+
+	mockDB.EXPECT().ExpirePoints(mock.Anything).Return(int64(2), nil).Once()
+	require.NoError(t, service.ExpirePoints(context.Background()))
+
+	mockDB.EXPECT().ExpirePoints(mock.Anything).Return(int64(0), errors.New("db error")).Once()
+	require.Error(t, service.ExpirePoints(context.Background()))
+}
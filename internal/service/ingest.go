@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+func (s *AccrualService) StartIngestionRun(ctx context.Context, fileName, checksum string) (int, error) {
+	return s.db.StartIngestionRun(ctx, fileName, checksum)
+}
+
+func (s *AccrualService) FinishIngestionRun(ctx context.Context, run models.IngestionRun) error {
+	logger.Log.Info("Загрузка файла заказов завершена",
+		zap.String("file", run.FileName), zap.String("status", run.Status),
+		zap.Int("imported", run.Imported), zap.Int("skipped", run.Skipped), zap.Int("failed", run.Failed))
+	return s.db.FinishIngestionRun(ctx, run)
+}
+
+// IngestOrders загружает строки файла заказов и проставляет каждой строке
+// результат. Заказы одного покупателя загружаются пачками, как через
+// пакетную загрузку, поэтому повторная загрузка тех же строк безопасна.
+func (s *AccrualService) IngestOrders(ctx context.Context, lines []models.IngestionLine) error {
+	byLogin := make(map[string][]int)
+	var logins []string
+	for i := range lines {
+		if !auth.IsValidLuhn(lines[i].Order) {
+			lines[i].Status = models.BatchOrderInvalid
+			lines[i].Error = "неверный номер заказа"
+			continue
+		}
+		if _, ok := byLogin[lines[i].Login]; !ok {
+			logins = append(logins, lines[i].Login)
+		}
+		byLogin[lines[i].Login] = append(byLogin[lines[i].Login], i)
+	}
+
+	for _, login := range logins {
+		indexes := byLogin[login]
+		user, err := s.db.GetUserByLogin(ctx, login)
+		if err != nil {
+			return err
+		}
+		if user == nil || user.Blocked || user.Role != models.RoleCustomer {
+			for _, i := range indexes {
+				lines[i].Status = models.BatchOrderInvalid
+				lines[i].Error = "покупатель не найден"
+			}
+			continue
+		}
+		for len(indexes) > 0 {
+			chunk := indexes
+			if s.cfg.OrderBatchLimit > 0 && len(chunk) > s.cfg.OrderBatchLimit {
+				chunk = chunk[:s.cfg.OrderBatchLimit]
+			}
+			indexes = indexes[len(chunk):]
+
+			numbers := make([]string, len(chunk))
+			for j, i := range chunk {
+				numbers[j] = lines[i].Order
+			}
+			results, err := s.CreateOrders(ctx, user.ID, numbers)
+			if err != nil {
+				return err
+			}
+			for j, i := range chunk {
+				lines[i].Status = results[j].Status
+				if results[j].Status == models.BatchOrderConflict {
+					lines[i].Error = "заказ загружен другим пользователем"
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestIngestOrders(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB, cfg: Config{OrderBatchLimit: 1}}
+
+	mockDB.EXPECT().GetUserByLogin(mock.Anything, "alice").
+		Return(&models.User{ID: 1, Role: models.RoleCustomer}, nil).Once()
+	mockDB.EXPECT().GetUserByLogin(mock.Anything, "shop").
+		Return(&models.User{ID: 7, Role: models.RoleMerchant}, nil).Once()
+	// Лимит пачки 1: заказы alice загружаются по одному.
+	mockDB.EXPECT().GetOrderOwners(mock.Anything, []string{"12345678903"}).Return(map[string]int{}, nil).Once()
+	mockDB.EXPECT().SaveOrders(mock.Anything, 1, []string{"12345678903"}).Return([]string{"12345678903"}, nil).Once()
+	mockDB.EXPECT().GetOrderOwners(mock.Anything, []string{"79927398713"}).
+		Return(map[string]int{"79927398713": 2}, nil).Once()
+	mockDB.EXPECT().SaveOrders(mock.Anything, 1, []string(nil)).Return(nil, nil).Once()
+
+	lines := []models.IngestionLine{
+		{Line: 1, Login: "alice", Order: "12345678903"},
+		{Line: 2, Login: "alice", Order: "123"},
+		{Line: 3, Login: "shop", Order: "4539578763621486"},
+		{Line: 4, Login: "alice", Order: "79927398713"},
+	}
+	require.NoError(t, service.IngestOrders(context.Background(), lines))
+
+	require.Equal(t, models.BatchOrderAccepted, lines[0].Status)
+	require.Empty(t, lines[0].Error)
+	require.Equal(t, "неверный номер заказа", lines[1].Error)
+	require.Equal(t, "покупатель не найден", lines[2].Error)
+	require.Equal(t, models.BatchOrderConflict, lines[3].Status)
+	require.Equal(t, "заказ загружен другим пользователем", lines[3].Error)
+}
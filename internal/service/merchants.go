@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+var ErrInvalidMerchant = errors.New("не указаны название или логин мерчанта, либо отрицательный лимит запросов")
+
+func (s *AccrualService) CreateMerchant(ctx context.Context, adminID int, req models.MerchantCreate) (models.MerchantCredentials, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Login = strings.TrimSpace(req.Login)
+	if req.Name == "" || req.Login == "" || req.RateLimit < 0 || strings.HasPrefix(req.Login, models.DeletedLoginPrefix) {
+		return models.MerchantCredentials{}, ErrInvalidMerchant
+	}
+	apiKey, err := auth.GenerateAPIKey()
+	if err != nil {
+		return models.MerchantCredentials{}, err
+	}
+	merchant := models.Merchant{Name: req.Name, RateLimit: req.RateLimit}
+	if req.SignRequests {
+		if merchant.HMACSecret, err = auth.GenerateSigningSecret(); err != nil {
+			return models.MerchantCredentials{}, err
+		}
+	}
+	keyID, err := s.db.CreateMerchant(ctx, adminID, req.Login, &merchant, auth.HashToken(apiKey))
+	if err != nil {
+		return models.MerchantCredentials{}, err
+	}
+	logger.Log.Info("Создан мерчант", zap.Int("merchant", merchant.ID), zap.Int("admin", adminID))
+	return models.MerchantCredentials{
+		MerchantID: merchant.ID,
+		KeyID:      keyID,
+		APIKey:     apiKey,
+		HMACSecret: merchant.HMACSecret,
+	}, nil
+}
+
+func (s *AccrualService) IssueMerchantAPIKey(ctx context.Context, adminID, merchantID int) (models.MerchantCredentials, error) {
+	apiKey, err := auth.GenerateAPIKey()
+	if err != nil {
+		return models.MerchantCredentials{}, err
+	}
+	keyID, err := s.db.AddMerchantAPIKey(ctx, adminID, merchantID, auth.HashToken(apiKey))
+	if err != nil {
+		return models.MerchantCredentials{}, err
+	}
+	return models.MerchantCredentials{MerchantID: merchantID, KeyID: keyID, APIKey: apiKey}, nil
+}
+
+func (s *AccrualService) RevokeMerchantAPIKey(ctx context.Context, adminID, merchantID, keyID int) error {
+	return s.db.RevokeMerchantAPIKey(ctx, adminID, merchantID, keyID)
+}
+
+// RegisterRequestNonce отклоняет повтор подписанного запроса мерчанта, пока
+// его метка времени ещё в допустимом окне.
+func (s *AccrualService) RegisterRequestNonce(ctx context.Context, merchantID int, nonce string) error {
+	return s.db.RegisterMerchantNonce(ctx, merchantID, nonce, 2*auth.SignatureTolerance)
+}
+
+// IssueCustomerToken выпускает одноразовый токен, которым покупатель
+// разрешает магазину загрузить заказ на свой счёт. Токен живёт
+// CustomerTokenTTL, в базе хранится только хеш.
+func (s *AccrualService) IssueCustomerToken(ctx context.Context, userID int) (models.CustomerToken, error) {
+	token, err := auth.GenerateCustomerToken()
+	if err != nil {
+		return models.CustomerToken{}, err
+	}
+	expiresAt, err := s.db.CreateCustomerToken(ctx, userID, auth.HashToken(token), s.cfg.CustomerTokenTTL)
+	if err != nil {
+		return models.CustomerToken{}, err
+	}
+	return models.CustomerToken{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// AuthenticateMerchant проверяет API-ключ и возвращает мерчанта с правами
+// его учётной записи.
+func (s *AccrualService) AuthenticateMerchant(ctx context.Context, apiKey string) (*models.Merchant, error) {
+	if apiKey == "" {
+		return nil, models.ErrInvalidAPIKey
+	}
+	merchant, err := s.db.GetMerchantByAPIKey(ctx, auth.HashToken(apiKey))
+	if err != nil {
+		return nil, err
+	}
+	merchant.Permissions, err = s.db.GetUserPermissions(ctx, merchant.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return merchant, nil
+}
+
+// SubmitMerchantOrder регистрирует заказ покупателя от имени мерчанта. Проверки
+// номера и принадлежности заказа те же, что и при загрузке самим покупателем.
+func (s *AccrualService) SubmitMerchantOrder(ctx context.Context, merchantID int, req models.MerchantOrder) CreateStatus {
+	customerID, status := s.resolveCustomer(ctx, merchantID, req)
+	if status != StatusOK {
+		return status
+	}
+	return s.createMerchantOrder(ctx, merchantID, customerID, req.Order, req.Items)
+}
+
+// merchantAttachWindow — сколько после загрузки заказа мерчант может
+// привязать его к себе повтором запроса.
+const merchantAttachWindow = 5 * time.Minute
+
+// createMerchantOrder загружает заказ покупателю и помечает его мерчантом.
+// Повтор запроса вскоре после загрузки тоже привязывает заказ, чтобы сбой
+// привязки можно было довести до конца. Заказ, который покупатель загрузил
+// сам раньше или который привязан к другому мерчанту, — конфликт.
+func (s *AccrualService) createMerchantOrder(ctx context.Context, merchantID, userID int, order string, items []models.OrderItem) CreateStatus {
+	status := s.createOrder(ctx, userID, order, items)
+	if status != StatusOK && status != StatusAlreadyExist {
+		return status
+	}
+	attached, err := s.db.AttachOrderToMerchant(ctx, merchantID, order, merchantAttachWindow)
+	if err != nil {
+		return StatusError
+	}
+	if !attached {
+		logger.Log.Warn("Мерчант пытается привязать чужой заказ", zap.Int("merchant", merchantID), zap.String("order", order))
+		return StatusConflict
+	}
+	return status
+}
+
+// resolveCustomer находит покупателя по логину или по одноразовому токену
+// покупателя. Заблокированные учётные записи и служебные роли покупателями
+// не считаются. Номер заказа проверяется до погашения токена, чтобы опечатка
+// не сжигала его.
+func (s *AccrualService) resolveCustomer(ctx context.Context, merchantID int, req models.MerchantOrder) (int, CreateStatus) {
+	if (req.CustomerLogin == "") == (req.CustomerToken == "") || !auth.IsValidLuhn(req.Order) {
+		return 0, StatusInvalid
+	}
+	if req.CustomerLogin != "" {
+		user, err := s.db.GetUserByLogin(ctx, req.CustomerLogin)
+		if err != nil {
+			return 0, StatusError
+		}
+		if user == nil || user.Blocked || user.Role != models.RoleCustomer {
+			return 0, StatusNotFound
+		}
+		return user.ID, StatusOK
+	}
+	userID, err := s.db.ConsumeCustomerToken(ctx, merchantID, auth.HashToken(req.CustomerToken))
+	if errors.Is(err, models.ErrInvalidCustomerToken) {
+		logger.Log.Warn("Неверный токен покупателя", zap.Int("merchant", merchantID))
+		return 0, StatusNotFound
+	}
+	if err != nil {
+		return 0, StatusError
+	}
+	return userID, StatusOK
+}
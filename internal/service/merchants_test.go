@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestCreateMerchant(t *testing.T) {
+	t.Run("с подписью запросов", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		var keyHash string
+		mockDB.EXPECT().CreateMerchant(mock.Anything, 1, "shop", mock.Anything, mock.Anything).
+			RunAndReturn(func(_ context.Context, _ int, _ string, m *models.Merchant, hash string) (int, error) {
+				require.Equal(t, "Shop", m.Name)
+				require.NotEmpty(t, m.HMACSecret)
+				m.ID = 3
+				keyHash = hash
+				return 11, nil
+			}).Once()
+
+		creds, err := service.CreateMerchant(context.Background(), 1,
+			models.MerchantCreate{Name: " Shop ", Login: "shop", RateLimit: 60, SignRequests: true})
+		require.NoError(t, err)
+		require.Equal(t, 3, creds.MerchantID)
+		require.Equal(t, 11, creds.KeyID)
+		require.True(t, strings.HasPrefix(creds.APIKey, "gmk_"))
+		require.Equal(t, auth.HashToken(creds.APIKey), keyHash)
+		require.NotEmpty(t, creds.HMACSecret)
+	})
+
+	t.Run("без названия", func(t *testing.T) {
+		service := &AccrualService{db: NewMockStorage(t)}
+		_, err := service.CreateMerchant(context.Background(), 1, models.MerchantCreate{Login: "shop"})
+		require.ErrorIs(t, err, ErrInvalidMerchant)
+	})
+}
+func TestAuthenticateMerchant(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB}
+
+	mockDB.EXPECT().GetMerchantByAPIKey(mock.Anything, auth.HashToken("gmk_key")).
+		Return(&models.Merchant{ID: 3, UserID: 7}, nil).Once()
+	mockDB.EXPECT().GetUserPermissions(mock.Anything, 7).Return([]string{models.PermOrdersSubmit}, nil).Once()
+
+	merchant, err := service.AuthenticateMerchant(context.Background(), "gmk_key")
+	require.NoError(t, err)
+	require.Equal(t, []string{models.PermOrdersSubmit}, merchant.Permissions)
+
+	_, err = service.AuthenticateMerchant(context.Background(), "")
+	require.ErrorIs(t, err, models.ErrInvalidAPIKey)
+}
+func TestSubmitMerchantOrder(t *testing.T) {
+	const order = "12345678903"
+	const customerToken = "customer-token"
+	auth.SetSecretKey("test-secret")
+	t.Cleanup(func() { auth.SetSecretKey("") })
+	accessToken, err := auth.GenerateJWT(&models.User{ID: 5, Role: models.RoleCustomer})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		req       models.MerchantOrder
+		mockSetup func(db *MockStorage)
+		want      CreateStatus
+	}{
+		{
+			name: "по логину",
+			req:  models.MerchantOrder{Order: order, CustomerLogin: "alice"},
+			mockSetup: func(db *MockStorage) {
+				db.EXPECT().GetUserByLogin(mock.Anything, "alice").
+					Return(&models.User{ID: 5, Role: models.RoleCustomer}, nil).Once()
+				db.EXPECT().IsOrderExists(mock.Anything, order).Return(0, nil).Once()
+				db.EXPECT().SaveOrder(mock.Anything, 5, mock.Anything).Return(nil).Once()
+				db.EXPECT().AttachOrderToMerchant(mock.Anything, 3, order, merchantAttachWindow).Return(true, nil).Once()
+			},
+			want: StatusOK,
+		},
+		{
+			name: "по токену, заказ уже загружен покупателем",
+			req:  models.MerchantOrder{Order: order, CustomerToken: customerToken},
+			mockSetup: func(db *MockStorage) {
+				db.EXPECT().ConsumeCustomerToken(mock.Anything, 3, auth.HashToken(customerToken)).Return(5, nil).Once()
+				db.EXPECT().IsOrderExists(mock.Anything, order).Return(5, nil).Once()
+				db.EXPECT().AttachOrderToMerchant(mock.Anything, 3, order, merchantAttachWindow).Return(true, nil).Once()
+			},
+			want: StatusAlreadyExist,
+		},
+		{
+			name: "давний заказ покупателя не присваивается",
+			req:  models.MerchantOrder{Order: order, CustomerLogin: "alice"},
+			mockSetup: func(db *MockStorage) {
+				db.EXPECT().GetUserByLogin(mock.Anything, "alice").
+					Return(&models.User{ID: 5, Role: models.RoleCustomer}, nil).Once()
+				db.EXPECT().IsOrderExists(mock.Anything, order).Return(5, nil).Once()
+				db.EXPECT().AttachOrderToMerchant(mock.Anything, 3, order, merchantAttachWindow).Return(false, nil).Once()
+			},
+			want: StatusConflict,
+		},
+		{
+			name: "заказ другого покупателя",
+			req:  models.MerchantOrder{Order: order, CustomerLogin: "alice"},
+			mockSetup: func(db *MockStorage) {
+				db.EXPECT().GetUserByLogin(mock.Anything, "alice").
+					Return(&models.User{ID: 5, Role: models.RoleCustomer}, nil).Once()
+				db.EXPECT().IsOrderExists(mock.Anything, order).Return(6, nil).Once()
+			},
+			want: StatusConflict,
+		},
+		{
+			name: "покупатель не найден",
+			req:  models.MerchantOrder{Order: order, CustomerLogin: "bob"},
+			mockSetup: func(db *MockStorage) {
+				db.EXPECT().GetUserByLogin(mock.Anything, "bob").Return(nil, nil).Once()
+			},
+			want: StatusNotFound,
+		},
+		{
+			name: "заблокированный покупатель",
+			req:  models.MerchantOrder{Order: order, CustomerLogin: "alice"},
+			mockSetup: func(db *MockStorage) {
+				db.EXPECT().GetUserByLogin(mock.Anything, "alice").
+					Return(&models.User{ID: 5, Role: models.RoleCustomer, Blocked: true}, nil).Once()
+			},
+			want: StatusNotFound,
+		},
+		{
+			name: "access-токен вместо токена покупателя",
+			req:  models.MerchantOrder{Order: order, CustomerToken: accessToken},
+			mockSetup: func(db *MockStorage) {
+				db.EXPECT().ConsumeCustomerToken(mock.Anything, 3, auth.HashToken(accessToken)).
+					Return(0, models.ErrInvalidCustomerToken).Once()
+			},
+			want: StatusNotFound,
+		},
+		{
+			name: "погашенный или просроченный токен",
+			req:  models.MerchantOrder{Order: order, CustomerToken: customerToken},
+			mockSetup: func(db *MockStorage) {
+				db.EXPECT().ConsumeCustomerToken(mock.Anything, 3, auth.HashToken(customerToken)).
+					Return(0, models.ErrInvalidCustomerToken).Once()
+			},
+			want: StatusNotFound,
+		},
+		{
+			name:      "неверный номер не сжигает токен",
+			req:       models.MerchantOrder{Order: "12345678900", CustomerToken: customerToken},
+			mockSetup: func(db *MockStorage) {},
+			want:      StatusInvalid,
+		},
+		{
+			name:      "указаны и логин, и токен",
+			req:       models.MerchantOrder{Order: order, CustomerLogin: "alice", CustomerToken: customerToken},
+			mockSetup: func(db *MockStorage) {},
+			want:      StatusInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := NewMockStorage(t)
+			service := &AccrualService{db: mockDB}
+			tt.mockSetup(mockDB)
+
+			require.Equal(t, tt.want, service.SubmitMerchantOrder(context.Background(), 3, tt.req))
+		})
+	}
+}
+func TestIssueCustomerToken(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB, cfg: Config{CustomerTokenTTL: 10 * time.Minute}}
+	expiresAt := time.Now().Add(10 * time.Minute)
+
+	var storedHash string
+	mockDB.EXPECT().CreateCustomerToken(mock.Anything, 5, mock.Anything, 10*time.Minute).
+		RunAndReturn(func(_ context.Context, _ int, hash string, _ time.Duration) (time.Time, error) {
+			storedHash = hash
+			return expiresAt, nil
+		}).Once()
+
+	token, err := service.IssueCustomerToken(context.Background(), 5)
+	require.NoError(t, err)
+	require.NotEmpty(t, token.Token)
+	require.Equal(t, auth.HashToken(token.Token), storedHash)
+	require.Equal(t, expiresAt, token.ExpiresAt)
+}
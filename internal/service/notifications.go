@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+const (
+	// directNotifyAttempts — попыток отправить письмо в notifyNow.
+	directNotifyAttempts = 3
+	directNotifyBackoff  = 500 * time.Millisecond
+)
+
+func (s *AccrualService) GetNotificationPreferences(ctx context.Context, userID int) (models.NotificationPreferences, error) {
+	return s.db.GetNotificationPreferences(ctx, userID)
+}
+
+// UpdateNotificationPreferences сохраняет настройки уведомлений. Пустой
+// адрес отключает письма, язык по умолчанию — русский. Новый адрес
+// сохраняется как PendingEmail, на него уходит токен подтверждения; до
+// подтверждения письма идут на прежний адрес.
+func (s *AccrualService) UpdateNotificationPreferences(ctx context.Context, userID int, prefs models.NotificationPreferences) (models.NotificationPreferences, error) {
+	if prefs.Email != "" {
+		addr, err := mail.ParseAddress(prefs.Email)
+		if err != nil || addr.Name != "" || addr.Address != prefs.Email {
+			return models.NotificationPreferences{}, models.ErrInvalidPreferences
+		}
+	}
+	switch prefs.Locale {
+	case "":
+		prefs.Locale = models.LocaleRU
+	case models.LocaleRU, models.LocaleEN:
+	default:
+		return models.NotificationPreferences{}, models.ErrInvalidPreferences
+	}
+	current, err := s.db.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		return models.NotificationPreferences{}, err
+	}
+	prefs.Login = ""
+	prefs.PendingEmail = ""
+	if prefs.Email == "" || prefs.Email == current.Email {
+		if err := s.db.SaveNotificationPreferences(ctx, userID, prefs, "", 0); err != nil {
+			return models.NotificationPreferences{}, err
+		}
+		return prefs, nil
+	}
+
+	if s.notifier == nil {
+		return models.NotificationPreferences{}, fmt.Errorf("не настроена доставка уведомлений")
+	}
+	token, err := auth.GenerateResetToken()
+	if err != nil {
+		return models.NotificationPreferences{}, err
+	}
+	prefs.PendingEmail = prefs.Email
+	prefs.Email = current.Email
+	if err := s.db.SaveNotificationPreferences(ctx, userID, prefs, auth.HashToken(token), s.cfg.EmailVerificationTTL); err != nil {
+		return models.NotificationPreferences{}, err
+	}
+	err = s.notifyNow(ctx, models.Notification{
+		Kind:      models.NotificationEmailVerification,
+		UserID:    userID,
+		Login:     current.Login,
+		Email:     prefs.PendingEmail,
+		Locale:    prefs.Locale,
+		Token:     token,
+		ExpiresAt: time.Now().Add(s.cfg.EmailVerificationTTL),
+	})
+	if err != nil {
+		return models.NotificationPreferences{}, err
+	}
+	return prefs, nil
+}
+
+// ConfirmNotificationEmail подтверждает PendingEmail токеном из письма.
+func (s *AccrualService) ConfirmNotificationEmail(ctx context.Context, userID int, token string) error {
+	if token == "" {
+		return models.ErrInvalidEmailToken
+	}
+	if err := s.db.ConfirmNotificationEmail(ctx, userID, auth.HashToken(token)); err != nil {
+		return err
+	}
+	logger.Log.Info("Адрес почты подтверждён", zap.Int("user", userID))
+	return nil
+}
+
+// NotifyPointsCredited сообщает пользователю о начислении баллов за заказ.
+// Подписывается на шину событий; ошибка доставки приводит к повторной
+// публикации события.
+func (s *AccrualService) NotifyPointsCredited(ctx context.Context, e models.OrderProcessedEvent) error {
+	if e.Accrual <= 0 {
+		return nil
+	}
+	return s.notifyUser(ctx, e.UserID, models.Notification{
+		Kind:   models.NotificationPointsCredited,
+		Order:  e.Order,
+		Amount: e.Accrual,
+	})
+}
+
+// NotifyPointsWithdrawn сообщает пользователю о списании баллов.
+func (s *AccrualService) NotifyPointsWithdrawn(ctx context.Context, e models.PointsWithdrawnEvent) error {
+	return s.notifyUser(ctx, e.UserID, models.Notification{
+		Kind:   models.NotificationPointsWithdrawn,
+		Order:  e.Order,
+		Amount: e.Sum,
+	})
+}
+
+// notifyUser дополняет уведомление адресом и языком пользователя и
+// отправляет его, если пользователь не отключил уведомления этого вида.
+func (s *AccrualService) notifyUser(ctx context.Context, userID int, n models.Notification) error {
+	if s.notifier == nil {
+		return nil
+	}
+	prefs, err := s.db.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+	enabled := prefs.PointsCredited
+	if n.Kind == models.NotificationPointsWithdrawn {
+		enabled = prefs.PointsWithdrawn
+	}
+	if !enabled {
+		logger.Log.Debug("Уведомление отключено пользователем", zap.String("kind", n.Kind), zap.Int("user", userID))
+		return nil
+	}
+	n.UserID = userID
+	n.Login = prefs.Login
+	n.Email = prefs.Email
+	n.Locale = prefs.Locale
+	return s.notifier.Notify(ctx, n)
+}
+
+// notifyNow отправляет письмо, которого пользователь ждёт прямо сейчас:
+// токен сброса пароля или подтверждения адреса. Такие письма идут мимо
+// outbox, поэтому временную ошибку доставки повторяем здесь, но не больше
+// directNotifyAttempts раз и не дольше контекста запроса.
+func (s *AccrualService) notifyNow(ctx context.Context, n models.Notification) error {
+	backoff := directNotifyBackoff
+	for attempt := 1; ; attempt++ {
+		err := s.notifier.Notify(ctx, n)
+		var temporary interface{ Temporary() bool }
+		if err == nil || attempt >= directNotifyAttempts || !errors.As(err, &temporary) || !temporary.Temporary() {
+			return err
+		}
+		logger.Log.Warn("Ошибка отправки письма, повтор",
+			zap.String("kind", n.Kind), zap.Int("user", n.UserID), zap.Int("attempt", attempt), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
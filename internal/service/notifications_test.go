@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/internal/events"
+	"github.com/scoring-service/internal/notify"
+	"github.com/scoring-service/internal/notify/smtptest"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestUpdateNotificationPreferences(t *testing.T) {
+	current := models.NotificationPreferences{Login: "alice", Email: "alice@example.com", Locale: models.LocaleRU}
+
+	t.Run("язык по умолчанию", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+		want := models.NotificationPreferences{Email: "alice@example.com", Locale: models.LocaleRU, PointsCredited: true}
+		mockDB.EXPECT().GetNotificationPreferences(mock.Anything, 1).Return(current, nil).Once()
+		mockDB.EXPECT().SaveNotificationPreferences(mock.Anything, 1, want, "", time.Duration(0)).Return(nil).Once()
+
+		prefs, err := service.UpdateNotificationPreferences(context.Background(), 1,
+			models.NotificationPreferences{Email: "alice@example.com", PointsCredited: true})
+		require.NoError(t, err)
+		require.Equal(t, want, prefs)
+	})
+
+	t.Run("новый адрес ждёт подтверждения", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		notifier := &notifierStub{}
+		service := &AccrualService{db: mockDB, notifier: notifier, cfg: Config{EmailVerificationTTL: time.Hour}}
+		want := models.NotificationPreferences{
+			Email: "alice@example.com", PendingEmail: "new@example.com", Locale: models.LocaleEN, PointsWithdrawn: true,
+		}
+		var tokenHash string
+		mockDB.EXPECT().GetNotificationPreferences(mock.Anything, 1).Return(current, nil).Once()
+		mockDB.EXPECT().SaveNotificationPreferences(mock.Anything, 1, want, mock.Anything, time.Hour).
+			Run(func(ctx context.Context, userID int, prefs models.NotificationPreferences, hash string, ttl time.Duration) {
+				tokenHash = hash
+			}).Return(nil).Once()
+
+		prefs, err := service.UpdateNotificationPreferences(context.Background(), 1,
+			models.NotificationPreferences{Email: "new@example.com", Locale: models.LocaleEN, PointsWithdrawn: true})
+		require.NoError(t, err)
+		require.Equal(t, want, prefs)
+
+		require.Len(t, notifier.sent, 1)
+		sent := notifier.sent[0]
+		require.Equal(t, models.NotificationEmailVerification, sent.Kind)
+		require.Equal(t, "new@example.com", sent.Email)
+		require.Equal(t, "alice", sent.Login)
+		require.Equal(t, auth.HashToken(sent.Token), tokenHash)
+	})
+
+	t.Run("пустой адрес отключает письма", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+		want := models.NotificationPreferences{Locale: models.LocaleRU}
+		mockDB.EXPECT().GetNotificationPreferences(mock.Anything, 1).
+			Return(models.NotificationPreferences{Email: "alice@example.com", PendingEmail: "new@example.com"}, nil).Once()
+		mockDB.EXPECT().SaveNotificationPreferences(mock.Anything, 1, want, "", time.Duration(0)).Return(nil).Once()
+
+		prefs, err := service.UpdateNotificationPreferences(context.Background(), 1, models.NotificationPreferences{})
+		require.NoError(t, err)
+		require.Equal(t, want, prefs)
+	})
+
+	for _, prefs := range []models.NotificationPreferences{
+		{Email: "alice"},
+		{Email: "Alice <alice@example.com>"},
+		{Email: "alice@example.com", Locale: "de"},
+	} {
+		service := &AccrualService{db: NewMockStorage(t)}
+		_, err := service.UpdateNotificationPreferences(context.Background(), 1, prefs)
+		require.ErrorIs(t, err, models.ErrInvalidPreferences)
+	}
+}
+
+func TestConfirmNotificationEmail(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB}
+	mockDB.EXPECT().ConfirmNotificationEmail(mock.Anything, 1, auth.HashToken("token")).Return(nil).Once()
+	mockDB.EXPECT().ConfirmNotificationEmail(mock.Anything, 1, auth.HashToken("stale")).
+		Return(models.ErrInvalidEmailToken).Once()
+
+	require.NoError(t, service.ConfirmNotificationEmail(context.Background(), 1, "token"))
+	require.ErrorIs(t, service.ConfirmNotificationEmail(context.Background(), 1, "stale"), models.ErrInvalidEmailToken)
+	require.ErrorIs(t, service.ConfirmNotificationEmail(context.Background(), 1, ""), models.ErrInvalidEmailToken)
+}
+
+func TestNotifyPoints(t *testing.T) {
+	prefs := models.NotificationPreferences{
+		Login: "alice", Email: "alice@example.com", Locale: models.LocaleEN, PointsWithdrawn: true,
+	}
+
+	t.Run("списание", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		notifier := &notifierStub{}
+		service := &AccrualService{db: mockDB, notifier: notifier}
+		mockDB.EXPECT().GetNotificationPreferences(mock.Anything, 1).Return(prefs, nil).Once()
+
+		require.NoError(t, service.NotifyPointsWithdrawn(context.Background(),
+			models.PointsWithdrawnEvent{UserID: 1, Order: "2377225624", Sum: 100}))
+		require.Equal(t, []models.Notification{{
+			Kind:   models.NotificationPointsWithdrawn,
+			UserID: 1,
+			Login:  "alice",
+			Email:  "alice@example.com",
+			Locale: models.LocaleEN,
+			Order:  "2377225624",
+			Amount: 100,
+		}}, notifier.sent)
+	})
+
+	t.Run("начисления отключены", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		notifier := &notifierStub{}
+		service := &AccrualService{db: mockDB, notifier: notifier}
+		mockDB.EXPECT().GetNotificationPreferences(mock.Anything, 1).Return(prefs, nil).Once()
+
+		require.NoError(t, service.NotifyPointsCredited(context.Background(),
+			models.OrderProcessedEvent{UserID: 1, Order: "12345678903", Accrual: 500}))
+		require.Empty(t, notifier.sent)
+	})
+
+	t.Run("заказ без начисления", func(t *testing.T) {
+		notifier := &notifierStub{}
+		service := &AccrualService{db: NewMockStorage(t), notifier: notifier}
+
+		require.NoError(t, service.NotifyPointsCredited(context.Background(),
+			models.OrderProcessedEvent{UserID: 1, Order: "12345678903"}))
+		require.Empty(t, notifier.sent)
+	})
+}
+
+// TestNotificationsEndToEnd проходит путь от события на шине до письма,
+// принятого SMTP-сервером.
+func TestNotificationsEndToEnd(t *testing.T) {
+	// Первое соединение сервер отклоняет: событие публикуется повторно, как
+	// это сделает outbox, и письмо уходит со второй попытки.
+	server := smtptest.NewServer(t, 1)
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB}
+	service.SetNotifier(&notify.SMTPNotifier{Addr: server.Addr, From: "noreply@gophermart.local"})
+
+	mockDB.EXPECT().GetNotificationPreferences(mock.Anything, 1).Return(models.NotificationPreferences{
+		Login: "alice", Email: "alice@example.com", Locale: models.LocaleRU, PointsCredited: true, PointsWithdrawn: true,
+	}, nil).Times(3)
+
+	bus := events.NewBus()
+	events.Subscribe(bus, "notify.points_credited", service.NotifyPointsCredited)
+	events.Subscribe(bus, "notify.points_withdrawn", service.NotifyPointsWithdrawn)
+
+	for i, event := range []models.DomainEvent{
+		models.OrderProcessedEvent{UserID: 1, Order: "12345678903", Accrual: 729.98},
+		models.PointsWithdrawnEvent{UserID: 1, Order: "2377225624", Sum: 100},
+	} {
+		payload, err := json.Marshal(event)
+		require.NoError(t, err)
+		outboxEvent := models.OutboxEvent{ID: int64(i + 1), Type: event.EventType(), Payload: payload}
+		if i == 0 {
+			require.Error(t, bus.Publish(context.Background(), outboxEvent, nil))
+		}
+		require.NoError(t, bus.Publish(context.Background(), outboxEvent, nil))
+	}
+
+	messages := server.Messages()
+	require.Len(t, messages, 2)
+	require.Equal(t, []string{"alice@example.com"}, messages[0].To)
+	require.Equal(t, "Начислено 729.98 баллов", messages[0].Subject)
+	require.Equal(t, "Списано 100.00 баллов", messages[1].Subject)
+	require.Contains(t, messages[1].Body, "В счёт заказа 2377225624 списано 100.00 баллов.")
+}
+
+// Письмо с токеном идёт мимо outbox, поэтому временный отказ SMTP-сервера
+// повторяется сразу, а постоянный — нет.
+func TestNotifyNowRetries(t *testing.T) {
+	n := models.Notification{Kind: models.NotificationPasswordReset, UserID: 3, Email: "alice@example.com", Token: "token"}
+
+	t.Run("временная ошибка повторяется", func(t *testing.T) {
+		server := smtptest.NewServer(t, 1)
+		service := &AccrualService{notifier: &notify.SMTPNotifier{Addr: server.Addr, From: "noreply@gophermart.local"}}
+
+		require.NoError(t, service.notifyNow(context.Background(), n))
+		require.Len(t, server.Messages(), 1)
+	})
+
+	t.Run("попытки ограничены", func(t *testing.T) {
+		server := smtptest.NewServer(t, directNotifyAttempts)
+		service := &AccrualService{notifier: &notify.SMTPNotifier{Addr: server.Addr, From: "noreply@gophermart.local"}}
+
+		require.Error(t, service.notifyNow(context.Background(), n))
+		require.Empty(t, server.Messages())
+	})
+
+	t.Run("прочие ошибки не повторяются", func(t *testing.T) {
+		failing := &failingNotifier{err: errors.New("шаблон не найден")}
+		service := &AccrualService{notifier: failing}
+
+		require.ErrorIs(t, service.notifyNow(context.Background(), n), failing.err)
+		require.Equal(t, 1, failing.calls)
+	})
+}
+
+type failingNotifier struct {
+	err   error
+	calls int
+}
+
+func (f *failingNotifier) Notify(ctx context.Context, n models.Notification) error {
+	f.calls++
+	return f.err
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// LoadPasswordDenylist читает список распространённых паролей: по одному
+// на строку, пустые строки и строки, начинающиеся с #, пропускаются.
+func LoadPasswordDenylist(path string) (map[string]struct{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть список запрещённых паролей: %w", err)
+	}
+	defer f.Close()
+
+	denylist := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		denylist[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("не удалось прочитать список запрещённых паролей: %w", err)
+	}
+	return denylist, nil
+}
+
+func (s *AccrualService) validatePassword(password string) error {
+	policy := s.cfg.Password
+	length := utf8.RuneCountInString(password)
+	if policy.MinLength > 0 && length < policy.MinLength {
+		return fmt.Errorf("%w: длина должна быть не меньше %d символов", models.ErrWeakPassword, policy.MinLength)
+	}
+	if policy.MaxLength > 0 && length > policy.MaxLength {
+		return fmt.Errorf("%w: длина должна быть не больше %d символов", models.ErrWeakPassword, policy.MaxLength)
+	}
+	if policy.MaxBytes > 0 && len(password) > policy.MaxBytes {
+		return fmt.Errorf("%w: длина должна быть не больше %d байт", models.ErrWeakPassword, policy.MaxBytes)
+	}
+	if _, ok := policy.Denylist[strings.ToLower(password)]; ok {
+		return fmt.Errorf("%w: пароль входит в список распространённых", models.ErrWeakPassword)
+	}
+	return nil
+}
+
+// rehashPassword пересчитывает хеш, полученный устаревшим алгоритмом или с
+// устаревшими параметрами. Вход не прерывается, если обновить хеш не удалось.
+func (s *AccrualService) rehashPassword(ctx context.Context, userID int, password, hash string) {
+	if !auth.PasswordNeedsRehash(hash) {
+		return
+	}
+	newHash, err := auth.HashPassword(password)
+	if err != nil {
+		logger.Log.Error("не удалось пересчитать хеш пароля", zap.Int("user_id", userID), zap.Error(err))
+		return
+	}
+	if err := s.db.UpdatePasswordHash(ctx, userID, newHash); err != nil {
+		logger.Log.Error("не удалось обновить хеш пароля", zap.Int("user_id", userID), zap.Error(err))
+	}
+}
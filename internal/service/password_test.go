@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestValidatePassword(t *testing.T) {
+	service := &AccrualService{cfg: Config{Password: models.PasswordPolicy{
+		MinLength: 8,
+		MaxLength: 16,
+		Denylist:  map[string]struct{}{"password123": {}},
+	}}}
+
+	tests := []struct {
+		name        string
+		password    string
+		errContains string
+	}{
+		{name: "подходящий пароль", password: "s3cret-enough"},
+		{name: "кириллица считается по символам", password: "пароль-ок"},
+		{name: "короткий пароль", password: "short", errContains: "не меньше 8 символов"},
+		{name: "длинный пароль", password: strings.Repeat("a", 17), errContains: "не больше 16 символов"},
+		{name: "распространённый пароль", password: "Password123", errContains: "распространённых"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.validatePassword(tt.password)
+			if tt.errContains == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, models.ErrWeakPassword)
+			require.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+func TestValidatePasswordMaxBytes(t *testing.T) {
+	service := &AccrualService{cfg: Config{Password: models.PasswordPolicy{
+		MaxLength: 72,
+		MaxBytes:  72,
+	}}}
+
+	require.NoError(t, service.validatePassword(strings.Repeat("a", 72)))
+	// 40 символов кириллицы укладываются в MaxLength, но занимают 80 байт.
+	err := service.validatePassword(strings.Repeat("ж", 40))
+	require.ErrorIs(t, err, models.ErrWeakPassword)
+	require.Contains(t, err.Error(), "не больше 72 байт")
+}
+func TestLoadPasswordDenylist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "common.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# топ паролей\nQwerty\n\n  123456  \n"), 0o600))
+
+	denylist, err := LoadPasswordDenylist(path)
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"qwerty": {}, "123456": {}}, denylist)
+
+	_, err = LoadPasswordDenylist(filepath.Join(t.TempDir(), "missing.txt"))
+	require.Error(t, err)
+}
+func TestAuthorizeUserRehash(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB}
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	mockDB.EXPECT().GetUserByLogin(mock.Anything, "alice").
+		Return(&models.User{ID: 3, Login: "alice", Password: string(legacy)}, nil).Once()
+	mockDB.EXPECT().UpdatePasswordHash(mock.Anything, 3, mock.MatchedBy(func(hash string) bool {
+		return strings.HasPrefix(hash, "$argon2id$")
+	})).Return(nil).Once()
+	mockDB.EXPECT().GetUserPermissions(mock.Anything, 3).Return(nil, nil).Once()
+
+	require.NoError(t, service.AuthorizeUser(context.Background(), &models.User{Login: "alice", Password: "secret"}))
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"log"
 	"sync"
 	"time"
@@ -24,6 +25,8 @@ var (
 	once            sync.Once
 )
 
+var ErrQueueFull = errors.New("очередь опроса заказов переполнена")
+
 func GetQueueManager(service *AccrualService) *QueueManager {
 	once.Do(func() {
 		managerInstance = QueueManager{
@@ -54,14 +57,21 @@ func (q *QueueManager) startWorkers() {
 	}
 }
 
-func (q *QueueManager) EnqueueOrder(orderNum string) {
+// EnqueueOrder ставит заказ в очередь опроса, не блокируя вызывающего.
+// Заказ, уже стоящий в очереди, повторно не добавляется.
+func (q *QueueManager) EnqueueOrder(orderNum string) error {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 	if _, exists := q.queue[orderNum]; exists {
-		return
+		return nil
+	}
+	select {
+	case q.jobChan <- orderNum:
+		q.queue[orderNum] = struct{}{}
+		return nil
+	default:
+		return ErrQueueFull
 	}
-	q.queue[orderNum] = struct{}{}
-	q.jobChan <- orderNum
 }
 
 func (q *QueueManager) DequeueOrder(orderNum string) {
@@ -90,12 +100,16 @@ func (q *QueueManager) processPendingOrders() {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	pendingOrders, err := q.service.db.GetPendingOrders(ctx)
+	pendingOrders, err := q.service.db.GetPendingOrders(ctx, q.service.pollDelay())
 	if err != nil {
 		return
 	}
 
 	for _, order := range pendingOrders {
-		q.EnqueueOrder(order)
+		if err := q.EnqueueOrder(order); err != nil {
+			// Оставшиеся заказы подберёт следующий проход.
+			logger.Log.Warn("Заказы не поставлены в очередь опроса", zap.Int("pending", len(pendingOrders)), zap.Error(err))
+			return
+		}
 	}
 }
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// reconcileTimeout ограничивает одну сверку: она читает историю операций
+// всех пользователей.
+const reconcileTimeout = 10 * time.Minute
+
+// Reconcile сверяет балансы пользователей с историей операций и
+// сохраняет итог. При ReconcileAutoCorrect расхождения исправляются от
+// имени сверки; исправление пользователя, которое не удалось, не мешает
+// остальным. Итог сохраняется и тогда, когда сверка завершилась ошибкой.
+func (s *AccrualService) Reconcile(ctx context.Context) (models.ReconciliationRun, error) {
+	run := models.ReconciliationRun{
+		StartedAt:   time.Now(),
+		AutoCorrect: s.cfg.ReconcileAutoCorrect,
+		Drifts:      []models.BalanceDrift{},
+	}
+	if err := s.db.StartReconciliationRun(ctx, &run); err != nil {
+		return run, err
+	}
+	checked, drifts, err := s.db.FindBalanceDrifts(ctx)
+	run.UsersChecked = checked
+	var errs []error
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, d := range drifts {
+		logger.Log.Warn("Баланс пользователя расходится с историей операций",
+			zap.Int("user", d.UserID),
+			zap.Float64("balance", d.Balance),
+			zap.Float64("expected_balance", d.ExpectedBalance),
+			zap.Float64("withdrawn", d.Withdrawn),
+			zap.Float64("expected_withdrawn", d.ExpectedWithdrawn),
+		)
+		if run.AutoCorrect {
+			corrected, ok, err := s.db.CorrectBalance(ctx, run.ID, d.UserID)
+			if err != nil {
+				errs = append(errs, err)
+			} else if ok {
+				d = corrected
+				logger.Log.Info("Баланс пользователя исправлен", zap.Int("user", d.UserID))
+			}
+		}
+		run.Drifts = append(run.Drifts, d)
+	}
+	if err := errors.Join(errs...); err != nil {
+		run.Error = err.Error()
+	}
+	run.FinishedAt = time.Now()
+
+	if err := s.db.FinishReconciliationRun(ctx, run); err != nil {
+		errs = append(errs, err)
+	}
+	return run, errors.Join(errs...)
+}
+
+func (s *AccrualService) GetLastReconciliation(ctx context.Context) (models.ReconciliationRun, error) {
+	return s.db.GetLastReconciliationRun(ctx)
+}
+
+func (s *AccrualService) RunReconciliationJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			jobCtx, cancel := context.WithTimeout(ctx, reconcileTimeout)
+			run, err := s.Reconcile(jobCtx)
+			if err != nil {
+				logger.Log.Error("Ошибка сверки балансов", zap.Error(err))
+			} else {
+				logger.Log.Info("Сверка балансов завершена",
+					zap.Int("users", run.UsersChecked), zap.Int("drifts", len(run.Drifts)))
+			}
+			cancel()
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestReconcile(t *testing.T) {
+	drifts := []models.BalanceDrift{
+		{UserID: 3, Login: "alice", Balance: 1000, ExpectedBalance: 500},
+		{UserID: 4, Login: "bob", Balance: 200, ExpectedBalance: 300},
+	}
+
+	t.Run("только отчёт", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+		mockDB.EXPECT().StartReconciliationRun(mock.Anything, mock.Anything).Return(nil).Once()
+		mockDB.EXPECT().FindBalanceDrifts(mock.Anything).Return(42, drifts, nil).Once()
+		mockDB.EXPECT().FinishReconciliationRun(mock.Anything, mock.MatchedBy(func(run models.ReconciliationRun) bool {
+			return !run.AutoCorrect && run.UsersChecked == 42 && len(run.Drifts) == 2 && run.Error == ""
+		})).Return(nil).Once()
+
+		run, err := service.Reconcile(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, drifts, run.Drifts)
+	})
+
+	t.Run("с исправлением", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB, cfg: Config{ReconcileAutoCorrect: true}}
+		mockDB.EXPECT().StartReconciliationRun(mock.Anything, mock.Anything).
+			Run(func(_ context.Context, run *models.ReconciliationRun) { run.ID = 7 }).Return(nil).Once()
+		mockDB.EXPECT().FindBalanceDrifts(mock.Anything).Return(42, drifts, nil).Once()
+		fixed := drifts[0]
+		fixed.Corrected = true
+		mockDB.EXPECT().CorrectBalance(mock.Anything, 7, 3).Return(fixed, true, nil).Once()
+		mockDB.EXPECT().CorrectBalance(mock.Anything, 7, 4).Return(models.BalanceDrift{}, false, errors.New("serialization failure")).Once()
+		mockDB.EXPECT().FinishReconciliationRun(mock.Anything, mock.MatchedBy(func(run models.ReconciliationRun) bool {
+			return run.ID == 7 && run.AutoCorrect && run.Error == "serialization failure"
+		})).Return(nil).Once()
+
+		run, err := service.Reconcile(context.Background())
+		require.Error(t, err)
+		require.True(t, run.Drifts[0].Corrected)
+		require.False(t, run.Drifts[1].Corrected)
+	})
+
+	t.Run("сверка не зарегистрирована", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB, cfg: Config{ReconcileAutoCorrect: true}}
+		mockDB.EXPECT().StartReconciliationRun(mock.Anything, mock.Anything).Return(errors.New("db down")).Once()
+
+		_, err := service.Reconcile(context.Background())
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+const (
+	referralAlphabet   = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	referralCodeLength = 8
+	// referralCodeAttempts ограничивает повторы при совпадении кода.
+	referralCodeAttempts = 5
+)
+
+func newReferralCode() (string, error) {
+	buf := make([]byte, referralCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i := range buf {
+		buf[i] = referralAlphabet[int(buf[i])%len(referralAlphabet)]
+	}
+	return string(buf), nil
+}
+
+func (s *AccrualService) findReferrer(ctx context.Context, code string) (*models.User, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return nil, nil
+	}
+	referrer, err := s.db.GetUserByReferralCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if referrer == nil {
+		return nil, models.ErrInvalidReferralCode
+	}
+	return referrer, nil
+}
+
+// createUser сохраняет пользователя с новым реферальным кодом. Код,
+// совпавший с уже выданным, генерируется заново.
+func (s *AccrualService) createUser(ctx context.Context, user *models.User) error {
+	var err error
+	for attempt := 0; attempt < referralCodeAttempts; attempt++ {
+		user.ReferralCode, err = newReferralCode()
+		if err != nil {
+			return err
+		}
+		err = s.db.CreateUser(ctx, user)
+		if !errors.Is(err, models.ErrReferralCodeTaken) {
+			return err
+		}
+		logger.Log.Warn("Сгенерированный реферальный код уже занят", zap.Int("attempt", attempt+1))
+	}
+	return err
+}
+
+func (s *AccrualService) GetUserReferrals(ctx context.Context, userID int) (models.ReferralSummary, error) {
+	return s.db.GetUserReferrals(ctx, userID)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestRegisterUserWithReferral(t *testing.T) {
+	t.Run("приглашение по коду", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		mockDB.EXPECT().GetUserByReferralCode(mock.Anything, "ABCD2345").
+			Return(&models.User{ID: 1, Login: "alice"}, nil).Once()
+		mockDB.EXPECT().CreateUser(mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+			return len(u.ReferralCode) == referralCodeLength && u.ReferrerID == 1
+		})).Run(func(_ context.Context, u *models.User) { u.ID = 2 }).Return(nil).Once()
+		mockDB.EXPECT().GetUserPermissions(mock.Anything, 2).Return(nil, nil).Once()
+
+		user := &models.User{Login: "bob", Password: "secret", InvitedWith: " abcd2345 "}
+		require.NoError(t, service.ReagisterUser(context.Background(), user))
+	})
+
+	t.Run("совпадение реферального кода", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		var codes []string
+		mockDB.EXPECT().CreateUser(mock.Anything, mock.Anything).
+			Run(func(_ context.Context, u *models.User) { codes = append(codes, u.ReferralCode) }).
+			Return(models.ErrReferralCodeTaken).Once()
+		mockDB.EXPECT().CreateUser(mock.Anything, mock.Anything).
+			Run(func(_ context.Context, u *models.User) {
+				codes = append(codes, u.ReferralCode)
+				u.ID = 2
+			}).
+			Return(nil).Once()
+		mockDB.EXPECT().GetUserPermissions(mock.Anything, 2).Return(nil, nil).Once()
+
+		user := &models.User{Login: "bob", Password: "secret"}
+		require.NoError(t, service.ReagisterUser(context.Background(), user))
+		require.Len(t, codes, 2)
+		require.Len(t, codes[1], referralCodeLength)
+	})
+
+	t.Run("код занят на всех попытках", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		mockDB.EXPECT().CreateUser(mock.Anything, mock.Anything).
+			Return(models.ErrReferralCodeTaken).Times(referralCodeAttempts)
+
+		user := &models.User{Login: "bob", Password: "secret"}
+		require.ErrorIs(t, service.ReagisterUser(context.Background(), user), models.ErrReferralCodeTaken)
+	})
+
+	t.Run("неизвестный код", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		mockDB.EXPECT().GetUserByReferralCode(mock.Anything, "NOPE").Return(nil, nil).Once()
+
+		user := &models.User{Login: "bob", Password: "secret", InvitedWith: "NOPE"}
+		require.ErrorIs(t, service.ReagisterUser(context.Background(), user), models.ErrInvalidReferralCode)
+	})
+}
+func TestNewReferralCode(t *testing.T) {
+	code, err := newReferralCode()
+	require.NoError(t, err)
+	require.Len(t, code, referralCodeLength)
+	for _, c := range code {
+		require.Contains(t, referralAlphabet, string(c))
+	}
+}
@@ -2,18 +2,16 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
-	"fmt"
 	"net/http"
-	"net/url"
-	"path"
-	"strconv"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/internal/campaign"
+	"github.com/scoring-service/internal/webhook"
 	"github.com/scoring-service/pkg/logger"
 	"github.com/scoring-service/pkg/models"
 )
@@ -26,16 +24,161 @@ type Storage interface {
 	GetUserWithdrawals(ctx context.Context, userID int) ([]models.Withdrawal, error)
 	GetUserBalance(ctx context.Context, userID int) (models.Balance, error)
 	SaveOrder(ctx context.Context, user int, order *models.Order) error
-	UpdateOrder(ctx context.Context, accrual *models.AccrualResponse) error
+	UpdateOrder(ctx context.Context, accrual *models.AccrualResponse, policy models.CreditPolicy) error
 	IsOrderExists(ctx context.Context, orderNum string) (int, error)
 	Withdraw(ctx context.Context, userID int, order string, sum float64) error
-	GetPendingOrders(ctx context.Context) ([]string, error)
+	GetPendingOrders(ctx context.Context, olderThan time.Duration) ([]string, error)
+	Transfer(ctx context.Context, senderID int, recipientLogin string, sum float64, limits models.TransferLimits) error
+	GetUserTransfers(ctx context.Context, userID int) ([]models.Transfer, error)
+	GetExpiringPoints(ctx context.Context, userID int) ([]models.ExpiringPoints, error)
+	ExpirePoints(ctx context.Context) (int64, error)
+	GetTiers(ctx context.Context) ([]models.Tier, error)
+	GetRollingAccrual(ctx context.Context, userID int, windowMonths int) (float64, error)
+	ListCampaigns(ctx context.Context) ([]models.Campaign, error)
+	GetCampaign(ctx context.Context, id int) (models.Campaign, error)
+	CreateCampaign(ctx context.Context, c *models.Campaign) error
+	UpdateCampaign(ctx context.Context, c *models.Campaign) error
+	DeactivateCampaign(ctx context.Context, id int) error
+	GetProcessedOrderFacts(ctx context.Context, from, to time.Time) ([]campaign.OrderFacts, error)
+	GetUserByReferralCode(ctx context.Context, code string) (*models.User, error)
+	GetUserReferrals(ctx context.Context, userID int) (models.ReferralSummary, error)
+	SearchUsers(ctx context.Context, query string, limit int) ([]models.AdminUser, error)
+	GetAdminUser(ctx context.Context, userID int) (models.AdminUser, error)
+	IsUserBlocked(ctx context.Context, userID int) (bool, error)
+	LogAdminAction(ctx context.Context, action models.AdminAction) error
+	AdjustBalance(ctx context.Context, adminID, userID int, adjustment models.Adjustment, lifetimeMonths int) error
+	SetUserBlocked(ctx context.Context, adminID, userID int, blocked bool, reason string) error
+	GetUserPermissions(ctx context.Context, userID int) ([]string, error)
+	SetUserAccess(ctx context.Context, adminID, userID int, access models.UserAccess) error
+	CreateSession(ctx context.Context, userID int, tokenHash string, meta models.SessionMeta, ttl time.Duration) (int, error)
+	RotateRefreshToken(ctx context.Context, oldHash, newHash string, ttl time.Duration) (*models.User, error)
+	GetUserSessions(ctx context.Context, userID int) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID int) error
+	RevokeOtherSessions(ctx context.Context, userID, keepSessionID int) error
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, jti string, sessionID int) (bool, error)
+	GetLoginThrottles(ctx context.Context, login, ip string) ([]models.LoginThrottle, error)
+	RecordLoginFailure(ctx context.Context, login, ip, reason string, policy models.LoginPolicy) error
+	RecordLoginSuccess(ctx context.Context, login, ip string) error
+	UnlockLogin(ctx context.Context, adminID, userID int) error
+	UpdatePasswordHash(ctx context.Context, userID int, hash string) error
+	GetPasswordHash(ctx context.Context, userID int) (string, error)
+	CreatePasswordReset(ctx context.Context, userID int, tokenHash string, ttl time.Duration) error
+	ResetPassword(ctx context.Context, tokenHash, passwordHash string) (int, error)
+	DeleteUser(ctx context.Context, userID int) error
+	GetTOTP(ctx context.Context, userID int) (models.TOTP, error)
+	SaveTOTPSecret(ctx context.Context, userID int, secret string) error
+	EnableTOTP(ctx context.Context, userID int, step int64, codeHashes []string) error
+	DisableTOTP(ctx context.Context, userID int) error
+	UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error)
+	GetTOTPThrottle(ctx context.Context, userID int) (models.LoginThrottle, error)
+	RecordTOTPFailure(ctx context.Context, userID int, policy models.LoginPolicy) error
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+	SetWithdrawThreshold(ctx context.Context, userID int, threshold float64) error
+	CreateLoginChallenge(ctx context.Context, userID int, tokenHash string, ttl time.Duration, maxActive int) error
+	GetLoginChallenge(ctx context.Context, tokenHash string) (*models.User, error)
+	FailLoginChallenge(ctx context.Context, tokenHash string, maxAttempts int) error
+	ConsumeLoginChallenge(ctx context.Context, tokenHash string) (*models.User, error)
+	CreateMerchant(ctx context.Context, adminID int, login string, merchant *models.Merchant, keyHash string) (int, error)
+	AddMerchantAPIKey(ctx context.Context, adminID, merchantID int, keyHash string) (int, error)
+	RevokeMerchantAPIKey(ctx context.Context, adminID, merchantID, keyID int) error
+	GetMerchantByAPIKey(ctx context.Context, keyHash string) (*models.Merchant, error)
+	AttachOrderToMerchant(ctx context.Context, merchantID int, orderNum string, window time.Duration) (bool, error)
+	CreateCheckoutCode(ctx context.Context, userID int, codeHash string, ttl time.Duration) (time.Time, error)
+	RedeemCheckoutCode(ctx context.Context, merchantID int, codeHash string) (int, error)
+	ReleaseCheckoutCode(ctx context.Context, merchantID int, codeHash string) error
+	GetCheckoutThrottle(ctx context.Context, merchantID int) (models.LoginThrottle, error)
+	RecordCheckoutFailure(ctx context.Context, merchantID int, policy models.LoginPolicy) error
+	CreateCustomerToken(ctx context.Context, userID int, tokenHash string, ttl time.Duration) (time.Time, error)
+	ConsumeCustomerToken(ctx context.Context, merchantID int, tokenHash string) (int, error)
+	RegisterMerchantNonce(ctx context.Context, merchantID int, nonce string, ttl time.Duration) error
+	GetPasswordResetThrottle(ctx context.Context, login string) (models.LoginThrottle, error)
+	RecordPasswordResetRequest(ctx context.Context, login string, policy models.LoginPolicy) error
+	GetOrderOwners(ctx context.Context, numbers []string) (map[string]int, error)
+	SaveOrders(ctx context.Context, userID int, numbers []string) ([]string, error)
+	StartIngestionRun(ctx context.Context, fileName, checksum string) (int, error)
+	FinishIngestionRun(ctx context.Context, run models.IngestionRun) error
+	SaveOrderWithItems(ctx context.Context, user int, order *models.Order, items []models.OrderItem) error
+	GetOrderItems(ctx context.Context, orderNum string) ([]models.OrderItem, error)
+	ApplyAccrualCallback(ctx context.Context, signature string, ttl time.Duration, accruals []models.AccrualResponse, policy models.CreditPolicy) error
+	WithdrawAtMerchant(ctx context.Context, merchantID, userID int, order string, sum float64) error
+	CreateWebhookSubscription(ctx context.Context, merchantID int, sub *models.WebhookSubscription) error
+	GetWebhookSubscriptions(ctx context.Context, merchantID int) ([]models.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, merchantID, subscriptionID int) error
+	GetWebhookDeliveries(ctx context.Context, merchantID int, status string, limit int) ([]models.WebhookDelivery, error)
+	RedeliverWebhook(ctx context.Context, merchantID, deliveryID int) error
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+	RecordWebhookAttempt(ctx context.Context, attempt models.WebhookAttempt) error
+	GetNotificationPreferences(ctx context.Context, userID int) (models.NotificationPreferences, error)
+	SaveNotificationPreferences(ctx context.Context, userID int, prefs models.NotificationPreferences, tokenHash string, ttl time.Duration) error
+	ConfirmNotificationEmail(ctx context.Context, userID int, tokenHash string) error
+	StreamStatement(ctx context.Context, userID int, from, to time.Time, fn func(models.StatementEntry) error) error
+	GetBalanceAt(ctx context.Context, userID int, at time.Time) (models.Balance, error)
+	FindBalanceDrifts(ctx context.Context) (int, []models.BalanceDrift, error)
+	CorrectBalance(ctx context.Context, runID, userID int) (models.BalanceDrift, bool, error)
+	StartReconciliationRun(ctx context.Context, run *models.ReconciliationRun) error
+	FinishReconciliationRun(ctx context.Context, run models.ReconciliationRun) error
+	GetLastReconciliationRun(ctx context.Context) (models.ReconciliationRun, error)
+}
+
+type OrderQueue interface {
+	EnqueueOrder(orderNum string) error
+}
+
+type Config struct {
+	TransferLimits       models.TransferLimits
+	PointsLifetimeMonths int
+	TierWindowMonths     int
+	Referral             models.ReferralPolicy
+	RefreshTokenTTL      time.Duration
+	Login                models.LoginPolicy
+	Password             models.PasswordPolicy
+	PasswordResetTTL     time.Duration
+	// PasswordResetThrottle ограничивает письма сброса пароля на один логин;
+	// используются MaxFailures, Lockout и Window.
+	PasswordResetThrottle models.LoginPolicy
+	TOTPIssuer            string
+	LoginChallengeTTL     time.Duration
+	CheckoutCodeTTL       time.Duration
+	// CheckoutCodeSecret — ключ HMAC, с которым в базе хранятся коды оплаты.
+	CheckoutCodeSecret string
+	// CustomerTokenTTL — срок действия токена покупателя для загрузки
+	// заказа мерчантом.
+	CustomerTokenTTL time.Duration
+	// CheckoutThrottle ограничивает перебор кодов оплаты мерчантом;
+	// используются MaxFailures, Lockout и Window.
+	CheckoutThrottle models.LoginPolicy
+	// OrderBatchLimit — максимум номеров в одной пакетной загрузке.
+	OrderBatchLimit int
+	// AccrualCallbackSecret подписывает уведомления системы начислений;
+	// пустой секрет выключает приём уведомлений.
+	AccrualCallbackSecret string
+	// AccrualCallbackTimeout — сколько ждать уведомления, прежде чем
+	// опросить систему начислений о заказе.
+	AccrualCallbackTimeout time.Duration
+	// WebhookMaxAttempts — попыток доставки вебхука до перехода в DEAD;
+	// задержка между попытками растёт от WebhookRetryBase до WebhookRetryMax.
+	WebhookMaxAttempts int
+	WebhookRetryBase   time.Duration
+	WebhookRetryMax    time.Duration
+	// ReconcileAutoCorrect — исправлять ли найденные сверкой расхождения
+	// балансов.
+	ReconcileAutoCorrect bool
+	// EmailVerificationTTL — срок действия токена подтверждения адреса почты.
+	EmailVerificationTTL time.Duration
 }
 
 type AccrualService struct {
-	db     Storage
-	client *http.Client
-	apiURL string
+	db       Storage
+	client   *http.Client
+	apiURL   string
+	cfg      Config
+	queue    OrderQueue
+	notifier Notifier
+	provider AccrualProvider
+	webhooks WebhookSender
+	resolver webhook.Resolver
+	blocked  blockedCache
 }
 type CreateStatus int
 
@@ -45,85 +188,36 @@ const (
 	StatusConflict
 	StatusInvalid
 	StatusError
+	StatusNotFound
+	StatusLimitExceeded
+	StatusTOTPRequired
+	StatusThrottled
 )
 
-func NewAccrualService(db Storage, apiURL string) *AccrualService {
+func NewAccrualService(db Storage, apiURL string, cfg Config) *AccrualService {
 	serviceInstance := AccrualService{
 		db:     db,
 		client: &http.Client{},
 		apiURL: apiURL,
+		cfg:    cfg,
 	}
 	return &serviceInstance
 }
 
-func (s *AccrualService) FetchAccrual(ctx context.Context, orderNumber string) error {
-	attempts := 0
-	maxAttempts := 5
-	backoff := time.Second
-	for {
-		if attempts >= maxAttempts {
-			logger.Log.Error("max retries reached")
-			return errors.New("max retries reached")
-		}
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
-		u, err := url.Parse(s.apiURL)
-		if err != nil {
-			return fmt.Errorf("неверный API URL: %w", err)
-		}
-		u.Path = path.Join(u.Path, "api/orders", orderNumber)
-
-		resp, err := s.client.Get(u.String())
-		if err != nil {
-			logger.Log.Error(err.Error())
-
-			time.Sleep(backoff)
-			backoff *= 2
-			attempts++
-			continue
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode == http.StatusNoContent {
-			logger.Log.Error("order not registered")
-			return errors.New("order not registered")
-		}
-
-		if resp.StatusCode == http.StatusTooManyRequests {
-			retryKey := resp.Header.Get("Retry-After")
-			if retryKey != "" {
-				retry, err := strconv.Atoi(retryKey)
-				if err == nil {
-					backoff = time.Second
-					time.Sleep(time.Second * time.Duration(retry))
-				}
-
-			}
-			continue
-		}
-
-		if resp.StatusCode == http.StatusInternalServerError {
-			logger.Log.Error("internal server error")
-			return errors.New("internal server error")
-		}
-
-		var accrual models.AccrualResponse
-		if err := json.NewDecoder(resp.Body).Decode(&accrual); err != nil {
-			logger.Log.Error(err.Error())
-			return err
-		}
-		if err := s.db.UpdateOrder(ctx, &accrual); err != nil {
-			logger.Log.Error(err.Error())
-			return err
-		}
-
-		return nil
+func (s *AccrualService) SetQueue(queue OrderQueue) {
+	s.queue = queue
+}
 
+func (s *AccrualService) FetchAccrual(ctx context.Context, orderNumber string) error {
+	provider := s.provider
+	if provider == nil {
+		provider = &HTTPAccrualProvider{Client: s.client, URL: s.apiURL}
 	}
+	accrual, err := provider.GetAccrual(ctx, orderNumber)
+	if err != nil {
+		return err
+	}
+	return s.applyAccrual(ctx, accrual)
 }
 
 func (s *AccrualService) UserExist(ctx context.Context, login string) (bool, error) {
@@ -138,16 +232,30 @@ func (s *AccrualService) UserExist(ctx context.Context, login string) (bool, err
 }
 
 func (s *AccrualService) ReagisterUser(ctx context.Context, user *models.User) error {
+	if strings.HasPrefix(user.Login, models.DeletedLoginPrefix) {
+		return models.ErrReservedLogin
+	}
+	if err := s.validatePassword(user.Password); err != nil {
+		return err
+	}
+	referrer, err := s.findReferrer(ctx, user.InvitedWith)
+	if err != nil {
+		return err
+	}
 	hashedPassword, err := auth.HashPassword(user.Password)
 	if err != nil {
 		return err
 	}
 	user.Password = hashedPassword
-	err = s.db.CreateUser(ctx, user)
-	if err != nil {
+	if referrer != nil {
+		user.ReferrerID = referrer.ID
+	}
+	if err := s.createUser(ctx, user); err != nil {
 		return err
 	}
-	return nil
+	user.Role = models.RoleCustomer
+	user.Permissions, err = s.db.GetUserPermissions(ctx, user.ID)
+	return err
 }
 
 func (s *AccrualService) AuthorizeUser(ctx context.Context, newUser *models.User) error {
@@ -155,12 +263,21 @@ func (s *AccrualService) AuthorizeUser(ctx context.Context, newUser *models.User
 	if err != nil {
 		return err
 	}
+	if user == nil {
+		return models.ErrUserNotFound
+	}
 	if !auth.CheckPasswordHash(newUser.Password, user.Password) {
-		logger.Log.Error("Неверная пара логин/пароль", zap.String("login", newUser.Login), zap.String("Password", newUser.Password))
-		return errors.New("неверная пара логин/пароль")
+		logger.Log.Error("Неверная пара логин/пароль", zap.String("login", newUser.Login))
+		return models.ErrInvalidCredentials
+	}
+	if user.Blocked {
+		return models.ErrUserBlocked
 	}
+	s.rehashPassword(ctx, user.ID, newUser.Password, user.Password)
 	newUser.ID = user.ID
-	return nil
+	newUser.Role = user.Role
+	newUser.Permissions, err = s.db.GetUserPermissions(ctx, user.ID)
+	return err
 }
 
 func (s *AccrualService) GetUserOrders(ctx context.Context, id int) ([]models.Order, error) {
@@ -170,9 +287,21 @@ func (s *AccrualService) GetUserWithdrawals(ctx context.Context, id int) ([]mode
 	return s.db.GetUserWithdrawals(ctx, id)
 }
 func (s *AccrualService) GetUserBalance(ctx context.Context, id int) (models.Balance, error) {
-	return s.db.GetUserBalance(ctx, id)
+	balance, err := s.db.GetUserBalance(ctx, id)
+	if err != nil {
+		return balance, err
+	}
+	balance.Expiring, err = s.db.GetExpiringPoints(ctx, id)
+	if err != nil {
+		return balance, err
+	}
+	return balance, nil
 }
 func (s *AccrualService) CreateOrder(ctx context.Context, userID int, orderNum string) CreateStatus {
+	return s.createOrder(ctx, userID, orderNum, nil)
+}
+
+func (s *AccrualService) createOrder(ctx context.Context, userID int, orderNum string, items []models.OrderItem) CreateStatus {
 	if !auth.IsValidLuhn(orderNum) {
 		logger.Log.Error("invalid order number format", zap.String("order", orderNum))
 		return StatusInvalid
@@ -186,7 +315,17 @@ func (s *AccrualService) CreateOrder(ctx context.Context, userID int, orderNum s
 			Number: orderNum,
 			Status: models.OrderNew,
 		}
-		if err := s.db.SaveOrder(ctx, userID, &newOrder); err != nil {
+		if len(items) > 0 {
+			err = s.db.SaveOrderWithItems(ctx, userID, &newOrder, items)
+		} else {
+			err = s.db.SaveOrder(ctx, userID, &newOrder)
+		}
+		switch {
+		case errors.Is(err, models.ErrOrderExists):
+			return StatusAlreadyExist
+		case errors.Is(err, models.ErrOrderConflict):
+			return StatusConflict
+		case err != nil:
 			return StatusError
 		}
 		return StatusOK
@@ -199,6 +338,12 @@ func (s *AccrualService) CreateOrder(ctx context.Context, userID int, orderNum s
 
 }
 func (s *AccrualService) CreateWithdraw(ctx context.Context, userID int, withdraw models.Withdraw) CreateStatus {
+	return s.createWithdraw(ctx, 0, userID, withdraw)
+}
+
+// createWithdraw списывает баллы; списание на кассе мерчанта (merchantID не
+// ноль) порождает для него событие points.withdrawn.
+func (s *AccrualService) createWithdraw(ctx context.Context, merchantID, userID int, withdraw models.Withdraw) CreateStatus {
 	if !auth.IsValidLuhn(withdraw.Order) {
 		logger.Log.Error("invalid order number format", zap.String("order", withdraw.Order))
 		return StatusInvalid
@@ -210,8 +355,15 @@ func (s *AccrualService) CreateWithdraw(ctx context.Context, userID int, withdra
 	if balance.Current < withdraw.Sum {
 		return StatusConflict
 	}
+	if status := s.spendNeedsTOTP(ctx, userID, withdraw.Sum, withdraw.TOTP); status != StatusOK {
+		return status
+	}
 
-	err = s.db.Withdraw(ctx, userID, withdraw.Order, withdraw.Sum)
+	if merchantID != 0 {
+		err = s.db.WithdrawAtMerchant(ctx, merchantID, userID, withdraw.Order, withdraw.Sum)
+	} else {
+		err = s.db.Withdraw(ctx, userID, withdraw.Order, withdraw.Sum)
+	}
 	if err != nil {
 		return StatusError
 	}
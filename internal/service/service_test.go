@@ -104,7 +104,7 @@ func TestFetchAccrual(t *testing.T) {
 			defer cancel()
 
 			if tt.expectedCall {
-				mockDB.EXPECT().UpdateOrder(mock.Anything, mock.Anything).Return(nil).Once()
+				mockDB.EXPECT().UpdateOrder(mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
 			}
 
 			err := service.FetchAccrual(ctx, "123456")
@@ -185,7 +185,7 @@ func TestUserExist(t *testing.T) {
 }
 func TestRegisterUser(t *testing.T) {
 	mockDB := NewMockStorage(t)
-	service := &AccrualService{db: mockDB}
+	service := &AccrualService{db: mockDB, cfg: Config{Password: models.PasswordPolicy{MaxLength: 128}}}
 
 	tests := []struct {
 		name        string
@@ -204,16 +204,20 @@ func TestRegisterUser(t *testing.T) {
 					})).
 					Return(nil).
 					Once()
+				mockDB.EXPECT().
+					GetUserPermissions(mock.Anything, 0).
+					Return(nil, nil).
+					Once()
 			},
 			wantErr: false,
 		},
 		{
-			name: "ошибка хеширования пароля",
+			name: "слишком длинный пароль",
 			user: &models.User{Login: "fail", Password: string(make([]byte, 1000000))},
 			mockDBFunc: func() {
 			},
 			wantErr:     true,
-			errContains: "bcrypt",
+			errContains: "не больше 128 символов",
 		},
 		{
 			name: "ошибка создания пользователя в БД",
@@ -303,6 +307,13 @@ func TestAuthorizeUser(t *testing.T) {
 					Once()
 			}
 
+			if !tt.wantErr {
+				mockDB.EXPECT().
+					GetUserPermissions(mock.Anything, tt.storedUser.ID).
+					Return([]string{models.PermUsersRead}, nil).
+					Once()
+			}
+
 			err := service.AuthorizeUser(context.Background(), tt.inputUser)
 
 			if tt.wantErr {
@@ -312,6 +323,7 @@ func TestAuthorizeUser(t *testing.T) {
 				}
 			} else {
 				require.NoError(t, err)
+				require.Equal(t, []string{models.PermUsersRead}, tt.inputUser.Permissions)
 			}
 		})
 	}
@@ -463,6 +475,9 @@ func TestCreateWithdraw(t *testing.T) {
 					Once()
 
 				if tt.balanceErr == nil && tt.balance.Current >= tt.withdraw.Sum {
+					mockDB.On("GetTOTP", mock.Anything, tt.userID).
+						Return(models.TOTP{}, nil).
+						Once()
 					mockDB.On("Withdraw", mock.Anything, tt.userID, tt.withdraw.Order, tt.withdraw.Sum).
 						Return(tt.withdrawErr).
 						Once()
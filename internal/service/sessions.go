@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// StartSession открывает сессию для только что аутентифицированного
+// пользователя и возвращает её первый refresh-токен.
+func (s *AccrualService) StartSession(ctx context.Context, user *models.User, meta models.SessionMeta) (string, error) {
+	refreshToken, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return "", err
+	}
+	user.SessionID, err = s.db.CreateSession(ctx, user.ID, auth.HashToken(refreshToken), meta, s.cfg.RefreshTokenTTL)
+	if err != nil {
+		return "", err
+	}
+	return refreshToken, nil
+}
+
+// RefreshSession обменивает refresh-токен на новый и возвращает пользователя
+// с актуальными ролью и правами для выпуска access-токена.
+func (s *AccrualService) RefreshSession(ctx context.Context, refreshToken string) (*models.User, string, error) {
+	if refreshToken == "" {
+		return nil, "", models.ErrInvalidRefreshToken
+	}
+	newToken, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return nil, "", err
+	}
+	user, err := s.db.RotateRefreshToken(ctx, auth.HashToken(refreshToken), auth.HashToken(newToken), s.cfg.RefreshTokenTTL)
+	if err != nil {
+		if errors.Is(err, models.ErrRefreshTokenReused) {
+			logger.Log.Warn("Повторное использование refresh-токена, сессия отозвана")
+		}
+		return nil, "", err
+	}
+	if user.Blocked {
+		return nil, "", models.ErrUserBlocked
+	}
+	user.Permissions, err = s.db.GetUserPermissions(ctx, user.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	return user, newToken, nil
+}
+
+// Logout отзывает текущую сессию и сам access-токен, которым выполнен запрос.
+func (s *AccrualService) Logout(ctx context.Context, principal *auth.Principal) error {
+	if principal.SessionID != 0 {
+		err := s.db.RevokeSession(ctx, principal.UserID, principal.SessionID)
+		if err != nil && !errors.Is(err, models.ErrSessionNotFound) {
+			return err
+		}
+	}
+	if principal.TokenID == "" {
+		return nil
+	}
+	return s.db.RevokeToken(ctx, principal.TokenID, principal.ExpiresAt)
+}
+
+func (s *AccrualService) GetUserSessions(ctx context.Context, userID, currentSessionID int) ([]models.Session, error) {
+	sessions, err := s.db.GetUserSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+func (s *AccrualService) RevokeSession(ctx context.Context, userID, sessionID int) error {
+	return s.db.RevokeSession(ctx, userID, sessionID)
+}
+
+func (s *AccrualService) RevokeOtherSessions(ctx context.Context, userID, keepSessionID int) error {
+	if err := s.db.RevokeOtherSessions(ctx, userID, keepSessionID); err != nil {
+		return err
+	}
+	logger.Log.Info("Отозваны остальные сессии пользователя", zap.Int("user", userID))
+	return nil
+}
+
+func (s *AccrualService) IsTokenRevoked(ctx context.Context, jti string, sessionID int) (bool, error) {
+	if jti == "" && sessionID == 0 {
+		return false, nil
+	}
+	return s.db.IsTokenRevoked(ctx, jti, sessionID)
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestStartSession(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB, cfg: Config{RefreshTokenTTL: time.Hour}}
+
+	var storedHash string
+	mockDB.EXPECT().CreateSession(mock.Anything, 1, mock.Anything, models.SessionMeta{IP: "10.0.0.1"}, time.Hour).
+		Run(func(_ context.Context, _ int, hash string, _ models.SessionMeta, _ time.Duration) { storedHash = hash }).
+		Return(11, nil).Once()
+
+	user := &models.User{ID: 1}
+	token, err := service.StartSession(context.Background(), user, models.SessionMeta{IP: "10.0.0.1"})
+	require.NoError(t, err)
+	require.Equal(t, 11, user.SessionID)
+	require.NotEqual(t, token, storedHash)
+	require.Equal(t, auth.HashToken(token), storedHash)
+}
+func TestRefreshSession(t *testing.T) {
+	t.Run("ротация", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB, cfg: Config{RefreshTokenTTL: time.Hour}}
+
+		mockDB.EXPECT().RotateRefreshToken(mock.Anything, auth.HashToken("old"), mock.Anything, time.Hour).
+			Return(&models.User{ID: 1, Role: models.RoleSupport, SessionID: 11}, nil).Once()
+		mockDB.EXPECT().GetUserPermissions(mock.Anything, 1).Return([]string{models.PermUsersRead}, nil).Once()
+
+		user, token, err := service.RefreshSession(context.Background(), "old")
+		require.NoError(t, err)
+		require.NotEmpty(t, token)
+		require.NotEqual(t, "old", token)
+		require.Equal(t, []string{models.PermUsersRead}, user.Permissions)
+	})
+
+	t.Run("заблокированный пользователь", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		mockDB.EXPECT().RotateRefreshToken(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(&models.User{ID: 1, Blocked: true}, nil).Once()
+
+		_, _, err := service.RefreshSession(context.Background(), "old")
+		require.ErrorIs(t, err, models.ErrUserBlocked)
+	})
+
+	t.Run("пустой токен", func(t *testing.T) {
+		service := &AccrualService{db: NewMockStorage(t)}
+		_, _, err := service.RefreshSession(context.Background(), "")
+		require.ErrorIs(t, err, models.ErrInvalidRefreshToken)
+	})
+}
+func TestLogout(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB}
+	expiresAt := time.Now().Add(time.Hour)
+
+	mockDB.EXPECT().RevokeSession(mock.Anything, 1, 11).Return(nil).Once()
+	mockDB.EXPECT().RevokeToken(mock.Anything, "jti", expiresAt).Return(nil).Once()
+
+	err := service.Logout(context.Background(), &auth.Principal{UserID: 1, SessionID: 11, TokenID: "jti", ExpiresAt: expiresAt})
+	require.NoError(t, err)
+}
+func TestGetUserSessionsMarksCurrent(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB}
+
+	mockDB.EXPECT().GetUserSessions(mock.Anything, 1).
+		Return([]models.Session{{ID: 11}, {ID: 12}}, nil).Once()
+
+	sessions, err := service.GetUserSessions(context.Background(), 1, 12)
+	require.NoError(t, err)
+	require.False(t, sessions[0].Current)
+	require.True(t, sessions[1].Current)
+}
+func TestIsTokenRevokedLegacyToken(t *testing.T) {
+	service := &AccrualService{db: NewMockStorage(t)}
+
+	revoked, err := service.IsTokenRevoked(context.Background(), "", 0)
+	require.NoError(t, err)
+	require.False(t, revoked)
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+// StreamStatement передаёт в fn операции по счёту за [from, to) с
+// балансом после каждой операции.
+func (s *AccrualService) StreamStatement(ctx context.Context, userID int, from, to time.Time, fn func(models.StatementEntry) error) error {
+	if !from.Before(to) {
+		return models.ErrInvalidPeriod
+	}
+	return s.db.StreamStatement(ctx, userID, from, to, fn)
+}
+
+// GetBalanceAt возвращает баланс пользователя на момент at. Баланс
+// восстанавливается по истории операций, сгорающие баллы не показываются.
+func (s *AccrualService) GetBalanceAt(ctx context.Context, userID int, at time.Time) (models.Balance, error) {
+	if at.After(time.Now()) {
+		return models.Balance{}, models.ErrInvalidPeriod
+	}
+	return s.db.GetBalanceAt(ctx, userID, at)
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestStreamStatement(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	fn := func(models.StatementEntry) error { return nil }
+
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB}
+	mockDB.EXPECT().StreamStatement(mock.Anything, 1, from, to, mock.Anything).Return(nil).Once()
+	require.NoError(t, service.StreamStatement(context.Background(), 1, from, to, fn))
+
+	require.ErrorIs(t, service.StreamStatement(context.Background(), 1, to, from, fn), models.ErrInvalidPeriod)
+	require.ErrorIs(t, service.StreamStatement(context.Background(), 1, from, from, fn), models.ErrInvalidPeriod)
+}
+
+func TestGetBalanceAt(t *testing.T) {
+	at := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB}
+	mockDB.EXPECT().GetBalanceAt(mock.Anything, 1, at).Return(models.Balance{Current: 600, Withdrawn: 100}, nil).Once()
+
+	balance, err := service.GetBalanceAt(context.Background(), 1, at)
+	require.NoError(t, err)
+	require.Equal(t, models.Balance{Current: 600, Withdrawn: 100}, balance)
+
+	_, err = service.GetBalanceAt(context.Background(), 1, time.Now().Add(time.Hour))
+	require.ErrorIs(t, err, models.ErrInvalidPeriod)
+}
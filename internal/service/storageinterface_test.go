@@ -4,7 +4,9 @@ package service
 
 import (
 	context "context"
+	time "time"
 
+	campaign "github.com/scoring-service/internal/campaign"
 	models "github.com/scoring-service/pkg/models"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -22,17 +24,76 @@ func (_m *MockStorage) EXPECT() *MockStorage_Expecter {
 	return &MockStorage_Expecter{mock: &_m.Mock}
 }
 
-// CreateUser provides a mock function with given fields: ctx, user
-func (_m *MockStorage) CreateUser(ctx context.Context, user *models.User) error {
-	ret := _m.Called(ctx, user)
+// AddMerchantAPIKey provides a mock function with given fields: ctx, adminID, merchantID, keyHash
+func (_m *MockStorage) AddMerchantAPIKey(ctx context.Context, adminID int, merchantID int, keyHash string) (int, error) {
+	ret := _m.Called(ctx, adminID, merchantID, keyHash)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateUser")
+		panic("no return value specified for AddMerchantAPIKey")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) (int, error)); ok {
+		return rf(ctx, adminID, merchantID, keyHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) int); ok {
+		r0 = rf(ctx, adminID, merchantID, keyHash)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, string) error); ok {
+		r1 = rf(ctx, adminID, merchantID, keyHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_AddMerchantAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddMerchantAPIKey'
+type MockStorage_AddMerchantAPIKey_Call struct {
+	*mock.Call
+}
+
+// AddMerchantAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - adminID int
+//   - merchantID int
+//   - keyHash string
+func (_e *MockStorage_Expecter) AddMerchantAPIKey(ctx interface{}, adminID interface{}, merchantID interface{}, keyHash interface{}) *MockStorage_AddMerchantAPIKey_Call {
+	return &MockStorage_AddMerchantAPIKey_Call{Call: _e.mock.On("AddMerchantAPIKey", ctx, adminID, merchantID, keyHash)}
+}
+
+func (_c *MockStorage_AddMerchantAPIKey_Call) Run(run func(ctx context.Context, adminID int, merchantID int, keyHash string)) *MockStorage_AddMerchantAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_AddMerchantAPIKey_Call) Return(_a0 int, _a1 error) *MockStorage_AddMerchantAPIKey_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_AddMerchantAPIKey_Call) RunAndReturn(run func(context.Context, int, int, string) (int, error)) *MockStorage_AddMerchantAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AdjustBalance provides a mock function with given fields: ctx, adminID, userID, adjustment, lifetimeMonths
+func (_m *MockStorage) AdjustBalance(ctx context.Context, adminID int, userID int, adjustment models.Adjustment, lifetimeMonths int) error {
+	ret := _m.Called(ctx, adminID, userID, adjustment, lifetimeMonths)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdjustBalance")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
-		r0 = rf(ctx, user)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, models.Adjustment, int) error); ok {
+		r0 = rf(ctx, adminID, userID, adjustment, lifetimeMonths)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -40,114 +101,109 @@ func (_m *MockStorage) CreateUser(ctx context.Context, user *models.User) error
 	return r0
 }
 
-// MockStorage_CreateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateUser'
-type MockStorage_CreateUser_Call struct {
+// MockStorage_AdjustBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdjustBalance'
+type MockStorage_AdjustBalance_Call struct {
 	*mock.Call
 }
 
-// CreateUser is a helper method to define mock.On call
+// AdjustBalance is a helper method to define mock.On call
 //   - ctx context.Context
-//   - user *models.User
-func (_e *MockStorage_Expecter) CreateUser(ctx interface{}, user interface{}) *MockStorage_CreateUser_Call {
-	return &MockStorage_CreateUser_Call{Call: _e.mock.On("CreateUser", ctx, user)}
+//   - adminID int
+//   - userID int
+//   - adjustment models.Adjustment
+//   - lifetimeMonths int
+func (_e *MockStorage_Expecter) AdjustBalance(ctx interface{}, adminID interface{}, userID interface{}, adjustment interface{}, lifetimeMonths interface{}) *MockStorage_AdjustBalance_Call {
+	return &MockStorage_AdjustBalance_Call{Call: _e.mock.On("AdjustBalance", ctx, adminID, userID, adjustment, lifetimeMonths)}
 }
 
-func (_c *MockStorage_CreateUser_Call) Run(run func(ctx context.Context, user *models.User)) *MockStorage_CreateUser_Call {
+func (_c *MockStorage_AdjustBalance_Call) Run(run func(ctx context.Context, adminID int, userID int, adjustment models.Adjustment, lifetimeMonths int)) *MockStorage_AdjustBalance_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*models.User))
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(models.Adjustment), args[4].(int))
 	})
 	return _c
 }
 
-func (_c *MockStorage_CreateUser_Call) Return(_a0 error) *MockStorage_CreateUser_Call {
+func (_c *MockStorage_AdjustBalance_Call) Return(_a0 error) *MockStorage_AdjustBalance_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockStorage_CreateUser_Call) RunAndReturn(run func(context.Context, *models.User) error) *MockStorage_CreateUser_Call {
+func (_c *MockStorage_AdjustBalance_Call) RunAndReturn(run func(context.Context, int, int, models.Adjustment, int) error) *MockStorage_AdjustBalance_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetPendingOrders provides a mock function with given fields: ctx
-func (_m *MockStorage) GetPendingOrders(ctx context.Context) ([]string, error) {
-	ret := _m.Called(ctx)
+// ApplyAccrualCallback provides a mock function with given fields: ctx, signature, ttl, accruals, policy
+func (_m *MockStorage) ApplyAccrualCallback(ctx context.Context, signature string, ttl time.Duration, accruals []models.AccrualResponse, policy models.CreditPolicy) error {
+	ret := _m.Called(ctx, signature, ttl, accruals, policy)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetPendingOrders")
-	}
-
-	var r0 []string
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]string, error)); ok {
-		return rf(ctx)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context) []string); ok {
-		r0 = rf(ctx)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
-		}
+		panic("no return value specified for ApplyAccrualCallback")
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration, []models.AccrualResponse, models.CreditPolicy) error); ok {
+		r0 = rf(ctx, signature, ttl, accruals, policy)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// MockStorage_GetPendingOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPendingOrders'
-type MockStorage_GetPendingOrders_Call struct {
+// MockStorage_ApplyAccrualCallback_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApplyAccrualCallback'
+type MockStorage_ApplyAccrualCallback_Call struct {
 	*mock.Call
 }
 
-// GetPendingOrders is a helper method to define mock.On call
+// ApplyAccrualCallback is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *MockStorage_Expecter) GetPendingOrders(ctx interface{}) *MockStorage_GetPendingOrders_Call {
-	return &MockStorage_GetPendingOrders_Call{Call: _e.mock.On("GetPendingOrders", ctx)}
+//   - signature string
+//   - ttl time.Duration
+//   - accruals []models.AccrualResponse
+//   - policy models.CreditPolicy
+func (_e *MockStorage_Expecter) ApplyAccrualCallback(ctx interface{}, signature interface{}, ttl interface{}, accruals interface{}, policy interface{}) *MockStorage_ApplyAccrualCallback_Call {
+	return &MockStorage_ApplyAccrualCallback_Call{Call: _e.mock.On("ApplyAccrualCallback", ctx, signature, ttl, accruals, policy)}
 }
 
-func (_c *MockStorage_GetPendingOrders_Call) Run(run func(ctx context.Context)) *MockStorage_GetPendingOrders_Call {
+func (_c *MockStorage_ApplyAccrualCallback_Call) Run(run func(ctx context.Context, signature string, ttl time.Duration, accruals []models.AccrualResponse, policy models.CreditPolicy)) *MockStorage_ApplyAccrualCallback_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Duration), args[3].([]models.AccrualResponse), args[4].(models.CreditPolicy))
 	})
 	return _c
 }
 
-func (_c *MockStorage_GetPendingOrders_Call) Return(_a0 []string, _a1 error) *MockStorage_GetPendingOrders_Call {
-	_c.Call.Return(_a0, _a1)
+func (_c *MockStorage_ApplyAccrualCallback_Call) Return(_a0 error) *MockStorage_ApplyAccrualCallback_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockStorage_GetPendingOrders_Call) RunAndReturn(run func(context.Context) ([]string, error)) *MockStorage_GetPendingOrders_Call {
+func (_c *MockStorage_ApplyAccrualCallback_Call) RunAndReturn(run func(context.Context, string, time.Duration, []models.AccrualResponse, models.CreditPolicy) error) *MockStorage_ApplyAccrualCallback_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserBalance provides a mock function with given fields: ctx, userID
-func (_m *MockStorage) GetUserBalance(ctx context.Context, userID int) (models.Balance, error) {
-	ret := _m.Called(ctx, userID)
+// AttachOrderToMerchant provides a mock function with given fields: ctx, merchantID, orderNum, window
+func (_m *MockStorage) AttachOrderToMerchant(ctx context.Context, merchantID int, orderNum string, window time.Duration) (bool, error) {
+	ret := _m.Called(ctx, merchantID, orderNum, window)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserBalance")
+		panic("no return value specified for AttachOrderToMerchant")
 	}
 
-	var r0 models.Balance
+	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) (models.Balance, error)); ok {
-		return rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, time.Duration) (bool, error)); ok {
+		return rf(ctx, merchantID, orderNum, window)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) models.Balance); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, time.Duration) bool); ok {
+		r0 = rf(ctx, merchantID, orderNum, window)
 	} else {
-		r0 = ret.Get(0).(models.Balance)
+		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, time.Duration) error); ok {
+		r1 = rf(ctx, merchantID, orderNum, window)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -155,58 +211,60 @@ func (_m *MockStorage) GetUserBalance(ctx context.Context, userID int) (models.B
 	return r0, r1
 }
 
-// MockStorage_GetUserBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserBalance'
-type MockStorage_GetUserBalance_Call struct {
+// MockStorage_AttachOrderToMerchant_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AttachOrderToMerchant'
+type MockStorage_AttachOrderToMerchant_Call struct {
 	*mock.Call
 }
 
-// GetUserBalance is a helper method to define mock.On call
+// AttachOrderToMerchant is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID int
-func (_e *MockStorage_Expecter) GetUserBalance(ctx interface{}, userID interface{}) *MockStorage_GetUserBalance_Call {
-	return &MockStorage_GetUserBalance_Call{Call: _e.mock.On("GetUserBalance", ctx, userID)}
+//   - merchantID int
+//   - orderNum string
+//   - window time.Duration
+func (_e *MockStorage_Expecter) AttachOrderToMerchant(ctx interface{}, merchantID interface{}, orderNum interface{}, window interface{}) *MockStorage_AttachOrderToMerchant_Call {
+	return &MockStorage_AttachOrderToMerchant_Call{Call: _e.mock.On("AttachOrderToMerchant", ctx, merchantID, orderNum, window)}
 }
 
-func (_c *MockStorage_GetUserBalance_Call) Run(run func(ctx context.Context, userID int)) *MockStorage_GetUserBalance_Call {
+func (_c *MockStorage_AttachOrderToMerchant_Call) Run(run func(ctx context.Context, merchantID int, orderNum string, window time.Duration)) *MockStorage_AttachOrderToMerchant_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int))
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(time.Duration))
 	})
 	return _c
 }
 
-func (_c *MockStorage_GetUserBalance_Call) Return(_a0 models.Balance, _a1 error) *MockStorage_GetUserBalance_Call {
+func (_c *MockStorage_AttachOrderToMerchant_Call) Return(_a0 bool, _a1 error) *MockStorage_AttachOrderToMerchant_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStorage_GetUserBalance_Call) RunAndReturn(run func(context.Context, int) (models.Balance, error)) *MockStorage_GetUserBalance_Call {
+func (_c *MockStorage_AttachOrderToMerchant_Call) RunAndReturn(run func(context.Context, int, string, time.Duration) (bool, error)) *MockStorage_AttachOrderToMerchant_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserByLogin provides a mock function with given fields: ctx, login
-func (_m *MockStorage) GetUserByLogin(ctx context.Context, login string) (*models.User, error) {
-	ret := _m.Called(ctx, login)
+// ClaimWebhookDeliveries provides a mock function with given fields: ctx, limit, lease
+func (_m *MockStorage) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	ret := _m.Called(ctx, limit, lease)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserByLogin")
+		panic("no return value specified for ClaimWebhookDeliveries")
 	}
 
-	var r0 *models.User
+	var r0 []models.WebhookDelivery
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
-		return rf(ctx, login)
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Duration) ([]models.WebhookDelivery, error)); ok {
+		return rf(ctx, limit, lease)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
-		r0 = rf(ctx, login)
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Duration) []models.WebhookDelivery); ok {
+		r0 = rf(ctx, limit, lease)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.User)
+			r0 = ret.Get(0).([]models.WebhookDelivery)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, login)
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Duration) error); ok {
+		r1 = rf(ctx, limit, lease)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -214,117 +272,105 @@ func (_m *MockStorage) GetUserByLogin(ctx context.Context, login string) (*model
 	return r0, r1
 }
 
-// MockStorage_GetUserByLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByLogin'
-type MockStorage_GetUserByLogin_Call struct {
+// MockStorage_ClaimWebhookDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimWebhookDeliveries'
+type MockStorage_ClaimWebhookDeliveries_Call struct {
 	*mock.Call
 }
 
-// GetUserByLogin is a helper method to define mock.On call
+// ClaimWebhookDeliveries is a helper method to define mock.On call
 //   - ctx context.Context
-//   - login string
-func (_e *MockStorage_Expecter) GetUserByLogin(ctx interface{}, login interface{}) *MockStorage_GetUserByLogin_Call {
-	return &MockStorage_GetUserByLogin_Call{Call: _e.mock.On("GetUserByLogin", ctx, login)}
+//   - limit int
+//   - lease time.Duration
+func (_e *MockStorage_Expecter) ClaimWebhookDeliveries(ctx interface{}, limit interface{}, lease interface{}) *MockStorage_ClaimWebhookDeliveries_Call {
+	return &MockStorage_ClaimWebhookDeliveries_Call{Call: _e.mock.On("ClaimWebhookDeliveries", ctx, limit, lease)}
 }
 
-func (_c *MockStorage_GetUserByLogin_Call) Run(run func(ctx context.Context, login string)) *MockStorage_GetUserByLogin_Call {
+func (_c *MockStorage_ClaimWebhookDeliveries_Call) Run(run func(ctx context.Context, limit int, lease time.Duration)) *MockStorage_ClaimWebhookDeliveries_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Duration))
 	})
 	return _c
 }
 
-func (_c *MockStorage_GetUserByLogin_Call) Return(_a0 *models.User, _a1 error) *MockStorage_GetUserByLogin_Call {
+func (_c *MockStorage_ClaimWebhookDeliveries_Call) Return(_a0 []models.WebhookDelivery, _a1 error) *MockStorage_ClaimWebhookDeliveries_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStorage_GetUserByLogin_Call) RunAndReturn(run func(context.Context, string) (*models.User, error)) *MockStorage_GetUserByLogin_Call {
+func (_c *MockStorage_ClaimWebhookDeliveries_Call) RunAndReturn(run func(context.Context, int, time.Duration) ([]models.WebhookDelivery, error)) *MockStorage_ClaimWebhookDeliveries_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserOrders provides a mock function with given fields: ctx, userID
-func (_m *MockStorage) GetUserOrders(ctx context.Context, userID int) ([]models.Order, error) {
-	ret := _m.Called(ctx, userID)
+// ConfirmNotificationEmail provides a mock function with given fields: ctx, userID, tokenHash
+func (_m *MockStorage) ConfirmNotificationEmail(ctx context.Context, userID int, tokenHash string) error {
+	ret := _m.Called(ctx, userID, tokenHash)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserOrders")
-	}
-
-	var r0 []models.Order
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.Order, error)); ok {
-		return rf(ctx, userID)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) []models.Order); ok {
-		r0 = rf(ctx, userID)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Order)
-		}
+		panic("no return value specified for ConfirmNotificationEmail")
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, userID)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, userID, tokenHash)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// MockStorage_GetUserOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserOrders'
-type MockStorage_GetUserOrders_Call struct {
+// MockStorage_ConfirmNotificationEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConfirmNotificationEmail'
+type MockStorage_ConfirmNotificationEmail_Call struct {
 	*mock.Call
 }
 
-// GetUserOrders is a helper method to define mock.On call
+// ConfirmNotificationEmail is a helper method to define mock.On call
 //   - ctx context.Context
 //   - userID int
-func (_e *MockStorage_Expecter) GetUserOrders(ctx interface{}, userID interface{}) *MockStorage_GetUserOrders_Call {
-	return &MockStorage_GetUserOrders_Call{Call: _e.mock.On("GetUserOrders", ctx, userID)}
+//   - tokenHash string
+func (_e *MockStorage_Expecter) ConfirmNotificationEmail(ctx interface{}, userID interface{}, tokenHash interface{}) *MockStorage_ConfirmNotificationEmail_Call {
+	return &MockStorage_ConfirmNotificationEmail_Call{Call: _e.mock.On("ConfirmNotificationEmail", ctx, userID, tokenHash)}
 }
 
-func (_c *MockStorage_GetUserOrders_Call) Run(run func(ctx context.Context, userID int)) *MockStorage_GetUserOrders_Call {
+func (_c *MockStorage_ConfirmNotificationEmail_Call) Run(run func(ctx context.Context, userID int, tokenHash string)) *MockStorage_ConfirmNotificationEmail_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int))
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockStorage_GetUserOrders_Call) Return(_a0 []models.Order, _a1 error) *MockStorage_GetUserOrders_Call {
-	_c.Call.Return(_a0, _a1)
+func (_c *MockStorage_ConfirmNotificationEmail_Call) Return(_a0 error) *MockStorage_ConfirmNotificationEmail_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockStorage_GetUserOrders_Call) RunAndReturn(run func(context.Context, int) ([]models.Order, error)) *MockStorage_GetUserOrders_Call {
+func (_c *MockStorage_ConfirmNotificationEmail_Call) RunAndReturn(run func(context.Context, int, string) error) *MockStorage_ConfirmNotificationEmail_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserWithdrawals provides a mock function with given fields: ctx, userID
-func (_m *MockStorage) GetUserWithdrawals(ctx context.Context, userID int) ([]models.Withdrawal, error) {
-	ret := _m.Called(ctx, userID)
+// ConsumeCustomerToken provides a mock function with given fields: ctx, merchantID, tokenHash
+func (_m *MockStorage) ConsumeCustomerToken(ctx context.Context, merchantID int, tokenHash string) (int, error) {
+	ret := _m.Called(ctx, merchantID, tokenHash)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserWithdrawals")
+		panic("no return value specified for ConsumeCustomerToken")
 	}
 
-	var r0 []models.Withdrawal
+	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.Withdrawal, error)); ok {
-		return rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (int, error)); ok {
+		return rf(ctx, merchantID, tokenHash)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) []models.Withdrawal); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) int); ok {
+		r0 = rf(ctx, merchantID, tokenHash)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Withdrawal)
-		}
+		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, merchantID, tokenHash)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -332,56 +378,59 @@ func (_m *MockStorage) GetUserWithdrawals(ctx context.Context, userID int) ([]mo
 	return r0, r1
 }
 
-// MockStorage_GetUserWithdrawals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserWithdrawals'
-type MockStorage_GetUserWithdrawals_Call struct {
+// MockStorage_ConsumeCustomerToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConsumeCustomerToken'
+type MockStorage_ConsumeCustomerToken_Call struct {
 	*mock.Call
 }
 
-// GetUserWithdrawals is a helper method to define mock.On call
+// ConsumeCustomerToken is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID int
-func (_e *MockStorage_Expecter) GetUserWithdrawals(ctx interface{}, userID interface{}) *MockStorage_GetUserWithdrawals_Call {
-	return &MockStorage_GetUserWithdrawals_Call{Call: _e.mock.On("GetUserWithdrawals", ctx, userID)}
+//   - merchantID int
+//   - tokenHash string
+func (_e *MockStorage_Expecter) ConsumeCustomerToken(ctx interface{}, merchantID interface{}, tokenHash interface{}) *MockStorage_ConsumeCustomerToken_Call {
+	return &MockStorage_ConsumeCustomerToken_Call{Call: _e.mock.On("ConsumeCustomerToken", ctx, merchantID, tokenHash)}
 }
 
-func (_c *MockStorage_GetUserWithdrawals_Call) Run(run func(ctx context.Context, userID int)) *MockStorage_GetUserWithdrawals_Call {
+func (_c *MockStorage_ConsumeCustomerToken_Call) Run(run func(ctx context.Context, merchantID int, tokenHash string)) *MockStorage_ConsumeCustomerToken_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int))
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockStorage_GetUserWithdrawals_Call) Return(_a0 []models.Withdrawal, _a1 error) *MockStorage_GetUserWithdrawals_Call {
+func (_c *MockStorage_ConsumeCustomerToken_Call) Return(_a0 int, _a1 error) *MockStorage_ConsumeCustomerToken_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStorage_GetUserWithdrawals_Call) RunAndReturn(run func(context.Context, int) ([]models.Withdrawal, error)) *MockStorage_GetUserWithdrawals_Call {
+func (_c *MockStorage_ConsumeCustomerToken_Call) RunAndReturn(run func(context.Context, int, string) (int, error)) *MockStorage_ConsumeCustomerToken_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// IsOrderExists provides a mock function with given fields: ctx, orderNum
-func (_m *MockStorage) IsOrderExists(ctx context.Context, orderNum string) (int, error) {
-	ret := _m.Called(ctx, orderNum)
+// ConsumeLoginChallenge provides a mock function with given fields: ctx, tokenHash
+func (_m *MockStorage) ConsumeLoginChallenge(ctx context.Context, tokenHash string) (*models.User, error) {
+	ret := _m.Called(ctx, tokenHash)
 
 	if len(ret) == 0 {
-		panic("no return value specified for IsOrderExists")
+		panic("no return value specified for ConsumeLoginChallenge")
 	}
 
-	var r0 int
+	var r0 *models.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
-		return rf(ctx, orderNum)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
+		return rf(ctx, tokenHash)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
-		r0 = rf(ctx, orderNum)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, tokenHash)
 	} else {
-		r0 = ret.Get(0).(int)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, orderNum)
+		r1 = rf(ctx, tokenHash)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -389,94 +438,111 @@ func (_m *MockStorage) IsOrderExists(ctx context.Context, orderNum string) (int,
 	return r0, r1
 }
 
-// MockStorage_IsOrderExists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsOrderExists'
-type MockStorage_IsOrderExists_Call struct {
+// MockStorage_ConsumeLoginChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConsumeLoginChallenge'
+type MockStorage_ConsumeLoginChallenge_Call struct {
 	*mock.Call
 }
 
-// IsOrderExists is a helper method to define mock.On call
+// ConsumeLoginChallenge is a helper method to define mock.On call
 //   - ctx context.Context
-//   - orderNum string
-func (_e *MockStorage_Expecter) IsOrderExists(ctx interface{}, orderNum interface{}) *MockStorage_IsOrderExists_Call {
-	return &MockStorage_IsOrderExists_Call{Call: _e.mock.On("IsOrderExists", ctx, orderNum)}
+//   - tokenHash string
+func (_e *MockStorage_Expecter) ConsumeLoginChallenge(ctx interface{}, tokenHash interface{}) *MockStorage_ConsumeLoginChallenge_Call {
+	return &MockStorage_ConsumeLoginChallenge_Call{Call: _e.mock.On("ConsumeLoginChallenge", ctx, tokenHash)}
 }
 
-func (_c *MockStorage_IsOrderExists_Call) Run(run func(ctx context.Context, orderNum string)) *MockStorage_IsOrderExists_Call {
+func (_c *MockStorage_ConsumeLoginChallenge_Call) Run(run func(ctx context.Context, tokenHash string)) *MockStorage_ConsumeLoginChallenge_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *MockStorage_IsOrderExists_Call) Return(_a0 int, _a1 error) *MockStorage_IsOrderExists_Call {
+func (_c *MockStorage_ConsumeLoginChallenge_Call) Return(_a0 *models.User, _a1 error) *MockStorage_ConsumeLoginChallenge_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStorage_IsOrderExists_Call) RunAndReturn(run func(context.Context, string) (int, error)) *MockStorage_IsOrderExists_Call {
+func (_c *MockStorage_ConsumeLoginChallenge_Call) RunAndReturn(run func(context.Context, string) (*models.User, error)) *MockStorage_ConsumeLoginChallenge_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// SaveOrder provides a mock function with given fields: ctx, user, order
-func (_m *MockStorage) SaveOrder(ctx context.Context, user int, order *models.Order) error {
-	ret := _m.Called(ctx, user, order)
+// CorrectBalance provides a mock function with given fields: ctx, runID, userID
+func (_m *MockStorage) CorrectBalance(ctx context.Context, runID int, userID int) (models.BalanceDrift, bool, error) {
+	ret := _m.Called(ctx, runID, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SaveOrder")
+		panic("no return value specified for CorrectBalance")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Order) error); ok {
-		r0 = rf(ctx, user, order)
+	var r0 models.BalanceDrift
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (models.BalanceDrift, bool, error)); ok {
+		return rf(ctx, runID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) models.BalanceDrift); ok {
+		r0 = rf(ctx, runID, userID)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(models.BalanceDrift)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) bool); ok {
+		r1 = rf(ctx, runID, userID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
+		r2 = rf(ctx, runID, userID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// MockStorage_SaveOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveOrder'
-type MockStorage_SaveOrder_Call struct {
+// MockStorage_CorrectBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CorrectBalance'
+type MockStorage_CorrectBalance_Call struct {
 	*mock.Call
 }
 
-// SaveOrder is a helper method to define mock.On call
+// CorrectBalance is a helper method to define mock.On call
 //   - ctx context.Context
-//   - user int
-//   - order *models.Order
-func (_e *MockStorage_Expecter) SaveOrder(ctx interface{}, user interface{}, order interface{}) *MockStorage_SaveOrder_Call {
-	return &MockStorage_SaveOrder_Call{Call: _e.mock.On("SaveOrder", ctx, user, order)}
+//   - runID int
+//   - userID int
+func (_e *MockStorage_Expecter) CorrectBalance(ctx interface{}, runID interface{}, userID interface{}) *MockStorage_CorrectBalance_Call {
+	return &MockStorage_CorrectBalance_Call{Call: _e.mock.On("CorrectBalance", ctx, runID, userID)}
 }
 
-func (_c *MockStorage_SaveOrder_Call) Run(run func(ctx context.Context, user int, order *models.Order)) *MockStorage_SaveOrder_Call {
+func (_c *MockStorage_CorrectBalance_Call) Run(run func(ctx context.Context, runID int, userID int)) *MockStorage_CorrectBalance_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(*models.Order))
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
 	})
 	return _c
 }
 
-func (_c *MockStorage_SaveOrder_Call) Return(_a0 error) *MockStorage_SaveOrder_Call {
-	_c.Call.Return(_a0)
+func (_c *MockStorage_CorrectBalance_Call) Return(_a0 models.BalanceDrift, _a1 bool, _a2 error) *MockStorage_CorrectBalance_Call {
+	_c.Call.Return(_a0, _a1, _a2)
 	return _c
 }
 
-func (_c *MockStorage_SaveOrder_Call) RunAndReturn(run func(context.Context, int, *models.Order) error) *MockStorage_SaveOrder_Call {
+func (_c *MockStorage_CorrectBalance_Call) RunAndReturn(run func(context.Context, int, int) (models.BalanceDrift, bool, error)) *MockStorage_CorrectBalance_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateOrder provides a mock function with given fields: ctx, accrual
-func (_m *MockStorage) UpdateOrder(ctx context.Context, accrual *models.AccrualResponse) error {
-	ret := _m.Called(ctx, accrual)
+// CreateCampaign provides a mock function with given fields: ctx, c
+func (_m *MockStorage) CreateCampaign(ctx context.Context, c *models.Campaign) error {
+	ret := _m.Called(ctx, c)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateOrder")
+		panic("no return value specified for CreateCampaign")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.AccrualResponse) error); ok {
-		r0 = rf(ctx, accrual)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Campaign) error); ok {
+		r0 = rf(ctx, c)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -484,47 +550,4842 @@ func (_m *MockStorage) UpdateOrder(ctx context.Context, accrual *models.AccrualR
 	return r0
 }
 
-// MockStorage_UpdateOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateOrder'
-type MockStorage_UpdateOrder_Call struct {
+// MockStorage_CreateCampaign_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateCampaign'
+type MockStorage_CreateCampaign_Call struct {
 	*mock.Call
 }
 
-// UpdateOrder is a helper method to define mock.On call
+// CreateCampaign is a helper method to define mock.On call
 //   - ctx context.Context
-//   - accrual *models.AccrualResponse
-func (_e *MockStorage_Expecter) UpdateOrder(ctx interface{}, accrual interface{}) *MockStorage_UpdateOrder_Call {
-	return &MockStorage_UpdateOrder_Call{Call: _e.mock.On("UpdateOrder", ctx, accrual)}
+//   - c *models.Campaign
+func (_e *MockStorage_Expecter) CreateCampaign(ctx interface{}, c interface{}) *MockStorage_CreateCampaign_Call {
+	return &MockStorage_CreateCampaign_Call{Call: _e.mock.On("CreateCampaign", ctx, c)}
 }
 
-func (_c *MockStorage_UpdateOrder_Call) Run(run func(ctx context.Context, accrual *models.AccrualResponse)) *MockStorage_UpdateOrder_Call {
+func (_c *MockStorage_CreateCampaign_Call) Run(run func(ctx context.Context, c *models.Campaign)) *MockStorage_CreateCampaign_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*models.AccrualResponse))
+		run(args[0].(context.Context), args[1].(*models.Campaign))
 	})
 	return _c
 }
 
-func (_c *MockStorage_UpdateOrder_Call) Return(_a0 error) *MockStorage_UpdateOrder_Call {
+func (_c *MockStorage_CreateCampaign_Call) Return(_a0 error) *MockStorage_CreateCampaign_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockStorage_UpdateOrder_Call) RunAndReturn(run func(context.Context, *models.AccrualResponse) error) *MockStorage_UpdateOrder_Call {
+func (_c *MockStorage_CreateCampaign_Call) RunAndReturn(run func(context.Context, *models.Campaign) error) *MockStorage_CreateCampaign_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Withdraw provides a mock function with given fields: ctx, userID, order, sum
-func (_m *MockStorage) Withdraw(ctx context.Context, userID int, order string, sum float64) error {
-	ret := _m.Called(ctx, userID, order, sum)
+// CreateCheckoutCode provides a mock function with given fields: ctx, userID, codeHash, ttl
+func (_m *MockStorage) CreateCheckoutCode(ctx context.Context, userID int, codeHash string, ttl time.Duration) (time.Time, error) {
+	ret := _m.Called(ctx, userID, codeHash, ttl)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Withdraw")
+		panic("no return value specified for CreateCheckoutCode")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, string, float64) error); ok {
-		r0 = rf(ctx, userID, order, sum)
-	} else {
+	var r0 time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, time.Duration) (time.Time, error)); ok {
+		return rf(ctx, userID, codeHash, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, time.Duration) time.Time); ok {
+		r0 = rf(ctx, userID, codeHash, ttl)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, time.Duration) error); ok {
+		r1 = rf(ctx, userID, codeHash, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_CreateCheckoutCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateCheckoutCode'
+type MockStorage_CreateCheckoutCode_Call struct {
+	*mock.Call
+}
+
+// CreateCheckoutCode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - codeHash string
+//   - ttl time.Duration
+func (_e *MockStorage_Expecter) CreateCheckoutCode(ctx interface{}, userID interface{}, codeHash interface{}, ttl interface{}) *MockStorage_CreateCheckoutCode_Call {
+	return &MockStorage_CreateCheckoutCode_Call{Call: _e.mock.On("CreateCheckoutCode", ctx, userID, codeHash, ttl)}
+}
+
+func (_c *MockStorage_CreateCheckoutCode_Call) Run(run func(ctx context.Context, userID int, codeHash string, ttl time.Duration)) *MockStorage_CreateCheckoutCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockStorage_CreateCheckoutCode_Call) Return(_a0 time.Time, _a1 error) *MockStorage_CreateCheckoutCode_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_CreateCheckoutCode_Call) RunAndReturn(run func(context.Context, int, string, time.Duration) (time.Time, error)) *MockStorage_CreateCheckoutCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateCustomerToken provides a mock function with given fields: ctx, userID, tokenHash, ttl
+func (_m *MockStorage) CreateCustomerToken(ctx context.Context, userID int, tokenHash string, ttl time.Duration) (time.Time, error) {
+	ret := _m.Called(ctx, userID, tokenHash, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCustomerToken")
+	}
+
+	var r0 time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, time.Duration) (time.Time, error)); ok {
+		return rf(ctx, userID, tokenHash, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, time.Duration) time.Time); ok {
+		r0 = rf(ctx, userID, tokenHash, ttl)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, time.Duration) error); ok {
+		r1 = rf(ctx, userID, tokenHash, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_CreateCustomerToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateCustomerToken'
+type MockStorage_CreateCustomerToken_Call struct {
+	*mock.Call
+}
+
+// CreateCustomerToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - tokenHash string
+//   - ttl time.Duration
+func (_e *MockStorage_Expecter) CreateCustomerToken(ctx interface{}, userID interface{}, tokenHash interface{}, ttl interface{}) *MockStorage_CreateCustomerToken_Call {
+	return &MockStorage_CreateCustomerToken_Call{Call: _e.mock.On("CreateCustomerToken", ctx, userID, tokenHash, ttl)}
+}
+
+func (_c *MockStorage_CreateCustomerToken_Call) Run(run func(ctx context.Context, userID int, tokenHash string, ttl time.Duration)) *MockStorage_CreateCustomerToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockStorage_CreateCustomerToken_Call) Return(_a0 time.Time, _a1 error) *MockStorage_CreateCustomerToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_CreateCustomerToken_Call) RunAndReturn(run func(context.Context, int, string, time.Duration) (time.Time, error)) *MockStorage_CreateCustomerToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateLoginChallenge provides a mock function with given fields: ctx, userID, tokenHash, ttl, maxActive
+func (_m *MockStorage) CreateLoginChallenge(ctx context.Context, userID int, tokenHash string, ttl time.Duration, maxActive int) error {
+	ret := _m.Called(ctx, userID, tokenHash, ttl, maxActive)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateLoginChallenge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, time.Duration, int) error); ok {
+		r0 = rf(ctx, userID, tokenHash, ttl, maxActive)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_CreateLoginChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateLoginChallenge'
+type MockStorage_CreateLoginChallenge_Call struct {
+	*mock.Call
+}
+
+// CreateLoginChallenge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - tokenHash string
+//   - ttl time.Duration
+//   - maxActive int
+func (_e *MockStorage_Expecter) CreateLoginChallenge(ctx interface{}, userID interface{}, tokenHash interface{}, ttl interface{}, maxActive interface{}) *MockStorage_CreateLoginChallenge_Call {
+	return &MockStorage_CreateLoginChallenge_Call{Call: _e.mock.On("CreateLoginChallenge", ctx, userID, tokenHash, ttl, maxActive)}
+}
+
+func (_c *MockStorage_CreateLoginChallenge_Call) Run(run func(ctx context.Context, userID int, tokenHash string, ttl time.Duration, maxActive int)) *MockStorage_CreateLoginChallenge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(time.Duration), args[4].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_CreateLoginChallenge_Call) Return(_a0 error) *MockStorage_CreateLoginChallenge_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_CreateLoginChallenge_Call) RunAndReturn(run func(context.Context, int, string, time.Duration, int) error) *MockStorage_CreateLoginChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateMerchant provides a mock function with given fields: ctx, adminID, login, merchant, keyHash
+func (_m *MockStorage) CreateMerchant(ctx context.Context, adminID int, login string, merchant *models.Merchant, keyHash string) (int, error) {
+	ret := _m.Called(ctx, adminID, login, merchant, keyHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateMerchant")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, *models.Merchant, string) (int, error)); ok {
+		return rf(ctx, adminID, login, merchant, keyHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, *models.Merchant, string) int); ok {
+		r0 = rf(ctx, adminID, login, merchant, keyHash)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, *models.Merchant, string) error); ok {
+		r1 = rf(ctx, adminID, login, merchant, keyHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_CreateMerchant_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateMerchant'
+type MockStorage_CreateMerchant_Call struct {
+	*mock.Call
+}
+
+// CreateMerchant is a helper method to define mock.On call
+//   - ctx context.Context
+//   - adminID int
+//   - login string
+//   - merchant *models.Merchant
+//   - keyHash string
+func (_e *MockStorage_Expecter) CreateMerchant(ctx interface{}, adminID interface{}, login interface{}, merchant interface{}, keyHash interface{}) *MockStorage_CreateMerchant_Call {
+	return &MockStorage_CreateMerchant_Call{Call: _e.mock.On("CreateMerchant", ctx, adminID, login, merchant, keyHash)}
+}
+
+func (_c *MockStorage_CreateMerchant_Call) Run(run func(ctx context.Context, adminID int, login string, merchant *models.Merchant, keyHash string)) *MockStorage_CreateMerchant_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(*models.Merchant), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_CreateMerchant_Call) Return(_a0 int, _a1 error) *MockStorage_CreateMerchant_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_CreateMerchant_Call) RunAndReturn(run func(context.Context, int, string, *models.Merchant, string) (int, error)) *MockStorage_CreateMerchant_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreatePasswordReset provides a mock function with given fields: ctx, userID, tokenHash, ttl
+func (_m *MockStorage) CreatePasswordReset(ctx context.Context, userID int, tokenHash string, ttl time.Duration) error {
+	ret := _m.Called(ctx, userID, tokenHash, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePasswordReset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, time.Duration) error); ok {
+		r0 = rf(ctx, userID, tokenHash, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_CreatePasswordReset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePasswordReset'
+type MockStorage_CreatePasswordReset_Call struct {
+	*mock.Call
+}
+
+// CreatePasswordReset is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - tokenHash string
+//   - ttl time.Duration
+func (_e *MockStorage_Expecter) CreatePasswordReset(ctx interface{}, userID interface{}, tokenHash interface{}, ttl interface{}) *MockStorage_CreatePasswordReset_Call {
+	return &MockStorage_CreatePasswordReset_Call{Call: _e.mock.On("CreatePasswordReset", ctx, userID, tokenHash, ttl)}
+}
+
+func (_c *MockStorage_CreatePasswordReset_Call) Run(run func(ctx context.Context, userID int, tokenHash string, ttl time.Duration)) *MockStorage_CreatePasswordReset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockStorage_CreatePasswordReset_Call) Return(_a0 error) *MockStorage_CreatePasswordReset_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_CreatePasswordReset_Call) RunAndReturn(run func(context.Context, int, string, time.Duration) error) *MockStorage_CreatePasswordReset_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateSession provides a mock function with given fields: ctx, userID, tokenHash, meta, ttl
+func (_m *MockStorage) CreateSession(ctx context.Context, userID int, tokenHash string, meta models.SessionMeta, ttl time.Duration) (int, error) {
+	ret := _m.Called(ctx, userID, tokenHash, meta, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSession")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, models.SessionMeta, time.Duration) (int, error)); ok {
+		return rf(ctx, userID, tokenHash, meta, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, models.SessionMeta, time.Duration) int); ok {
+		r0 = rf(ctx, userID, tokenHash, meta, ttl)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, models.SessionMeta, time.Duration) error); ok {
+		r1 = rf(ctx, userID, tokenHash, meta, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_CreateSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateSession'
+type MockStorage_CreateSession_Call struct {
+	*mock.Call
+}
+
+// CreateSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - tokenHash string
+//   - meta models.SessionMeta
+//   - ttl time.Duration
+func (_e *MockStorage_Expecter) CreateSession(ctx interface{}, userID interface{}, tokenHash interface{}, meta interface{}, ttl interface{}) *MockStorage_CreateSession_Call {
+	return &MockStorage_CreateSession_Call{Call: _e.mock.On("CreateSession", ctx, userID, tokenHash, meta, ttl)}
+}
+
+func (_c *MockStorage_CreateSession_Call) Run(run func(ctx context.Context, userID int, tokenHash string, meta models.SessionMeta, ttl time.Duration)) *MockStorage_CreateSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(models.SessionMeta), args[4].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockStorage_CreateSession_Call) Return(_a0 int, _a1 error) *MockStorage_CreateSession_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_CreateSession_Call) RunAndReturn(run func(context.Context, int, string, models.SessionMeta, time.Duration) (int, error)) *MockStorage_CreateSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateUser provides a mock function with given fields: ctx, user
+func (_m *MockStorage) CreateUser(ctx context.Context, user *models.User) error {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_CreateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateUser'
+type MockStorage_CreateUser_Call struct {
+	*mock.Call
+}
+
+// CreateUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *models.User
+func (_e *MockStorage_Expecter) CreateUser(ctx interface{}, user interface{}) *MockStorage_CreateUser_Call {
+	return &MockStorage_CreateUser_Call{Call: _e.mock.On("CreateUser", ctx, user)}
+}
+
+func (_c *MockStorage_CreateUser_Call) Run(run func(ctx context.Context, user *models.User)) *MockStorage_CreateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.User))
+	})
+	return _c
+}
+
+func (_c *MockStorage_CreateUser_Call) Return(_a0 error) *MockStorage_CreateUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_CreateUser_Call) RunAndReturn(run func(context.Context, *models.User) error) *MockStorage_CreateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateWebhookSubscription provides a mock function with given fields: ctx, merchantID, sub
+func (_m *MockStorage) CreateWebhookSubscription(ctx context.Context, merchantID int, sub *models.WebhookSubscription) error {
+	ret := _m.Called(ctx, merchantID, sub)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWebhookSubscription")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.WebhookSubscription) error); ok {
+		r0 = rf(ctx, merchantID, sub)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_CreateWebhookSubscription_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateWebhookSubscription'
+type MockStorage_CreateWebhookSubscription_Call struct {
+	*mock.Call
+}
+
+// CreateWebhookSubscription is a helper method to define mock.On call
+//   - ctx context.Context
+//   - merchantID int
+//   - sub *models.WebhookSubscription
+func (_e *MockStorage_Expecter) CreateWebhookSubscription(ctx interface{}, merchantID interface{}, sub interface{}) *MockStorage_CreateWebhookSubscription_Call {
+	return &MockStorage_CreateWebhookSubscription_Call{Call: _e.mock.On("CreateWebhookSubscription", ctx, merchantID, sub)}
+}
+
+func (_c *MockStorage_CreateWebhookSubscription_Call) Run(run func(ctx context.Context, merchantID int, sub *models.WebhookSubscription)) *MockStorage_CreateWebhookSubscription_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.WebhookSubscription))
+	})
+	return _c
+}
+
+func (_c *MockStorage_CreateWebhookSubscription_Call) Return(_a0 error) *MockStorage_CreateWebhookSubscription_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_CreateWebhookSubscription_Call) RunAndReturn(run func(context.Context, int, *models.WebhookSubscription) error) *MockStorage_CreateWebhookSubscription_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeactivateCampaign provides a mock function with given fields: ctx, id
+func (_m *MockStorage) DeactivateCampaign(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeactivateCampaign")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_DeactivateCampaign_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeactivateCampaign'
+type MockStorage_DeactivateCampaign_Call struct {
+	*mock.Call
+}
+
+// DeactivateCampaign is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockStorage_Expecter) DeactivateCampaign(ctx interface{}, id interface{}) *MockStorage_DeactivateCampaign_Call {
+	return &MockStorage_DeactivateCampaign_Call{Call: _e.mock.On("DeactivateCampaign", ctx, id)}
+}
+
+func (_c *MockStorage_DeactivateCampaign_Call) Run(run func(ctx context.Context, id int)) *MockStorage_DeactivateCampaign_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeactivateCampaign_Call) Return(_a0 error) *MockStorage_DeactivateCampaign_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_DeactivateCampaign_Call) RunAndReturn(run func(context.Context, int) error) *MockStorage_DeactivateCampaign_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteUser provides a mock function with given fields: ctx, userID
+func (_m *MockStorage) DeleteUser(ctx context.Context, userID int) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_DeleteUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUser'
+type MockStorage_DeleteUser_Call struct {
+	*mock.Call
+}
+
+// DeleteUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockStorage_Expecter) DeleteUser(ctx interface{}, userID interface{}) *MockStorage_DeleteUser_Call {
+	return &MockStorage_DeleteUser_Call{Call: _e.mock.On("DeleteUser", ctx, userID)}
+}
+
+func (_c *MockStorage_DeleteUser_Call) Run(run func(ctx context.Context, userID int)) *MockStorage_DeleteUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeleteUser_Call) Return(_a0 error) *MockStorage_DeleteUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_DeleteUser_Call) RunAndReturn(run func(context.Context, int) error) *MockStorage_DeleteUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteWebhookSubscription provides a mock function with given fields: ctx, merchantID, subscriptionID
+func (_m *MockStorage) DeleteWebhookSubscription(ctx context.Context, merchantID int, subscriptionID int) error {
+	ret := _m.Called(ctx, merchantID, subscriptionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebhookSubscription")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, merchantID, subscriptionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_DeleteWebhookSubscription_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteWebhookSubscription'
+type MockStorage_DeleteWebhookSubscription_Call struct {
+	*mock.Call
+}
+
+// DeleteWebhookSubscription is a helper method to define mock.On call
+//   - ctx context.Context
+//   - merchantID int
+//   - subscriptionID int
+func (_e *MockStorage_Expecter) DeleteWebhookSubscription(ctx interface{}, merchantID interface{}, subscriptionID interface{}) *MockStorage_DeleteWebhookSubscription_Call {
+	return &MockStorage_DeleteWebhookSubscription_Call{Call: _e.mock.On("DeleteWebhookSubscription", ctx, merchantID, subscriptionID)}
+}
+
+func (_c *MockStorage_DeleteWebhookSubscription_Call) Run(run func(ctx context.Context, merchantID int, subscriptionID int)) *MockStorage_DeleteWebhookSubscription_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeleteWebhookSubscription_Call) Return(_a0 error) *MockStorage_DeleteWebhookSubscription_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_DeleteWebhookSubscription_Call) RunAndReturn(run func(context.Context, int, int) error) *MockStorage_DeleteWebhookSubscription_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DisableTOTP provides a mock function with given fields: ctx, userID
+func (_m *MockStorage) DisableTOTP(ctx context.Context, userID int) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableTOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_DisableTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DisableTOTP'
+type MockStorage_DisableTOTP_Call struct {
+	*mock.Call
+}
+
+// DisableTOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockStorage_Expecter) DisableTOTP(ctx interface{}, userID interface{}) *MockStorage_DisableTOTP_Call {
+	return &MockStorage_DisableTOTP_Call{Call: _e.mock.On("DisableTOTP", ctx, userID)}
+}
+
+func (_c *MockStorage_DisableTOTP_Call) Run(run func(ctx context.Context, userID int)) *MockStorage_DisableTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_DisableTOTP_Call) Return(_a0 error) *MockStorage_DisableTOTP_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_DisableTOTP_Call) RunAndReturn(run func(context.Context, int) error) *MockStorage_DisableTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EnableTOTP provides a mock function with given fields: ctx, userID, step, codeHashes
+func (_m *MockStorage) EnableTOTP(ctx context.Context, userID int, step int64, codeHashes []string) error {
+	ret := _m.Called(ctx, userID, step, codeHashes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnableTOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int64, []string) error); ok {
+		r0 = rf(ctx, userID, step, codeHashes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_EnableTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnableTOTP'
+type MockStorage_EnableTOTP_Call struct {
+	*mock.Call
+}
+
+// EnableTOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - step int64
+//   - codeHashes []string
+func (_e *MockStorage_Expecter) EnableTOTP(ctx interface{}, userID interface{}, step interface{}, codeHashes interface{}) *MockStorage_EnableTOTP_Call {
+	return &MockStorage_EnableTOTP_Call{Call: _e.mock.On("EnableTOTP", ctx, userID, step, codeHashes)}
+}
+
+func (_c *MockStorage_EnableTOTP_Call) Run(run func(ctx context.Context, userID int, step int64, codeHashes []string)) *MockStorage_EnableTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int64), args[3].([]string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_EnableTOTP_Call) Return(_a0 error) *MockStorage_EnableTOTP_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_EnableTOTP_Call) RunAndReturn(run func(context.Context, int, int64, []string) error) *MockStorage_EnableTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExpirePoints provides a mock function with given fields: ctx
+func (_m *MockStorage) ExpirePoints(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpirePoints")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_ExpirePoints_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExpirePoints'
+type MockStorage_ExpirePoints_Call struct {
+	*mock.Call
+}
+
+// ExpirePoints is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockStorage_Expecter) ExpirePoints(ctx interface{}) *MockStorage_ExpirePoints_Call {
+	return &MockStorage_ExpirePoints_Call{Call: _e.mock.On("ExpirePoints", ctx)}
+}
+
+func (_c *MockStorage_ExpirePoints_Call) Run(run func(ctx context.Context)) *MockStorage_ExpirePoints_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockStorage_ExpirePoints_Call) Return(_a0 int64, _a1 error) *MockStorage_ExpirePoints_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_ExpirePoints_Call) RunAndReturn(run func(context.Context) (int64, error)) *MockStorage_ExpirePoints_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FailLoginChallenge provides a mock function with given fields: ctx, tokenHash, maxAttempts
+func (_m *MockStorage) FailLoginChallenge(ctx context.Context, tokenHash string, maxAttempts int) error {
+	ret := _m.Called(ctx, tokenHash, maxAttempts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FailLoginChallenge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = rf(ctx, tokenHash, maxAttempts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_FailLoginChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FailLoginChallenge'
+type MockStorage_FailLoginChallenge_Call struct {
+	*mock.Call
+}
+
+// FailLoginChallenge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenHash string
+//   - maxAttempts int
+func (_e *MockStorage_Expecter) FailLoginChallenge(ctx interface{}, tokenHash interface{}, maxAttempts interface{}) *MockStorage_FailLoginChallenge_Call {
+	return &MockStorage_FailLoginChallenge_Call{Call: _e.mock.On("FailLoginChallenge", ctx, tokenHash, maxAttempts)}
+}
+
+func (_c *MockStorage_FailLoginChallenge_Call) Run(run func(ctx context.Context, tokenHash string, maxAttempts int)) *MockStorage_FailLoginChallenge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_FailLoginChallenge_Call) Return(_a0 error) *MockStorage_FailLoginChallenge_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_FailLoginChallenge_Call) RunAndReturn(run func(context.Context, string, int) error) *MockStorage_FailLoginChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindBalanceDrifts provides a mock function with given fields: ctx
+func (_m *MockStorage) FindBalanceDrifts(ctx context.Context) (int, []models.BalanceDrift, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindBalanceDrifts")
+	}
+
+	var r0 int
+	var r1 []models.BalanceDrift
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, []models.BalanceDrift, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) []models.BalanceDrift); ok {
+		r1 = rf(ctx)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]models.BalanceDrift)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = rf(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockStorage_FindBalanceDrifts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindBalanceDrifts'
+type MockStorage_FindBalanceDrifts_Call struct {
+	*mock.Call
+}
+
+// FindBalanceDrifts is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockStorage_Expecter) FindBalanceDrifts(ctx interface{}) *MockStorage_FindBalanceDrifts_Call {
+	return &MockStorage_FindBalanceDrifts_Call{Call: _e.mock.On("FindBalanceDrifts", ctx)}
+}
+
+func (_c *MockStorage_FindBalanceDrifts_Call) Run(run func(ctx context.Context)) *MockStorage_FindBalanceDrifts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindBalanceDrifts_Call) Return(_a0 int, _a1 []models.BalanceDrift, _a2 error) *MockStorage_FindBalanceDrifts_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockStorage_FindBalanceDrifts_Call) RunAndReturn(run func(context.Context) (int, []models.BalanceDrift, error)) *MockStorage_FindBalanceDrifts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FinishIngestionRun provides a mock function with given fields: ctx, run
+func (_m *MockStorage) FinishIngestionRun(ctx context.Context, run models.IngestionRun) error {
+	ret := _m.Called(ctx, run)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishIngestionRun")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.IngestionRun) error); ok {
+		r0 = rf(ctx, run)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_FinishIngestionRun_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FinishIngestionRun'
+type MockStorage_FinishIngestionRun_Call struct {
+	*mock.Call
+}
+
+// FinishIngestionRun is a helper method to define mock.On call
+//   - ctx context.Context
+//   - run models.IngestionRun
+func (_e *MockStorage_Expecter) FinishIngestionRun(ctx interface{}, run interface{}) *MockStorage_FinishIngestionRun_Call {
+	return &MockStorage_FinishIngestionRun_Call{Call: _e.mock.On("FinishIngestionRun", ctx, run)}
+}
+
+func (_c *MockStorage_FinishIngestionRun_Call) Run(run func(ctx context.Context, run models.IngestionRun)) *MockStorage_FinishIngestionRun_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.IngestionRun))
+	})
+	return _c
+}
+
+func (_c *MockStorage_FinishIngestionRun_Call) Return(_a0 error) *MockStorage_FinishIngestionRun_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_FinishIngestionRun_Call) RunAndReturn(run func(context.Context, models.IngestionRun) error) *MockStorage_FinishIngestionRun_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FinishReconciliationRun provides a mock function with given fields: ctx, run
+func (_m *MockStorage) FinishReconciliationRun(ctx context.Context, run models.ReconciliationRun) error {
+	ret := _m.Called(ctx, run)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishReconciliationRun")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.ReconciliationRun) error); ok {
+		r0 = rf(ctx, run)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_FinishReconciliationRun_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FinishReconciliationRun'
+type MockStorage_FinishReconciliationRun_Call struct {
+	*mock.Call
+}
+
+// FinishReconciliationRun is a helper method to define mock.On call
+//   - ctx context.Context
+//   - run models.ReconciliationRun
+func (_e *MockStorage_Expecter) FinishReconciliationRun(ctx interface{}, run interface{}) *MockStorage_FinishReconciliationRun_Call {
+	return &MockStorage_FinishReconciliationRun_Call{Call: _e.mock.On("FinishReconciliationRun", ctx, run)}
+}
+
+func (_c *MockStorage_FinishReconciliationRun_Call) Run(run func(ctx context.Context, run models.ReconciliationRun)) *MockStorage_FinishReconciliationRun_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.ReconciliationRun))
+	})
+	return _c
+}
+
+func (_c *MockStorage_FinishReconciliationRun_Call) Return(_a0 error) *MockStorage_FinishReconciliationRun_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_FinishReconciliationRun_Call) RunAndReturn(run func(context.Context, models.ReconciliationRun) error) *MockStorage_FinishReconciliationRun_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAdminUser provides a mock function with given fields: ctx, userID
+func (_m *MockStorage) GetAdminUser(ctx context.Context, userID int) (models.AdminUser, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAdminUser")
+	}
+
+	var r0 models.AdminUser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.AdminUser, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.AdminUser); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(models.AdminUser)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetAdminUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAdminUser'
+type MockStorage_GetAdminUser_Call struct {
+	*mock.Call
+}
+
+// GetAdminUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockStorage_Expecter) GetAdminUser(ctx interface{}, userID interface{}) *MockStorage_GetAdminUser_Call {
+	return &MockStorage_GetAdminUser_Call{Call: _e.mock.On("GetAdminUser", ctx, userID)}
+}
+
+func (_c *MockStorage_GetAdminUser_Call) Run(run func(ctx context.Context, userID int)) *MockStorage_GetAdminUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetAdminUser_Call) Return(_a0 models.AdminUser, _a1 error) *MockStorage_GetAdminUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetAdminUser_Call) RunAndReturn(run func(context.Context, int) (models.AdminUser, error)) *MockStorage_GetAdminUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBalanceAt provides a mock function with given fields: ctx, userID, at
+func (_m *MockStorage) GetBalanceAt(ctx context.Context, userID int, at time.Time) (models.Balance, error) {
+	ret := _m.Called(ctx, userID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalanceAt")
+	}
+
+	var r0 models.Balance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) (models.Balance, error)); ok {
+		return rf(ctx, userID, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) models.Balance); ok {
+		r0 = rf(ctx, userID, at)
+	} else {
+		r0 = ret.Get(0).(models.Balance)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time) error); ok {
+		r1 = rf(ctx, userID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetBalanceAt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBalanceAt'
+type MockStorage_GetBalanceAt_Call struct {
+	*mock.Call
+}
+
+// GetBalanceAt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - at time.Time
+func (_e *MockStorage_Expecter) GetBalanceAt(ctx interface{}, userID interface{}, at interface{}) *MockStorage_GetBalanceAt_Call {
+	return &MockStorage_GetBalanceAt_Call{Call: _e.mock.On("GetBalanceAt", ctx, userID, at)}
+}
+
+func (_c *MockStorage_GetBalanceAt_Call) Run(run func(ctx context.Context, userID int, at time.Time)) *MockStorage_GetBalanceAt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetBalanceAt_Call) Return(_a0 models.Balance, _a1 error) *MockStorage_GetBalanceAt_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetBalanceAt_Call) RunAndReturn(run func(context.Context, int, time.Time) (models.Balance, error)) *MockStorage_GetBalanceAt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCampaign provides a mock function with given fields: ctx, id
+func (_m *MockStorage) GetCampaign(ctx context.Context, id int) (models.Campaign, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCampaign")
+	}
+
+	var r0 models.Campaign
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.Campaign, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.Campaign); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Campaign)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetCampaign_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCampaign'
+type MockStorage_GetCampaign_Call struct {
+	*mock.Call
+}
+
+// GetCampaign is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockStorage_Expecter) GetCampaign(ctx interface{}, id interface{}) *MockStorage_GetCampaign_Call {
+	return &MockStorage_GetCampaign_Call{Call: _e.mock.On("GetCampaign", ctx, id)}
+}
+
+func (_c *MockStorage_GetCampaign_Call) Run(run func(ctx context.Context, id int)) *MockStorage_GetCampaign_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetCampaign_Call) Return(_a0 models.Campaign, _a1 error) *MockStorage_GetCampaign_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetCampaign_Call) RunAndReturn(run func(context.Context, int) (models.Campaign, error)) *MockStorage_GetCampaign_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCheckoutThrottle provides a mock function with given fields: ctx, merchantID
+func (_m *MockStorage) GetCheckoutThrottle(ctx context.Context, merchantID int) (models.LoginThrottle, error) {
+	ret := _m.Called(ctx, merchantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCheckoutThrottle")
+	}
+
+	var r0 models.LoginThrottle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.LoginThrottle, error)); ok {
+		return rf(ctx, merchantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.LoginThrottle); ok {
+		r0 = rf(ctx, merchantID)
+	} else {
+		r0 = ret.Get(0).(models.LoginThrottle)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, merchantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetCheckoutThrottle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCheckoutThrottle'
+type MockStorage_GetCheckoutThrottle_Call struct {
+	*mock.Call
+}
+
+// GetCheckoutThrottle is a helper method to define mock.On call
+//   - ctx context.Context
+//   - merchantID int
+func (_e *MockStorage_Expecter) GetCheckoutThrottle(ctx interface{}, merchantID interface{}) *MockStorage_GetCheckoutThrottle_Call {
+	return &MockStorage_GetCheckoutThrottle_Call{Call: _e.mock.On("GetCheckoutThrottle", ctx, merchantID)}
+}
+
+func (_c *MockStorage_GetCheckoutThrottle_Call) Run(run func(ctx context.Context, merchantID int)) *MockStorage_GetCheckoutThrottle_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetCheckoutThrottle_Call) Return(_a0 models.LoginThrottle, _a1 error) *MockStorage_GetCheckoutThrottle_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetCheckoutThrottle_Call) RunAndReturn(run func(context.Context, int) (models.LoginThrottle, error)) *MockStorage_GetCheckoutThrottle_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetExpiringPoints provides a mock function with given fields: ctx, userID
+func (_m *MockStorage) GetExpiringPoints(ctx context.Context, userID int) ([]models.ExpiringPoints, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetExpiringPoints")
+	}
+
+	var r0 []models.ExpiringPoints
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.ExpiringPoints, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.ExpiringPoints); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ExpiringPoints)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetExpiringPoints_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetExpiringPoints'
+type MockStorage_GetExpiringPoints_Call struct {
+	*mock.Call
+}
+
+// GetExpiringPoints is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockStorage_Expecter) GetExpiringPoints(ctx interface{}, userID interface{}) *MockStorage_GetExpiringPoints_Call {
+	return &MockStorage_GetExpiringPoints_Call{Call: _e.mock.On("GetExpiringPoints", ctx, userID)}
+}
+
+func (_c *MockStorage_GetExpiringPoints_Call) Run(run func(ctx context.Context, userID int)) *MockStorage_GetExpiringPoints_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetExpiringPoints_Call) Return(_a0 []models.ExpiringPoints, _a1 error) *MockStorage_GetExpiringPoints_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetExpiringPoints_Call) RunAndReturn(run func(context.Context, int) ([]models.ExpiringPoints, error)) *MockStorage_GetExpiringPoints_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLastReconciliationRun provides a mock function with given fields: ctx
+func (_m *MockStorage) GetLastReconciliationRun(ctx context.Context) (models.ReconciliationRun, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastReconciliationRun")
+	}
+
+	var r0 models.ReconciliationRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (models.ReconciliationRun, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) models.ReconciliationRun); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(models.ReconciliationRun)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetLastReconciliationRun_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLastReconciliationRun'
+type MockStorage_GetLastReconciliationRun_Call struct {
+	*mock.Call
+}
+
+// GetLastReconciliationRun is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockStorage_Expecter) GetLastReconciliationRun(ctx interface{}) *MockStorage_GetLastReconciliationRun_Call {
+	return &MockStorage_GetLastReconciliationRun_Call{Call: _e.mock.On("GetLastReconciliationRun", ctx)}
+}
+
+func (_c *MockStorage_GetLastReconciliationRun_Call) Run(run func(ctx context.Context)) *MockStorage_GetLastReconciliationRun_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetLastReconciliationRun_Call) Return(_a0 models.ReconciliationRun, _a1 error) *MockStorage_GetLastReconciliationRun_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetLastReconciliationRun_Call) RunAndReturn(run func(context.Context) (models.ReconciliationRun, error)) *MockStorage_GetLastReconciliationRun_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLoginChallenge provides a mock function with given fields: ctx, tokenHash
+func (_m *MockStorage) GetLoginChallenge(ctx context.Context, tokenHash string) (*models.User, error) {
+	ret := _m.Called(ctx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoginChallenge")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
+		return rf(ctx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetLoginChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLoginChallenge'
+type MockStorage_GetLoginChallenge_Call struct {
+	*mock.Call
+}
+
+// GetLoginChallenge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenHash string
+func (_e *MockStorage_Expecter) GetLoginChallenge(ctx interface{}, tokenHash interface{}) *MockStorage_GetLoginChallenge_Call {
+	return &MockStorage_GetLoginChallenge_Call{Call: _e.mock.On("GetLoginChallenge", ctx, tokenHash)}
+}
+
+func (_c *MockStorage_GetLoginChallenge_Call) Run(run func(ctx context.Context, tokenHash string)) *MockStorage_GetLoginChallenge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetLoginChallenge_Call) Return(_a0 *models.User, _a1 error) *MockStorage_GetLoginChallenge_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetLoginChallenge_Call) RunAndReturn(run func(context.Context, string) (*models.User, error)) *MockStorage_GetLoginChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLoginThrottles provides a mock function with given fields: ctx, login, ip
+func (_m *MockStorage) GetLoginThrottles(ctx context.Context, login string, ip string) ([]models.LoginThrottle, error) {
+	ret := _m.Called(ctx, login, ip)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoginThrottles")
+	}
+
+	var r0 []models.LoginThrottle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]models.LoginThrottle, error)); ok {
+		return rf(ctx, login, ip)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []models.LoginThrottle); ok {
+		r0 = rf(ctx, login, ip)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.LoginThrottle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, login, ip)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetLoginThrottles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLoginThrottles'
+type MockStorage_GetLoginThrottles_Call struct {
+	*mock.Call
+}
+
+// GetLoginThrottles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login string
+//   - ip string
+func (_e *MockStorage_Expecter) GetLoginThrottles(ctx interface{}, login interface{}, ip interface{}) *MockStorage_GetLoginThrottles_Call {
+	return &MockStorage_GetLoginThrottles_Call{Call: _e.mock.On("GetLoginThrottles", ctx, login, ip)}
+}
+
+func (_c *MockStorage_GetLoginThrottles_Call) Run(run func(ctx context.Context, login string, ip string)) *MockStorage_GetLoginThrottles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetLoginThrottles_Call) Return(_a0 []models.LoginThrottle, _a1 error) *MockStorage_GetLoginThrottles_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetLoginThrottles_Call) RunAndReturn(run func(context.Context, string, string) ([]models.LoginThrottle, error)) *MockStorage_GetLoginThrottles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMerchantByAPIKey provides a mock function with given fields: ctx, keyHash
+func (_m *MockStorage) GetMerchantByAPIKey(ctx context.Context, keyHash string) (*models.Merchant, error) {
+	ret := _m.Called(ctx, keyHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMerchantByAPIKey")
+	}
+
+	var r0 *models.Merchant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Merchant, error)); ok {
+		return rf(ctx, keyHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Merchant); ok {
+		r0 = rf(ctx, keyHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Merchant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, keyHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetMerchantByAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMerchantByAPIKey'
+type MockStorage_GetMerchantByAPIKey_Call struct {
+	*mock.Call
+}
+
+// GetMerchantByAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keyHash string
+func (_e *MockStorage_Expecter) GetMerchantByAPIKey(ctx interface{}, keyHash interface{}) *MockStorage_GetMerchantByAPIKey_Call {
+	return &MockStorage_GetMerchantByAPIKey_Call{Call: _e.mock.On("GetMerchantByAPIKey", ctx, keyHash)}
+}
+
+func (_c *MockStorage_GetMerchantByAPIKey_Call) Run(run func(ctx context.Context, keyHash string)) *MockStorage_GetMerchantByAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetMerchantByAPIKey_Call) Return(_a0 *models.Merchant, _a1 error) *MockStorage_GetMerchantByAPIKey_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetMerchantByAPIKey_Call) RunAndReturn(run func(context.Context, string) (*models.Merchant, error)) *MockStorage_GetMerchantByAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetNotificationPreferences provides a mock function with given fields: ctx, userID
+func (_m *MockStorage) GetNotificationPreferences(ctx context.Context, userID int) (models.NotificationPreferences, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNotificationPreferences")
+	}
+
+	var r0 models.NotificationPreferences
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.NotificationPreferences, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.NotificationPreferences); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(models.NotificationPreferences)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetNotificationPreferences_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNotificationPreferences'
+type MockStorage_GetNotificationPreferences_Call struct {
+	*mock.Call
+}
+
+// GetNotificationPreferences is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockStorage_Expecter) GetNotificationPreferences(ctx interface{}, userID interface{}) *MockStorage_GetNotificationPreferences_Call {
+	return &MockStorage_GetNotificationPreferences_Call{Call: _e.mock.On("GetNotificationPreferences", ctx, userID)}
+}
+
+func (_c *MockStorage_GetNotificationPreferences_Call) Run(run func(ctx context.Context, userID int)) *MockStorage_GetNotificationPreferences_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetNotificationPreferences_Call) Return(_a0 models.NotificationPreferences, _a1 error) *MockStorage_GetNotificationPreferences_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetNotificationPreferences_Call) RunAndReturn(run func(context.Context, int) (models.NotificationPreferences, error)) *MockStorage_GetNotificationPreferences_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrderItems provides a mock function with given fields: ctx, orderNum
+func (_m *MockStorage) GetOrderItems(ctx context.Context, orderNum string) ([]models.OrderItem, error) {
+	ret := _m.Called(ctx, orderNum)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderItems")
+	}
+
+	var r0 []models.OrderItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]models.OrderItem, error)); ok {
+		return rf(ctx, orderNum)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []models.OrderItem); ok {
+		r0 = rf(ctx, orderNum)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.OrderItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, orderNum)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetOrderItems_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrderItems'
+type MockStorage_GetOrderItems_Call struct {
+	*mock.Call
+}
+
+// GetOrderItems is a helper method to define mock.On call
+//   - ctx context.Context
+//   - orderNum string
+func (_e *MockStorage_Expecter) GetOrderItems(ctx interface{}, orderNum interface{}) *MockStorage_GetOrderItems_Call {
+	return &MockStorage_GetOrderItems_Call{Call: _e.mock.On("GetOrderItems", ctx, orderNum)}
+}
+
+func (_c *MockStorage_GetOrderItems_Call) Run(run func(ctx context.Context, orderNum string)) *MockStorage_GetOrderItems_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetOrderItems_Call) Return(_a0 []models.OrderItem, _a1 error) *MockStorage_GetOrderItems_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetOrderItems_Call) RunAndReturn(run func(context.Context, string) ([]models.OrderItem, error)) *MockStorage_GetOrderItems_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrderOwners provides a mock function with given fields: ctx, numbers
+func (_m *MockStorage) GetOrderOwners(ctx context.Context, numbers []string) (map[string]int, error) {
+	ret := _m.Called(ctx, numbers)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderOwners")
+	}
+
+	var r0 map[string]int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) (map[string]int, error)); ok {
+		return rf(ctx, numbers)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) map[string]int); ok {
+		r0 = rf(ctx, numbers)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, numbers)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetOrderOwners_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrderOwners'
+type MockStorage_GetOrderOwners_Call struct {
+	*mock.Call
+}
+
+// GetOrderOwners is a helper method to define mock.On call
+//   - ctx context.Context
+//   - numbers []string
+func (_e *MockStorage_Expecter) GetOrderOwners(ctx interface{}, numbers interface{}) *MockStorage_GetOrderOwners_Call {
+	return &MockStorage_GetOrderOwners_Call{Call: _e.mock.On("GetOrderOwners", ctx, numbers)}
+}
+
+func (_c *MockStorage_GetOrderOwners_Call) Run(run func(ctx context.Context, numbers []string)) *MockStorage_GetOrderOwners_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetOrderOwners_Call) Return(_a0 map[string]int, _a1 error) *MockStorage_GetOrderOwners_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetOrderOwners_Call) RunAndReturn(run func(context.Context, []string) (map[string]int, error)) *MockStorage_GetOrderOwners_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPasswordHash provides a mock function with given fields: ctx, userID
+func (_m *MockStorage) GetPasswordHash(ctx context.Context, userID int) (string, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPasswordHash")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (string, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) string); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetPasswordHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPasswordHash'
+type MockStorage_GetPasswordHash_Call struct {
+	*mock.Call
+}
+
+// GetPasswordHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockStorage_Expecter) GetPasswordHash(ctx interface{}, userID interface{}) *MockStorage_GetPasswordHash_Call {
+	return &MockStorage_GetPasswordHash_Call{Call: _e.mock.On("GetPasswordHash", ctx, userID)}
+}
+
+func (_c *MockStorage_GetPasswordHash_Call) Run(run func(ctx context.Context, userID int)) *MockStorage_GetPasswordHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetPasswordHash_Call) Return(_a0 string, _a1 error) *MockStorage_GetPasswordHash_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetPasswordHash_Call) RunAndReturn(run func(context.Context, int) (string, error)) *MockStorage_GetPasswordHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPasswordResetThrottle provides a mock function with given fields: ctx, login
+func (_m *MockStorage) GetPasswordResetThrottle(ctx context.Context, login string) (models.LoginThrottle, error) {
+	ret := _m.Called(ctx, login)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPasswordResetThrottle")
+	}
+
+	var r0 models.LoginThrottle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.LoginThrottle, error)); ok {
+		return rf(ctx, login)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.LoginThrottle); ok {
+		r0 = rf(ctx, login)
+	} else {
+		r0 = ret.Get(0).(models.LoginThrottle)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, login)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetPasswordResetThrottle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPasswordResetThrottle'
+type MockStorage_GetPasswordResetThrottle_Call struct {
+	*mock.Call
+}
+
+// GetPasswordResetThrottle is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login string
+func (_e *MockStorage_Expecter) GetPasswordResetThrottle(ctx interface{}, login interface{}) *MockStorage_GetPasswordResetThrottle_Call {
+	return &MockStorage_GetPasswordResetThrottle_Call{Call: _e.mock.On("GetPasswordResetThrottle", ctx, login)}
+}
+
+func (_c *MockStorage_GetPasswordResetThrottle_Call) Run(run func(ctx context.Context, login string)) *MockStorage_GetPasswordResetThrottle_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetPasswordResetThrottle_Call) Return(_a0 models.LoginThrottle, _a1 error) *MockStorage_GetPasswordResetThrottle_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetPasswordResetThrottle_Call) RunAndReturn(run func(context.Context, string) (models.LoginThrottle, error)) *MockStorage_GetPasswordResetThrottle_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPendingOrders provides a mock function with given fields: ctx, olderThan
+func (_m *MockStorage) GetPendingOrders(ctx context.Context, olderThan time.Duration) ([]string, error) {
+	ret := _m.Called(ctx, olderThan)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingOrders")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) ([]string, error)); ok {
+		return rf(ctx, olderThan)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) []string); ok {
+		r0 = rf(ctx, olderThan)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
+		r1 = rf(ctx, olderThan)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetPendingOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPendingOrders'
+type MockStorage_GetPendingOrders_Call struct {
+	*mock.Call
+}
+
+// GetPendingOrders is a helper method to define mock.On call
+//   - ctx context.Context
+//   - olderThan time.Duration
+func (_e *MockStorage_Expecter) GetPendingOrders(ctx interface{}, olderThan interface{}) *MockStorage_GetPendingOrders_Call {
+	return &MockStorage_GetPendingOrders_Call{Call: _e.mock.On("GetPendingOrders", ctx, olderThan)}
+}
+
+func (_c *MockStorage_GetPendingOrders_Call) Run(run func(ctx context.Context, olderThan time.Duration)) *MockStorage_GetPendingOrders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetPendingOrders_Call) Return(_a0 []string, _a1 error) *MockStorage_GetPendingOrders_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetPendingOrders_Call) RunAndReturn(run func(context.Context, time.Duration) ([]string, error)) *MockStorage_GetPendingOrders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetProcessedOrderFacts provides a mock function with given fields: ctx, from, to
+func (_m *MockStorage) GetProcessedOrderFacts(ctx context.Context, from time.Time, to time.Time) ([]campaign.OrderFacts, error) {
+	ret := _m.Called(ctx, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProcessedOrderFacts")
+	}
+
+	var r0 []campaign.OrderFacts
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) ([]campaign.OrderFacts, error)); ok {
+		return rf(ctx, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []campaign.OrderFacts); ok {
+		r0 = rf(ctx, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]campaign.OrderFacts)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetProcessedOrderFacts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetProcessedOrderFacts'
+type MockStorage_GetProcessedOrderFacts_Call struct {
+	*mock.Call
+}
+
+// GetProcessedOrderFacts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - from time.Time
+//   - to time.Time
+func (_e *MockStorage_Expecter) GetProcessedOrderFacts(ctx interface{}, from interface{}, to interface{}) *MockStorage_GetProcessedOrderFacts_Call {
+	return &MockStorage_GetProcessedOrderFacts_Call{Call: _e.mock.On("GetProcessedOrderFacts", ctx, from, to)}
+}
+
+func (_c *MockStorage_GetProcessedOrderFacts_Call) Run(run func(ctx context.Context, from time.Time, to time.Time)) *MockStorage_GetProcessedOrderFacts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetProcessedOrderFacts_Call) Return(_a0 []campaign.OrderFacts, _a1 error) *MockStorage_GetProcessedOrderFacts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetProcessedOrderFacts_Call) RunAndReturn(run func(context.Context, time.Time, time.Time) ([]campaign.OrderFacts, error)) *MockStorage_GetProcessedOrderFacts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRollingAccrual provides a mock function with given fields: ctx, userID, windowMonths
+func (_m *MockStorage) GetRollingAccrual(ctx context.Context, userID int, windowMonths int) (float64, error) {
+	ret := _m.Called(ctx, userID, windowMonths)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRollingAccrual")
+	}
+
+	var r0 float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (float64, error)); ok {
+		return rf(ctx, userID, windowMonths)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) float64); ok {
+		r0 = rf(ctx, userID, windowMonths)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, userID, windowMonths)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetRollingAccrual_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRollingAccrual'
+type MockStorage_GetRollingAccrual_Call struct {
+	*mock.Call
+}
+
+// GetRollingAccrual is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - windowMonths int
+func (_e *MockStorage_Expecter) GetRollingAccrual(ctx interface{}, userID interface{}, windowMonths interface{}) *MockStorage_GetRollingAccrual_Call {
+	return &MockStorage_GetRollingAccrual_Call{Call: _e.mock.On("GetRollingAccrual", ctx, userID, windowMonths)}
+}
+
+func (_c *MockStorage_GetRollingAccrual_Call) Run(run func(ctx context.Context, userID int, windowMonths int)) *MockStorage_GetRollingAccrual_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetRollingAccrual_Call) Return(_a0 float64, _a1 error) *MockStorage_GetRollingAccrual_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetRollingAccrual_Call) RunAndReturn(run func(context.Context, int, int) (float64, error)) *MockStorage_GetRollingAccrual_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTOTP provides a mock function with given fields: ctx, userID
+func (_m *MockStorage) GetTOTP(ctx context.Context, userID int) (models.TOTP, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTOTP")
+	}
+
+	var r0 models.TOTP
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.TOTP, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.TOTP); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(models.TOTP)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTOTP'
+type MockStorage_GetTOTP_Call struct {
+	*mock.Call
+}
+
+// GetTOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockStorage_Expecter) GetTOTP(ctx interface{}, userID interface{}) *MockStorage_GetTOTP_Call {
+	return &MockStorage_GetTOTP_Call{Call: _e.mock.On("GetTOTP", ctx, userID)}
+}
+
+func (_c *MockStorage_GetTOTP_Call) Run(run func(ctx context.Context, userID int)) *MockStorage_GetTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetTOTP_Call) Return(_a0 models.TOTP, _a1 error) *MockStorage_GetTOTP_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetTOTP_Call) RunAndReturn(run func(context.Context, int) (models.TOTP, error)) *MockStorage_GetTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTOTPThrottle provides a mock function with given fields: ctx, userID
+func (_m *MockStorage) GetTOTPThrottle(ctx context.Context, userID int) (models.LoginThrottle, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTOTPThrottle")
+	}
+
+	var r0 models.LoginThrottle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.LoginThrottle, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.LoginThrottle); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(models.LoginThrottle)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetTOTPThrottle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTOTPThrottle'
+type MockStorage_GetTOTPThrottle_Call struct {
+	*mock.Call
+}
+
+// GetTOTPThrottle is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockStorage_Expecter) GetTOTPThrottle(ctx interface{}, userID interface{}) *MockStorage_GetTOTPThrottle_Call {
+	return &MockStorage_GetTOTPThrottle_Call{Call: _e.mock.On("GetTOTPThrottle", ctx, userID)}
+}
+
+func (_c *MockStorage_GetTOTPThrottle_Call) Run(run func(ctx context.Context, userID int)) *MockStorage_GetTOTPThrottle_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetTOTPThrottle_Call) Return(_a0 models.LoginThrottle, _a1 error) *MockStorage_GetTOTPThrottle_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetTOTPThrottle_Call) RunAndReturn(run func(context.Context, int) (models.LoginThrottle, error)) *MockStorage_GetTOTPThrottle_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTiers provides a mock function with given fields: ctx
+func (_m *MockStorage) GetTiers(ctx context.Context) ([]models.Tier, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTiers")
+	}
+
+	var r0 []models.Tier
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Tier, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Tier); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Tier)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetTiers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTiers'
+type MockStorage_GetTiers_Call struct {
+	*mock.Call
+}
+
+// GetTiers is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockStorage_Expecter) GetTiers(ctx interface{}) *MockStorage_GetTiers_Call {
+	return &MockStorage_GetTiers_Call{Call: _e.mock.On("GetTiers", ctx)}
+}
+
+func (_c *MockStorage_GetTiers_Call) Run(run func(ctx context.Context)) *MockStorage_GetTiers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetTiers_Call) Return(_a0 []models.Tier, _a1 error) *MockStorage_GetTiers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetTiers_Call) RunAndReturn(run func(context.Context) ([]models.Tier, error)) *MockStorage_GetTiers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserBalance provides a mock function with given fields: ctx, userID
+func (_m *MockStorage) GetUserBalance(ctx context.Context, userID int) (models.Balance, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserBalance")
+	}
+
+	var r0 models.Balance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.Balance, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.Balance); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(models.Balance)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetUserBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserBalance'
+type MockStorage_GetUserBalance_Call struct {
+	*mock.Call
+}
+
+// GetUserBalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockStorage_Expecter) GetUserBalance(ctx interface{}, userID interface{}) *MockStorage_GetUserBalance_Call {
+	return &MockStorage_GetUserBalance_Call{Call: _e.mock.On("GetUserBalance", ctx, userID)}
+}
+
+func (_c *MockStorage_GetUserBalance_Call) Run(run func(ctx context.Context, userID int)) *MockStorage_GetUserBalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetUserBalance_Call) Return(_a0 models.Balance, _a1 error) *MockStorage_GetUserBalance_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetUserBalance_Call) RunAndReturn(run func(context.Context, int) (models.Balance, error)) *MockStorage_GetUserBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserByLogin provides a mock function with given fields: ctx, login
+func (_m *MockStorage) GetUserByLogin(ctx context.Context, login string) (*models.User, error) {
+	ret := _m.Called(ctx, login)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByLogin")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
+		return rf(ctx, login)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, login)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, login)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetUserByLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByLogin'
+type MockStorage_GetUserByLogin_Call struct {
+	*mock.Call
+}
+
+// GetUserByLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login string
+func (_e *MockStorage_Expecter) GetUserByLogin(ctx interface{}, login interface{}) *MockStorage_GetUserByLogin_Call {
+	return &MockStorage_GetUserByLogin_Call{Call: _e.mock.On("GetUserByLogin", ctx, login)}
+}
+
+func (_c *MockStorage_GetUserByLogin_Call) Run(run func(ctx context.Context, login string)) *MockStorage_GetUserByLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetUserByLogin_Call) Return(_a0 *models.User, _a1 error) *MockStorage_GetUserByLogin_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetUserByLogin_Call) RunAndReturn(run func(context.Context, string) (*models.User, error)) *MockStorage_GetUserByLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserByReferralCode provides a mock function with given fields: ctx, code
+func (_m *MockStorage) GetUserByReferralCode(ctx context.Context, code string) (*models.User, error) {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByReferralCode")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetUserByReferralCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByReferralCode'
+type MockStorage_GetUserByReferralCode_Call struct {
+	*mock.Call
+}
+
+// GetUserByReferralCode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - code string
+func (_e *MockStorage_Expecter) GetUserByReferralCode(ctx interface{}, code interface{}) *MockStorage_GetUserByReferralCode_Call {
+	return &MockStorage_GetUserByReferralCode_Call{Call: _e.mock.On("GetUserByReferralCode", ctx, code)}
+}
+
+func (_c *MockStorage_GetUserByReferralCode_Call) Run(run func(ctx context.Context, code string)) *MockStorage_GetUserByReferralCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetUserByReferralCode_Call) Return(_a0 *models.User, _a1 error) *MockStorage_GetUserByReferralCode_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetUserByReferralCode_Call) RunAndReturn(run func(context.Context, string) (*models.User, error)) *MockStorage_GetUserByReferralCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserOrders provides a mock function with given fields: ctx, userID
+func (_m *MockStorage) GetUserOrders(ctx context.Context, userID int) ([]models.Order, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserOrders")
+	}
+
+	var r0 []models.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.Order, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.Order); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetUserOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserOrders'
+type MockStorage_GetUserOrders_Call struct {
+	*mock.Call
+}
+
+// GetUserOrders is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockStorage_Expecter) GetUserOrders(ctx interface{}, userID interface{}) *MockStorage_GetUserOrders_Call {
+	return &MockStorage_GetUserOrders_Call{Call: _e.mock.On("GetUserOrders", ctx, userID)}
+}
+
+func (_c *MockStorage_GetUserOrders_Call) Run(run func(ctx context.Context, userID int)) *MockStorage_GetUserOrders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetUserOrders_Call) Return(_a0 []models.Order, _a1 error) *MockStorage_GetUserOrders_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetUserOrders_Call) RunAndReturn(run func(context.Context, int) ([]models.Order, error)) *MockStorage_GetUserOrders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserPermissions provides a mock function with given fields: ctx, userID
+func (_m *MockStorage) GetUserPermissions(ctx context.Context, userID int) ([]string, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserPermissions")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]string, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []string); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetUserPermissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserPermissions'
+type MockStorage_GetUserPermissions_Call struct {
+	*mock.Call
+}
+
+// GetUserPermissions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockStorage_Expecter) GetUserPermissions(ctx interface{}, userID interface{}) *MockStorage_GetUserPermissions_Call {
+	return &MockStorage_GetUserPermissions_Call{Call: _e.mock.On("GetUserPermissions", ctx, userID)}
+}
+
+func (_c *MockStorage_GetUserPermissions_Call) Run(run func(ctx context.Context, userID int)) *MockStorage_GetUserPermissions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetUserPermissions_Call) Return(_a0 []string, _a1 error) *MockStorage_GetUserPermissions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetUserPermissions_Call) RunAndReturn(run func(context.Context, int) ([]string, error)) *MockStorage_GetUserPermissions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserReferrals provides a mock function with given fields: ctx, userID
+func (_m *MockStorage) GetUserReferrals(ctx context.Context, userID int) (models.ReferralSummary, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserReferrals")
+	}
+
+	var r0 models.ReferralSummary
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.ReferralSummary, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.ReferralSummary); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(models.ReferralSummary)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetUserReferrals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserReferrals'
+type MockStorage_GetUserReferrals_Call struct {
+	*mock.Call
+}
+
+// GetUserReferrals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockStorage_Expecter) GetUserReferrals(ctx interface{}, userID interface{}) *MockStorage_GetUserReferrals_Call {
+	return &MockStorage_GetUserReferrals_Call{Call: _e.mock.On("GetUserReferrals", ctx, userID)}
+}
+
+func (_c *MockStorage_GetUserReferrals_Call) Run(run func(ctx context.Context, userID int)) *MockStorage_GetUserReferrals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetUserReferrals_Call) Return(_a0 models.ReferralSummary, _a1 error) *MockStorage_GetUserReferrals_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetUserReferrals_Call) RunAndReturn(run func(context.Context, int) (models.ReferralSummary, error)) *MockStorage_GetUserReferrals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserSessions provides a mock function with given fields: ctx, userID
+func (_m *MockStorage) GetUserSessions(ctx context.Context, userID int) ([]models.Session, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserSessions")
+	}
+
+	var r0 []models.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.Session, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.Session); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetUserSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserSessions'
+type MockStorage_GetUserSessions_Call struct {
+	*mock.Call
+}
+
+// GetUserSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockStorage_Expecter) GetUserSessions(ctx interface{}, userID interface{}) *MockStorage_GetUserSessions_Call {
+	return &MockStorage_GetUserSessions_Call{Call: _e.mock.On("GetUserSessions", ctx, userID)}
+}
+
+func (_c *MockStorage_GetUserSessions_Call) Run(run func(ctx context.Context, userID int)) *MockStorage_GetUserSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetUserSessions_Call) Return(_a0 []models.Session, _a1 error) *MockStorage_GetUserSessions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetUserSessions_Call) RunAndReturn(run func(context.Context, int) ([]models.Session, error)) *MockStorage_GetUserSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserTransfers provides a mock function with given fields: ctx, userID
+func (_m *MockStorage) GetUserTransfers(ctx context.Context, userID int) ([]models.Transfer, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserTransfers")
+	}
+
+	var r0 []models.Transfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.Transfer, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.Transfer); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Transfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetUserTransfers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserTransfers'
+type MockStorage_GetUserTransfers_Call struct {
+	*mock.Call
+}
+
+// GetUserTransfers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockStorage_Expecter) GetUserTransfers(ctx interface{}, userID interface{}) *MockStorage_GetUserTransfers_Call {
+	return &MockStorage_GetUserTransfers_Call{Call: _e.mock.On("GetUserTransfers", ctx, userID)}
+}
+
+func (_c *MockStorage_GetUserTransfers_Call) Run(run func(ctx context.Context, userID int)) *MockStorage_GetUserTransfers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetUserTransfers_Call) Return(_a0 []models.Transfer, _a1 error) *MockStorage_GetUserTransfers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetUserTransfers_Call) RunAndReturn(run func(context.Context, int) ([]models.Transfer, error)) *MockStorage_GetUserTransfers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserWithdrawals provides a mock function with given fields: ctx, userID
+func (_m *MockStorage) GetUserWithdrawals(ctx context.Context, userID int) ([]models.Withdrawal, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserWithdrawals")
+	}
+
+	var r0 []models.Withdrawal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.Withdrawal, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.Withdrawal); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Withdrawal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetUserWithdrawals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserWithdrawals'
+type MockStorage_GetUserWithdrawals_Call struct {
+	*mock.Call
+}
+
+// GetUserWithdrawals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockStorage_Expecter) GetUserWithdrawals(ctx interface{}, userID interface{}) *MockStorage_GetUserWithdrawals_Call {
+	return &MockStorage_GetUserWithdrawals_Call{Call: _e.mock.On("GetUserWithdrawals", ctx, userID)}
+}
+
+func (_c *MockStorage_GetUserWithdrawals_Call) Run(run func(ctx context.Context, userID int)) *MockStorage_GetUserWithdrawals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetUserWithdrawals_Call) Return(_a0 []models.Withdrawal, _a1 error) *MockStorage_GetUserWithdrawals_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetUserWithdrawals_Call) RunAndReturn(run func(context.Context, int) ([]models.Withdrawal, error)) *MockStorage_GetUserWithdrawals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWebhookDeliveries provides a mock function with given fields: ctx, merchantID, status, limit
+func (_m *MockStorage) GetWebhookDeliveries(ctx context.Context, merchantID int, status string, limit int) ([]models.WebhookDelivery, error) {
+	ret := _m.Called(ctx, merchantID, status, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhookDeliveries")
+	}
+
+	var r0 []models.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, int) ([]models.WebhookDelivery, error)); ok {
+		return rf(ctx, merchantID, status, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, int) []models.WebhookDelivery); ok {
+		r0 = rf(ctx, merchantID, status, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, int) error); ok {
+		r1 = rf(ctx, merchantID, status, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetWebhookDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWebhookDeliveries'
+type MockStorage_GetWebhookDeliveries_Call struct {
+	*mock.Call
+}
+
+// GetWebhookDeliveries is a helper method to define mock.On call
+//   - ctx context.Context
+//   - merchantID int
+//   - status string
+//   - limit int
+func (_e *MockStorage_Expecter) GetWebhookDeliveries(ctx interface{}, merchantID interface{}, status interface{}, limit interface{}) *MockStorage_GetWebhookDeliveries_Call {
+	return &MockStorage_GetWebhookDeliveries_Call{Call: _e.mock.On("GetWebhookDeliveries", ctx, merchantID, status, limit)}
+}
+
+func (_c *MockStorage_GetWebhookDeliveries_Call) Run(run func(ctx context.Context, merchantID int, status string, limit int)) *MockStorage_GetWebhookDeliveries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetWebhookDeliveries_Call) Return(_a0 []models.WebhookDelivery, _a1 error) *MockStorage_GetWebhookDeliveries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetWebhookDeliveries_Call) RunAndReturn(run func(context.Context, int, string, int) ([]models.WebhookDelivery, error)) *MockStorage_GetWebhookDeliveries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWebhookSubscriptions provides a mock function with given fields: ctx, merchantID
+func (_m *MockStorage) GetWebhookSubscriptions(ctx context.Context, merchantID int) ([]models.WebhookSubscription, error) {
+	ret := _m.Called(ctx, merchantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhookSubscriptions")
+	}
+
+	var r0 []models.WebhookSubscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.WebhookSubscription, error)); ok {
+		return rf(ctx, merchantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.WebhookSubscription); ok {
+		r0 = rf(ctx, merchantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.WebhookSubscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, merchantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetWebhookSubscriptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWebhookSubscriptions'
+type MockStorage_GetWebhookSubscriptions_Call struct {
+	*mock.Call
+}
+
+// GetWebhookSubscriptions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - merchantID int
+func (_e *MockStorage_Expecter) GetWebhookSubscriptions(ctx interface{}, merchantID interface{}) *MockStorage_GetWebhookSubscriptions_Call {
+	return &MockStorage_GetWebhookSubscriptions_Call{Call: _e.mock.On("GetWebhookSubscriptions", ctx, merchantID)}
+}
+
+func (_c *MockStorage_GetWebhookSubscriptions_Call) Run(run func(ctx context.Context, merchantID int)) *MockStorage_GetWebhookSubscriptions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetWebhookSubscriptions_Call) Return(_a0 []models.WebhookSubscription, _a1 error) *MockStorage_GetWebhookSubscriptions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetWebhookSubscriptions_Call) RunAndReturn(run func(context.Context, int) ([]models.WebhookSubscription, error)) *MockStorage_GetWebhookSubscriptions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsOrderExists provides a mock function with given fields: ctx, orderNum
+func (_m *MockStorage) IsOrderExists(ctx context.Context, orderNum string) (int, error) {
+	ret := _m.Called(ctx, orderNum)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsOrderExists")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, orderNum)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, orderNum)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, orderNum)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_IsOrderExists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsOrderExists'
+type MockStorage_IsOrderExists_Call struct {
+	*mock.Call
+}
+
+// IsOrderExists is a helper method to define mock.On call
+//   - ctx context.Context
+//   - orderNum string
+func (_e *MockStorage_Expecter) IsOrderExists(ctx interface{}, orderNum interface{}) *MockStorage_IsOrderExists_Call {
+	return &MockStorage_IsOrderExists_Call{Call: _e.mock.On("IsOrderExists", ctx, orderNum)}
+}
+
+func (_c *MockStorage_IsOrderExists_Call) Run(run func(ctx context.Context, orderNum string)) *MockStorage_IsOrderExists_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_IsOrderExists_Call) Return(_a0 int, _a1 error) *MockStorage_IsOrderExists_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_IsOrderExists_Call) RunAndReturn(run func(context.Context, string) (int, error)) *MockStorage_IsOrderExists_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsTokenRevoked provides a mock function with given fields: ctx, jti, sessionID
+func (_m *MockStorage) IsTokenRevoked(ctx context.Context, jti string, sessionID int) (bool, error) {
+	ret := _m.Called(ctx, jti, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsTokenRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (bool, error)); ok {
+		return rf(ctx, jti, sessionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) bool); ok {
+		r0 = rf(ctx, jti, sessionID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, jti, sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_IsTokenRevoked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsTokenRevoked'
+type MockStorage_IsTokenRevoked_Call struct {
+	*mock.Call
+}
+
+// IsTokenRevoked is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jti string
+//   - sessionID int
+func (_e *MockStorage_Expecter) IsTokenRevoked(ctx interface{}, jti interface{}, sessionID interface{}) *MockStorage_IsTokenRevoked_Call {
+	return &MockStorage_IsTokenRevoked_Call{Call: _e.mock.On("IsTokenRevoked", ctx, jti, sessionID)}
+}
+
+func (_c *MockStorage_IsTokenRevoked_Call) Run(run func(ctx context.Context, jti string, sessionID int)) *MockStorage_IsTokenRevoked_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_IsTokenRevoked_Call) Return(_a0 bool, _a1 error) *MockStorage_IsTokenRevoked_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_IsTokenRevoked_Call) RunAndReturn(run func(context.Context, string, int) (bool, error)) *MockStorage_IsTokenRevoked_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsUserBlocked provides a mock function with given fields: ctx, userID
+func (_m *MockStorage) IsUserBlocked(ctx context.Context, userID int) (bool, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsUserBlocked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (bool, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) bool); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_IsUserBlocked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsUserBlocked'
+type MockStorage_IsUserBlocked_Call struct {
+	*mock.Call
+}
+
+// IsUserBlocked is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockStorage_Expecter) IsUserBlocked(ctx interface{}, userID interface{}) *MockStorage_IsUserBlocked_Call {
+	return &MockStorage_IsUserBlocked_Call{Call: _e.mock.On("IsUserBlocked", ctx, userID)}
+}
+
+func (_c *MockStorage_IsUserBlocked_Call) Run(run func(ctx context.Context, userID int)) *MockStorage_IsUserBlocked_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_IsUserBlocked_Call) Return(_a0 bool, _a1 error) *MockStorage_IsUserBlocked_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_IsUserBlocked_Call) RunAndReturn(run func(context.Context, int) (bool, error)) *MockStorage_IsUserBlocked_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListCampaigns provides a mock function with given fields: ctx
+func (_m *MockStorage) ListCampaigns(ctx context.Context) ([]models.Campaign, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCampaigns")
+	}
+
+	var r0 []models.Campaign
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Campaign, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Campaign); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Campaign)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_ListCampaigns_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListCampaigns'
+type MockStorage_ListCampaigns_Call struct {
+	*mock.Call
+}
+
+// ListCampaigns is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockStorage_Expecter) ListCampaigns(ctx interface{}) *MockStorage_ListCampaigns_Call {
+	return &MockStorage_ListCampaigns_Call{Call: _e.mock.On("ListCampaigns", ctx)}
+}
+
+func (_c *MockStorage_ListCampaigns_Call) Run(run func(ctx context.Context)) *MockStorage_ListCampaigns_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockStorage_ListCampaigns_Call) Return(_a0 []models.Campaign, _a1 error) *MockStorage_ListCampaigns_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_ListCampaigns_Call) RunAndReturn(run func(context.Context) ([]models.Campaign, error)) *MockStorage_ListCampaigns_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LogAdminAction provides a mock function with given fields: ctx, action
+func (_m *MockStorage) LogAdminAction(ctx context.Context, action models.AdminAction) error {
+	ret := _m.Called(ctx, action)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LogAdminAction")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.AdminAction) error); ok {
+		r0 = rf(ctx, action)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_LogAdminAction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LogAdminAction'
+type MockStorage_LogAdminAction_Call struct {
+	*mock.Call
+}
+
+// LogAdminAction is a helper method to define mock.On call
+//   - ctx context.Context
+//   - action models.AdminAction
+func (_e *MockStorage_Expecter) LogAdminAction(ctx interface{}, action interface{}) *MockStorage_LogAdminAction_Call {
+	return &MockStorage_LogAdminAction_Call{Call: _e.mock.On("LogAdminAction", ctx, action)}
+}
+
+func (_c *MockStorage_LogAdminAction_Call) Run(run func(ctx context.Context, action models.AdminAction)) *MockStorage_LogAdminAction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.AdminAction))
+	})
+	return _c
+}
+
+func (_c *MockStorage_LogAdminAction_Call) Return(_a0 error) *MockStorage_LogAdminAction_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_LogAdminAction_Call) RunAndReturn(run func(context.Context, models.AdminAction) error) *MockStorage_LogAdminAction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordCheckoutFailure provides a mock function with given fields: ctx, merchantID, policy
+func (_m *MockStorage) RecordCheckoutFailure(ctx context.Context, merchantID int, policy models.LoginPolicy) error {
+	ret := _m.Called(ctx, merchantID, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordCheckoutFailure")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.LoginPolicy) error); ok {
+		r0 = rf(ctx, merchantID, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_RecordCheckoutFailure_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordCheckoutFailure'
+type MockStorage_RecordCheckoutFailure_Call struct {
+	*mock.Call
+}
+
+// RecordCheckoutFailure is a helper method to define mock.On call
+//   - ctx context.Context
+//   - merchantID int
+//   - policy models.LoginPolicy
+func (_e *MockStorage_Expecter) RecordCheckoutFailure(ctx interface{}, merchantID interface{}, policy interface{}) *MockStorage_RecordCheckoutFailure_Call {
+	return &MockStorage_RecordCheckoutFailure_Call{Call: _e.mock.On("RecordCheckoutFailure", ctx, merchantID, policy)}
+}
+
+func (_c *MockStorage_RecordCheckoutFailure_Call) Run(run func(ctx context.Context, merchantID int, policy models.LoginPolicy)) *MockStorage_RecordCheckoutFailure_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.LoginPolicy))
+	})
+	return _c
+}
+
+func (_c *MockStorage_RecordCheckoutFailure_Call) Return(_a0 error) *MockStorage_RecordCheckoutFailure_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_RecordCheckoutFailure_Call) RunAndReturn(run func(context.Context, int, models.LoginPolicy) error) *MockStorage_RecordCheckoutFailure_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordLoginFailure provides a mock function with given fields: ctx, login, ip, reason, policy
+func (_m *MockStorage) RecordLoginFailure(ctx context.Context, login string, ip string, reason string, policy models.LoginPolicy) error {
+	ret := _m.Called(ctx, login, ip, reason, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordLoginFailure")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, models.LoginPolicy) error); ok {
+		r0 = rf(ctx, login, ip, reason, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_RecordLoginFailure_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordLoginFailure'
+type MockStorage_RecordLoginFailure_Call struct {
+	*mock.Call
+}
+
+// RecordLoginFailure is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login string
+//   - ip string
+//   - reason string
+//   - policy models.LoginPolicy
+func (_e *MockStorage_Expecter) RecordLoginFailure(ctx interface{}, login interface{}, ip interface{}, reason interface{}, policy interface{}) *MockStorage_RecordLoginFailure_Call {
+	return &MockStorage_RecordLoginFailure_Call{Call: _e.mock.On("RecordLoginFailure", ctx, login, ip, reason, policy)}
+}
+
+func (_c *MockStorage_RecordLoginFailure_Call) Run(run func(ctx context.Context, login string, ip string, reason string, policy models.LoginPolicy)) *MockStorage_RecordLoginFailure_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(models.LoginPolicy))
+	})
+	return _c
+}
+
+func (_c *MockStorage_RecordLoginFailure_Call) Return(_a0 error) *MockStorage_RecordLoginFailure_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_RecordLoginFailure_Call) RunAndReturn(run func(context.Context, string, string, string, models.LoginPolicy) error) *MockStorage_RecordLoginFailure_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordLoginSuccess provides a mock function with given fields: ctx, login, ip
+func (_m *MockStorage) RecordLoginSuccess(ctx context.Context, login string, ip string) error {
+	ret := _m.Called(ctx, login, ip)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordLoginSuccess")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, login, ip)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_RecordLoginSuccess_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordLoginSuccess'
+type MockStorage_RecordLoginSuccess_Call struct {
+	*mock.Call
+}
+
+// RecordLoginSuccess is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login string
+//   - ip string
+func (_e *MockStorage_Expecter) RecordLoginSuccess(ctx interface{}, login interface{}, ip interface{}) *MockStorage_RecordLoginSuccess_Call {
+	return &MockStorage_RecordLoginSuccess_Call{Call: _e.mock.On("RecordLoginSuccess", ctx, login, ip)}
+}
+
+func (_c *MockStorage_RecordLoginSuccess_Call) Run(run func(ctx context.Context, login string, ip string)) *MockStorage_RecordLoginSuccess_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_RecordLoginSuccess_Call) Return(_a0 error) *MockStorage_RecordLoginSuccess_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_RecordLoginSuccess_Call) RunAndReturn(run func(context.Context, string, string) error) *MockStorage_RecordLoginSuccess_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordPasswordResetRequest provides a mock function with given fields: ctx, login, policy
+func (_m *MockStorage) RecordPasswordResetRequest(ctx context.Context, login string, policy models.LoginPolicy) error {
+	ret := _m.Called(ctx, login, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordPasswordResetRequest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.LoginPolicy) error); ok {
+		r0 = rf(ctx, login, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_RecordPasswordResetRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordPasswordResetRequest'
+type MockStorage_RecordPasswordResetRequest_Call struct {
+	*mock.Call
+}
+
+// RecordPasswordResetRequest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login string
+//   - policy models.LoginPolicy
+func (_e *MockStorage_Expecter) RecordPasswordResetRequest(ctx interface{}, login interface{}, policy interface{}) *MockStorage_RecordPasswordResetRequest_Call {
+	return &MockStorage_RecordPasswordResetRequest_Call{Call: _e.mock.On("RecordPasswordResetRequest", ctx, login, policy)}
+}
+
+func (_c *MockStorage_RecordPasswordResetRequest_Call) Run(run func(ctx context.Context, login string, policy models.LoginPolicy)) *MockStorage_RecordPasswordResetRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.LoginPolicy))
+	})
+	return _c
+}
+
+func (_c *MockStorage_RecordPasswordResetRequest_Call) Return(_a0 error) *MockStorage_RecordPasswordResetRequest_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_RecordPasswordResetRequest_Call) RunAndReturn(run func(context.Context, string, models.LoginPolicy) error) *MockStorage_RecordPasswordResetRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordTOTPFailure provides a mock function with given fields: ctx, userID, policy
+func (_m *MockStorage) RecordTOTPFailure(ctx context.Context, userID int, policy models.LoginPolicy) error {
+	ret := _m.Called(ctx, userID, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordTOTPFailure")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.LoginPolicy) error); ok {
+		r0 = rf(ctx, userID, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_RecordTOTPFailure_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordTOTPFailure'
+type MockStorage_RecordTOTPFailure_Call struct {
+	*mock.Call
+}
+
+// RecordTOTPFailure is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - policy models.LoginPolicy
+func (_e *MockStorage_Expecter) RecordTOTPFailure(ctx interface{}, userID interface{}, policy interface{}) *MockStorage_RecordTOTPFailure_Call {
+	return &MockStorage_RecordTOTPFailure_Call{Call: _e.mock.On("RecordTOTPFailure", ctx, userID, policy)}
+}
+
+func (_c *MockStorage_RecordTOTPFailure_Call) Run(run func(ctx context.Context, userID int, policy models.LoginPolicy)) *MockStorage_RecordTOTPFailure_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.LoginPolicy))
+	})
+	return _c
+}
+
+func (_c *MockStorage_RecordTOTPFailure_Call) Return(_a0 error) *MockStorage_RecordTOTPFailure_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_RecordTOTPFailure_Call) RunAndReturn(run func(context.Context, int, models.LoginPolicy) error) *MockStorage_RecordTOTPFailure_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordWebhookAttempt provides a mock function with given fields: ctx, attempt
+func (_m *MockStorage) RecordWebhookAttempt(ctx context.Context, attempt models.WebhookAttempt) error {
+	ret := _m.Called(ctx, attempt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordWebhookAttempt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.WebhookAttempt) error); ok {
+		r0 = rf(ctx, attempt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_RecordWebhookAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordWebhookAttempt'
+type MockStorage_RecordWebhookAttempt_Call struct {
+	*mock.Call
+}
+
+// RecordWebhookAttempt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - attempt models.WebhookAttempt
+func (_e *MockStorage_Expecter) RecordWebhookAttempt(ctx interface{}, attempt interface{}) *MockStorage_RecordWebhookAttempt_Call {
+	return &MockStorage_RecordWebhookAttempt_Call{Call: _e.mock.On("RecordWebhookAttempt", ctx, attempt)}
+}
+
+func (_c *MockStorage_RecordWebhookAttempt_Call) Run(run func(ctx context.Context, attempt models.WebhookAttempt)) *MockStorage_RecordWebhookAttempt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.WebhookAttempt))
+	})
+	return _c
+}
+
+func (_c *MockStorage_RecordWebhookAttempt_Call) Return(_a0 error) *MockStorage_RecordWebhookAttempt_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_RecordWebhookAttempt_Call) RunAndReturn(run func(context.Context, models.WebhookAttempt) error) *MockStorage_RecordWebhookAttempt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RedeemCheckoutCode provides a mock function with given fields: ctx, merchantID, codeHash
+func (_m *MockStorage) RedeemCheckoutCode(ctx context.Context, merchantID int, codeHash string) (int, error) {
+	ret := _m.Called(ctx, merchantID, codeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedeemCheckoutCode")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (int, error)); ok {
+		return rf(ctx, merchantID, codeHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) int); ok {
+		r0 = rf(ctx, merchantID, codeHash)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, merchantID, codeHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_RedeemCheckoutCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RedeemCheckoutCode'
+type MockStorage_RedeemCheckoutCode_Call struct {
+	*mock.Call
+}
+
+// RedeemCheckoutCode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - merchantID int
+//   - codeHash string
+func (_e *MockStorage_Expecter) RedeemCheckoutCode(ctx interface{}, merchantID interface{}, codeHash interface{}) *MockStorage_RedeemCheckoutCode_Call {
+	return &MockStorage_RedeemCheckoutCode_Call{Call: _e.mock.On("RedeemCheckoutCode", ctx, merchantID, codeHash)}
+}
+
+func (_c *MockStorage_RedeemCheckoutCode_Call) Run(run func(ctx context.Context, merchantID int, codeHash string)) *MockStorage_RedeemCheckoutCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_RedeemCheckoutCode_Call) Return(_a0 int, _a1 error) *MockStorage_RedeemCheckoutCode_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_RedeemCheckoutCode_Call) RunAndReturn(run func(context.Context, int, string) (int, error)) *MockStorage_RedeemCheckoutCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RedeliverWebhook provides a mock function with given fields: ctx, merchantID, deliveryID
+func (_m *MockStorage) RedeliverWebhook(ctx context.Context, merchantID int, deliveryID int) error {
+	ret := _m.Called(ctx, merchantID, deliveryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedeliverWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, merchantID, deliveryID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_RedeliverWebhook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RedeliverWebhook'
+type MockStorage_RedeliverWebhook_Call struct {
+	*mock.Call
+}
+
+// RedeliverWebhook is a helper method to define mock.On call
+//   - ctx context.Context
+//   - merchantID int
+//   - deliveryID int
+func (_e *MockStorage_Expecter) RedeliverWebhook(ctx interface{}, merchantID interface{}, deliveryID interface{}) *MockStorage_RedeliverWebhook_Call {
+	return &MockStorage_RedeliverWebhook_Call{Call: _e.mock.On("RedeliverWebhook", ctx, merchantID, deliveryID)}
+}
+
+func (_c *MockStorage_RedeliverWebhook_Call) Run(run func(ctx context.Context, merchantID int, deliveryID int)) *MockStorage_RedeliverWebhook_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_RedeliverWebhook_Call) Return(_a0 error) *MockStorage_RedeliverWebhook_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_RedeliverWebhook_Call) RunAndReturn(run func(context.Context, int, int) error) *MockStorage_RedeliverWebhook_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RegisterMerchantNonce provides a mock function with given fields: ctx, merchantID, nonce, ttl
+func (_m *MockStorage) RegisterMerchantNonce(ctx context.Context, merchantID int, nonce string, ttl time.Duration) error {
+	ret := _m.Called(ctx, merchantID, nonce, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegisterMerchantNonce")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, time.Duration) error); ok {
+		r0 = rf(ctx, merchantID, nonce, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_RegisterMerchantNonce_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RegisterMerchantNonce'
+type MockStorage_RegisterMerchantNonce_Call struct {
+	*mock.Call
+}
+
+// RegisterMerchantNonce is a helper method to define mock.On call
+//   - ctx context.Context
+//   - merchantID int
+//   - nonce string
+//   - ttl time.Duration
+func (_e *MockStorage_Expecter) RegisterMerchantNonce(ctx interface{}, merchantID interface{}, nonce interface{}, ttl interface{}) *MockStorage_RegisterMerchantNonce_Call {
+	return &MockStorage_RegisterMerchantNonce_Call{Call: _e.mock.On("RegisterMerchantNonce", ctx, merchantID, nonce, ttl)}
+}
+
+func (_c *MockStorage_RegisterMerchantNonce_Call) Run(run func(ctx context.Context, merchantID int, nonce string, ttl time.Duration)) *MockStorage_RegisterMerchantNonce_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockStorage_RegisterMerchantNonce_Call) Return(_a0 error) *MockStorage_RegisterMerchantNonce_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_RegisterMerchantNonce_Call) RunAndReturn(run func(context.Context, int, string, time.Duration) error) *MockStorage_RegisterMerchantNonce_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReleaseCheckoutCode provides a mock function with given fields: ctx, merchantID, codeHash
+func (_m *MockStorage) ReleaseCheckoutCode(ctx context.Context, merchantID int, codeHash string) error {
+	ret := _m.Called(ctx, merchantID, codeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseCheckoutCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, merchantID, codeHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_ReleaseCheckoutCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseCheckoutCode'
+type MockStorage_ReleaseCheckoutCode_Call struct {
+	*mock.Call
+}
+
+// ReleaseCheckoutCode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - merchantID int
+//   - codeHash string
+func (_e *MockStorage_Expecter) ReleaseCheckoutCode(ctx interface{}, merchantID interface{}, codeHash interface{}) *MockStorage_ReleaseCheckoutCode_Call {
+	return &MockStorage_ReleaseCheckoutCode_Call{Call: _e.mock.On("ReleaseCheckoutCode", ctx, merchantID, codeHash)}
+}
+
+func (_c *MockStorage_ReleaseCheckoutCode_Call) Run(run func(ctx context.Context, merchantID int, codeHash string)) *MockStorage_ReleaseCheckoutCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_ReleaseCheckoutCode_Call) Return(_a0 error) *MockStorage_ReleaseCheckoutCode_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_ReleaseCheckoutCode_Call) RunAndReturn(run func(context.Context, int, string) error) *MockStorage_ReleaseCheckoutCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetPassword provides a mock function with given fields: ctx, tokenHash, passwordHash
+func (_m *MockStorage) ResetPassword(ctx context.Context, tokenHash string, passwordHash string) (int, error) {
+	ret := _m.Called(ctx, tokenHash, passwordHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (int, error)); ok {
+		return rf(ctx, tokenHash, passwordHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) int); ok {
+		r0 = rf(ctx, tokenHash, passwordHash)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, tokenHash, passwordHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_ResetPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetPassword'
+type MockStorage_ResetPassword_Call struct {
+	*mock.Call
+}
+
+// ResetPassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenHash string
+//   - passwordHash string
+func (_e *MockStorage_Expecter) ResetPassword(ctx interface{}, tokenHash interface{}, passwordHash interface{}) *MockStorage_ResetPassword_Call {
+	return &MockStorage_ResetPassword_Call{Call: _e.mock.On("ResetPassword", ctx, tokenHash, passwordHash)}
+}
+
+func (_c *MockStorage_ResetPassword_Call) Run(run func(ctx context.Context, tokenHash string, passwordHash string)) *MockStorage_ResetPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_ResetPassword_Call) Return(_a0 int, _a1 error) *MockStorage_ResetPassword_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_ResetPassword_Call) RunAndReturn(run func(context.Context, string, string) (int, error)) *MockStorage_ResetPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeMerchantAPIKey provides a mock function with given fields: ctx, adminID, merchantID, keyID
+func (_m *MockStorage) RevokeMerchantAPIKey(ctx context.Context, adminID int, merchantID int, keyID int) error {
+	ret := _m.Called(ctx, adminID, merchantID, keyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeMerchantAPIKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) error); ok {
+		r0 = rf(ctx, adminID, merchantID, keyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_RevokeMerchantAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeMerchantAPIKey'
+type MockStorage_RevokeMerchantAPIKey_Call struct {
+	*mock.Call
+}
+
+// RevokeMerchantAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - adminID int
+//   - merchantID int
+//   - keyID int
+func (_e *MockStorage_Expecter) RevokeMerchantAPIKey(ctx interface{}, adminID interface{}, merchantID interface{}, keyID interface{}) *MockStorage_RevokeMerchantAPIKey_Call {
+	return &MockStorage_RevokeMerchantAPIKey_Call{Call: _e.mock.On("RevokeMerchantAPIKey", ctx, adminID, merchantID, keyID)}
+}
+
+func (_c *MockStorage_RevokeMerchantAPIKey_Call) Run(run func(ctx context.Context, adminID int, merchantID int, keyID int)) *MockStorage_RevokeMerchantAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_RevokeMerchantAPIKey_Call) Return(_a0 error) *MockStorage_RevokeMerchantAPIKey_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_RevokeMerchantAPIKey_Call) RunAndReturn(run func(context.Context, int, int, int) error) *MockStorage_RevokeMerchantAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeOtherSessions provides a mock function with given fields: ctx, userID, keepSessionID
+func (_m *MockStorage) RevokeOtherSessions(ctx context.Context, userID int, keepSessionID int) error {
+	ret := _m.Called(ctx, userID, keepSessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeOtherSessions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, userID, keepSessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_RevokeOtherSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeOtherSessions'
+type MockStorage_RevokeOtherSessions_Call struct {
+	*mock.Call
+}
+
+// RevokeOtherSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - keepSessionID int
+func (_e *MockStorage_Expecter) RevokeOtherSessions(ctx interface{}, userID interface{}, keepSessionID interface{}) *MockStorage_RevokeOtherSessions_Call {
+	return &MockStorage_RevokeOtherSessions_Call{Call: _e.mock.On("RevokeOtherSessions", ctx, userID, keepSessionID)}
+}
+
+func (_c *MockStorage_RevokeOtherSessions_Call) Run(run func(ctx context.Context, userID int, keepSessionID int)) *MockStorage_RevokeOtherSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_RevokeOtherSessions_Call) Return(_a0 error) *MockStorage_RevokeOtherSessions_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_RevokeOtherSessions_Call) RunAndReturn(run func(context.Context, int, int) error) *MockStorage_RevokeOtherSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeSession provides a mock function with given fields: ctx, userID, sessionID
+func (_m *MockStorage) RevokeSession(ctx context.Context, userID int, sessionID int) error {
+	ret := _m.Called(ctx, userID, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, userID, sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_RevokeSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeSession'
+type MockStorage_RevokeSession_Call struct {
+	*mock.Call
+}
+
+// RevokeSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - sessionID int
+func (_e *MockStorage_Expecter) RevokeSession(ctx interface{}, userID interface{}, sessionID interface{}) *MockStorage_RevokeSession_Call {
+	return &MockStorage_RevokeSession_Call{Call: _e.mock.On("RevokeSession", ctx, userID, sessionID)}
+}
+
+func (_c *MockStorage_RevokeSession_Call) Run(run func(ctx context.Context, userID int, sessionID int)) *MockStorage_RevokeSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_RevokeSession_Call) Return(_a0 error) *MockStorage_RevokeSession_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_RevokeSession_Call) RunAndReturn(run func(context.Context, int, int) error) *MockStorage_RevokeSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeToken provides a mock function with given fields: ctx, jti, expiresAt
+func (_m *MockStorage) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	ret := _m.Called(ctx, jti, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, jti, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_RevokeToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeToken'
+type MockStorage_RevokeToken_Call struct {
+	*mock.Call
+}
+
+// RevokeToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jti string
+//   - expiresAt time.Time
+func (_e *MockStorage_Expecter) RevokeToken(ctx interface{}, jti interface{}, expiresAt interface{}) *MockStorage_RevokeToken_Call {
+	return &MockStorage_RevokeToken_Call{Call: _e.mock.On("RevokeToken", ctx, jti, expiresAt)}
+}
+
+func (_c *MockStorage_RevokeToken_Call) Run(run func(ctx context.Context, jti string, expiresAt time.Time)) *MockStorage_RevokeToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockStorage_RevokeToken_Call) Return(_a0 error) *MockStorage_RevokeToken_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_RevokeToken_Call) RunAndReturn(run func(context.Context, string, time.Time) error) *MockStorage_RevokeToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RotateRefreshToken provides a mock function with given fields: ctx, oldHash, newHash, ttl
+func (_m *MockStorage) RotateRefreshToken(ctx context.Context, oldHash string, newHash string, ttl time.Duration) (*models.User, error) {
+	ret := _m.Called(ctx, oldHash, newHash, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateRefreshToken")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) (*models.User, error)); ok {
+		return rf(ctx, oldHash, newHash, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) *models.User); ok {
+		r0 = rf(ctx, oldHash, newHash, ttl)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Duration) error); ok {
+		r1 = rf(ctx, oldHash, newHash, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_RotateRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RotateRefreshToken'
+type MockStorage_RotateRefreshToken_Call struct {
+	*mock.Call
+}
+
+// RotateRefreshToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - oldHash string
+//   - newHash string
+//   - ttl time.Duration
+func (_e *MockStorage_Expecter) RotateRefreshToken(ctx interface{}, oldHash interface{}, newHash interface{}, ttl interface{}) *MockStorage_RotateRefreshToken_Call {
+	return &MockStorage_RotateRefreshToken_Call{Call: _e.mock.On("RotateRefreshToken", ctx, oldHash, newHash, ttl)}
+}
+
+func (_c *MockStorage_RotateRefreshToken_Call) Run(run func(ctx context.Context, oldHash string, newHash string, ttl time.Duration)) *MockStorage_RotateRefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockStorage_RotateRefreshToken_Call) Return(_a0 *models.User, _a1 error) *MockStorage_RotateRefreshToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_RotateRefreshToken_Call) RunAndReturn(run func(context.Context, string, string, time.Duration) (*models.User, error)) *MockStorage_RotateRefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveNotificationPreferences provides a mock function with given fields: ctx, userID, prefs, tokenHash, ttl
+func (_m *MockStorage) SaveNotificationPreferences(ctx context.Context, userID int, prefs models.NotificationPreferences, tokenHash string, ttl time.Duration) error {
+	ret := _m.Called(ctx, userID, prefs, tokenHash, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveNotificationPreferences")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.NotificationPreferences, string, time.Duration) error); ok {
+		r0 = rf(ctx, userID, prefs, tokenHash, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_SaveNotificationPreferences_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveNotificationPreferences'
+type MockStorage_SaveNotificationPreferences_Call struct {
+	*mock.Call
+}
+
+// SaveNotificationPreferences is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - prefs models.NotificationPreferences
+//   - tokenHash string
+//   - ttl time.Duration
+func (_e *MockStorage_Expecter) SaveNotificationPreferences(ctx interface{}, userID interface{}, prefs interface{}, tokenHash interface{}, ttl interface{}) *MockStorage_SaveNotificationPreferences_Call {
+	return &MockStorage_SaveNotificationPreferences_Call{Call: _e.mock.On("SaveNotificationPreferences", ctx, userID, prefs, tokenHash, ttl)}
+}
+
+func (_c *MockStorage_SaveNotificationPreferences_Call) Run(run func(ctx context.Context, userID int, prefs models.NotificationPreferences, tokenHash string, ttl time.Duration)) *MockStorage_SaveNotificationPreferences_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.NotificationPreferences), args[3].(string), args[4].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockStorage_SaveNotificationPreferences_Call) Return(_a0 error) *MockStorage_SaveNotificationPreferences_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_SaveNotificationPreferences_Call) RunAndReturn(run func(context.Context, int, models.NotificationPreferences, string, time.Duration) error) *MockStorage_SaveNotificationPreferences_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveOrder provides a mock function with given fields: ctx, user, order
+func (_m *MockStorage) SaveOrder(ctx context.Context, user int, order *models.Order) error {
+	ret := _m.Called(ctx, user, order)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveOrder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Order) error); ok {
+		r0 = rf(ctx, user, order)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_SaveOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveOrder'
+type MockStorage_SaveOrder_Call struct {
+	*mock.Call
+}
+
+// SaveOrder is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user int
+//   - order *models.Order
+func (_e *MockStorage_Expecter) SaveOrder(ctx interface{}, user interface{}, order interface{}) *MockStorage_SaveOrder_Call {
+	return &MockStorage_SaveOrder_Call{Call: _e.mock.On("SaveOrder", ctx, user, order)}
+}
+
+func (_c *MockStorage_SaveOrder_Call) Run(run func(ctx context.Context, user int, order *models.Order)) *MockStorage_SaveOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.Order))
+	})
+	return _c
+}
+
+func (_c *MockStorage_SaveOrder_Call) Return(_a0 error) *MockStorage_SaveOrder_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_SaveOrder_Call) RunAndReturn(run func(context.Context, int, *models.Order) error) *MockStorage_SaveOrder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveOrderWithItems provides a mock function with given fields: ctx, user, order, items
+func (_m *MockStorage) SaveOrderWithItems(ctx context.Context, user int, order *models.Order, items []models.OrderItem) error {
+	ret := _m.Called(ctx, user, order, items)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveOrderWithItems")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Order, []models.OrderItem) error); ok {
+		r0 = rf(ctx, user, order, items)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_SaveOrderWithItems_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveOrderWithItems'
+type MockStorage_SaveOrderWithItems_Call struct {
+	*mock.Call
+}
+
+// SaveOrderWithItems is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user int
+//   - order *models.Order
+//   - items []models.OrderItem
+func (_e *MockStorage_Expecter) SaveOrderWithItems(ctx interface{}, user interface{}, order interface{}, items interface{}) *MockStorage_SaveOrderWithItems_Call {
+	return &MockStorage_SaveOrderWithItems_Call{Call: _e.mock.On("SaveOrderWithItems", ctx, user, order, items)}
+}
+
+func (_c *MockStorage_SaveOrderWithItems_Call) Run(run func(ctx context.Context, user int, order *models.Order, items []models.OrderItem)) *MockStorage_SaveOrderWithItems_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.Order), args[3].([]models.OrderItem))
+	})
+	return _c
+}
+
+func (_c *MockStorage_SaveOrderWithItems_Call) Return(_a0 error) *MockStorage_SaveOrderWithItems_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_SaveOrderWithItems_Call) RunAndReturn(run func(context.Context, int, *models.Order, []models.OrderItem) error) *MockStorage_SaveOrderWithItems_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveOrders provides a mock function with given fields: ctx, userID, numbers
+func (_m *MockStorage) SaveOrders(ctx context.Context, userID int, numbers []string) ([]string, error) {
+	ret := _m.Called(ctx, userID, numbers)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveOrders")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []string) ([]string, error)); ok {
+		return rf(ctx, userID, numbers)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, []string) []string); ok {
+		r0 = rf(ctx, userID, numbers)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, []string) error); ok {
+		r1 = rf(ctx, userID, numbers)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_SaveOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveOrders'
+type MockStorage_SaveOrders_Call struct {
+	*mock.Call
+}
+
+// SaveOrders is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - numbers []string
+func (_e *MockStorage_Expecter) SaveOrders(ctx interface{}, userID interface{}, numbers interface{}) *MockStorage_SaveOrders_Call {
+	return &MockStorage_SaveOrders_Call{Call: _e.mock.On("SaveOrders", ctx, userID, numbers)}
+}
+
+func (_c *MockStorage_SaveOrders_Call) Run(run func(ctx context.Context, userID int, numbers []string)) *MockStorage_SaveOrders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_SaveOrders_Call) Return(_a0 []string, _a1 error) *MockStorage_SaveOrders_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_SaveOrders_Call) RunAndReturn(run func(context.Context, int, []string) ([]string, error)) *MockStorage_SaveOrders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveTOTPSecret provides a mock function with given fields: ctx, userID, secret
+func (_m *MockStorage) SaveTOTPSecret(ctx context.Context, userID int, secret string) error {
+	ret := _m.Called(ctx, userID, secret)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveTOTPSecret")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, userID, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_SaveTOTPSecret_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveTOTPSecret'
+type MockStorage_SaveTOTPSecret_Call struct {
+	*mock.Call
+}
+
+// SaveTOTPSecret is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - secret string
+func (_e *MockStorage_Expecter) SaveTOTPSecret(ctx interface{}, userID interface{}, secret interface{}) *MockStorage_SaveTOTPSecret_Call {
+	return &MockStorage_SaveTOTPSecret_Call{Call: _e.mock.On("SaveTOTPSecret", ctx, userID, secret)}
+}
+
+func (_c *MockStorage_SaveTOTPSecret_Call) Run(run func(ctx context.Context, userID int, secret string)) *MockStorage_SaveTOTPSecret_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_SaveTOTPSecret_Call) Return(_a0 error) *MockStorage_SaveTOTPSecret_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_SaveTOTPSecret_Call) RunAndReturn(run func(context.Context, int, string) error) *MockStorage_SaveTOTPSecret_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SearchUsers provides a mock function with given fields: ctx, query, limit
+func (_m *MockStorage) SearchUsers(ctx context.Context, query string, limit int) ([]models.AdminUser, error) {
+	ret := _m.Called(ctx, query, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchUsers")
+	}
+
+	var r0 []models.AdminUser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]models.AdminUser, error)); ok {
+		return rf(ctx, query, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []models.AdminUser); ok {
+		r0 = rf(ctx, query, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AdminUser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, query, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_SearchUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SearchUsers'
+type MockStorage_SearchUsers_Call struct {
+	*mock.Call
+}
+
+// SearchUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query string
+//   - limit int
+func (_e *MockStorage_Expecter) SearchUsers(ctx interface{}, query interface{}, limit interface{}) *MockStorage_SearchUsers_Call {
+	return &MockStorage_SearchUsers_Call{Call: _e.mock.On("SearchUsers", ctx, query, limit)}
+}
+
+func (_c *MockStorage_SearchUsers_Call) Run(run func(ctx context.Context, query string, limit int)) *MockStorage_SearchUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_SearchUsers_Call) Return(_a0 []models.AdminUser, _a1 error) *MockStorage_SearchUsers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_SearchUsers_Call) RunAndReturn(run func(context.Context, string, int) ([]models.AdminUser, error)) *MockStorage_SearchUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetUserAccess provides a mock function with given fields: ctx, adminID, userID, access
+func (_m *MockStorage) SetUserAccess(ctx context.Context, adminID int, userID int, access models.UserAccess) error {
+	ret := _m.Called(ctx, adminID, userID, access)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserAccess")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, models.UserAccess) error); ok {
+		r0 = rf(ctx, adminID, userID, access)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_SetUserAccess_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetUserAccess'
+type MockStorage_SetUserAccess_Call struct {
+	*mock.Call
+}
+
+// SetUserAccess is a helper method to define mock.On call
+//   - ctx context.Context
+//   - adminID int
+//   - userID int
+//   - access models.UserAccess
+func (_e *MockStorage_Expecter) SetUserAccess(ctx interface{}, adminID interface{}, userID interface{}, access interface{}) *MockStorage_SetUserAccess_Call {
+	return &MockStorage_SetUserAccess_Call{Call: _e.mock.On("SetUserAccess", ctx, adminID, userID, access)}
+}
+
+func (_c *MockStorage_SetUserAccess_Call) Run(run func(ctx context.Context, adminID int, userID int, access models.UserAccess)) *MockStorage_SetUserAccess_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(models.UserAccess))
+	})
+	return _c
+}
+
+func (_c *MockStorage_SetUserAccess_Call) Return(_a0 error) *MockStorage_SetUserAccess_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_SetUserAccess_Call) RunAndReturn(run func(context.Context, int, int, models.UserAccess) error) *MockStorage_SetUserAccess_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetUserBlocked provides a mock function with given fields: ctx, adminID, userID, blocked, reason
+func (_m *MockStorage) SetUserBlocked(ctx context.Context, adminID int, userID int, blocked bool, reason string) error {
+	ret := _m.Called(ctx, adminID, userID, blocked, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserBlocked")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, bool, string) error); ok {
+		r0 = rf(ctx, adminID, userID, blocked, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_SetUserBlocked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetUserBlocked'
+type MockStorage_SetUserBlocked_Call struct {
+	*mock.Call
+}
+
+// SetUserBlocked is a helper method to define mock.On call
+//   - ctx context.Context
+//   - adminID int
+//   - userID int
+//   - blocked bool
+//   - reason string
+func (_e *MockStorage_Expecter) SetUserBlocked(ctx interface{}, adminID interface{}, userID interface{}, blocked interface{}, reason interface{}) *MockStorage_SetUserBlocked_Call {
+	return &MockStorage_SetUserBlocked_Call{Call: _e.mock.On("SetUserBlocked", ctx, adminID, userID, blocked, reason)}
+}
+
+func (_c *MockStorage_SetUserBlocked_Call) Run(run func(ctx context.Context, adminID int, userID int, blocked bool, reason string)) *MockStorage_SetUserBlocked_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(bool), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_SetUserBlocked_Call) Return(_a0 error) *MockStorage_SetUserBlocked_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_SetUserBlocked_Call) RunAndReturn(run func(context.Context, int, int, bool, string) error) *MockStorage_SetUserBlocked_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetWithdrawThreshold provides a mock function with given fields: ctx, userID, threshold
+func (_m *MockStorage) SetWithdrawThreshold(ctx context.Context, userID int, threshold float64) error {
+	ret := _m.Called(ctx, userID, threshold)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetWithdrawThreshold")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, float64) error); ok {
+		r0 = rf(ctx, userID, threshold)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_SetWithdrawThreshold_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetWithdrawThreshold'
+type MockStorage_SetWithdrawThreshold_Call struct {
+	*mock.Call
+}
+
+// SetWithdrawThreshold is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - threshold float64
+func (_e *MockStorage_Expecter) SetWithdrawThreshold(ctx interface{}, userID interface{}, threshold interface{}) *MockStorage_SetWithdrawThreshold_Call {
+	return &MockStorage_SetWithdrawThreshold_Call{Call: _e.mock.On("SetWithdrawThreshold", ctx, userID, threshold)}
+}
+
+func (_c *MockStorage_SetWithdrawThreshold_Call) Run(run func(ctx context.Context, userID int, threshold float64)) *MockStorage_SetWithdrawThreshold_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(float64))
+	})
+	return _c
+}
+
+func (_c *MockStorage_SetWithdrawThreshold_Call) Return(_a0 error) *MockStorage_SetWithdrawThreshold_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_SetWithdrawThreshold_Call) RunAndReturn(run func(context.Context, int, float64) error) *MockStorage_SetWithdrawThreshold_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StartIngestionRun provides a mock function with given fields: ctx, fileName, checksum
+func (_m *MockStorage) StartIngestionRun(ctx context.Context, fileName string, checksum string) (int, error) {
+	ret := _m.Called(ctx, fileName, checksum)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartIngestionRun")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (int, error)); ok {
+		return rf(ctx, fileName, checksum)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) int); ok {
+		r0 = rf(ctx, fileName, checksum)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, fileName, checksum)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_StartIngestionRun_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartIngestionRun'
+type MockStorage_StartIngestionRun_Call struct {
+	*mock.Call
+}
+
+// StartIngestionRun is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fileName string
+//   - checksum string
+func (_e *MockStorage_Expecter) StartIngestionRun(ctx interface{}, fileName interface{}, checksum interface{}) *MockStorage_StartIngestionRun_Call {
+	return &MockStorage_StartIngestionRun_Call{Call: _e.mock.On("StartIngestionRun", ctx, fileName, checksum)}
+}
+
+func (_c *MockStorage_StartIngestionRun_Call) Run(run func(ctx context.Context, fileName string, checksum string)) *MockStorage_StartIngestionRun_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_StartIngestionRun_Call) Return(_a0 int, _a1 error) *MockStorage_StartIngestionRun_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_StartIngestionRun_Call) RunAndReturn(run func(context.Context, string, string) (int, error)) *MockStorage_StartIngestionRun_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StartReconciliationRun provides a mock function with given fields: ctx, run
+func (_m *MockStorage) StartReconciliationRun(ctx context.Context, run *models.ReconciliationRun) error {
+	ret := _m.Called(ctx, run)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartReconciliationRun")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ReconciliationRun) error); ok {
+		r0 = rf(ctx, run)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_StartReconciliationRun_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartReconciliationRun'
+type MockStorage_StartReconciliationRun_Call struct {
+	*mock.Call
+}
+
+// StartReconciliationRun is a helper method to define mock.On call
+//   - ctx context.Context
+//   - run *models.ReconciliationRun
+func (_e *MockStorage_Expecter) StartReconciliationRun(ctx interface{}, run interface{}) *MockStorage_StartReconciliationRun_Call {
+	return &MockStorage_StartReconciliationRun_Call{Call: _e.mock.On("StartReconciliationRun", ctx, run)}
+}
+
+func (_c *MockStorage_StartReconciliationRun_Call) Run(run func(ctx context.Context, run *models.ReconciliationRun)) *MockStorage_StartReconciliationRun_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.ReconciliationRun))
+	})
+	return _c
+}
+
+func (_c *MockStorage_StartReconciliationRun_Call) Return(_a0 error) *MockStorage_StartReconciliationRun_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_StartReconciliationRun_Call) RunAndReturn(run func(context.Context, *models.ReconciliationRun) error) *MockStorage_StartReconciliationRun_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StreamStatement provides a mock function with given fields: ctx, userID, from, to, fn
+func (_m *MockStorage) StreamStatement(ctx context.Context, userID int, from time.Time, to time.Time, fn func(models.StatementEntry) error) error {
+	ret := _m.Called(ctx, userID, from, to, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamStatement")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, time.Time, func(models.StatementEntry) error) error); ok {
+		r0 = rf(ctx, userID, from, to, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_StreamStatement_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StreamStatement'
+type MockStorage_StreamStatement_Call struct {
+	*mock.Call
+}
+
+// StreamStatement is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - from time.Time
+//   - to time.Time
+//   - fn func(models.StatementEntry) error
+func (_e *MockStorage_Expecter) StreamStatement(ctx interface{}, userID interface{}, from interface{}, to interface{}, fn interface{}) *MockStorage_StreamStatement_Call {
+	return &MockStorage_StreamStatement_Call{Call: _e.mock.On("StreamStatement", ctx, userID, from, to, fn)}
+}
+
+func (_c *MockStorage_StreamStatement_Call) Run(run func(ctx context.Context, userID int, from time.Time, to time.Time, fn func(models.StatementEntry) error)) *MockStorage_StreamStatement_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time), args[3].(time.Time), args[4].(func(models.StatementEntry) error))
+	})
+	return _c
+}
+
+func (_c *MockStorage_StreamStatement_Call) Return(_a0 error) *MockStorage_StreamStatement_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_StreamStatement_Call) RunAndReturn(run func(context.Context, int, time.Time, time.Time, func(models.StatementEntry) error) error) *MockStorage_StreamStatement_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Transfer provides a mock function with given fields: ctx, senderID, recipientLogin, sum, limits
+func (_m *MockStorage) Transfer(ctx context.Context, senderID int, recipientLogin string, sum float64, limits models.TransferLimits) error {
+	ret := _m.Called(ctx, senderID, recipientLogin, sum, limits)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Transfer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, float64, models.TransferLimits) error); ok {
+		r0 = rf(ctx, senderID, recipientLogin, sum, limits)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_Transfer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Transfer'
+type MockStorage_Transfer_Call struct {
+	*mock.Call
+}
+
+// Transfer is a helper method to define mock.On call
+//   - ctx context.Context
+//   - senderID int
+//   - recipientLogin string
+//   - sum float64
+//   - limits models.TransferLimits
+func (_e *MockStorage_Expecter) Transfer(ctx interface{}, senderID interface{}, recipientLogin interface{}, sum interface{}, limits interface{}) *MockStorage_Transfer_Call {
+	return &MockStorage_Transfer_Call{Call: _e.mock.On("Transfer", ctx, senderID, recipientLogin, sum, limits)}
+}
+
+func (_c *MockStorage_Transfer_Call) Run(run func(ctx context.Context, senderID int, recipientLogin string, sum float64, limits models.TransferLimits)) *MockStorage_Transfer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(float64), args[4].(models.TransferLimits))
+	})
+	return _c
+}
+
+func (_c *MockStorage_Transfer_Call) Return(_a0 error) *MockStorage_Transfer_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_Transfer_Call) RunAndReturn(run func(context.Context, int, string, float64, models.TransferLimits) error) *MockStorage_Transfer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UnlockLogin provides a mock function with given fields: ctx, adminID, userID
+func (_m *MockStorage) UnlockLogin(ctx context.Context, adminID int, userID int) error {
+	ret := _m.Called(ctx, adminID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnlockLogin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, adminID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_UnlockLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnlockLogin'
+type MockStorage_UnlockLogin_Call struct {
+	*mock.Call
+}
+
+// UnlockLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - adminID int
+//   - userID int
+func (_e *MockStorage_Expecter) UnlockLogin(ctx interface{}, adminID interface{}, userID interface{}) *MockStorage_UnlockLogin_Call {
+	return &MockStorage_UnlockLogin_Call{Call: _e.mock.On("UnlockLogin", ctx, adminID, userID)}
+}
+
+func (_c *MockStorage_UnlockLogin_Call) Run(run func(ctx context.Context, adminID int, userID int)) *MockStorage_UnlockLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockStorage_UnlockLogin_Call) Return(_a0 error) *MockStorage_UnlockLogin_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_UnlockLogin_Call) RunAndReturn(run func(context.Context, int, int) error) *MockStorage_UnlockLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateCampaign provides a mock function with given fields: ctx, c
+func (_m *MockStorage) UpdateCampaign(ctx context.Context, c *models.Campaign) error {
+	ret := _m.Called(ctx, c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCampaign")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Campaign) error); ok {
+		r0 = rf(ctx, c)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_UpdateCampaign_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateCampaign'
+type MockStorage_UpdateCampaign_Call struct {
+	*mock.Call
+}
+
+// UpdateCampaign is a helper method to define mock.On call
+//   - ctx context.Context
+//   - c *models.Campaign
+func (_e *MockStorage_Expecter) UpdateCampaign(ctx interface{}, c interface{}) *MockStorage_UpdateCampaign_Call {
+	return &MockStorage_UpdateCampaign_Call{Call: _e.mock.On("UpdateCampaign", ctx, c)}
+}
+
+func (_c *MockStorage_UpdateCampaign_Call) Run(run func(ctx context.Context, c *models.Campaign)) *MockStorage_UpdateCampaign_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Campaign))
+	})
+	return _c
+}
+
+func (_c *MockStorage_UpdateCampaign_Call) Return(_a0 error) *MockStorage_UpdateCampaign_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_UpdateCampaign_Call) RunAndReturn(run func(context.Context, *models.Campaign) error) *MockStorage_UpdateCampaign_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateOrder provides a mock function with given fields: ctx, accrual, policy
+func (_m *MockStorage) UpdateOrder(ctx context.Context, accrual *models.AccrualResponse, policy models.CreditPolicy) error {
+	ret := _m.Called(ctx, accrual, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateOrder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.AccrualResponse, models.CreditPolicy) error); ok {
+		r0 = rf(ctx, accrual, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_UpdateOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateOrder'
+type MockStorage_UpdateOrder_Call struct {
+	*mock.Call
+}
+
+// UpdateOrder is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accrual *models.AccrualResponse
+//   - policy models.CreditPolicy
+func (_e *MockStorage_Expecter) UpdateOrder(ctx interface{}, accrual interface{}, policy interface{}) *MockStorage_UpdateOrder_Call {
+	return &MockStorage_UpdateOrder_Call{Call: _e.mock.On("UpdateOrder", ctx, accrual, policy)}
+}
+
+func (_c *MockStorage_UpdateOrder_Call) Run(run func(ctx context.Context, accrual *models.AccrualResponse, policy models.CreditPolicy)) *MockStorage_UpdateOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.AccrualResponse), args[2].(models.CreditPolicy))
+	})
+	return _c
+}
+
+func (_c *MockStorage_UpdateOrder_Call) Return(_a0 error) *MockStorage_UpdateOrder_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_UpdateOrder_Call) RunAndReturn(run func(context.Context, *models.AccrualResponse, models.CreditPolicy) error) *MockStorage_UpdateOrder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdatePasswordHash provides a mock function with given fields: ctx, userID, hash
+func (_m *MockStorage) UpdatePasswordHash(ctx context.Context, userID int, hash string) error {
+	ret := _m.Called(ctx, userID, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePasswordHash")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, userID, hash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_UpdatePasswordHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePasswordHash'
+type MockStorage_UpdatePasswordHash_Call struct {
+	*mock.Call
+}
+
+// UpdatePasswordHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - hash string
+func (_e *MockStorage_Expecter) UpdatePasswordHash(ctx interface{}, userID interface{}, hash interface{}) *MockStorage_UpdatePasswordHash_Call {
+	return &MockStorage_UpdatePasswordHash_Call{Call: _e.mock.On("UpdatePasswordHash", ctx, userID, hash)}
+}
+
+func (_c *MockStorage_UpdatePasswordHash_Call) Run(run func(ctx context.Context, userID int, hash string)) *MockStorage_UpdatePasswordHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_UpdatePasswordHash_Call) Return(_a0 error) *MockStorage_UpdatePasswordHash_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_UpdatePasswordHash_Call) RunAndReturn(run func(context.Context, int, string) error) *MockStorage_UpdatePasswordHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseRecoveryCode provides a mock function with given fields: ctx, userID, codeHash
+func (_m *MockStorage) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	ret := _m.Called(ctx, userID, codeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (bool, error)); ok {
+		return rf(ctx, userID, codeHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) bool); ok {
+		r0 = rf(ctx, userID, codeHash)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, userID, codeHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_UseRecoveryCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseRecoveryCode'
+type MockStorage_UseRecoveryCode_Call struct {
+	*mock.Call
+}
+
+// UseRecoveryCode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - codeHash string
+func (_e *MockStorage_Expecter) UseRecoveryCode(ctx interface{}, userID interface{}, codeHash interface{}) *MockStorage_UseRecoveryCode_Call {
+	return &MockStorage_UseRecoveryCode_Call{Call: _e.mock.On("UseRecoveryCode", ctx, userID, codeHash)}
+}
+
+func (_c *MockStorage_UseRecoveryCode_Call) Run(run func(ctx context.Context, userID int, codeHash string)) *MockStorage_UseRecoveryCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_UseRecoveryCode_Call) Return(_a0 bool, _a1 error) *MockStorage_UseRecoveryCode_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_UseRecoveryCode_Call) RunAndReturn(run func(context.Context, int, string) (bool, error)) *MockStorage_UseRecoveryCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseTOTPStep provides a mock function with given fields: ctx, userID, step
+func (_m *MockStorage) UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error) {
+	ret := _m.Called(ctx, userID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseTOTPStep")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int64) (bool, error)); ok {
+		return rf(ctx, userID, step)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int64) bool); ok {
+		r0 = rf(ctx, userID, step)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int64) error); ok {
+		r1 = rf(ctx, userID, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_UseTOTPStep_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseTOTPStep'
+type MockStorage_UseTOTPStep_Call struct {
+	*mock.Call
+}
+
+// UseTOTPStep is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - step int64
+func (_e *MockStorage_Expecter) UseTOTPStep(ctx interface{}, userID interface{}, step interface{}) *MockStorage_UseTOTPStep_Call {
+	return &MockStorage_UseTOTPStep_Call{Call: _e.mock.On("UseTOTPStep", ctx, userID, step)}
+}
+
+func (_c *MockStorage_UseTOTPStep_Call) Run(run func(ctx context.Context, userID int, step int64)) *MockStorage_UseTOTPStep_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MockStorage_UseTOTPStep_Call) Return(_a0 bool, _a1 error) *MockStorage_UseTOTPStep_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_UseTOTPStep_Call) RunAndReturn(run func(context.Context, int, int64) (bool, error)) *MockStorage_UseTOTPStep_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Withdraw provides a mock function with given fields: ctx, userID, order, sum
+func (_m *MockStorage) Withdraw(ctx context.Context, userID int, order string, sum float64) error {
+	ret := _m.Called(ctx, userID, order, sum)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Withdraw")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, float64) error); ok {
+		r0 = rf(ctx, userID, order, sum)
+	} else {
 		r0 = ret.Error(0)
 	}
 
@@ -562,6 +5423,56 @@ func (_c *MockStorage_Withdraw_Call) RunAndReturn(run func(context.Context, int,
 	return _c
 }
 
+// WithdrawAtMerchant provides a mock function with given fields: ctx, merchantID, userID, order, sum
+func (_m *MockStorage) WithdrawAtMerchant(ctx context.Context, merchantID int, userID int, order string, sum float64) error {
+	ret := _m.Called(ctx, merchantID, userID, order, sum)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithdrawAtMerchant")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string, float64) error); ok {
+		r0 = rf(ctx, merchantID, userID, order, sum)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_WithdrawAtMerchant_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithdrawAtMerchant'
+type MockStorage_WithdrawAtMerchant_Call struct {
+	*mock.Call
+}
+
+// WithdrawAtMerchant is a helper method to define mock.On call
+//   - ctx context.Context
+//   - merchantID int
+//   - userID int
+//   - order string
+//   - sum float64
+func (_e *MockStorage_Expecter) WithdrawAtMerchant(ctx interface{}, merchantID interface{}, userID interface{}, order interface{}, sum interface{}) *MockStorage_WithdrawAtMerchant_Call {
+	return &MockStorage_WithdrawAtMerchant_Call{Call: _e.mock.On("WithdrawAtMerchant", ctx, merchantID, userID, order, sum)}
+}
+
+func (_c *MockStorage_WithdrawAtMerchant_Call) Run(run func(ctx context.Context, merchantID int, userID int, order string, sum float64)) *MockStorage_WithdrawAtMerchant_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(string), args[4].(float64))
+	})
+	return _c
+}
+
+func (_c *MockStorage_WithdrawAtMerchant_Call) Return(_a0 error) *MockStorage_WithdrawAtMerchant_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_WithdrawAtMerchant_Call) RunAndReturn(run func(context.Context, int, int, string, float64) error) *MockStorage_WithdrawAtMerchant_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockStorage creates a new instance of MockStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockStorage(t interface {
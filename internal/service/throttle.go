@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// ThrottledError возвращается, когда попытка отклонена защитой от перебора;
+// Wait — сколько осталось ждать.
+type ThrottledError struct {
+	Wait time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("слишком много попыток, повторите через %s", e.Wait)
+}
+
+// CheckLoginThrottle возвращает, сколько ещё нужно подождать перед
+// следующей попыткой входа с этим логином или с этого IP.
+func (s *AccrualService) CheckLoginThrottle(ctx context.Context, login, ip string) (time.Duration, error) {
+	throttles, err := s.db.GetLoginThrottles(ctx, login, ip)
+	if err != nil {
+		return 0, err
+	}
+	var wait time.Duration
+	now := time.Now()
+	for _, t := range throttles {
+		wait = max(wait, throttleWait(t, s.cfg.Login, now))
+	}
+	return wait, nil
+}
+
+// RecordLoginAttempt учитывает результат проверки пароля. Ошибки, не
+// связанные с учётными данными, попыткой не считаются.
+func (s *AccrualService) RecordLoginAttempt(ctx context.Context, login, ip string, authErr error) error {
+	var reason string
+	switch {
+	case authErr == nil:
+		return s.db.RecordLoginSuccess(ctx, login, ip)
+	case errors.Is(authErr, models.ErrInvalidCredentials):
+		reason = models.LoginReasonInvalidPassword
+	case errors.Is(authErr, models.ErrUserNotFound):
+		reason = models.LoginReasonUnknownLogin
+	case errors.Is(authErr, models.ErrUserBlocked):
+		reason = models.LoginReasonBlocked
+	default:
+		return nil
+	}
+	logger.Log.Warn("Неудачная попытка входа", zap.String("login", login), zap.String("ip", ip), zap.String("reason", reason))
+	return s.db.RecordLoginFailure(ctx, login, ip, reason, s.cfg.Login)
+}
+
+func (s *AccrualService) UnlockLogin(ctx context.Context, adminID, userID int) error {
+	return s.db.UnlockLogin(ctx, adminID, userID)
+}
+
+func throttleWait(t models.LoginThrottle, policy models.LoginPolicy, now time.Time) time.Duration {
+	var wait time.Duration
+	if t.LockedUntil.After(now) {
+		wait = t.LockedUntil.Sub(now)
+	}
+	if t.Failures == 0 || policy.BaseDelay <= 0 || (policy.Window > 0 && now.Sub(t.LastFailureAt) > policy.Window) {
+		return wait
+	}
+	delay := policy.MaxDelay
+	if shift := t.Failures - 1; shift < 32 {
+		delay = min(policy.BaseDelay<<shift, policy.MaxDelay)
+	}
+	if until := t.LastFailureAt.Add(delay); until.After(now) {
+		wait = max(wait, until.Sub(now))
+	}
+	return wait
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestThrottleWait(t *testing.T) {
+	now := time.Date(2025, 5, 31, 12, 0, 0, 0, time.UTC)
+	policy := models.LoginPolicy{BaseDelay: time.Second, MaxDelay: time.Minute, Window: time.Hour}
+
+	tests := []struct {
+		name     string
+		throttle models.LoginThrottle
+		want     time.Duration
+	}{
+		{
+			name:     "первая неудача",
+			throttle: models.LoginThrottle{Failures: 1, LastFailureAt: now},
+			want:     time.Second,
+		},
+		{
+			name:     "задержка растёт экспоненциально",
+			throttle: models.LoginThrottle{Failures: 4, LastFailureAt: now.Add(-3 * time.Second)},
+			want:     5 * time.Second,
+		},
+		{
+			name:     "задержка ограничена сверху",
+			throttle: models.LoginThrottle{Failures: 40, LastFailureAt: now},
+			want:     time.Minute,
+		},
+		{
+			name:     "задержка уже прошла",
+			throttle: models.LoginThrottle{Failures: 2, LastFailureAt: now.Add(-5 * time.Second)},
+			want:     0,
+		},
+		{
+			name:     "блокировка дольше задержки",
+			throttle: models.LoginThrottle{Failures: 5, LastFailureAt: now, LockedUntil: now.Add(15 * time.Minute)},
+			want:     15 * time.Minute,
+		},
+		{
+			name:     "старые неудачи не учитываются",
+			throttle: models.LoginThrottle{Failures: 30, LastFailureAt: now.Add(-2 * time.Hour)},
+			want:     0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, throttleWait(tt.throttle, policy, now))
+		})
+	}
+
+	t.Run("без окна неудачи не забываются", func(t *testing.T) {
+		noWindow := models.LoginPolicy{BaseDelay: time.Second, MaxDelay: time.Minute}
+		throttle := models.LoginThrottle{Failures: 30, LastFailureAt: now.Add(-30 * time.Second)}
+		require.Equal(t, 30*time.Second, throttleWait(throttle, noWindow, now))
+	})
+}
+func TestCheckLoginThrottle(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB, cfg: Config{Login: models.LoginPolicy{
+		BaseDelay: time.Second, MaxDelay: time.Minute, Window: time.Hour,
+	}}}
+
+	mockDB.EXPECT().GetLoginThrottles(mock.Anything, "alice", "10.0.0.1").Return([]models.LoginThrottle{
+		{Scope: models.ThrottleScopeLogin, Key: "alice", Failures: 1, LastFailureAt: time.Now().Add(-time.Hour)},
+		{Scope: models.ThrottleScopeIP, Key: "10.0.0.1", LockedUntil: time.Now().Add(10 * time.Minute)},
+	}, nil).Once()
+
+	wait, err := service.CheckLoginThrottle(context.Background(), "alice", "10.0.0.1")
+	require.NoError(t, err)
+	require.InDelta(t, (10 * time.Minute).Seconds(), wait.Seconds(), 1)
+}
+func TestRecordLoginAttempt(t *testing.T) {
+	policy := models.LoginPolicy{MaxFailures: 5}
+
+	t.Run("успешный вход", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB, cfg: Config{Login: policy}}
+
+		mockDB.EXPECT().RecordLoginSuccess(mock.Anything, "alice", "10.0.0.1").Return(nil).Once()
+		require.NoError(t, service.RecordLoginAttempt(context.Background(), "alice", "10.0.0.1", nil))
+	})
+
+	t.Run("неверный пароль", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB, cfg: Config{Login: policy}}
+
+		mockDB.EXPECT().RecordLoginFailure(mock.Anything, "alice", "10.0.0.1", models.LoginReasonInvalidPassword, policy).
+			Return(nil).Once()
+		require.NoError(t, service.RecordLoginAttempt(context.Background(), "alice", "10.0.0.1", models.ErrInvalidCredentials))
+	})
+
+	t.Run("ошибка БД не считается попыткой", func(t *testing.T) {
+		service := &AccrualService{db: NewMockStorage(t)}
+		require.NoError(t, service.RecordLoginAttempt(context.Background(), "alice", "10.0.0.1", errors.New("db failure")))
+	})
+}
+func TestAuthorizeUnknownUser(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB}
+
+	mockDB.EXPECT().GetUserByLogin(mock.Anything, "ghost").Return(nil, nil).Once()
+
+	err := service.AuthorizeUser(context.Background(), &models.User{Login: "ghost", Password: "secret"})
+	require.ErrorIs(t, err, models.ErrUserNotFound)
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func (s *AccrualService) GetUserTier(ctx context.Context, userID int) (models.TierStatus, error) {
+	tiers, err := s.db.GetTiers(ctx)
+	if err != nil {
+		return models.TierStatus{}, err
+	}
+	accrued, err := s.db.GetRollingAccrual(ctx, userID, s.cfg.TierWindowMonths)
+	if err != nil {
+		return models.TierStatus{}, err
+	}
+	return tierStatus(tiers, accrued), nil
+}
+
+// tierStatus ожидает уровни, отсортированные по возрастанию порога.
+func tierStatus(tiers []models.Tier, accrued float64) models.TierStatus {
+	status := models.TierStatus{Multiplier: 1, Accrued: accrued, Progress: 1}
+	current := -1
+	for i, tier := range tiers {
+		if tier.MinPoints <= accrued {
+			current = i
+		}
+	}
+	floor := 0.0
+	if current >= 0 {
+		status.Tier = tiers[current].Name
+		status.Multiplier = tiers[current].Multiplier
+		floor = tiers[current].MinPoints
+	}
+	if current+1 < len(tiers) {
+		next := tiers[current+1]
+		status.NextTier = next.Name
+		status.PointsToNext = next.MinPoints - accrued
+		// Уровни с одинаковым порогом дали бы деление на ноль, а NaN не
+		// сериализуется в JSON.
+		status.Progress = 0
+		if span := next.MinPoints - floor; span > 0 {
+			status.Progress = (accrued - floor) / span
+		}
+	}
+	return status
+}
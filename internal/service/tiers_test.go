@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+var testTiers = []models.Tier{
+	{Name: "Bronze", MinPoints: 0, Multiplier: 1},
+	{Name: "Silver", MinPoints: 1000, Multiplier: 1.25},
+	{Name: "Gold", MinPoints: 5000, Multiplier: 1.5},
+}
+
+func TestTierStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		tiers    []models.Tier
+		accrued  float64
+		expected models.TierStatus
+	}{
+		{
+			name:    "начальный уровень",
+			tiers:   testTiers,
+			accrued: 250,
+			expected: models.TierStatus{
+				Tier: "Bronze", Multiplier: 1, Accrued: 250,
+				NextTier: "Silver", PointsToNext: 750, Progress: 0.25,
+			},
+		},
+		{
+			name:    "промежуточный уровень",
+			tiers:   testTiers,
+			accrued: 3000,
+			expected: models.TierStatus{
+				Tier: "Silver", Multiplier: 1.25, Accrued: 3000,
+				NextTier: "Gold", PointsToNext: 2000, Progress: 0.5,
+			},
+		},
+		{
+			name:     "максимальный уровень",
+			tiers:    testTiers,
+			accrued:  7000,
+			expected: models.TierStatus{Tier: "Gold", Multiplier: 1.5, Accrued: 7000, Progress: 1},
+		},
+		{
+			name: "одинаковые пороги",
+			tiers: []models.Tier{
+				{Name: "Bronze", MinPoints: 0, Multiplier: 1},
+				{Name: "Silver", MinPoints: 0, Multiplier: 1.25},
+			},
+			accrued: -10,
+			expected: models.TierStatus{
+				Multiplier: 1, Accrued: -10, NextTier: "Bronze", PointsToNext: 10, Progress: 0,
+			},
+		},
+		{
+			name:     "уровни не настроены",
+			accrued:  100,
+			expected: models.TierStatus{Multiplier: 1, Accrued: 100, Progress: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, tierStatus(tt.tiers, tt.accrued))
+		})
+	}
+}
+func TestGetUserTier(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB, cfg: Config{TierWindowMonths: 12}}
+
+	mockDB.EXPECT().GetTiers(mock.Anything).Return(testTiers, nil).Once()
+	mockDB.EXPECT().GetRollingAccrual(mock.Anything, 1, 12).Return(1200.0, nil).Once()
+
+	status, err := service.GetUserTier(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, "Silver", status.Tier)
+
+	mockDB.EXPECT().GetTiers(mock.Anything).Return(nil, errors.New("db error")).Once()
+
+	_, err = service.GetUserTier(context.Background(), 1)
+	require.Error(t, err)
+}
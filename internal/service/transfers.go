@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+func (s *AccrualService) CreateTransfer(ctx context.Context, userID int, transfer models.TransferRequest) CreateStatus {
+	if transfer.Recipient == "" || transfer.Sum <= 0 {
+		return StatusInvalid
+	}
+	if status := s.spendNeedsTOTP(ctx, userID, transfer.Sum, transfer.TOTP); status != StatusOK {
+		return status
+	}
+	err := s.db.Transfer(ctx, userID, transfer.Recipient, transfer.Sum, s.cfg.TransferLimits)
+	switch {
+	case err == nil:
+		return StatusOK
+	case errors.Is(err, models.ErrUserNotFound):
+		return StatusNotFound
+	case errors.Is(err, models.ErrSelfTransfer):
+		return StatusInvalid
+	case errors.Is(err, models.ErrInsufficientFunds):
+		return StatusConflict
+	case errors.Is(err, models.ErrTransferLimitExceeded):
+		return StatusLimitExceeded
+	default:
+		logger.Log.Error("Ошибка перевода баллов", zap.Int("user", userID), zap.String("recipient", transfer.Recipient), zap.Error(err))
+		return StatusError
+	}
+}
+
+func (s *AccrualService) GetUserTransfers(ctx context.Context, id int) ([]models.Transfer, error) {
+	return s.db.GetUserTransfers(ctx, id)
+}
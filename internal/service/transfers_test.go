@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestCreateTransfer(t *testing.T) {
+	limits := models.TransferLimits{DailySum: 500, DailyCount: 3}
+
+	tests := []struct {
+		name           string
+		transfer       models.TransferRequest
+		callStorage    bool
+		storageErr     error
+		expectedStatus CreateStatus
+	}{
+		{
+			name:           "пустой получатель",
+			transfer:       models.TransferRequest{Sum: 10},
+			expectedStatus: StatusInvalid,
+		},
+		{
+			name:           "неположительная сумма",
+			transfer:       models.TransferRequest{Recipient: "bob", Sum: 0},
+			expectedStatus: StatusInvalid,
+		},
+		{
+			name:           "успешный перевод",
+			transfer:       models.TransferRequest{Recipient: "bob", Sum: 10},
+			callStorage:    true,
+			expectedStatus: StatusOK,
+		},
+		{
+			name:           "получатель не найден",
+			transfer:       models.TransferRequest{Recipient: "nobody", Sum: 10},
+			callStorage:    true,
+			storageErr:     models.ErrUserNotFound,
+			expectedStatus: StatusNotFound,
+		},
+		{
+			name:           "перевод самому себе",
+			transfer:       models.TransferRequest{Recipient: "alice", Sum: 10},
+			callStorage:    true,
+			storageErr:     models.ErrSelfTransfer,
+			expectedStatus: StatusInvalid,
+		},
+		{
+			name:           "недостаточно средств",
+			transfer:       models.TransferRequest{Recipient: "bob", Sum: 10},
+			callStorage:    true,
+			storageErr:     models.ErrInsufficientFunds,
+			expectedStatus: StatusConflict,
+		},
+		{
+			name:           "превышен лимит",
+			transfer:       models.TransferRequest{Recipient: "bob", Sum: 10},
+			callStorage:    true,
+			storageErr:     models.ErrTransferLimitExceeded,
+			expectedStatus: StatusLimitExceeded,
+		},
+		{
+			name:           "ошибка БД",
+			transfer:       models.TransferRequest{Recipient: "bob", Sum: 10},
+			callStorage:    true,
+			storageErr:     errors.New("db error"),
+			expectedStatus: StatusError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := NewMockStorage(t)
+			service := &AccrualService{db: mockDB, cfg: Config{TransferLimits: limits}}
+
+			if tt.callStorage {
+				mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(models.TOTP{}, nil).Once()
+				mockDB.EXPECT().
+					Transfer(mock.Anything, 1, tt.transfer.Recipient, tt.transfer.Sum, limits).
+					Return(tt.storageErr).
+					Once()
+			}
+
+			status := service.CreateTransfer(context.Background(), 1, tt.transfer)
+			require.Equal(t, tt.expectedStatus, status)
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+const (
+	recoveryCodeCount = 10
+	// maxChallengeAttempts — сколько неверных кодов допускается на второй
+	// шаг входа, прежде чем придётся заново вводить пароль.
+	maxChallengeAttempts = 5
+	// maxActiveChallenges — сколько незавершённых входов может быть у
+	// пользователя одновременно; более старые гасятся новым.
+	maxActiveChallenges = 3
+)
+
+// EnrollTOTP выпускает новый секрет TOTP. Двухфакторная аутентификация
+// включается только после подтверждения первым кодом.
+func (s *AccrualService) EnrollTOTP(ctx context.Context, userID int) (models.TOTPEnrollment, error) {
+	user, err := s.db.GetAdminUser(ctx, userID)
+	if err != nil {
+		return models.TOTPEnrollment{}, err
+	}
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return models.TOTPEnrollment{}, err
+	}
+	if err := s.db.SaveTOTPSecret(ctx, userID, secret); err != nil {
+		return models.TOTPEnrollment{}, err
+	}
+	return models.TOTPEnrollment{
+		Secret: secret,
+		URI:    auth.TOTPURI(s.cfg.TOTPIssuer, user.Login, secret),
+	}, nil
+}
+
+// ConfirmTOTP включает двухфакторную аутентификацию и возвращает коды
+// восстановления. Коды показываются один раз, в базе хранятся их хеши.
+func (s *AccrualService) ConfirmTOTP(ctx context.Context, userID int, code string) ([]string, error) {
+	t, err := s.db.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Secret == "" {
+		return nil, models.ErrTOTPNotEnabled
+	}
+	if t.Enabled {
+		return nil, models.ErrTOTPAlreadyEnabled
+	}
+	step, ok := auth.VerifyTOTP(t.Secret, code, time.Now(), t.LastStep)
+	if !ok {
+		return nil, models.ErrInvalidTOTP
+	}
+	codes, err := auth.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = auth.HashRecoveryCode(code)
+	}
+	if err := s.db.EnableTOTP(ctx, userID, step, hashes); err != nil {
+		return nil, err
+	}
+	logger.Log.Info("Подключена двухфакторная аутентификация", zap.Int("user", userID))
+	return codes, nil
+}
+
+// DisableTOTP отключает двухфакторную аутентификацию по коду TOTP или коду
+// восстановления.
+func (s *AccrualService) DisableTOTP(ctx context.Context, userID int, code string) error {
+	t, err := s.db.GetTOTP(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !t.Enabled {
+		return models.ErrTOTPNotEnabled
+	}
+	if err := s.checkSecondFactor(ctx, userID, t, code, true); err != nil {
+		return err
+	}
+	if err := s.db.DisableTOTP(ctx, userID); err != nil {
+		return err
+	}
+	logger.Log.Info("Отключена двухфакторная аутентификация", zap.Int("user", userID))
+	return nil
+}
+
+// SetTwoFactorSettings меняет порог подтверждения списаний. Изменение
+// требует свежего кода TOTP, иначе украденная сессия могла бы просто
+// снять защиту.
+func (s *AccrualService) SetTwoFactorSettings(ctx context.Context, userID int, settings models.TwoFactorSettings) error {
+	if settings.WithdrawThreshold < 0 {
+		return ErrInvalidAmount
+	}
+	t, err := s.db.GetTOTP(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !t.Enabled {
+		return models.ErrTOTPNotEnabled
+	}
+	if err := s.checkSecondFactor(ctx, userID, t, settings.Code, false); err != nil {
+		return err
+	}
+	return s.db.SetWithdrawThreshold(ctx, userID, settings.WithdrawThreshold)
+}
+
+// StartLoginChallenge вызывается после проверки пароля. Для пользователя с
+// включённой 2FA выпускает токен второго шага входа; если 2FA не включена,
+// ChallengeToken пуст.
+func (s *AccrualService) StartLoginChallenge(ctx context.Context, user *models.User) (models.LoginChallenge, error) {
+	t, err := s.db.GetTOTP(ctx, user.ID)
+	if err != nil || !t.Enabled {
+		return models.LoginChallenge{}, err
+	}
+	token, err := auth.GenerateChallengeToken()
+	if err != nil {
+		return models.LoginChallenge{}, err
+	}
+	if err := s.db.CreateLoginChallenge(ctx, user.ID, auth.HashToken(token), s.cfg.LoginChallengeTTL, maxActiveChallenges); err != nil {
+		return models.LoginChallenge{}, err
+	}
+	return models.LoginChallenge{
+		ChallengeToken: token,
+		ExpiresIn:      int(s.cfg.LoginChallengeTTL.Seconds()),
+	}, nil
+}
+
+// CompleteLoginChallenge завершает вход кодом TOTP или кодом
+// восстановления и возвращает пользователя для выпуска сессии. Неверные
+// коды учитываются в защите входа так же, как неверные пароли.
+func (s *AccrualService) CompleteLoginChallenge(ctx context.Context, challengeToken, code, ip string) (*models.User, error) {
+	if challengeToken == "" {
+		return nil, models.ErrInvalidChallenge
+	}
+	tokenHash := auth.HashToken(challengeToken)
+	pending, err := s.db.GetLoginChallenge(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	wait, err := s.CheckLoginThrottle(ctx, pending.Login, ip)
+	if err != nil {
+		return nil, err
+	}
+	if wait > 0 {
+		return nil, &ThrottledError{Wait: wait}
+	}
+	t, err := s.db.GetTOTP(ctx, pending.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !t.Enabled {
+		return nil, models.ErrInvalidChallenge
+	}
+	if err := s.verifySecondFactor(ctx, pending.ID, t, code, true); err != nil {
+		if errors.Is(err, models.ErrInvalidTOTP) {
+			if err := s.db.FailLoginChallenge(ctx, tokenHash, maxChallengeAttempts); err != nil {
+				return nil, err
+			}
+			logger.Log.Warn("Неверный код второго шага входа", zap.String("login", pending.Login), zap.String("ip", ip))
+			if err := s.db.RecordLoginFailure(ctx, pending.Login, ip, models.LoginReasonInvalidTOTP, s.cfg.Login); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+	user, err := s.db.ConsumeLoginChallenge(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	if user.Blocked {
+		return nil, models.ErrUserBlocked
+	}
+	user.Permissions, err = s.db.GetUserPermissions(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// spendNeedsTOTP проверяет, что списание или перевод выше порога
+// пользователя подтверждены свежим кодом TOTP. Коды восстановления здесь не
+// принимаются. Пока неверных кодов слишком много, возвращается
+// StatusThrottled.
+func (s *AccrualService) spendNeedsTOTP(ctx context.Context, userID int, sum float64, code string) CreateStatus {
+	t, err := s.db.GetTOTP(ctx, userID)
+	if err != nil {
+		return StatusError
+	}
+	if !t.Enabled || t.WithdrawThreshold == 0 || sum <= t.WithdrawThreshold {
+		return StatusOK
+	}
+	if err := s.checkSecondFactor(ctx, userID, t, code, false); err != nil {
+		var throttled *ThrottledError
+		switch {
+		case errors.Is(err, models.ErrInvalidTOTP):
+			return StatusTOTPRequired
+		case errors.As(err, &throttled):
+			return StatusThrottled
+		}
+		return StatusError
+	}
+	return StatusOK
+}
+
+// checkSecondFactor проверяет код 2FA в открытой сессии. Неверные коды
+// считаются по пользователю, а после серии ошибок код не проверяется вовсе:
+// иначе владелец украденной сессии мог бы перебирать шестизначные коды.
+// Пустой код — это ещё не попытка: клиент узнаёт так, что код нужен.
+func (s *AccrualService) checkSecondFactor(ctx context.Context, userID int, t models.TOTP, code string, allowRecovery bool) error {
+	throttle, err := s.db.GetTOTPThrottle(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if wait := throttleWait(throttle, s.cfg.Login, time.Now()); wait > 0 {
+		return &ThrottledError{Wait: wait}
+	}
+	err = s.verifySecondFactor(ctx, userID, t, code, allowRecovery)
+	if errors.Is(err, models.ErrInvalidTOTP) && code != "" {
+		logger.Log.Warn("Неверный код двухфакторной аутентификации", zap.Int("user", userID))
+		if err := s.db.RecordTOTPFailure(ctx, userID, s.cfg.Login); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (s *AccrualService) verifySecondFactor(ctx context.Context, userID int, t models.TOTP, code string, allowRecovery bool) error {
+	if code == "" {
+		return models.ErrInvalidTOTP
+	}
+	if step, ok := auth.VerifyTOTP(t.Secret, code, time.Now(), t.LastStep); ok {
+		used, err := s.db.UseTOTPStep(ctx, userID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return models.ErrInvalidTOTP
+		}
+		return nil
+	}
+	if !allowRecovery {
+		return models.ErrInvalidTOTP
+	}
+	used, err := s.db.UseRecoveryCode(ctx, userID, auth.HashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return models.ErrInvalidTOTP
+	}
+	logger.Log.Warn("Вход по коду восстановления", zap.Int("user", userID))
+	return nil
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// currentCode возвращает код текущего шага вместе с самим шагом, чтобы
+// тест не зависел от смены шага между настройкой моков и вызовом.
+func currentCode(t *testing.T) (string, int64) {
+	step := auth.TOTPStep(time.Now())
+	code, err := auth.TOTPCode(testTOTPSecret, step)
+	require.NoError(t, err)
+	return code, step
+}
+
+func TestEnrollTOTP(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB, cfg: Config{TOTPIssuer: "Gophermart"}}
+
+	mockDB.EXPECT().GetAdminUser(mock.Anything, 1).Return(models.AdminUser{ID: 1, Login: "alice"}, nil).Once()
+	mockDB.EXPECT().SaveTOTPSecret(mock.Anything, 1, mock.Anything).Return(nil).Once()
+
+	enrollment, err := service.EnrollTOTP(context.Background(), 1)
+	require.NoError(t, err)
+	require.NotEmpty(t, enrollment.Secret)
+	require.Contains(t, enrollment.URI, "otpauth://totp/Gophermart:alice?")
+	require.Contains(t, enrollment.URI, "secret="+enrollment.Secret)
+}
+func TestConfirmTOTP(t *testing.T) {
+	t.Run("верный код включает 2FA", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		code, step := currentCode(t)
+		mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(models.TOTP{Secret: testTOTPSecret}, nil).Once()
+		mockDB.EXPECT().EnableTOTP(mock.Anything, 1, step, mock.MatchedBy(func(hashes []string) bool {
+			return len(hashes) == recoveryCodeCount
+		})).Return(nil).Once()
+
+		codes, err := service.ConfirmTOTP(context.Background(), 1, code)
+		require.NoError(t, err)
+		require.Len(t, codes, recoveryCodeCount)
+	})
+
+	t.Run("неверный код", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(models.TOTP{Secret: testTOTPSecret}, nil).Once()
+
+		_, err := service.ConfirmTOTP(context.Background(), 1, "000000")
+		require.ErrorIs(t, err, models.ErrInvalidTOTP)
+	})
+
+	t.Run("подключение не начато", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(models.TOTP{}, nil).Once()
+
+		_, err := service.ConfirmTOTP(context.Background(), 1, "123456")
+		require.ErrorIs(t, err, models.ErrTOTPNotEnabled)
+	})
+}
+func TestStartLoginChallenge(t *testing.T) {
+	t.Run("без 2FA", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(models.TOTP{}, nil).Once()
+
+		challenge, err := service.StartLoginChallenge(context.Background(), &models.User{ID: 1})
+		require.NoError(t, err)
+		require.Empty(t, challenge.ChallengeToken)
+	})
+
+	t.Run("с 2FA", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB, cfg: Config{LoginChallengeTTL: 5 * time.Minute}}
+
+		mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(models.TOTP{Secret: testTOTPSecret, Enabled: true}, nil).Once()
+		mockDB.EXPECT().CreateLoginChallenge(mock.Anything, 1, mock.Anything, 5*time.Minute, maxActiveChallenges).Return(nil).Once()
+
+		challenge, err := service.StartLoginChallenge(context.Background(), &models.User{ID: 1})
+		require.NoError(t, err)
+		require.NotEmpty(t, challenge.ChallengeToken)
+		require.Equal(t, 300, challenge.ExpiresIn)
+	})
+}
+func TestCompleteLoginChallenge(t *testing.T) {
+	tokenHash := auth.HashToken("challenge")
+	enabled := models.TOTP{Secret: testTOTPSecret, Enabled: true}
+	pending := &models.User{ID: 1, Login: "alice"}
+	policy := models.LoginPolicy{MaxFailures: 5, BaseDelay: time.Second, MaxDelay: time.Minute}
+
+	t.Run("код TOTP", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		code, step := currentCode(t)
+		mockDB.EXPECT().GetLoginChallenge(mock.Anything, tokenHash).Return(pending, nil).Once()
+		mockDB.EXPECT().GetLoginThrottles(mock.Anything, "alice", "10.0.0.1").Return(nil, nil).Once()
+		mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(enabled, nil).Once()
+		mockDB.EXPECT().UseTOTPStep(mock.Anything, 1, step).Return(true, nil).Once()
+		mockDB.EXPECT().ConsumeLoginChallenge(mock.Anything, tokenHash).
+			Return(&models.User{ID: 1, Login: "alice", Role: models.RoleCustomer}, nil).Once()
+		mockDB.EXPECT().GetUserPermissions(mock.Anything, 1).Return(nil, nil).Once()
+
+		user, err := service.CompleteLoginChallenge(context.Background(), "challenge", code, "10.0.0.1")
+		require.NoError(t, err)
+		require.Equal(t, 1, user.ID)
+	})
+
+	t.Run("код восстановления", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		mockDB.EXPECT().GetLoginChallenge(mock.Anything, tokenHash).Return(pending, nil).Once()
+		mockDB.EXPECT().GetLoginThrottles(mock.Anything, "alice", "10.0.0.1").Return(nil, nil).Once()
+		mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(enabled, nil).Once()
+		mockDB.EXPECT().UseRecoveryCode(mock.Anything, 1, auth.HashRecoveryCode("abcd-efgh")).Return(true, nil).Once()
+		mockDB.EXPECT().ConsumeLoginChallenge(mock.Anything, tokenHash).
+			Return(&models.User{ID: 1, Login: "alice"}, nil).Once()
+		mockDB.EXPECT().GetUserPermissions(mock.Anything, 1).Return(nil, nil).Once()
+
+		_, err := service.CompleteLoginChallenge(context.Background(), "challenge", "ABCD-EFGH", "10.0.0.1")
+		require.NoError(t, err)
+	})
+
+	t.Run("неверный код учитывается", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB, cfg: Config{Login: policy}}
+
+		mockDB.EXPECT().GetLoginChallenge(mock.Anything, tokenHash).Return(pending, nil).Once()
+		mockDB.EXPECT().GetLoginThrottles(mock.Anything, "alice", "10.0.0.1").Return(nil, nil).Once()
+		mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(enabled, nil).Once()
+		mockDB.EXPECT().UseRecoveryCode(mock.Anything, 1, mock.Anything).Return(false, nil).Once()
+		mockDB.EXPECT().FailLoginChallenge(mock.Anything, tokenHash, maxChallengeAttempts).Return(nil).Once()
+		mockDB.EXPECT().RecordLoginFailure(mock.Anything, "alice", "10.0.0.1", models.LoginReasonInvalidTOTP, policy).Return(nil).Once()
+
+		_, err := service.CompleteLoginChallenge(context.Background(), "challenge", "wrong", "10.0.0.1")
+		require.ErrorIs(t, err, models.ErrInvalidTOTP)
+	})
+
+	t.Run("повтор уже принятого кода", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB, cfg: Config{Login: policy}}
+		code, step := currentCode(t)
+		used := enabled
+		used.LastStep = step + 1
+
+		mockDB.EXPECT().GetLoginChallenge(mock.Anything, tokenHash).Return(pending, nil).Once()
+		mockDB.EXPECT().GetLoginThrottles(mock.Anything, "alice", "10.0.0.1").Return(nil, nil).Once()
+		mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(used, nil).Once()
+		mockDB.EXPECT().UseRecoveryCode(mock.Anything, 1, mock.Anything).Return(false, nil).Once()
+		mockDB.EXPECT().FailLoginChallenge(mock.Anything, tokenHash, maxChallengeAttempts).Return(nil).Once()
+		mockDB.EXPECT().RecordLoginFailure(mock.Anything, "alice", "10.0.0.1", models.LoginReasonInvalidTOTP, policy).Return(nil).Once()
+
+		_, err := service.CompleteLoginChallenge(context.Background(), "challenge", code, "10.0.0.1")
+		require.ErrorIs(t, err, models.ErrInvalidTOTP)
+	})
+
+	t.Run("логин заблокирован перебором", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB, cfg: Config{Login: policy}}
+
+		mockDB.EXPECT().GetLoginChallenge(mock.Anything, tokenHash).Return(pending, nil).Once()
+		mockDB.EXPECT().GetLoginThrottles(mock.Anything, "alice", "10.0.0.1").
+			Return([]models.LoginThrottle{{LockedUntil: time.Now().Add(time.Minute)}}, nil).Once()
+
+		_, err := service.CompleteLoginChallenge(context.Background(), "challenge", "123456", "10.0.0.1")
+		var throttled *ThrottledError
+		require.ErrorAs(t, err, &throttled)
+		require.Positive(t, throttled.Wait)
+	})
+}
+func TestSetTwoFactorSettings(t *testing.T) {
+	protected := models.TOTP{Secret: testTOTPSecret, Enabled: true, WithdrawThreshold: 500}
+
+	t.Run("без кода порог не меняется", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(protected, nil).Once()
+		mockDB.EXPECT().GetTOTPThrottle(mock.Anything, 1).Return(models.LoginThrottle{}, nil).Once()
+
+		err := service.SetTwoFactorSettings(context.Background(), 1, models.TwoFactorSettings{})
+		require.ErrorIs(t, err, models.ErrInvalidTOTP)
+	})
+
+	t.Run("со свежим кодом", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		code, step := currentCode(t)
+		mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(protected, nil).Once()
+		mockDB.EXPECT().GetTOTPThrottle(mock.Anything, 1).Return(models.LoginThrottle{}, nil).Once()
+		mockDB.EXPECT().UseTOTPStep(mock.Anything, 1, step).Return(true, nil).Once()
+		mockDB.EXPECT().SetWithdrawThreshold(mock.Anything, 1, float64(1000)).Return(nil).Once()
+
+		err := service.SetTwoFactorSettings(context.Background(), 1, models.TwoFactorSettings{WithdrawThreshold: 1000, Code: code})
+		require.NoError(t, err)
+	})
+
+	t.Run("2FA не подключена", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(models.TOTP{}, nil).Once()
+
+		err := service.SetTwoFactorSettings(context.Background(), 1, models.TwoFactorSettings{WithdrawThreshold: 100})
+		require.ErrorIs(t, err, models.ErrTOTPNotEnabled)
+	})
+}
+func TestWithdrawRequiresTOTP(t *testing.T) {
+	order := "79927398713"
+	protected := models.TOTP{Secret: testTOTPSecret, Enabled: true, WithdrawThreshold: 500}
+
+	t.Run("ниже порога код не нужен", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		mockDB.EXPECT().GetUserBalance(mock.Anything, 1).Return(models.Balance{Current: 1000}, nil).Once()
+		mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(protected, nil).Once()
+		mockDB.EXPECT().Withdraw(mock.Anything, 1, order, float64(100)).Return(nil).Once()
+
+		require.Equal(t, StatusOK, service.CreateWithdraw(context.Background(), 1, models.Withdraw{Order: order, Sum: 100}))
+	})
+
+	t.Run("выше порога без кода", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		mockDB.EXPECT().GetUserBalance(mock.Anything, 1).Return(models.Balance{Current: 1000}, nil).Once()
+		mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(protected, nil).Once()
+		mockDB.EXPECT().GetTOTPThrottle(mock.Anything, 1).Return(models.LoginThrottle{}, nil).Once()
+
+		require.Equal(t, StatusTOTPRequired, service.CreateWithdraw(context.Background(), 1, models.Withdraw{Order: order, Sum: 600}))
+	})
+
+	t.Run("выше порога со свежим кодом", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		code, step := currentCode(t)
+		mockDB.EXPECT().GetUserBalance(mock.Anything, 1).Return(models.Balance{Current: 1000}, nil).Once()
+		mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(protected, nil).Once()
+		mockDB.EXPECT().GetTOTPThrottle(mock.Anything, 1).Return(models.LoginThrottle{}, nil).Once()
+		mockDB.EXPECT().UseTOTPStep(mock.Anything, 1, step).Return(true, nil).Once()
+		mockDB.EXPECT().Withdraw(mock.Anything, 1, order, float64(600)).Return(nil).Once()
+
+		status := service.CreateWithdraw(context.Background(), 1, models.Withdraw{Order: order, Sum: 600, TOTP: code})
+		require.Equal(t, StatusOK, status)
+	})
+}
+func TestTransferRequiresTOTP(t *testing.T) {
+	protected := models.TOTP{Secret: testTOTPSecret, Enabled: true, WithdrawThreshold: 500}
+
+	t.Run("выше порога без кода", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(protected, nil).Once()
+		mockDB.EXPECT().GetTOTPThrottle(mock.Anything, 1).Return(models.LoginThrottle{}, nil).Once()
+
+		status := service.CreateTransfer(context.Background(), 1, models.TransferRequest{Recipient: "bob", Sum: 600})
+		require.Equal(t, StatusTOTPRequired, status)
+	})
+
+	t.Run("выше порога со свежим кодом", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB}
+
+		code, step := currentCode(t)
+		mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(protected, nil).Once()
+		mockDB.EXPECT().GetTOTPThrottle(mock.Anything, 1).Return(models.LoginThrottle{}, nil).Once()
+		mockDB.EXPECT().UseTOTPStep(mock.Anything, 1, step).Return(true, nil).Once()
+		mockDB.EXPECT().Transfer(mock.Anything, 1, "bob", float64(600), models.TransferLimits{}).Return(nil).Once()
+
+		status := service.CreateTransfer(context.Background(), 1, models.TransferRequest{Recipient: "bob", Sum: 600, TOTP: code})
+		require.Equal(t, StatusOK, status)
+	})
+}
+func TestSecondFactorThrottle(t *testing.T) {
+	protected := models.TOTP{Secret: testTOTPSecret, Enabled: true, WithdrawThreshold: 500}
+	policy := models.LoginPolicy{MaxFailures: 5, Lockout: 15 * time.Minute, Window: time.Hour}
+	locked := models.LoginThrottle{Scope: models.ThrottleScopeTOTP, Key: "1", Failures: 5,
+		LastFailureAt: time.Now(), LockedUntil: time.Now().Add(time.Minute)}
+
+	t.Run("неверный код при переводе учитывается", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB, cfg: Config{Login: policy}}
+
+		mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(protected, nil).Once()
+		mockDB.EXPECT().GetTOTPThrottle(mock.Anything, 1).Return(models.LoginThrottle{}, nil).Once()
+		mockDB.EXPECT().RecordTOTPFailure(mock.Anything, 1, policy).Return(nil).Once()
+
+		status := service.CreateTransfer(context.Background(), 1, models.TransferRequest{Recipient: "bob", Sum: 600, TOTP: "000000"})
+		require.Equal(t, StatusTOTPRequired, status)
+	})
+
+	t.Run("после серии ошибок списание отклоняется без проверки кода", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB, cfg: Config{Login: policy}}
+
+		code, _ := currentCode(t)
+		mockDB.EXPECT().GetUserBalance(mock.Anything, 1).Return(models.Balance{Current: 1000}, nil).Once()
+		mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(protected, nil).Once()
+		mockDB.EXPECT().GetTOTPThrottle(mock.Anything, 1).Return(locked, nil).Once()
+
+		status := service.CreateWithdraw(context.Background(), 1, models.Withdraw{Order: "79927398713", Sum: 600, TOTP: code})
+		require.Equal(t, StatusThrottled, status)
+	})
+
+	t.Run("отключение 2FA под блокировкой", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB, cfg: Config{Login: policy}}
+
+		mockDB.EXPECT().GetTOTP(mock.Anything, 1).Return(protected, nil).Once()
+		mockDB.EXPECT().GetTOTPThrottle(mock.Anything, 1).Return(locked, nil).Once()
+
+		err := service.DisableTOTP(context.Background(), 1, "recovery-code")
+		var throttled *ThrottledError
+		require.ErrorAs(t, err, &throttled)
+		require.Positive(t, throttled.Wait)
+	})
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/internal/webhook"
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+const (
+	// webhookBatch — сколько доставок обработчик берёт за один проход;
+	// webhookWorkers — сколько из них отправляется одновременно. При таймауте
+	// запроса по умолчанию (10 с) проход укладывается в webhookJobTimeout.
+	webhookBatch      = 20
+	webhookWorkers    = 5
+	webhookJobTimeout = time.Minute
+	// webhookLease — на сколько откладываются взятые доставки; должно с
+	// запасом покрывать таймаут запроса к мерчанту.
+	webhookLease = 2 * time.Minute
+	// webhookLogLimit — сколько последних доставок показывает журнал.
+	webhookLogLimit = 100
+)
+
+// WebhookSender отправляет событие на адрес подписки и возвращает код
+// ответа получателя.
+type WebhookSender interface {
+	Send(ctx context.Context, d models.WebhookDelivery) (int, error)
+}
+
+func (s *AccrualService) SetWebhookSender(sender WebhookSender) {
+	s.webhooks = sender
+}
+
+func (s *AccrualService) hostResolver() webhook.Resolver {
+	if s.resolver == nil {
+		return net.DefaultResolver
+	}
+	return s.resolver
+}
+
+// CreateWebhookSubscription подписывает мерчанта на события. Пустой список
+// событий означает все события; секрет подписи возвращается только здесь.
+// Адрес во внутренней сети отклоняется; при доставке адрес проверяется ещё
+// раз при подключении.
+func (s *AccrualService) CreateWebhookSubscription(ctx context.Context, merchantID int, req models.WebhookSubscription) (models.WebhookSubscription, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return models.WebhookSubscription{}, models.ErrInvalidWebhook
+	}
+	events := []string{}
+	for _, event := range req.Events {
+		if !slices.Contains(models.WebhookEvents, event) {
+			return models.WebhookSubscription{}, models.ErrInvalidWebhook
+		}
+		if !slices.Contains(events, event) {
+			events = append(events, event)
+		}
+	}
+	if len(events) == 0 {
+		events = models.WebhookEvents
+	}
+	if err := webhook.CheckHost(ctx, s.hostResolver(), u.Hostname()); err != nil {
+		logger.Log.Warn("Адрес вебхука отклонён", zap.Int("merchant", merchantID), zap.Error(err))
+		return models.WebhookSubscription{}, models.ErrInvalidWebhook
+	}
+	secret, err := auth.GenerateSigningSecret()
+	if err != nil {
+		return models.WebhookSubscription{}, err
+	}
+
+	sub := models.WebhookSubscription{URL: u.String(), Events: events, Secret: secret}
+	if err := s.db.CreateWebhookSubscription(ctx, merchantID, &sub); err != nil {
+		return models.WebhookSubscription{}, err
+	}
+	logger.Log.Info("Создана подписка на вебхуки", zap.Int("merchant", merchantID), zap.Int("subscription", sub.ID))
+	return sub, nil
+}
+
+func (s *AccrualService) GetWebhookSubscriptions(ctx context.Context, merchantID int) ([]models.WebhookSubscription, error) {
+	return s.db.GetWebhookSubscriptions(ctx, merchantID)
+}
+
+func (s *AccrualService) DeleteWebhookSubscription(ctx context.Context, merchantID, subscriptionID int) error {
+	return s.db.DeleteWebhookSubscription(ctx, merchantID, subscriptionID)
+}
+
+func (s *AccrualService) GetWebhookDeliveries(ctx context.Context, merchantID int, status string) ([]models.WebhookDelivery, error) {
+	switch status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDead:
+	default:
+		return nil, models.ErrInvalidWebhook
+	}
+	return s.db.GetWebhookDeliveries(ctx, merchantID, status, webhookLogLimit)
+}
+
+func (s *AccrualService) RedeliverWebhook(ctx context.Context, merchantID, deliveryID int) error {
+	return s.db.RedeliverWebhook(ctx, merchantID, deliveryID)
+}
+
+// DeliverWebhooks отправляет доставки, которым подошёл срок, не больше
+// webhookWorkers одновременно, и возвращает, сколько из них принято
+// получателями. Неудачная попытка откладывает следующую с экспоненциальной
+// задержкой; после WebhookMaxAttempts попыток доставка переходит в DEAD.
+func (s *AccrualService) DeliverWebhooks(ctx context.Context) (int, error) {
+	if s.webhooks == nil {
+		return 0, nil
+	}
+	deliveries, err := s.db.ClaimWebhookDeliveries(ctx, webhookBatch, webhookLease)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		delivered int
+		firstErr  error
+	)
+	slots := make(chan struct{}, webhookWorkers)
+	for _, d := range deliveries {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			ok, err := s.deliverWebhook(ctx, d)
+			mu.Lock()
+			defer mu.Unlock()
+			if ok {
+				delivered++
+			}
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}()
+	}
+	wg.Wait()
+	return delivered, firstErr
+}
+
+// deliverWebhook отправляет одну доставку и записывает результат попытки.
+func (s *AccrualService) deliverWebhook(ctx context.Context, d models.WebhookDelivery) (bool, error) {
+	code, err := s.webhooks.Send(ctx, d)
+	attempt := models.WebhookAttempt{DeliveryID: d.ID, Status: models.DeliveryDelivered, StatusCode: code}
+	if err != nil {
+		attempt.Error = err.Error()
+		attempt.Status = models.DeliveryPending
+		if d.Attempts+1 >= s.cfg.WebhookMaxAttempts {
+			attempt.Status = models.DeliveryDead
+			logger.Log.Warn("Вебхук не доставлен", zap.Int("delivery", d.ID), zap.Error(err))
+		} else {
+			attempt.NextAttemptAt = time.Now().Add(s.webhookRetryDelay(d.Attempts + 1))
+		}
+	}
+	if err := s.db.RecordWebhookAttempt(ctx, attempt); err != nil {
+		return false, err
+	}
+	return err == nil, nil
+}
+
+// webhookRetryDelay — задержка после attempts неудачных попыток: база,
+// удваиваемая с каждой попыткой, но не больше WebhookRetryMax.
+func (s *AccrualService) webhookRetryDelay(attempts int) time.Duration {
+	delay := s.cfg.WebhookRetryBase
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= s.cfg.WebhookRetryMax {
+			return s.cfg.WebhookRetryMax
+		}
+	}
+	return min(delay, s.cfg.WebhookRetryMax)
+}
+
+func (s *AccrualService) RunWebhookWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			jobCtx, cancel := context.WithTimeout(ctx, webhookJobTimeout)
+			if _, err := s.DeliverWebhooks(jobCtx); err != nil {
+				logger.Log.Error("Ошибка доставки вебхуков", zap.Error(err))
+			}
+			cancel()
+		}
+	}
+}
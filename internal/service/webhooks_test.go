@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+type fakeResolver map[string][]netip.Addr
+
+func (f fakeResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	addrs, ok := f[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return addrs, nil
+}
+
+var testResolver = fakeResolver{
+	"shop.example":     {netip.MustParseAddr("93.184.216.34")},
+	"internal.example": {netip.MustParseAddr("10.0.0.5")},
+}
+
+type fakeWebhookSender map[string]int
+
+func (f fakeWebhookSender) Send(ctx context.Context, d models.WebhookDelivery) (int, error) {
+	code := f[d.URL]
+	if code == 0 {
+		return 0, errors.New("connection refused")
+	}
+	if code >= 300 {
+		return code, errors.New("неожиданный ответ")
+	}
+	return code, nil
+}
+
+func TestCreateWebhookSubscription(t *testing.T) {
+	t.Run("без фильтра — все события", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB, resolver: testResolver}
+		mockDB.EXPECT().CreateWebhookSubscription(mock.Anything, 3, mock.MatchedBy(func(sub *models.WebhookSubscription) bool {
+			return sub.URL == "https://shop.example/hooks" && sub.Secret != "" &&
+				len(sub.Events) == len(models.WebhookEvents)
+		})).Run(func(ctx context.Context, merchantID int, sub *models.WebhookSubscription) {
+			sub.ID = 5
+		}).Return(nil).Once()
+
+		sub, err := service.CreateWebhookSubscription(context.Background(), 3, models.WebhookSubscription{URL: "https://shop.example/hooks"})
+		require.NoError(t, err)
+		require.Equal(t, 5, sub.ID)
+		require.NotEmpty(t, sub.Secret)
+	})
+
+	t.Run("повторы в фильтре отбрасываются", func(t *testing.T) {
+		mockDB := NewMockStorage(t)
+		service := &AccrualService{db: mockDB, resolver: testResolver}
+		mockDB.EXPECT().CreateWebhookSubscription(mock.Anything, 3, mock.MatchedBy(func(sub *models.WebhookSubscription) bool {
+			return len(sub.Events) == 1 && sub.Events[0] == models.WebhookPointsWithdrawn
+		})).Return(nil).Once()
+
+		_, err := service.CreateWebhookSubscription(context.Background(), 3, models.WebhookSubscription{
+			URL:    "http://shop.example:9000/hooks",
+			Events: []string{models.WebhookPointsWithdrawn, models.WebhookPointsWithdrawn},
+		})
+		require.NoError(t, err)
+	})
+
+	for _, req := range []models.WebhookSubscription{
+		{URL: "ftp://shop.example/hooks"},
+		{URL: "/hooks"},
+		{URL: "https://shop.example/hooks", Events: []string{"order.created"}},
+		{URL: "http://localhost:9000/hooks"},
+		{URL: "http://127.0.0.1/hooks"},
+		{URL: "http://[::1]/hooks"},
+		{URL: "http://169.254.169.254/latest/meta-data"},
+		{URL: "https://internal.example/hooks"},
+		{URL: "https://unknown.example/hooks"},
+	} {
+		service := &AccrualService{db: NewMockStorage(t), resolver: testResolver}
+		_, err := service.CreateWebhookSubscription(context.Background(), 3, req)
+		require.ErrorIs(t, err, models.ErrInvalidWebhook)
+	}
+}
+
+func TestDeliverWebhooks(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB, cfg: Config{
+		WebhookMaxAttempts: 5,
+		WebhookRetryBase:   time.Minute,
+		WebhookRetryMax:    time.Hour,
+	}}
+	service.SetWebhookSender(fakeWebhookSender{"https://ok": 204, "https://busy": 503})
+
+	mockDB.EXPECT().ClaimWebhookDeliveries(mock.Anything, webhookBatch, webhookLease).Return([]models.WebhookDelivery{
+		{ID: 1, URL: "https://ok", Attempts: 0},
+		{ID: 2, URL: "https://busy", Attempts: 2},
+		{ID: 3, URL: "https://down", Attempts: 4},
+	}, nil).Once()
+	mockDB.EXPECT().RecordWebhookAttempt(mock.Anything, models.WebhookAttempt{
+		DeliveryID: 1, Status: models.DeliveryDelivered, StatusCode: 204,
+	}).Return(nil).Once()
+	mockDB.EXPECT().RecordWebhookAttempt(mock.Anything, mock.MatchedBy(func(a models.WebhookAttempt) bool {
+		delay := time.Until(a.NextAttemptAt)
+		return a.DeliveryID == 2 && a.Status == models.DeliveryPending && a.StatusCode == 503 &&
+			delay > 3*time.Minute && delay <= 4*time.Minute
+	})).Return(nil).Once()
+	mockDB.EXPECT().RecordWebhookAttempt(mock.Anything, mock.MatchedBy(func(a models.WebhookAttempt) bool {
+		return a.DeliveryID == 3 && a.Status == models.DeliveryDead && a.Error == "connection refused"
+	})).Return(nil).Once()
+
+	delivered, err := service.DeliverWebhooks(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, delivered)
+}
+
+// slowWebhookSender считает, сколько отправок идёт одновременно.
+type slowWebhookSender struct {
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (f *slowWebhookSender) Send(ctx context.Context, d models.WebhookDelivery) (int, error) {
+	f.mu.Lock()
+	f.inFlight++
+	f.peak = max(f.peak, f.inFlight)
+	f.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	f.mu.Lock()
+	f.inFlight--
+	f.mu.Unlock()
+	return 204, nil
+}
+
+func TestDeliverWebhooksConcurrency(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB, cfg: Config{WebhookMaxAttempts: 5}}
+	sender := &slowWebhookSender{}
+	service.SetWebhookSender(sender)
+
+	deliveries := make([]models.WebhookDelivery, webhookBatch)
+	for i := range deliveries {
+		deliveries[i] = models.WebhookDelivery{ID: i + 1, URL: "https://ok"}
+	}
+	mockDB.EXPECT().ClaimWebhookDeliveries(mock.Anything, webhookBatch, webhookLease).Return(deliveries, nil).Once()
+	mockDB.EXPECT().RecordWebhookAttempt(mock.Anything, mock.Anything).Return(nil).Times(webhookBatch)
+
+	delivered, err := service.DeliverWebhooks(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, webhookBatch, delivered)
+	require.Equal(t, webhookWorkers, sender.peak)
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	service := &AccrualService{cfg: Config{WebhookRetryBase: 30 * time.Second, WebhookRetryMax: 10 * time.Minute}}
+	require.Equal(t, 30*time.Second, service.webhookRetryDelay(1))
+	require.Equal(t, 2*time.Minute, service.webhookRetryDelay(3))
+	require.Equal(t, 10*time.Minute, service.webhookRetryDelay(20))
+}
+
+func TestGetWebhookDeliveries(t *testing.T) {
+	mockDB := NewMockStorage(t)
+	service := &AccrualService{db: mockDB}
+	mockDB.EXPECT().GetWebhookDeliveries(mock.Anything, 3, models.DeliveryDead, webhookLogLimit).
+		Return([]models.WebhookDelivery{{ID: 1, Status: models.DeliveryDead}}, nil).Once()
+
+	deliveries, err := service.GetWebhookDeliveries(context.Background(), 3, models.DeliveryDead)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+
+	_, err = service.GetWebhookDeliveries(context.Background(), 3, "LOST")
+	require.ErrorIs(t, err, models.ErrInvalidWebhook)
+}
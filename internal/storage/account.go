@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// GetPasswordHash возвращает хеш пароля действующего (не удалённого)
+// пользователя.
+func (db *PgStorage) GetPasswordHash(ctx context.Context, userID int) (string, error) {
+	var hash string
+	err := db.QueryRowContext(ctx, `
+        SELECT password_hash FROM users WHERE id = $1 AND deleted_at IS NULL;
+    `, userID).Scan(&hash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", models.ErrUserNotFound
+		}
+		logger.Log.Error(err.Error())
+		return "", err
+	}
+	return hash, nil
+}
+
+func (db *PgStorage) CreatePasswordReset(ctx context.Context, userID int, tokenHash string, ttl time.Duration) error {
+	_, err := db.ExecContext(ctx, `
+        INSERT INTO password_resets (user_id, token_hash, created_at, expires_at)
+        VALUES ($1, $2, NOW(), NOW() + make_interval(secs => $3::float8));
+    `, userID, tokenHash, ttl.Seconds())
+	if err != nil {
+		logger.Log.Error(err.Error())
+	}
+	return err
+}
+
+// ResetPassword гасит токен сброса вместе с остальными выданными
+// пользователю токенами, задаёт новый хеш пароля и завершает все сессии.
+func (db *PgStorage) ResetPassword(ctx context.Context, tokenHash, passwordHash string) (int, error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var userID int
+	err = tx.QueryRowContext(ctx, `
+        UPDATE password_resets SET used_at = NOW()
+        WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+        RETURNING user_id;
+    `, tokenHash).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, models.ErrInvalidResetToken
+		}
+		logger.Log.Error(err.Error())
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE password_resets SET used_at = NOW()
+        WHERE user_id = $1 AND used_at IS NULL;
+    `, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := tx.ExecContext(ctx, `
+        UPDATE users SET password_hash = $2 WHERE id = $1 AND deleted_at IS NULL;
+    `, userID, passwordHash)
+	if err != nil {
+		return 0, err
+	}
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		if err == nil {
+			err = models.ErrInvalidResetToken
+		}
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE sessions SET revoked_at = NOW()
+        WHERE user_id = $1 AND revoked_at IS NULL;
+    `, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return userID, tx.Commit()
+}
+
+// DeleteUser обезличивает учётную запись: логин заменяется на служебный и
+// освобождается, пароль и реферальный код стираются, вход блокируется,
+// из журнала входов убираются логин и IP.
+// Заказы, списания, переводы и партии баллов остаются для отчётности.
+func (db *PgStorage) DeleteUser(ctx context.Context, userID int) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var login string
+	err = tx.QueryRowContext(ctx, `
+        SELECT login FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE;
+    `, userID).Scan(&login)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ErrUserNotFound
+		}
+		logger.Log.Error(err.Error())
+		return err
+	}
+
+	deletedLogin := models.DeletedLoginPrefix + strconv.Itoa(userID)
+	_, err = tx.ExecContext(ctx, `
+        UPDATE users
+        SET login = $2,
+            password_hash = '',
+            referral_code = NULL,
+            deleted_at = NOW(),
+            blocked_at = COALESCE(blocked_at, NOW())
+        WHERE id = $1;
+    `, userID, deletedLogin)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	// Журнал входов остаётся для статистики, но без логина и адресов.
+	_, err = tx.ExecContext(ctx, `
+        UPDATE login_audit SET login = $2, ip = '' WHERE login = $1;
+    `, login, deletedLogin)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE sessions SET revoked_at = NOW()
+        WHERE user_id = $1 AND revoked_at IS NULL;
+    `, userID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+        UPDATE password_resets SET used_at = NOW()
+        WHERE user_id = $1 AND used_at IS NULL;
+    `, userID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM user_permissions WHERE user_id = $1;
+    `, userID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM login_throttle WHERE scope IN ($1, $3) AND key = $2;
+    `, models.ThrottleScopeLogin, login, models.ThrottleScopeReset)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM totp_recovery_codes WHERE user_id = $1;
+    `, userID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM user_totp WHERE user_id = $1;
+    `, userID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM notification_preferences WHERE user_id = $1;
+    `, userID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestGetPasswordHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	query := regexp.QuoteMeta(`SELECT password_hash FROM users WHERE id = $1 AND deleted_at IS NULL;`)
+
+	mock.ExpectQuery(query).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"password_hash"}).AddRow("$argon2id$hash"))
+	hash, err := store.GetPasswordHash(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "$argon2id$hash", hash)
+
+	mock.ExpectQuery(query).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"password_hash"}))
+	_, err = store.GetPasswordHash(context.Background(), 2)
+	assert.ErrorIs(t, err, models.ErrUserNotFound)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestCreatePasswordReset(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO password_resets (user_id, token_hash, created_at, expires_at)`)).
+		WithArgs(1, "hash", float64(3600)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, store.CreatePasswordReset(context.Background(), 1, "hash", time.Hour))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestResetPassword(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	ctx := context.Background()
+	consume := regexp.QuoteMeta(`UPDATE password_resets SET used_at = NOW() WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW() RETURNING user_id;`)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(consume).WithArgs("token").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(7))
+		mock.ExpectExec(regexp.QuoteMeta(`WHERE user_id = $1 AND used_at IS NULL;`)).
+			WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET password_hash = $2 WHERE id = $1 AND deleted_at IS NULL;`)).
+			WithArgs(7, "new-hash").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE sessions SET revoked_at = NOW()`)).
+			WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		userID, err := store.ResetPassword(ctx, "token", "new-hash")
+		assert.NoError(t, err)
+		assert.Equal(t, 7, userID)
+	})
+
+	t.Run("UsedOrExpired", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(consume).WithArgs("stale").WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+		mock.ExpectRollback()
+
+		_, err := store.ResetPassword(ctx, "stale", "new-hash")
+		assert.ErrorIs(t, err, models.ErrInvalidResetToken)
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestDeleteUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	ctx := context.Background()
+	lock := regexp.QuoteMeta(`SELECT login FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE;`)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lock).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"login"}).AddRow("alice"))
+		mock.ExpectExec(regexp.QuoteMeta(`SET login = $2, password_hash = '', referral_code = NULL,`)).
+			WithArgs(3, "#deleted-3").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE login_audit SET login = $2, ip = '' WHERE login = $1;`)).
+			WithArgs("alice", "#deleted-3").WillReturnResult(sqlmock.NewResult(0, 4))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE sessions SET revoked_at = NOW()`)).
+			WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE password_resets SET used_at = NOW()`)).
+			WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM user_permissions WHERE user_id = $1;`)).
+			WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM login_throttle WHERE scope IN ($1, $3) AND key = $2;`)).
+			WithArgs(models.ThrottleScopeLogin, "alice", models.ThrottleScopeReset).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM totp_recovery_codes WHERE user_id = $1;`)).
+			WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM user_totp WHERE user_id = $1;`)).
+			WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM notification_preferences WHERE user_id = $1;`)).
+			WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		assert.NoError(t, store.DeleteUser(ctx, 3))
+	})
+
+	t.Run("AlreadyDeleted", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lock).WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"login"}))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, store.DeleteUser(ctx, 4), models.ErrUserNotFound)
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
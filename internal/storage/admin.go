@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+const adminUserColumns = `id, login, role, blocked_at IS NOT NULL, COALESCE(current_balance, 0), COALESCE(withdrawn, 0)`
+
+func scanAdminUser(row rowScanner) (models.AdminUser, error) {
+	var u models.AdminUser
+	err := row.Scan(&u.ID, &u.Login, &u.Role, &u.Blocked, &u.Current, &u.Withdrawn)
+	return u, err
+}
+
+func (db *PgStorage) SearchUsers(ctx context.Context, query string, limit int) ([]models.AdminUser, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT `+adminUserColumns+`
+		FROM users
+		WHERE login ILIKE '%' || $1 || '%'
+		ORDER BY login
+		LIMIT $2
+	`, query, limit)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.AdminUser
+	for rows.Next() {
+		u, err := scanAdminUser(rows)
+		if err != nil {
+			logger.Log.Error(err.Error())
+			return nil, err
+		}
+		users = append(users, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (db *PgStorage) GetAdminUser(ctx context.Context, userID int) (models.AdminUser, error) {
+	u, err := scanAdminUser(db.QueryRowContext(ctx, `
+		SELECT `+adminUserColumns+`
+		FROM users
+		WHERE id = $1
+	`, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return u, models.ErrUserNotFound
+		}
+		logger.Log.Error(err.Error())
+		return u, err
+	}
+	return u, nil
+}
+
+func (db *PgStorage) IsUserBlocked(ctx context.Context, userID int) (bool, error) {
+	var blocked bool
+	err := db.QueryRowContext(ctx, `
+		SELECT blocked_at IS NOT NULL FROM users WHERE id = $1
+	`, userID).Scan(&blocked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return true, nil
+		}
+		logger.Log.Error(err.Error())
+		return false, err
+	}
+	return blocked, nil
+}
+
+func logAdminAction(ctx context.Context, tx *sql.Tx, action models.AdminAction) error {
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO admin_actions (admin_id, action, target_user_id, target_order, details, created_at)
+        VALUES ($1, $2, NULLIF($3, 0), NULLIF($4, ''), $5, NOW());
+    `, action.AdminID, action.Action, action.TargetUserID, action.TargetOrder, action.Details)
+	return err
+}
+
+func (db *PgStorage) LogAdminAction(ctx context.Context, action models.AdminAction) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := logAdminAction(ctx, tx, action); err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	return tx.Commit()
+}
+
+// AdjustBalance вносит ручную корректировку баланса. Положительная сумма
+// заводит новую партию баллов, отрицательная списывает партии по FIFO.
+func (db *PgStorage) AdjustBalance(ctx context.Context, adminID, userID int, adjustment models.Adjustment, lifetimeMonths int) error {
+	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var currentBalance float64
+	err = tx.QueryRowContext(ctx, `
+        SELECT current_balance FROM users WHERE id = $1 FOR UPDATE;
+    `, userID).Scan(&currentBalance)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ErrUserNotFound
+		}
+		return err
+	}
+
+	if adjustment.Amount > 0 {
+		if err := creditPoints(ctx, tx, userID, "", models.LotSourceAdjustment, adjustment.Amount, lifetimeMonths); err != nil {
+			return err
+		}
+	} else {
+		debit := -adjustment.Amount
+		if currentBalance < debit {
+			return models.ErrInsufficientFunds
+		}
+		if _, err := consumeLots(ctx, tx, userID, debit); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `
+            UPDATE users
+            SET current_balance = current_balance - $1
+            WHERE id = $2;
+        `, debit, userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO balance_adjustments (user_id, admin_id, amount, reason, created_at)
+        VALUES ($1, $2, $3, $4, NOW());
+    `, userID, adminID, adjustment.Amount, adjustment.Reason)
+	if err != nil {
+		return err
+	}
+	err = logAdminAction(ctx, tx, models.AdminAction{
+		AdminID:      adminID,
+		Action:       models.AdminActionAdjust,
+		TargetUserID: userID,
+		Details:      adjustment.Reason,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *PgStorage) SetUserBlocked(ctx context.Context, adminID, userID int, blocked bool, reason string) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+        UPDATE users
+        SET blocked_at = CASE WHEN $2 THEN COALESCE(blocked_at, NOW()) END
+        WHERE id = $1;
+    `, userID, blocked)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		if err == nil {
+			err = models.ErrUserNotFound
+		}
+		return err
+	}
+
+	action := models.AdminActionUnblock
+	if blocked {
+		action = models.AdminActionBlock
+	}
+	err = logAdminAction(ctx, tx, models.AdminAction{
+		AdminID:      adminID,
+		Action:       action,
+		TargetUserID: userID,
+		Details:      reason,
+	})
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestAdjustBalance(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	ctx := context.Background()
+
+	expectLock := func(balance float64) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT current_balance FROM users WHERE id = $1 FOR UPDATE;`)).
+			WithArgs(5).
+			WillReturnRows(sqlmock.NewRows([]string{"current_balance"}).AddRow(balance))
+	}
+	expectAudit := func(amount float64, reason string) {
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO balance_adjustments`)).
+			WithArgs(5, 1, amount, reason).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO admin_actions`)).
+			WithArgs(1, models.AdminActionAdjust, 5, "", reason).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+
+	t.Run("Credit", func(t *testing.T) {
+		expectLock(10)
+		mock.ExpectExec(regexp.QuoteMeta(`SET current_balance = current_balance + $1`)).
+			WithArgs(40.0, 5).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO accrual_lots`)).
+			WithArgs(5, "", models.LotSourceAdjustment, 40.0, 12).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		expectAudit(40, "goodwill")
+		mock.ExpectCommit()
+
+		assert.NoError(t, store.AdjustBalance(ctx, 1, 5, models.Adjustment{Amount: 40, Reason: "goodwill"}, 12))
+	})
+
+	t.Run("Debit", func(t *testing.T) {
+		expectLock(100)
+		mock.ExpectQuery(regexp.QuoteMeta(`UPDATE accrual_lots l SET remaining = l.remaining - c.take`)).
+			WithArgs(5, 30.0).
+			WillReturnRows(sqlmock.NewRows([]string{"take", "expires_at"}).AddRow(30.0, nil))
+		mock.ExpectExec(regexp.QuoteMeta(`SET current_balance = current_balance - $1`)).
+			WithArgs(30.0, 5).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectAudit(-30, "double credit")
+		mock.ExpectCommit()
+
+		assert.NoError(t, store.AdjustBalance(ctx, 1, 5, models.Adjustment{Amount: -30, Reason: "double credit"}, 12))
+	})
+
+	t.Run("DebitInsufficientFunds", func(t *testing.T) {
+		expectLock(10)
+		mock.ExpectRollback()
+
+		err := store.AdjustBalance(ctx, 1, 5, models.Adjustment{Amount: -30, Reason: "double credit"}, 12)
+		assert.ErrorIs(t, err, models.ErrInsufficientFunds)
+	})
+
+	t.Run("UserNotFound", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT current_balance FROM users WHERE id = $1 FOR UPDATE;`)).
+			WithArgs(5).
+			WillReturnRows(sqlmock.NewRows([]string{"current_balance"}))
+		mock.ExpectRollback()
+
+		err := store.AdjustBalance(ctx, 1, 5, models.Adjustment{Amount: 10, Reason: "goodwill"}, 12)
+		assert.ErrorIs(t, err, models.ErrUserNotFound)
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestSetUserBlocked(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	ctx := context.Background()
+
+	t.Run("Block", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET blocked_at = CASE WHEN $2 THEN COALESCE(blocked_at, NOW()) END WHERE id = $1;`)).
+			WithArgs(5, true).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO admin_actions`)).
+			WithArgs(1, models.AdminActionBlock, 5, "", "fraud").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, store.SetUserBlocked(ctx, 1, 5, true, "fraud"))
+	})
+
+	t.Run("UserNotFound", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET blocked_at`)).
+			WithArgs(6, false).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, store.SetUserBlocked(ctx, 1, 6, false, ""), models.ErrUserNotFound)
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestIsUserBlocked(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	query := regexp.QuoteMeta(`SELECT blocked_at IS NOT NULL FROM users WHERE id = $1`)
+
+	mock.ExpectQuery(query).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"blocked"}).AddRow(true))
+	blocked, err := store.IsUserBlocked(context.Background(), 1)
+	require.NoError(t, err)
+	assert.True(t, blocked)
+
+	mock.ExpectQuery(query).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"blocked"}))
+	blocked, err = store.IsUserBlocked(context.Background(), 2)
+	require.NoError(t, err)
+	assert.True(t, blocked)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
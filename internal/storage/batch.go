@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// orderChunkSize ограничивает число номеров в одном запросе: у PostgreSQL
+// не больше 65535 параметров, а пакетная загрузка может быть без лимита.
+const orderChunkSize = 1000
+
+// GetOrderOwners возвращает владельцев уже загруженных заказов из списка.
+// Номера, которых нет в базе, в ответ не попадают.
+func (db *PgStorage) GetOrderOwners(ctx context.Context, numbers []string) (map[string]int, error) {
+	owners := make(map[string]int, len(numbers))
+	for chunk := range slices.Chunk(numbers, orderChunkSize) {
+		if err := db.getOrderOwners(ctx, chunk, owners); err != nil {
+			return nil, err
+		}
+	}
+	return owners, nil
+}
+
+func (db *PgStorage) getOrderOwners(ctx context.Context, numbers []string, owners map[string]int) error {
+	args := make([]any, len(numbers))
+	placeholders := make([]string, len(numbers))
+	for i, number := range numbers {
+		args[i] = number
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	rows, err := db.QueryContext(ctx, `
+        SELECT number, user_id FROM orders
+        WHERE number IN (`+strings.Join(placeholders, ", ")+`);
+    `, args...)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var number string
+		var userID int
+		if err := rows.Scan(&number, &userID); err != nil {
+			logger.Log.Error(err.Error())
+			return err
+		}
+		owners[number] = userID
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// SaveOrders добавляет заказы пользователя в одной транзакции и возвращает
+// номера, которые действительно вставлены. Номер, загруженный параллельно
+// другим запросом, пропускается.
+func (db *PgStorage) SaveOrders(ctx context.Context, userID int, numbers []string) ([]string, error) {
+	if len(numbers) == 0 {
+		return nil, nil
+	}
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var inserted []string
+	for chunk := range slices.Chunk(numbers, orderChunkSize) {
+		if inserted, err = saveOrders(ctx, tx, userID, chunk, inserted); err != nil {
+			return nil, err
+		}
+	}
+
+	return inserted, tx.Commit()
+}
+
+func saveOrders(ctx context.Context, tx *sql.Tx, userID int, numbers, inserted []string) ([]string, error) {
+	args := make([]any, 0, len(numbers)+2)
+	args = append(args, userID, models.OrderNew)
+	values := make([]string, len(numbers))
+	for i, number := range numbers {
+		args = append(args, number)
+		values[i] = fmt.Sprintf("($1, $%d, $2, 0, NOW())", i+3)
+	}
+	rows, err := tx.QueryContext(ctx, `
+        INSERT INTO orders (user_id, number, status, accrual, uploaded_at)
+        VALUES `+strings.Join(values, ", ")+`
+        ON CONFLICT (number) DO NOTHING
+        RETURNING number;
+    `, args...)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var number string
+		if err := rows.Scan(&number); err != nil {
+			logger.Log.Error(err.Error())
+			return nil, err
+		}
+		inserted = append(inserted, number)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+
+	return inserted, nil
+}
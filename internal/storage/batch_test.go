@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestGetOrderOwners(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT number, user_id FROM orders WHERE number IN ($1, $2);`)).
+		WithArgs("12345678903", "79927398713").
+		WillReturnRows(sqlmock.NewRows([]string{"number", "user_id"}).AddRow("12345678903", 1))
+
+	owners, err := store.GetOrderOwners(context.Background(), []string{"12345678903", "79927398713"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"12345678903": 1}, owners)
+
+	owners, err = store.GetOrderOwners(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, owners)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestSaveOrders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`VALUES ($1, $3, $2, 0, NOW()), ($1, $4, $2, 0, NOW()) ON CONFLICT (number) DO NOTHING RETURNING number;`)).
+		WithArgs(1, models.OrderNew, "12345678903", "79927398713").
+		WillReturnRows(sqlmock.NewRows([]string{"number"}).AddRow("12345678903"))
+	mock.ExpectCommit()
+
+	inserted, err := store.SaveOrders(context.Background(), 1, []string{"12345678903", "79927398713"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"12345678903"}, inserted)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestOrderBatchChunks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	numbers := make([]string, orderChunkSize+1)
+	for i := range numbers {
+		numbers[i] = strconv.Itoa(i)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`$%d);`, orderChunkSize))).
+		WillReturnRows(sqlmock.NewRows([]string{"number", "user_id"}).AddRow("0", 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE number IN ($1);`)).WithArgs(numbers[orderChunkSize]).
+		WillReturnRows(sqlmock.NewRows([]string{"number", "user_id"}).AddRow(numbers[orderChunkSize], 2))
+	owners, err := store.GetOrderOwners(context.Background(), numbers)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"0": 1, numbers[orderChunkSize]: 2}, owners)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`($1, $%d, $2, 0, NOW()) ON CONFLICT`, orderChunkSize+2))).
+		WillReturnRows(sqlmock.NewRows([]string{"number"}).AddRow("0"))
+	mock.ExpectQuery(regexp.QuoteMeta(`VALUES ($1, $3, $2, 0, NOW()) ON CONFLICT`)).
+		WithArgs(1, models.OrderNew, numbers[orderChunkSize]).
+		WillReturnRows(sqlmock.NewRows([]string{"number"}).AddRow(numbers[orderChunkSize]))
+	mock.ExpectCommit()
+	inserted, err := store.SaveOrders(context.Background(), 1, numbers)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0", numbers[orderChunkSize]}, inserted)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
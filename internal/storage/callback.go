@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// ApplyAccrualCallback записывает результаты уведомления о начислениях в
+// одной транзакции с его подписью. Повтор той же подписи в пределах ttl
+// возвращает ErrCallbackReplayed; если запись результатов не удалась,
+// подпись не сохраняется и уведомление можно прислать снова.
+func (db *PgStorage) ApplyAccrualCallback(ctx context.Context, signature string, ttl time.Duration, accruals []models.AccrualResponse, policy models.CreditPolicy) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := registerCallbackNonce(ctx, tx, signature, ttl); err != nil {
+		return err
+	}
+	for i := range accruals {
+		if _, err := updateOrder(ctx, tx, &accruals[i], policy); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// registerCallbackNonce запоминает подпись принятого уведомления; более
+// старые подписи удаляются, их отсекает проверка метки времени.
+func registerCallbackNonce(ctx context.Context, tx *sql.Tx, signature string, ttl time.Duration) error {
+	_, err := tx.ExecContext(ctx, `
+        DELETE FROM accrual_callback_nonces
+        WHERE received_at < NOW() - $1 * INTERVAL '1 second';
+    `, ttl.Seconds())
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	res, err := tx.ExecContext(ctx, `
+        INSERT INTO accrual_callback_nonces (signature, received_at)
+        VALUES ($1, NOW())
+        ON CONFLICT (signature) DO NOTHING;
+    `, signature)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	if n == 0 {
+		return models.ErrCallbackReplayed
+	}
+	return nil
+}
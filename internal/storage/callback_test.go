@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestApplyAccrualCallback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	ctx := context.Background()
+	cleanup := regexp.QuoteMeta(`DELETE FROM accrual_callback_nonces`)
+	insert := regexp.QuoteMeta(`INSERT INTO accrual_callback_nonces (signature, received_at)`)
+	update := regexp.QuoteMeta(`UPDATE orders SET status = $2, accrual = NULL`)
+	accruals := []models.AccrualResponse{{Order: "12345678903", Status: models.OrderInvalid}}
+	policy := models.CreditPolicy{LifetimeMonths: 12, TierWindowMonths: 12}
+
+	t.Run("подпись и результаты в одной транзакции", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(cleanup).WithArgs(600.0).WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(insert).WithArgs("sig").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(update).WithArgs("12345678903", models.OrderInvalid).WillReturnError(sql.ErrNoRows)
+		mock.ExpectCommit()
+
+		require.NoError(t, store.ApplyAccrualCallback(ctx, "sig", 10*time.Minute, accruals, policy))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("повтор подписи", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(cleanup).WithArgs(600.0).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(insert).WithArgs("sig").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := store.ApplyAccrualCallback(ctx, "sig", 10*time.Minute, accruals, policy)
+		assert.ErrorIs(t, err, models.ErrCallbackReplayed)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ошибка записи не сохраняет подпись", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(cleanup).WithArgs(600.0).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(insert).WithArgs("sig").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(update).WithArgs("12345678903", models.OrderInvalid).WillReturnError(errors.New("db"))
+		mock.ExpectRollback()
+
+		err := store.ApplyAccrualCallback(ctx, "sig", 10*time.Minute, accruals, policy)
+		assert.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,230 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/scoring-service/internal/campaign"
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+const campaignColumns = `id, name, merchant, starts_at, ends_at, first_order_only, order_prefix, bonus_points, multiplier, active`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func scanCampaign(row rowScanner) (models.Campaign, error) {
+	var c models.Campaign
+	var endsAt sql.NullTime
+	err := row.Scan(&c.ID, &c.Name, &c.Merchant, &c.StartsAt, &endsAt, &c.FirstOrderOnly, &c.OrderPrefix, &c.BonusPoints, &c.Multiplier, &c.Active)
+	if endsAt.Valid {
+		c.EndsAt = &endsAt.Time
+	}
+	return c, err
+}
+
+func queryCampaigns(ctx context.Context, q queryer, query string, args ...any) ([]models.Campaign, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var campaigns []models.Campaign
+	for rows.Next() {
+		c, err := scanCampaign(rows)
+		if err != nil {
+			return nil, err
+		}
+		campaigns = append(campaigns, c)
+	}
+	return campaigns, rows.Err()
+}
+
+// applyCampaigns начисляет бонусы всех действующих акций за заказ, перешедший
+// в статус PROCESSED. Повторная обработка заказа бонус не дублирует.
+func applyCampaigns(ctx context.Context, tx *sql.Tx, userID int, accrual *models.AccrualResponse, policy models.CreditPolicy) error {
+	campaigns, err := queryCampaigns(ctx, tx, `
+        SELECT `+campaignColumns+`
+        FROM campaigns
+        WHERE active AND starts_at <= NOW() AND (ends_at IS NULL OR ends_at > NOW());
+    `)
+	if err != nil || len(campaigns) == 0 {
+		return err
+	}
+
+	var previous int
+	err = tx.QueryRowContext(ctx, `
+        SELECT COUNT(*) FROM orders WHERE user_id = $1 AND status = $2 AND number <> $3;
+    `, userID, models.OrderProcessed, accrual.Order).Scan(&previous)
+	if err != nil {
+		return err
+	}
+
+	// Акция, привязанная к магазину, действует только на заказы, которые
+	// зарегистрировал этот мерчант.
+	var merchant string
+	err = tx.QueryRowContext(ctx, `
+        SELECT COALESCE(m.name, '') FROM orders o LEFT JOIN merchants m ON m.id = o.merchant_id WHERE o.number = $1;
+    `, accrual.Order).Scan(&merchant)
+	if err != nil {
+		return err
+	}
+
+	facts := campaign.OrderFacts{
+		UserID:         userID,
+		Number:         accrual.Order,
+		Merchant:       merchant,
+		Accrual:        accrual.Accrual,
+		ProcessedAt:    time.Now(),
+		FirstProcessed: previous == 0,
+	}
+	for _, c := range campaigns {
+		bonus := campaign.Evaluate(c, facts)
+		if bonus <= 0 {
+			continue
+		}
+		res, err := tx.ExecContext(ctx, `
+            INSERT INTO campaign_bonuses (campaign_id, user_id, order_number, amount, created_at)
+            VALUES ($1, $2, $3, $4, NOW())
+            ON CONFLICT (campaign_id, order_number) DO NOTHING;
+        `, c.ID, userID, accrual.Order, bonus)
+		if err != nil {
+			return err
+		}
+		inserted, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if inserted == 0 {
+			continue
+		}
+		if err := creditPoints(ctx, tx, userID, accrual.Order, models.LotSourceCampaign, bonus, policy.LifetimeMonths); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *PgStorage) ListCampaigns(ctx context.Context) ([]models.Campaign, error) {
+	campaigns, err := queryCampaigns(ctx, db, `
+		SELECT `+campaignColumns+`
+		FROM campaigns
+		ORDER BY starts_at DESC, id DESC
+	`)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	return campaigns, nil
+}
+
+func (db *PgStorage) GetCampaign(ctx context.Context, id int) (models.Campaign, error) {
+	c, err := scanCampaign(db.QueryRowContext(ctx, `
+		SELECT `+campaignColumns+`
+		FROM campaigns
+		WHERE id = $1
+	`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c, models.ErrCampaignNotFound
+		}
+		logger.Log.Error(err.Error())
+		return c, err
+	}
+	return c, nil
+}
+
+func (db *PgStorage) CreateCampaign(ctx context.Context, c *models.Campaign) error {
+	err := db.QueryRowContext(ctx, `
+		INSERT INTO campaigns (name, merchant, starts_at, ends_at, first_order_only, order_prefix, bonus_points, multiplier, active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`, c.Name, c.Merchant, c.StartsAt, c.EndsAt, c.FirstOrderOnly, c.OrderPrefix, c.BonusPoints, c.Multiplier, c.Active).Scan(&c.ID)
+	if err != nil {
+		logger.Log.Error(err.Error())
+	}
+	return err
+}
+
+func (db *PgStorage) UpdateCampaign(ctx context.Context, c *models.Campaign) error {
+	res, err := db.ExecContext(ctx, `
+		UPDATE campaigns
+		SET name = $2, merchant = $3, starts_at = $4, ends_at = $5, first_order_only = $6,
+			order_prefix = $7, bonus_points = $8, multiplier = $9, active = $10
+		WHERE id = $1
+	`, c.ID, c.Name, c.Merchant, c.StartsAt, c.EndsAt, c.FirstOrderOnly, c.OrderPrefix, c.BonusPoints, c.Multiplier, c.Active)
+	return campaignAffected(res, err)
+}
+
+func (db *PgStorage) DeactivateCampaign(ctx context.Context, id int) error {
+	res, err := db.ExecContext(ctx, `
+		UPDATE campaigns SET active = FALSE WHERE id = $1
+	`, id)
+	return campaignAffected(res, err)
+}
+
+func campaignAffected(res sql.Result, err error) error {
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return models.ErrCampaignNotFound
+	}
+	return nil
+}
+
+// GetProcessedOrderFacts возвращает обработанные за период заказы в том
+// виде, в каком их видит applyCampaigns: с магазином, зарегистрировавшим
+// заказ, и с начислением до множителя уровня.
+func (db *PgStorage) GetProcessedOrderFacts(ctx context.Context, from, to time.Time) ([]campaign.OrderFacts, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT user_id, number, merchant, accrual, processed_at, first_processed
+		FROM (
+			SELECT o.user_id, o.number, COALESCE(m.name, '') AS merchant,
+				COALESCE(o.base_accrual, o.accrual, 0) AS accrual,
+				COALESCE(o.processed_at, o.uploaded_at) AS processed_at,
+				ROW_NUMBER() OVER (PARTITION BY o.user_id ORDER BY COALESCE(o.processed_at, o.uploaded_at), o.id) = 1 AS first_processed
+			FROM orders o
+			LEFT JOIN merchants m ON m.id = o.merchant_id
+			WHERE o.status = $1
+		) o
+		WHERE processed_at >= $2 AND processed_at < $3
+		ORDER BY processed_at
+	`, models.OrderProcessed, from, to)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var facts []campaign.OrderFacts
+	for rows.Next() {
+		var f campaign.OrderFacts
+		if err := rows.Scan(&f.UserID, &f.Number, &f.Merchant, &f.Accrual, &f.ProcessedAt, &f.FirstProcessed); err != nil {
+			logger.Log.Error(err.Error())
+			return nil, err
+		}
+		facts = append(facts, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+
+	return facts, nil
+}
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/campaign"
+	"github.com/scoring-service/pkg/models"
+)
+
+var campaignRowColumns = []string{"id", "name", "merchant", "starts_at", "ends_at", "first_order_only", "order_prefix", "bonus_points", "multiplier", "active"}
+
+func TestApplyCampaigns(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	accrual := &models.AccrualResponse{Order: "12345678903", Status: models.OrderProcessed, Accrual: 100}
+	policy := models.CreditPolicy{LifetimeMonths: 12}
+	start := time.Now().Add(-time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM campaigns WHERE active`)).
+		WillReturnRows(sqlmock.NewRows(campaignRowColumns).
+			AddRow(1, "weekend", "", start, nil, false, "", 0.0, 2.0, true).
+			AddRow(2, "welcome", "", start, nil, true, "", 100.0, 1.0, true).
+			AddRow(3, "shop", "acme", start, nil, false, "9999", 50.0, 1.0, true).
+			AddRow(4, "other shop", "globex", start, nil, false, "", 50.0, 1.0, true))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM orders WHERE user_id = $1 AND status = $2 AND number <> $3;`)).
+		WithArgs(42, models.OrderProcessed, accrual.Order).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta(`LEFT JOIN merchants m ON m.id = o.merchant_id WHERE o.number = $1`)).
+		WithArgs(accrual.Order).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("acme"))
+
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO campaign_bonuses`)).
+		WithArgs(1, 42, accrual.Order, 100.0).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`SET current_balance = current_balance + $1`)).
+		WithArgs(100.0, 42).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO accrual_lots`)).
+		WithArgs(42, accrual.Order, models.LotSourceCampaign, 100.0, 12).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Бонус за первый заказ уже был начислен при предыдущей обработке.
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO campaign_bonuses`)).
+		WithArgs(2, 42, accrual.Order, 100.0).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	require.NoError(t, applyCampaigns(ctx, tx, 42, accrual, policy))
+	require.NoError(t, tx.Commit())
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestGetCampaign(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	ctx := context.Background()
+	start := time.Date(2025, 5, 3, 0, 0, 0, 0, time.UTC)
+	end := start.Add(48 * time.Hour)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM campaigns WHERE id = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(campaignRowColumns).
+			AddRow(1, "weekend", "", start, end, false, "", 0.0, 2.0, true))
+
+	c, err := store.GetCampaign(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "weekend", c.Name)
+	require.NotNil(t, c.EndsAt)
+	assert.Equal(t, end, *c.EndsAt)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM campaigns WHERE id = $1`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows(campaignRowColumns))
+
+	_, err = store.GetCampaign(ctx, 2)
+	assert.ErrorIs(t, err, models.ErrCampaignNotFound)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestDeactivateCampaign(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE campaigns SET active = FALSE WHERE id = $1`)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, store.DeactivateCampaign(context.Background(), 1))
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE campaigns SET active = FALSE WHERE id = $1`)).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, store.DeactivateCampaign(context.Background(), 2), models.ErrCampaignNotFound)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// Пробный расчёт должен видеть заказы так же, как начисление: с магазином,
+// зарегистрировавшим заказ, и с начислением до множителя уровня.
+func TestGetProcessedOrderFacts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	at := from.Add(time.Hour)
+
+	columns := regexp.QuoteMeta(`COALESCE(m.name, '') AS merchant, COALESCE(o.base_accrual, o.accrual, 0) AS accrual,`)
+	join := regexp.QuoteMeta(`LEFT JOIN merchants m ON m.id = o.merchant_id`)
+	mock.ExpectQuery(columns+`.*`+join).
+		WithArgs(models.OrderProcessed, from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "number", "merchant", "accrual", "processed_at", "first_processed"}).
+			AddRow(1, "12345678903", "acme", 100.0, at, true).
+			AddRow(2, "2377225624", "", 200.0, at, false))
+
+	facts, err := store.GetProcessedOrderFacts(context.Background(), from, to)
+	require.NoError(t, err)
+	require.Equal(t, []campaign.OrderFacts{
+		{UserID: 1, Number: "12345678903", Merchant: "acme", Accrual: 100, ProcessedAt: at, FirstProcessed: true},
+		{UserID: 2, Number: "2377225624", Accrual: 200, ProcessedAt: at},
+	}, facts)
+
+	// Акция магазина находит его заказ, а множитель считается от базового
+	// начисления, а не от увеличенного уровнем.
+	merchantBonus := models.Campaign{Active: true, StartsAt: from, Merchant: "acme", BonusPoints: 25}
+	assert.Equal(t, 25.0, campaign.Evaluate(merchantBonus, facts[0]))
+	assert.Equal(t, 0.0, campaign.Evaluate(merchantBonus, facts[1]))
+	double := models.Campaign{Active: true, StartsAt: from, Multiplier: 2}
+	assert.Equal(t, 200.0, campaign.Evaluate(double, facts[1]))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
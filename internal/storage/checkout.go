@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// CreateCheckoutCode сохраняет новый код покупателя; прежний действующий код
+// при этом гаснет. Хеш истёкшего или погашенного кода может быть выдан
+// повторно, совпадение с действующим кодом возвращает ErrCheckoutCodeTaken.
+func (db *PgStorage) CreateCheckoutCode(ctx context.Context, userID int, codeHash string, ttl time.Duration) (time.Time, error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE checkout_codes SET expires_at = NOW()
+        WHERE user_id = $1 AND redeemed_at IS NULL AND expires_at > NOW();
+    `, userID)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return time.Time{}, err
+	}
+
+	var expiresAt time.Time
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO checkout_codes AS c (user_id, code_hash, created_at, expires_at)
+        VALUES ($1, $2, NOW(), NOW() + make_interval(secs => $3::float8))
+        ON CONFLICT (code_hash) DO UPDATE SET
+            user_id = EXCLUDED.user_id,
+            created_at = EXCLUDED.created_at,
+            expires_at = EXCLUDED.expires_at,
+            redeemed_at = NULL,
+            merchant_id = NULL
+        WHERE c.expires_at <= NOW() OR c.redeemed_at IS NOT NULL
+        RETURNING expires_at;
+    `, userID, codeHash, ttl.Seconds()).Scan(&expiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, models.ErrCheckoutCodeTaken
+		}
+		logger.Log.Error(err.Error())
+		return time.Time{}, err
+	}
+
+	return expiresAt, tx.Commit()
+}
+
+// RedeemCheckoutCode гасит действующий код и возвращает его владельца.
+// Заблокированным покупателям код не погашается.
+func (db *PgStorage) RedeemCheckoutCode(ctx context.Context, merchantID int, codeHash string) (int, error) {
+	var userID int
+	err := db.QueryRowContext(ctx, `
+        UPDATE checkout_codes c SET redeemed_at = NOW(), merchant_id = $2
+        FROM users u
+        WHERE c.code_hash = $1 AND c.redeemed_at IS NULL AND c.expires_at > NOW()
+            AND u.id = c.user_id AND u.blocked_at IS NULL
+        RETURNING c.user_id;
+    `, codeHash, merchantID).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, models.ErrInvalidCheckoutCode
+		}
+		logger.Log.Error(err.Error())
+		return 0, err
+	}
+	return userID, nil
+}
+
+// ReleaseCheckoutCode возвращает код, погашенный этим мерчантом, в
+// действующие, если списание или заказ по нему не прошли. Истёкший код так
+// и остаётся недействительным.
+func (db *PgStorage) ReleaseCheckoutCode(ctx context.Context, merchantID int, codeHash string) error {
+	_, err := db.ExecContext(ctx, `
+        UPDATE checkout_codes SET redeemed_at = NULL, merchant_id = NULL
+        WHERE code_hash = $1 AND merchant_id = $2 AND redeemed_at IS NOT NULL;
+    `, codeHash, merchantID)
+	if err != nil {
+		logger.Log.Error(err.Error())
+	}
+	return err
+}
+
+func (db *PgStorage) GetCheckoutThrottle(ctx context.Context, merchantID int) (models.LoginThrottle, error) {
+	return db.getThrottle(ctx, models.ThrottleScopeCheckout, strconv.Itoa(merchantID))
+}
+
+// RecordCheckoutFailure учитывает неверный код, введённый мерчантом. Счётчик
+// не сбрасывается удачным вводом, чтобы перебор нельзя было разбавлять
+// настоящими кодами.
+func (db *PgStorage) RecordCheckoutFailure(ctx context.Context, merchantID int, policy models.LoginPolicy) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = registerFailure(ctx, tx, models.ThrottleScopeCheckout, strconv.Itoa(merchantID), policy.MaxFailures, policy.Lockout, policy.Window)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+
+	return tx.Commit()
+}
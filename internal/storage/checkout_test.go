@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestCreateCheckoutCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	expireQuery := regexp.QuoteMeta(`UPDATE checkout_codes SET expires_at = NOW()`)
+	insertQuery := regexp.QuoteMeta(`INSERT INTO checkout_codes AS c (user_id, code_hash, created_at, expires_at)`)
+	expiresAt := time.Now().Add(5 * time.Minute)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(expireQuery).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(insertQuery).WithArgs(1, "hash", 300.0).
+		WillReturnRows(sqlmock.NewRows([]string{"expires_at"}).AddRow(expiresAt))
+	mock.ExpectCommit()
+
+	got, err := store.CreateCheckoutCode(context.Background(), 1, "hash", 5*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, expiresAt, got)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(expireQuery).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(insertQuery).WithArgs(2, "hash", 300.0).
+		WillReturnRows(sqlmock.NewRows([]string{"expires_at"}))
+	mock.ExpectRollback()
+
+	_, err = store.CreateCheckoutCode(context.Background(), 2, "hash", 5*time.Minute)
+	assert.ErrorIs(t, err, models.ErrCheckoutCodeTaken)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestRedeemCheckoutCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	query := regexp.QuoteMeta(`UPDATE checkout_codes c SET redeemed_at = NOW(), merchant_id = $2`)
+
+	mock.ExpectQuery(query).WithArgs("hash", 3).WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(5))
+	userID, err := store.RedeemCheckoutCode(context.Background(), 3, "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, userID)
+
+	mock.ExpectQuery(query).WithArgs("hash", 3).WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+	_, err = store.RedeemCheckoutCode(context.Background(), 3, "hash")
+	assert.ErrorIs(t, err, models.ErrInvalidCheckoutCode)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestReleaseCheckoutCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE checkout_codes SET redeemed_at = NULL, merchant_id = NULL WHERE code_hash = $1 AND merchant_id = $2`)).
+		WithArgs("hash", 3).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, store.ReleaseCheckoutCode(context.Background(), 3, "hash"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestCheckoutThrottle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM login_throttle`)).WithArgs(models.ThrottleScopeCheckout, "3").
+		WillReturnRows(sqlmock.NewRows([]string{"failures", "last_failure_at", "locked_until"}).AddRow(4, now, now))
+	got, err := store.GetCheckoutThrottle(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, models.LoginThrottle{Scope: models.ThrottleScopeCheckout, Key: "3", Failures: 4, LastFailureAt: now, LockedUntil: now}, got)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO login_throttle AS t`)).
+		WithArgs(models.ThrottleScopeCheckout, "3", 10, 900.0, 3600.0).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, store.RecordCheckoutFailure(context.Background(), 3,
+		models.LoginPolicy{MaxFailures: 10, Lockout: 15 * time.Minute, Window: time.Hour}))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
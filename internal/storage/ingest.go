@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// StartIngestionRun регистрирует загрузку файла. Файл с той же контрольной
+// суммой, уже загруженный успешно, возвращает ErrAlreadyIngested; прерванная
+// или неудачная загрузка начинается заново под тем же идентификатором.
+func (db *PgStorage) StartIngestionRun(ctx context.Context, fileName, checksum string) (int, error) {
+	var runID int
+	err := db.QueryRowContext(ctx, `
+        INSERT INTO ingestion_runs AS r (file_name, checksum, status, started_at)
+        VALUES ($1, $2, $3, NOW())
+        ON CONFLICT (checksum) DO UPDATE SET
+            file_name = EXCLUDED.file_name,
+            status = EXCLUDED.status,
+            lines = 0, imported = 0, skipped = 0, failed = 0, error = '',
+            started_at = NOW(),
+            finished_at = NULL
+        WHERE r.status <> $4
+        RETURNING id;
+    `, fileName, checksum, models.IngestionRunning, models.IngestionDone).Scan(&runID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, models.ErrAlreadyIngested
+		}
+		logger.Log.Error(err.Error())
+		return 0, err
+	}
+	return runID, nil
+}
+
+func (db *PgStorage) FinishIngestionRun(ctx context.Context, run models.IngestionRun) error {
+	_, err := db.ExecContext(ctx, `
+        UPDATE ingestion_runs
+        SET status = $2, lines = $3, imported = $4, skipped = $5, failed = $6, error = $7, finished_at = NOW()
+        WHERE id = $1;
+    `, run.ID, run.Status, run.Lines, run.Imported, run.Skipped, run.Failed, run.Error)
+	if err != nil {
+		logger.Log.Error(err.Error())
+	}
+	return err
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestStartIngestionRun(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	query := regexp.QuoteMeta(`INSERT INTO ingestion_runs AS r (file_name, checksum, status, started_at)`)
+
+	mock.ExpectQuery(query).WithArgs("orders.csv", "sum", models.IngestionRunning, models.IngestionDone).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	runID, err := store.StartIngestionRun(context.Background(), "orders.csv", "sum")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, runID)
+
+	mock.ExpectQuery(query).WithArgs("copy.csv", "sum", models.IngestionRunning, models.IngestionDone).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = store.StartIngestionRun(context.Background(), "copy.csv", "sum")
+	assert.ErrorIs(t, err, models.ErrAlreadyIngested)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestFinishIngestionRun(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE ingestion_runs`)).
+		WithArgs(4, models.IngestionDone, 3, 2, 1, 0, "").WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, store.FinishIngestionRun(context.Background(), models.IngestionRun{
+		ID: 4, Status: models.IngestionDone, Lines: 3, Imported: 2, Skipped: 1,
+	}))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// SaveOrderWithItems сохраняет заказ вместе с позициями чека, чтобы
+// локальный расчёт начислений не увидел заказ без позиций. Уже загруженный
+// заказ не меняется, и позиции к нему не добавляются: для своего заказа
+// возвращается ErrOrderExists, для чужого — ErrOrderConflict.
+func (db *PgStorage) SaveOrderWithItems(ctx context.Context, user int, order *models.Order, items []models.OrderItem) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+        INSERT INTO orders (user_id, number, status, accrual, uploaded_at)
+        VALUES ($1, $2, $3, $4, NOW())
+        ON CONFLICT (number) DO NOTHING;
+    `, user, order.Number, order.Status, sql.NullFloat64{Float64: order.Accrual, Valid: true})
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	if inserted == 0 {
+		var owner int
+		err = tx.QueryRowContext(ctx, `SELECT user_id FROM orders WHERE number = $1;`, order.Number).Scan(&owner)
+		if err != nil {
+			logger.Log.Error(err.Error())
+			return err
+		}
+		if owner != user {
+			return models.ErrOrderConflict
+		}
+		return models.ErrOrderExists
+	}
+
+	if len(items) > 0 {
+		args := make([]any, 0, len(items)*5+1)
+		args = append(args, order.Number)
+		values := make([]string, len(items))
+		for i, item := range items {
+			n := len(args)
+			values[i] = fmt.Sprintf("($1, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5)
+			args = append(args, item.Name, item.Category, item.Brand, item.Price, max(item.Quantity, 1))
+		}
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO order_items (order_number, name, category, brand, price, quantity)
+            VALUES `+strings.Join(values, ", ")+`;
+        `, args...)
+		if err != nil {
+			logger.Log.Error(err.Error())
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (db *PgStorage) GetOrderItems(ctx context.Context, orderNum string) ([]models.OrderItem, error) {
+	rows, err := db.QueryContext(ctx, `
+        SELECT name, category, brand, price, quantity
+        FROM order_items
+        WHERE order_number = $1
+        ORDER BY id;
+    `, orderNum)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.OrderItem
+	for rows.Next() {
+		var item models.OrderItem
+		if err := rows.Scan(&item.Name, &item.Category, &item.Brand, &item.Price, &item.Quantity); err != nil {
+			logger.Log.Error(err.Error())
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+
+	return items, nil
+}
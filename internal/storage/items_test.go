@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestSaveOrderWithItems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+
+	insert := regexp.QuoteMeta(`INSERT INTO orders (user_id, number, status, accrual, uploaded_at) VALUES ($1, $2, $3, $4, NOW()) ON CONFLICT (number) DO NOTHING;`)
+	mock.ExpectBegin()
+	mock.ExpectExec(insert).
+		WithArgs(1, "12345678903", models.OrderNew, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`VALUES ($1, $2, $3, $4, $5, $6), ($1, $7, $8, $9, $10, $11);`)).
+		WithArgs("12345678903", "Молоко", "dairy", "", 80.0, 2, "Хлеб", "", "", 40.0, 1).
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	mock.ExpectCommit()
+
+	err = store.SaveOrderWithItems(context.Background(), 1, &models.Order{Number: "12345678903", Status: models.OrderNew}, []models.OrderItem{
+		{Name: "Молоко", Category: "dairy", Price: 80, Quantity: 2},
+		{Name: "Хлеб", Price: 40},
+	})
+	assert.NoError(t, err)
+
+	// Заказ уже загружен: позиции не пишутся, владелец определяет ошибку.
+	for owner, want := range map[int]error{1: models.ErrOrderExists, 2: models.ErrOrderConflict} {
+		mock.ExpectBegin()
+		mock.ExpectExec(insert).
+			WithArgs(1, "12345678903", models.OrderNew, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT user_id FROM orders WHERE number = $1;`)).WithArgs("12345678903").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(owner))
+		mock.ExpectRollback()
+
+		err = store.SaveOrderWithItems(context.Background(), 1, &models.Order{Number: "12345678903", Status: models.OrderNew}, []models.OrderItem{
+			{Name: "Молоко", Price: 1000000},
+		})
+		assert.ErrorIs(t, err, want)
+	}
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestGetOrderItems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM order_items WHERE order_number = $1`)).WithArgs("12345678903").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "category", "brand", "price", "quantity"}).
+			AddRow("Молоко", "dairy", "acme", 80.0, 2))
+
+	items, err := store.GetOrderItems(context.Background(), "12345678903")
+	assert.NoError(t, err)
+	assert.Equal(t, []models.OrderItem{{Name: "Молоко", Category: "dairy", Brand: "acme", Price: 80, Quantity: 2}}, items)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
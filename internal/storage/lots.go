@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+type consumedLot struct {
+	amount    float64
+	expiresAt sql.NullTime
+}
+
+// consumeLots списывает sum с партий начислений пользователя в порядке их
+// поступления (FIFO) и возвращает списанные части.
+func consumeLots(ctx context.Context, tx *sql.Tx, userID int, sum float64) ([]consumedLot, error) {
+	rows, err := tx.QueryContext(ctx, `
+        UPDATE accrual_lots l
+        SET remaining = l.remaining - c.take
+        FROM (
+            SELECT id, LEAST(remaining, $2 - COALESCE(SUM(remaining) OVER (
+                ORDER BY accrued_at, id ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+            ), 0)) AS take
+            FROM accrual_lots
+            WHERE user_id = $1 AND remaining > 0
+        ) c
+        WHERE l.id = c.id AND c.take > 0
+        RETURNING c.take, l.expires_at;
+    `, userID, sum)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var consumed []consumedLot
+	for rows.Next() {
+		var lot consumedLot
+		if err := rows.Scan(&lot.amount, &lot.expiresAt); err != nil {
+			return nil, err
+		}
+		consumed = append(consumed, lot)
+	}
+	return consumed, rows.Err()
+}
+
+func (db *PgStorage) GetExpiringPoints(ctx context.Context, userID int) ([]models.ExpiringPoints, error) {
+	var expiring []models.ExpiringPoints
+
+	query := `
+		SELECT to_char(expires_at::date, 'YYYY-MM-DD'), SUM(remaining)
+		FROM accrual_lots
+		WHERE user_id = $1 AND remaining > 0 AND expires_at IS NOT NULL
+		GROUP BY expires_at::date
+		ORDER BY expires_at::date
+	`
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var points models.ExpiringPoints
+		if err := rows.Scan(&points.Date, &points.Amount); err != nil {
+			logger.Log.Error(err.Error())
+			return nil, err
+		}
+		expiring = append(expiring, points)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+
+	return expiring, nil
+}
+
+// ExpirePoints списывает остатки просроченных партий с баланса ровно на их
+// сумму. Если у кого-то баланс от этого ушёл бы ниже нуля, значит он уже
+// разошёлся с партиями: такое не скрывается, а откатывается целиком с
+// ErrExpiryOverdraft, чтобы расхождение разобрали сверкой. Партии LEGACY,
+// заведённые миграцией под балансы до появления сроков сгорания, не
+// сгорают: срок их баллов при начислении не назначался, и задним числом
+// он не вводится.
+func (db *PgStorage) ExpirePoints(ctx context.Context) (int64, error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+    WITH expired AS (
+        UPDATE accrual_lots
+        SET expired_amount = remaining, remaining = 0, expired_at = NOW()
+        WHERE expires_at <= NOW() AND remaining > 0
+        RETURNING user_id, expired_amount
+    ), totals AS (
+        SELECT user_id, SUM(expired_amount) AS amount
+        FROM expired
+        GROUP BY user_id
+    )
+    UPDATE users
+    SET current_balance = current_balance - totals.amount
+    FROM totals
+    WHERE users.id = totals.user_id
+    RETURNING users.id, users.current_balance;
+	`)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return 0, err
+	}
+	defer rows.Close()
+
+	var users int64
+	var overdrawn []int
+	for rows.Next() {
+		var userID int
+		var balance float64
+		if err := rows.Scan(&userID, &balance); err != nil {
+			logger.Log.Error(err.Error())
+			return 0, err
+		}
+		users++
+		if balance < 0 {
+			overdrawn = append(overdrawn, userID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		logger.Log.Error(err.Error())
+		return 0, err
+	}
+	if len(overdrawn) > 0 {
+		return 0, fmt.Errorf("%w: пользователи %v", models.ErrExpiryOverdraft, overdrawn)
+	}
+	return users, tx.Commit()
+}
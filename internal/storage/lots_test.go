@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestGetExpiringPoints(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	query := regexp.QuoteMeta(`SELECT to_char(expires_at::date, 'YYYY-MM-DD'), SUM(remaining) FROM accrual_lots`)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"date", "amount"}).
+				AddRow("2026-01-31", 120.5).
+				AddRow("2026-02-28", 40.0))
+
+		expiring, err := store.GetExpiringPoints(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, expiring, 2)
+		assert.Equal(t, "2026-01-31", expiring[0].Date)
+		assert.Equal(t, 120.5, expiring[0].Amount)
+	})
+
+	t.Run("QueryError", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(1).
+			WillReturnError(errors.New("db error"))
+
+		expiring, err := store.GetExpiringPoints(context.Background(), 1)
+		assert.Error(t, err)
+		assert.Nil(t, expiring)
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestExpirePoints(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	query := regexp.QuoteMeta(`WITH expired AS ( UPDATE accrual_lots SET expired_amount = remaining, remaining = 0, expired_at = NOW()`) +
+		`.+` + regexp.QuoteMeta(`SET current_balance = current_balance - totals.amount`) +
+		`.+` + regexp.QuoteMeta(`RETURNING users.id, users.current_balance;`)
+	columns := []string{"id", "current_balance"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 0.0).AddRow(2, 15.5).AddRow(3, 100.0))
+	mock.ExpectCommit()
+
+	users, err := store.ExpirePoints(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), users)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 10.0).AddRow(4, -20.0))
+	mock.ExpectRollback()
+
+	_, err = store.ExpirePoints(context.Background())
+	require.ErrorIs(t, err, models.ErrExpiryOverdraft)
+	assert.Contains(t, err.Error(), "[4]")
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,271 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// CreateMerchant заводит учётную запись мерчанта с ролью merchant, без
+// пароля, и первый API-ключ к ней.
+func (db *PgStorage) CreateMerchant(ctx context.Context, adminID int, login string, merchant *models.Merchant, keyHash string) (int, error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO users (login, password_hash, role)
+        VALUES ($1, '', $2)
+        ON CONFLICT (login) DO NOTHING
+        RETURNING id;
+    `, login, models.RoleMerchant).Scan(&merchant.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, models.ErrLoginTaken
+		}
+		logger.Log.Error(err.Error())
+		return 0, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO merchants (user_id, name, rate_limit, hmac_secret, created_at)
+        VALUES ($1, $2, $3, NULLIF($4, ''), NOW())
+        RETURNING id, created_at;
+    `, merchant.UserID, merchant.Name, merchant.RateLimit, merchant.HMACSecret).Scan(&merchant.ID, &merchant.CreatedAt)
+	if err != nil {
+		// По названию к мерчанту привязываются акции, поэтому оно уникально.
+		if isUniqueViolation(err, "merchants_name_key") {
+			return 0, models.ErrMerchantNameTaken
+		}
+		logger.Log.Error(err.Error())
+		return 0, err
+	}
+
+	keyID, err := insertMerchantKey(ctx, tx, merchant.ID, keyHash)
+	if err != nil {
+		return 0, err
+	}
+	err = logAdminAction(ctx, tx, models.AdminAction{
+		AdminID:      adminID,
+		Action:       models.AdminActionMerchantCreate,
+		TargetUserID: merchant.UserID,
+		Details:      merchant.Name,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return keyID, tx.Commit()
+}
+
+func insertMerchantKey(ctx context.Context, tx *sql.Tx, merchantID int, keyHash string) (int, error) {
+	var keyID int
+	err := tx.QueryRowContext(ctx, `
+        INSERT INTO merchant_api_keys (merchant_id, key_hash, created_at)
+        VALUES ($1, $2, NOW())
+        RETURNING id;
+    `, merchantID, keyHash).Scan(&keyID)
+	if err != nil {
+		logger.Log.Error(err.Error())
+	}
+	return keyID, err
+}
+
+func lockMerchant(ctx context.Context, tx *sql.Tx, merchantID int) (int, error) {
+	var userID int
+	err := tx.QueryRowContext(ctx, `
+        SELECT user_id FROM merchants WHERE id = $1 FOR UPDATE;
+    `, merchantID).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, models.ErrMerchantNotFound
+	}
+	return userID, err
+}
+
+// AddMerchantAPIKey выпускает мерчанту дополнительный ключ, чтобы старый
+// можно было отозвать после перехода на новый.
+func (db *PgStorage) AddMerchantAPIKey(ctx context.Context, adminID, merchantID int, keyHash string) (int, error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	userID, err := lockMerchant(ctx, tx, merchantID)
+	if err != nil {
+		return 0, err
+	}
+	keyID, err := insertMerchantKey(ctx, tx, merchantID, keyHash)
+	if err != nil {
+		return 0, err
+	}
+	err = logAdminAction(ctx, tx, models.AdminAction{
+		AdminID:      adminID,
+		Action:       models.AdminActionMerchantKey,
+		TargetUserID: userID,
+		Details:      fmt.Sprintf("key %d", keyID),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return keyID, tx.Commit()
+}
+
+func (db *PgStorage) RevokeMerchantAPIKey(ctx context.Context, adminID, merchantID, keyID int) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	userID, err := lockMerchant(ctx, tx, merchantID)
+	if err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, `
+        UPDATE merchant_api_keys SET revoked_at = NOW()
+        WHERE id = $1 AND merchant_id = $2 AND revoked_at IS NULL;
+    `, keyID, merchantID)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		if err == nil {
+			err = models.ErrInvalidAPIKey
+		}
+		return err
+	}
+	err = logAdminAction(ctx, tx, models.AdminAction{
+		AdminID:      adminID,
+		Action:       models.AdminActionMerchantRevoke,
+		TargetUserID: userID,
+		Details:      fmt.Sprintf("key %d", keyID),
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetMerchantByAPIKey находит мерчанта по хешу действующего ключа.
+// Ключи заблокированных учётных записей не принимаются.
+func (db *PgStorage) GetMerchantByAPIKey(ctx context.Context, keyHash string) (*models.Merchant, error) {
+	var m models.Merchant
+	err := db.QueryRowContext(ctx, `
+        SELECT m.id, m.user_id, m.name, m.rate_limit, COALESCE(m.hmac_secret, ''), m.created_at
+        FROM merchant_api_keys k
+        JOIN merchants m ON m.id = k.merchant_id
+        JOIN users u ON u.id = m.user_id
+        WHERE k.key_hash = $1 AND k.revoked_at IS NULL AND u.blocked_at IS NULL;
+    `, keyHash).Scan(&m.ID, &m.UserID, &m.Name, &m.RateLimit, &m.HMACSecret, &m.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrInvalidAPIKey
+		}
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	return &m, nil
+}
+
+// AttachOrderToMerchant помечает заказ как зарегистрированный мерчантом и
+// сообщает, привязан ли заказ к нему. Привязывается только заказ без
+// мерчанта, загруженный не раньше чем window назад, — то есть созданный
+// этим же запросом или его повтором; иначе мерчант, знающий логин и номер
+// заказа, присвоил бы себе чужой заказ. Заказ, уже привязанный к этому
+// мерчанту, считается привязанным.
+func (db *PgStorage) AttachOrderToMerchant(ctx context.Context, merchantID int, orderNum string, window time.Duration) (bool, error) {
+	res, err := db.ExecContext(ctx, `
+        UPDATE orders SET merchant_id = $1
+        WHERE number = $2
+            AND (merchant_id = $1 OR (merchant_id IS NULL AND uploaded_at > NOW() - make_interval(secs => $3::float8)));
+    `, merchantID, orderNum, window.Seconds())
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// CreateCustomerToken сохраняет хеш токена покупателя для загрузки заказа
+// мерчантом.
+func (db *PgStorage) CreateCustomerToken(ctx context.Context, userID int, tokenHash string, ttl time.Duration) (time.Time, error) {
+	var expiresAt time.Time
+	err := db.QueryRowContext(ctx, `
+        INSERT INTO customer_tokens (user_id, token_hash, created_at, expires_at)
+        VALUES ($1, $2, NOW(), NOW() + make_interval(secs => $3::float8))
+        RETURNING expires_at;
+    `, userID, tokenHash, ttl.Seconds()).Scan(&expiresAt)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return time.Time{}, err
+	}
+	return expiresAt, nil
+}
+
+// ConsumeCustomerToken гасит действующий токен и возвращает покупателя.
+// Токены заблокированных и служебных учётных записей не принимаются.
+func (db *PgStorage) ConsumeCustomerToken(ctx context.Context, merchantID int, tokenHash string) (int, error) {
+	var userID int
+	err := db.QueryRowContext(ctx, `
+        UPDATE customer_tokens t SET used_at = NOW(), merchant_id = $2
+        FROM users u
+        WHERE t.token_hash = $1 AND t.used_at IS NULL AND t.expires_at > NOW()
+            AND u.id = t.user_id AND u.blocked_at IS NULL AND u.role = $3
+        RETURNING t.user_id;
+    `, tokenHash, merchantID, models.RoleCustomer).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, models.ErrInvalidCustomerToken
+		}
+		logger.Log.Error(err.Error())
+		return 0, err
+	}
+	return userID, nil
+}
+
+// RegisterMerchantNonce запоминает X-Nonce подписанного запроса мерчанта.
+// Повтор в пределах ttl возвращает ErrRequestReplayed; более старые значения
+// удаляются, их отсекает проверка метки времени.
+func (db *PgStorage) RegisterMerchantNonce(ctx context.Context, merchantID int, nonce string, ttl time.Duration) error {
+	_, err := db.ExecContext(ctx, `
+        DELETE FROM merchant_request_nonces
+        WHERE merchant_id = $1 AND received_at < NOW() - make_interval(secs => $2::float8);
+    `, merchantID, ttl.Seconds())
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	res, err := db.ExecContext(ctx, `
+        INSERT INTO merchant_request_nonces (merchant_id, nonce, received_at)
+        VALUES ($1, $2, NOW())
+        ON CONFLICT (merchant_id, nonce) DO NOTHING;
+    `, merchantID, nonce)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	if n == 0 {
+		return models.ErrRequestReplayed
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestCreateMerchant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	userQuery := regexp.QuoteMeta(`INSERT INTO users (login, password_hash, role)`)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(userQuery).WithArgs("shop", models.RoleMerchant).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO merchants (user_id, name, rate_limit, hmac_secret, created_at)`)).
+		WithArgs(7, "Shop", 60, "secret").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, now))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO merchant_api_keys (merchant_id, key_hash, created_at)`)).
+		WithArgs(3, "hash").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO admin_actions`)).
+		WithArgs(1, models.AdminActionMerchantCreate, 7, "", "Shop").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	merchant := models.Merchant{Name: "Shop", RateLimit: 60, HMACSecret: "secret"}
+	keyID, err := store.CreateMerchant(context.Background(), 1, "shop", &merchant, "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, 11, keyID)
+	assert.Equal(t, models.Merchant{ID: 3, UserID: 7, Name: "Shop", RateLimit: 60, HMACSecret: "secret", CreatedAt: now}, merchant)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(userQuery).WithArgs("taken", models.RoleMerchant).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	_, err = store.CreateMerchant(context.Background(), 1, "taken", &models.Merchant{Name: "Other"}, "hash2")
+	assert.ErrorIs(t, err, models.ErrLoginTaken)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(userQuery).WithArgs("shop2", models.RoleMerchant).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO merchants (user_id, name, rate_limit, hmac_secret, created_at)`)).
+		WithArgs(8, "Shop", 0, "").
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "merchants_name_key"})
+	mock.ExpectRollback()
+
+	_, err = store.CreateMerchant(context.Background(), 1, "shop2", &models.Merchant{Name: "Shop"}, "hash3")
+	assert.ErrorIs(t, err, models.ErrMerchantNameTaken)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestRevokeMerchantAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	lockQuery := regexp.QuoteMeta(`SELECT user_id FROM merchants WHERE id = $1 FOR UPDATE;`)
+	revokeQuery := regexp.QuoteMeta(`UPDATE merchant_api_keys SET revoked_at = NOW()`)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockQuery).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(7))
+	mock.ExpectExec(revokeQuery).WithArgs(11, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO admin_actions`)).
+		WithArgs(1, models.AdminActionMerchantRevoke, 7, "", "key 11").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, store.RevokeMerchantAPIKey(context.Background(), 1, 3, 11))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockQuery).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(7))
+	mock.ExpectExec(revokeQuery).WithArgs(12, 3).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	assert.ErrorIs(t, store.RevokeMerchantAPIKey(context.Background(), 1, 3, 12), models.ErrInvalidAPIKey)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockQuery).WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+	mock.ExpectRollback()
+	assert.ErrorIs(t, store.RevokeMerchantAPIKey(context.Background(), 1, 4, 11), models.ErrMerchantNotFound)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestGetMerchantByAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	query := regexp.QuoteMeta(`WHERE k.key_hash = $1 AND k.revoked_at IS NULL AND u.blocked_at IS NULL;`)
+	columns := []string{"id", "user_id", "name", "rate_limit", "hmac_secret", "created_at"}
+	now := time.Now()
+
+	mock.ExpectQuery(query).WithArgs("hash").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(3, 7, "Shop", 60, "", now))
+	got, err := store.GetMerchantByAPIKey(context.Background(), "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, &models.Merchant{ID: 3, UserID: 7, Name: "Shop", RateLimit: 60, CreatedAt: now}, got)
+
+	mock.ExpectQuery(query).WithArgs("unknown").WillReturnRows(sqlmock.NewRows(columns))
+	_, err = store.GetMerchantByAPIKey(context.Background(), "unknown")
+	assert.ErrorIs(t, err, models.ErrInvalidAPIKey)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestConsumeCustomerToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	query := regexp.QuoteMeta(`UPDATE customer_tokens t SET used_at = NOW(), merchant_id = $2`)
+
+	mock.ExpectQuery(query).WithArgs("hash", 3, models.RoleCustomer).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(5))
+	userID, err := store.ConsumeCustomerToken(context.Background(), 3, "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, userID)
+
+	mock.ExpectQuery(query).WithArgs("hash", 3, models.RoleCustomer).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+	_, err = store.ConsumeCustomerToken(context.Background(), 3, "hash")
+	assert.ErrorIs(t, err, models.ErrInvalidCustomerToken)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestRegisterMerchantNonce(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	cleanup := regexp.QuoteMeta(`DELETE FROM merchant_request_nonces`)
+	insert := regexp.QuoteMeta(`ON CONFLICT (merchant_id, nonce) DO NOTHING`)
+
+	mock.ExpectExec(cleanup).WithArgs(3, float64(600)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(insert).WithArgs(3, "nonce").WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, store.RegisterMerchantNonce(context.Background(), 3, "nonce", 10*time.Minute))
+
+	mock.ExpectExec(cleanup).WithArgs(3, float64(600)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(insert).WithArgs(3, "nonce").WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, store.RegisterMerchantNonce(context.Background(), 3, "nonce", 10*time.Minute), models.ErrRequestReplayed)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestAttachOrderToMerchant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	query := regexp.QuoteMeta(`AND (merchant_id = $1 OR (merchant_id IS NULL AND uploaded_at > NOW() - make_interval(secs => $3::float8)));`)
+
+	mock.ExpectExec(query).WithArgs(3, "12345678903", 300.0).WillReturnResult(sqlmock.NewResult(0, 1))
+	attached, err := store.AttachOrderToMerchant(context.Background(), 3, "12345678903", 5*time.Minute)
+	require.NoError(t, err)
+	assert.True(t, attached)
+
+	mock.ExpectExec(query).WithArgs(3, "12345678903", 300.0).WillReturnResult(sqlmock.NewResult(0, 0))
+	attached, err = store.AttachOrderToMerchant(context.Background(), 3, "12345678903", 5*time.Minute)
+	require.NoError(t, err)
+	assert.False(t, attached)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// GetNotificationPreferences возвращает настройки уведомлений пользователя.
+// Если пользователь их не сохранял, возвращаются настройки по умолчанию.
+func (db *PgStorage) GetNotificationPreferences(ctx context.Context, userID int) (models.NotificationPreferences, error) {
+	var prefs models.NotificationPreferences
+	err := db.QueryRowContext(ctx, `
+        SELECT u.login, COALESCE(p.email, ''), COALESCE(p.pending_email, ''), COALESCE(p.locale, 'ru'),
+            COALESCE(p.points_credited, TRUE), COALESCE(p.points_withdrawn, TRUE)
+        FROM users u
+        LEFT JOIN notification_preferences p ON p.user_id = u.id
+        WHERE u.id = $1 AND u.deleted_at IS NULL;
+    `, userID).Scan(&prefs.Login, &prefs.Email, &prefs.PendingEmail, &prefs.Locale, &prefs.PointsCredited, &prefs.PointsWithdrawn)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.NotificationPreferences{}, models.ErrUserNotFound
+	}
+	if err != nil {
+		logger.Log.Error(err.Error())
+	}
+	return prefs, err
+}
+
+// SaveNotificationPreferences сохраняет настройки вместе с токеном
+// подтверждения PendingEmail. Пустой tokenHash сбрасывает прежний токен.
+func (db *PgStorage) SaveNotificationPreferences(ctx context.Context, userID int, prefs models.NotificationPreferences, tokenHash string, ttl time.Duration) error {
+	_, err := db.ExecContext(ctx, `
+        INSERT INTO notification_preferences (user_id, email, pending_email, locale, points_credited, points_withdrawn,
+            email_token_hash, email_token_expires_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''),
+            CASE WHEN $7 = '' THEN NULL ELSE NOW() + $8 * INTERVAL '1 second' END, NOW())
+        ON CONFLICT (user_id) DO UPDATE
+        SET email = EXCLUDED.email, pending_email = EXCLUDED.pending_email, locale = EXCLUDED.locale,
+            points_credited = EXCLUDED.points_credited, points_withdrawn = EXCLUDED.points_withdrawn,
+            email_token_hash = EXCLUDED.email_token_hash, email_token_expires_at = EXCLUDED.email_token_expires_at,
+            updated_at = NOW();
+    `, userID, prefs.Email, prefs.PendingEmail, prefs.Locale, prefs.PointsCredited, prefs.PointsWithdrawn,
+		tokenHash, ttl.Seconds())
+	if err != nil {
+		logger.Log.Error(err.Error())
+	}
+	return err
+}
+
+// ConfirmNotificationEmail делает PendingEmail адресом для писем, если
+// токен подтверждения верен и не просрочен.
+func (db *PgStorage) ConfirmNotificationEmail(ctx context.Context, userID int, tokenHash string) error {
+	res, err := db.ExecContext(ctx, `
+        UPDATE notification_preferences
+        SET email = pending_email, pending_email = '',
+            email_token_hash = NULL, email_token_expires_at = NULL, updated_at = NOW()
+        WHERE user_id = $1 AND pending_email <> ''
+            AND email_token_hash = $2 AND email_token_expires_at > NOW();
+    `, userID, tokenHash)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	if n == 0 {
+		return models.ErrInvalidEmailToken
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestGetNotificationPreferences(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	query := regexp.QuoteMeta(`LEFT JOIN notification_preferences p ON p.user_id = u.id`)
+
+	mock.ExpectQuery(query).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"login", "email", "pending_email", "locale", "points_credited", "points_withdrawn"}).
+			AddRow("alice", "alice@example.com", "new@example.com", models.LocaleEN, true, false))
+	prefs, err := store.GetNotificationPreferences(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, models.NotificationPreferences{
+		Login:          "alice",
+		Email:          "alice@example.com",
+		PendingEmail:   "new@example.com",
+		Locale:         models.LocaleEN,
+		PointsCredited: true,
+	}, prefs)
+
+	mock.ExpectQuery(query).WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"login", "email", "pending_email", "locale", "points_credited", "points_withdrawn"}))
+	_, err = store.GetNotificationPreferences(context.Background(), 2)
+	assert.ErrorIs(t, err, models.ErrUserNotFound)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveNotificationPreferences(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	query := regexp.QuoteMeta(`ON CONFLICT (user_id) DO UPDATE`)
+
+	mock.ExpectExec(query).
+		WithArgs(1, "alice@example.com", "", models.LocaleRU, false, true, "", 0.0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, store.SaveNotificationPreferences(context.Background(), 1, models.NotificationPreferences{
+		Email:           "alice@example.com",
+		Locale:          models.LocaleRU,
+		PointsWithdrawn: true,
+	}, "", 0))
+
+	mock.ExpectExec(query).
+		WithArgs(1, "alice@example.com", "new@example.com", models.LocaleRU, true, true, "hash", 3600.0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, store.SaveNotificationPreferences(context.Background(), 1, models.NotificationPreferences{
+		Email:           "alice@example.com",
+		PendingEmail:    "new@example.com",
+		Locale:          models.LocaleRU,
+		PointsCredited:  true,
+		PointsWithdrawn: true,
+	}, "hash", time.Hour))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConfirmNotificationEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	query := regexp.QuoteMeta(`SET email = pending_email, pending_email = ''`)
+
+	mock.ExpectExec(query).WithArgs(1, "hash").WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, store.ConfirmNotificationEmail(context.Background(), 1, "hash"))
+
+	mock.ExpectExec(query).WithArgs(1, "stale").WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, store.ConfirmNotificationEmail(context.Background(), 1, "stale"), models.ErrInvalidEmailToken)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// writeOutbox записывает событие в outbox. Вызывается в транзакции самого
+// изменения: событие публикуется тогда и только тогда, когда изменение
+// зафиксировано.
+func writeOutbox(ctx context.Context, tx *sql.Tx, event models.DomainEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO outbox (event_type, payload, created_at, available_at)
+        VALUES ($1, $2, NOW(), NOW());
+    `, event.EventType(), string(payload))
+	if err != nil {
+		logger.Log.Error(err.Error())
+	}
+	return err
+}
+
+// ClaimOutbox выбирает неопубликованные события в порядке записи и
+// откладывает их повторную выборку на lease. Событие, не отмеченное
+// MarkOutboxPublished до истечения lease, будет выбрано снова; события в
+// состоянии dead не выбираются.
+func (db *PgStorage) ClaimOutbox(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEvent, error) {
+	rows, err := db.QueryContext(ctx, `
+        WITH due AS (
+            SELECT id FROM outbox
+            WHERE published_at IS NULL AND dead_at IS NULL AND available_at <= NOW()
+            ORDER BY id
+            LIMIT $1
+            FOR UPDATE SKIP LOCKED
+        )
+        UPDATE outbox o
+        SET available_at = NOW() + $2 * INTERVAL '1 second'
+        FROM due
+        WHERE o.id = due.id
+        RETURNING o.id, o.event_type, o.payload, o.created_at, o.attempts,
+            COALESCE((SELECT string_agg(d.subscriber, ',') FROM outbox_deliveries d WHERE d.event_id = o.id), '');
+    `, limit, lease.Seconds())
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.OutboxEvent
+	for rows.Next() {
+		var e models.OutboxEvent
+		var delivered string
+		if err := rows.Scan(&e.ID, &e.Type, &e.Payload, &e.CreatedAt, &e.Attempts, &delivered); err != nil {
+			logger.Log.Error(err.Error())
+			return nil, err
+		}
+		if delivered != "" {
+			e.Delivered = strings.Split(delivered, ",")
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	return events, nil
+}
+
+func (db *PgStorage) MarkOutboxPublished(ctx context.Context, id int64) error {
+	_, err := db.ExecContext(ctx, `
+        UPDATE outbox SET published_at = NOW() WHERE id = $1;
+    `, id)
+	if err != nil {
+		logger.Log.Error(err.Error())
+	}
+	return err
+}
+
+// MarkOutboxDelivered запоминает, что получатель subscriber принял событие;
+// при повторной попытке он его уже не получит.
+func (db *PgStorage) MarkOutboxDelivered(ctx context.Context, id int64, subscriber string) error {
+	_, err := db.ExecContext(ctx, `
+        INSERT INTO outbox_deliveries (event_id, subscriber, delivered_at)
+        VALUES ($1, $2, NOW())
+        ON CONFLICT DO NOTHING;
+    `, id, subscriber)
+	if err != nil {
+		logger.Log.Error(err.Error())
+	}
+	return err
+}
+
+// FailOutboxEvent учитывает неудачную попытку публикации. Событие с dead
+// больше не выбирается, его остаётся разобрать вручную по last_error.
+func (db *PgStorage) FailOutboxEvent(ctx context.Context, id int64, lastError string, dead bool) error {
+	_, err := db.ExecContext(ctx, `
+        UPDATE outbox
+        SET attempts = attempts + 1, last_error = $2,
+            dead_at = CASE WHEN $3 THEN NOW() END
+        WHERE id = $1;
+    `, id, lastError, dead)
+	if err != nil {
+		logger.Log.Error(err.Error())
+	}
+	return err
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestClaimOutbox(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FOR UPDATE SKIP LOCKED`)).
+		WithArgs(100, 60.0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_type", "payload", "created_at", "attempts", "delivered"}).
+			AddRow(8, models.EventPointsWithdrawn, []byte(`{}`), now, 2, "notify,broker").
+			AddRow(7, models.EventUserRegistered, []byte(`{}`), now, 0, ""))
+
+	events, err := store.ClaimOutbox(context.Background(), 100, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, int64(7), events[0].ID)
+	assert.Equal(t, models.EventUserRegistered, events[0].Type)
+	assert.Empty(t, events[0].Delivered)
+	assert.Equal(t, 2, events[1].Attempts)
+	assert.Equal(t, []string{"notify", "broker"}, events[1].Delivered)
+
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO outbox_deliveries (event_id, subscriber, delivered_at)`)).
+		WithArgs(int64(8), "audit").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, store.MarkOutboxDelivered(context.Background(), 8, "audit"))
+
+	mock.ExpectExec(regexp.QuoteMeta(`SET attempts = attempts + 1, last_error = $2`)).
+		WithArgs(int64(8), "audit: timeout", true).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, store.FailOutboxEvent(context.Background(), 8, "audit: timeout", true))
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE outbox SET published_at = NOW() WHERE id = $1;`)).
+		WithArgs(int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, store.MarkOutboxPublished(context.Background(), 7))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"strings"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// GetUserPermissions возвращает права пользователя: выданные его роли
+// и назначенные ему лично.
+func (db *PgStorage) GetUserPermissions(ctx context.Context, userID int) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `
+        SELECT rp.permission
+        FROM users u
+        JOIN role_permissions rp ON rp.role = u.role
+        WHERE u.id = $1
+        UNION
+        SELECT permission
+        FROM user_permissions
+        WHERE user_id = $1
+        ORDER BY 1
+    `, userID)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var permissions []string
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			logger.Log.Error(err.Error())
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// SetUserAccess меняет роль пользователя и заменяет набор его личных прав.
+func (db *PgStorage) SetUserAccess(ctx context.Context, adminID, userID int, access models.UserAccess) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+        UPDATE users SET role = $2 WHERE id = $1;
+    `, userID, access.Role)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		if err == nil {
+			err = models.ErrUserNotFound
+		}
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM user_permissions WHERE user_id = $1;
+    `, userID)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	for _, permission := range access.Permissions {
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO user_permissions (user_id, permission, granted_at)
+            VALUES ($1, $2, NOW());
+        `, userID, permission)
+		if err != nil {
+			logger.Log.Error(err.Error())
+			return err
+		}
+	}
+
+	err = logAdminAction(ctx, tx, models.AdminAction{
+		AdminID:      adminID,
+		Action:       models.AdminActionAccess,
+		TargetUserID: userID,
+		Details:      "role=" + access.Role + " permissions=" + strings.Join(access.Permissions, ","),
+	})
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+
+	return tx.Commit()
+}
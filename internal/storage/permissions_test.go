@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestGetUserPermissions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`JOIN role_permissions rp ON rp.role = u.role`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"permission"}).
+			AddRow(models.PermBalanceAdjust).
+			AddRow(models.PermUsersRead))
+
+	permissions, err := store.GetUserPermissions(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{models.PermBalanceAdjust, models.PermUsersRead}, permissions)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestSetUserAccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET role = $2 WHERE id = $1;`)).
+			WithArgs(5, models.RoleSupport).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM user_permissions WHERE user_id = $1;`)).
+			WithArgs(5).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO user_permissions`)).
+			WithArgs(5, models.PermBalanceAdjust).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO admin_actions`)).
+			WithArgs(1, models.AdminActionAccess, 5, "", "role=support permissions=balance:adjust").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := store.SetUserAccess(ctx, 1, 5, models.UserAccess{
+			Role:        models.RoleSupport,
+			Permissions: []string{models.PermBalanceAdjust},
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("UserNotFound", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET role = $2 WHERE id = $1;`)).
+			WithArgs(6, models.RoleAdmin).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := store.SetUserAccess(ctx, 1, 6, models.UserAccess{Role: models.RoleAdmin})
+		assert.ErrorIs(t, err, models.ErrUserNotFound)
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
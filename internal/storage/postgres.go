@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
+	"time"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/pressly/goose/v3"
@@ -43,10 +45,10 @@ func InitDB(dsn string) (*PgStorage, error) {
 func (db *PgStorage) GetUserByLogin(ctx context.Context, login string) (*models.User, error) {
 	var user models.User
 
-	query := "SELECT id, login, password_hash FROM users WHERE login = $1"
+	query := "SELECT id, login, password_hash, role, blocked_at IS NOT NULL FROM users WHERE login = $1"
 	row := db.QueryRowContext(ctx, query, login)
 
-	err := row.Scan(&user.ID, &user.Login, &user.Password)
+	err := row.Scan(&user.ID, &user.Login, &user.Password, &user.Role, &user.Blocked)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -57,16 +59,53 @@ func (db *PgStorage) GetUserByLogin(ctx context.Context, login string) (*models.
 }
 
 func (db *PgStorage) CreateUser(ctx context.Context, user *models.User) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
 	var userID int
-	query := "INSERT INTO users (login, password_hash) VALUES ($1, $2) RETURNING id"
-	err := db.QueryRowContext(ctx, query, user.Login, user.Password).Scan(&userID)
+	query := "INSERT INTO users (login, password_hash, referral_code) VALUES ($1, $2, NULLIF($3, '')) RETURNING id"
+	err = tx.QueryRowContext(ctx, query, user.Login, user.Password, user.ReferralCode).Scan(&userID)
 	if err != nil {
+		if isUniqueViolation(err, "users_referral_code_key") {
+			return models.ErrReferralCodeTaken
+		}
 		logger.Log.Error("Ошибка при создании пользователя")
 		return fmt.Errorf("ошибка при создании пользователя: %w", err)
 	}
+	if user.ReferrerID != 0 {
+		if err := createReferral(ctx, tx, user.ReferrerID, userID); err != nil {
+			logger.Log.Error(err.Error())
+			return err
+		}
+	}
+	if err := writeOutbox(ctx, tx, models.UserRegisteredEvent{UserID: userID, Login: user.Login}); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
 	user.ID = userID
 	return nil
 }
+
+func (db *PgStorage) UpdatePasswordHash(ctx context.Context, userID int, hash string) error {
+	res, err := db.ExecContext(ctx, "UPDATE users SET password_hash = $2 WHERE id = $1", userID, hash)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		if err == nil {
+			err = models.ErrUserNotFound
+		}
+		return err
+	}
+	return nil
+}
 func (db *PgStorage) GetUserOrders(ctx context.Context, userID int) ([]models.Order, error) {
 	var orders []models.Order
 
@@ -177,22 +216,101 @@ func (db *PgStorage) SaveOrder(ctx context.Context, user int, order *models.Orde
 	}
 	return err
 }
-func (db *PgStorage) UpdateOrder(ctx context.Context, accrual *models.AccrualResponse) error {
-	_, err := db.ExecContext(ctx, `
-    WITH updated_order AS (
+func (db *PgStorage) UpdateOrder(ctx context.Context, accrual *models.AccrualResponse, policy models.CreditPolicy) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	updated, err := updateOrder(ctx, tx, accrual, policy)
+	if err != nil || !updated {
+		return err
+	}
+	return tx.Commit()
+}
+
+// updateOrder записывает результат расчёта в транзакции tx и сообщает,
+// изменился ли заказ.
+func updateOrder(ctx context.Context, tx *sql.Tx, accrual *models.AccrualResponse, policy models.CreditPolicy) (bool, error) {
+	var userID int
+	err := tx.QueryRowContext(ctx, `
         UPDATE orders
-        SET status = $2, accrual = $3
-        WHERE number = $1
-        RETURNING user_id, accrual
-    )
-    UPDATE users
-    SET current_balance = current_balance + uo.accrual
-    FROM updated_order uo
-    WHERE users.id = uo.user_id AND uo.accrual > 0;
-	`, accrual.Order, accrual.Status, sql.NullFloat64{Float64: accrual.Accrual, Valid: accrual.Accrual > 0})
+        SET status = $2, accrual = NULL,
+            processed_at = CASE WHEN $2 = 'PROCESSED' THEN NOW() END
+        WHERE number = $1 AND status NOT IN ('PROCESSED', 'INVALID')
+        RETURNING user_id;
+    `, accrual.Order, accrual.Status).Scan(&userID)
 	if err != nil {
+		// Заказ уже в финальном статусе — повторный результат не начисляется.
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
 		logger.Log.Error(err.Error())
+		return false, err
 	}
+
+	if accrual.Accrual > 0 {
+		if err := creditOrder(ctx, tx, userID, accrual, policy); err != nil {
+			logger.Log.Error(err.Error())
+			return false, err
+		}
+	}
+	if accrual.Status == models.OrderProcessed {
+		if err := applyCampaigns(ctx, tx, userID, accrual, policy); err != nil {
+			logger.Log.Error(err.Error())
+			return false, err
+		}
+		if err := applyReferral(ctx, tx, userID, policy); err != nil {
+			logger.Log.Error(err.Error())
+			return false, err
+		}
+	}
+	if isFinalOrderStatus(accrual.Status) {
+		if err := recordOrderEvents(ctx, tx, userID, accrual.Order, accrual.Status); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func creditOrder(ctx context.Context, tx *sql.Tx, userID int, accrual *models.AccrualResponse, policy models.CreditPolicy) error {
+	multiplier, err := recomputeTier(ctx, tx, userID, policy.TierWindowMonths)
+	if err != nil {
+		return err
+	}
+	credited := math.Round(accrual.Accrual*multiplier*100) / 100
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE orders SET accrual = $2, base_accrual = $3 WHERE number = $1;
+    `, accrual.Order, credited, accrual.Accrual)
+	if err != nil {
+		return err
+	}
+	if err := creditPoints(ctx, tx, userID, accrual.Order, models.LotSourceOrder, credited, policy.LifetimeMonths); err != nil {
+		return err
+	}
+
+	_, err = recomputeTier(ctx, tx, userID, policy.TierWindowMonths)
+	return err
+}
+
+// creditPoints зачисляет баллы на счёт пользователя и заводит под них партию
+// со сроком сгорания.
+func creditPoints(ctx context.Context, tx *sql.Tx, userID int, orderNumber string, source string, amount float64, lifetimeMonths int) error {
+	_, err := tx.ExecContext(ctx, `
+        UPDATE users
+        SET current_balance = current_balance + $1
+        WHERE id = $2;
+    `, amount, userID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO accrual_lots (user_id, order_number, source, amount, remaining, accrued_at, expires_at)
+        VALUES ($1, NULLIF($2, ''), $3, $4, $4, NOW(), CASE WHEN $5::int > 0 THEN NOW() + make_interval(months => $5::int) END);
+    `, userID, orderNumber, source, amount, lifetimeMonths)
 	return err
 }
 
@@ -213,6 +331,16 @@ func (db *PgStorage) IsOrderExists(ctx context.Context, orderNum string) (int, e
 }
 
 func (db *PgStorage) Withdraw(ctx context.Context, userID int, order string, sum float64) error {
+	return db.withdraw(ctx, 0, userID, order, sum)
+}
+
+// WithdrawAtMerchant списывает баллы на кассе мерчанта и в той же
+// транзакции сообщает ему о списании.
+func (db *PgStorage) WithdrawAtMerchant(ctx context.Context, merchantID, userID int, order string, sum float64) error {
+	return db.withdraw(ctx, merchantID, userID, order, sum)
+}
+
+func (db *PgStorage) withdraw(ctx context.Context, merchantID, userID int, order string, sum float64) error {
 	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
 	if err != nil {
 		return err
@@ -229,7 +357,7 @@ func (db *PgStorage) Withdraw(ctx context.Context, userID int, order string, sum
 	}
 
 	if currentBalance < sum {
-		return fmt.Errorf("недостаточно средств")
+		return models.ErrInsufficientFunds
 	}
 
 	_, err = tx.ExecContext(ctx, `
@@ -239,6 +367,9 @@ func (db *PgStorage) Withdraw(ctx context.Context, userID int, order string, sum
 	if err != nil {
 		return err
 	}
+	if _, err := consumeLots(ctx, tx, userID, sum); err != nil {
+		return err
+	}
 	_, err = tx.ExecContext(ctx, `
         UPDATE users
         SET current_balance = current_balance - $1,
@@ -248,19 +379,33 @@ func (db *PgStorage) Withdraw(ctx context.Context, userID int, order string, sum
 	if err != nil {
 		return err
 	}
+	err = writeOutbox(ctx, tx, models.PointsWithdrawnEvent{UserID: userID, Order: order, Sum: sum, MerchantID: merchantID})
+	if err != nil {
+		return err
+	}
+	if merchantID != 0 {
+		err = recordMerchantEvent(ctx, tx, merchantID, models.WebhookPointsWithdrawn,
+			models.WebhookWithdrawEvent{Order: order, Sum: sum})
+		if err != nil {
+			return err
+		}
+	}
 
 	return tx.Commit()
 }
 
-func (db *PgStorage) GetPendingOrders(ctx context.Context) ([]string, error) {
+// GetPendingOrders возвращает необработанные заказы, загруженные не позднее
+// чем olderThan назад; при нулевом olderThan — все.
+func (db *PgStorage) GetPendingOrders(ctx context.Context, olderThan time.Duration) ([]string, error) {
 	var orders []string
 
 	query := `
 		SELECT number
 		FROM orders
 		WHERE status IN ('NEW', 'PROCESSING')
+		  AND uploaded_at <= NOW() - $1 * INTERVAL '1 second'
 	`
-	rows, err := db.QueryContext(ctx, query)
+	rows, err := db.QueryContext(ctx, query, olderThan.Seconds())
 	if err != nil {
 		logger.Log.Error(err.Error())
 		return nil, err
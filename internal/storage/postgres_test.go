@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/scoring-service/pkg/models"
 	"github.com/stretchr/testify/assert"
@@ -61,21 +62,54 @@ func TestCreateUser(t *testing.T) {
 		Password: "hashedpassword",
 	}
 
-	mock.ExpectQuery("INSERT INTO users \\(login, password_hash\\) VALUES \\(\\$1, \\$2\\) RETURNING id").
-		WithArgs(user.Login, user.Password).
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO users (login, password_hash, referral_code) VALUES ($1, $2, NULLIF($3, '')) RETURNING id")).
+		WithArgs(user.Login, user.Password, user.ReferralCode).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO outbox (event_type, payload, created_at, available_at)`)).
+		WithArgs(models.EventUserRegistered, `{"user_id":1,"login":"testuser"}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	err := store.CreateUser(ctx, user)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, user.ID)
 
-	mock.ExpectQuery("^INSERT INTO users \\(login, password_hash\\) VALUES \\($1, $2\\) RETURNING id$").
-		WithArgs(user.Login, user.Password).
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO users (login, password_hash, referral_code) VALUES ($1, $2, NULLIF($3, '')) RETURNING id")).
+		WithArgs(user.Login, user.Password, user.ReferralCode).
 		WillReturnError(errors.New("db error"))
+	mock.ExpectRollback()
 
 	err = store.CreateUser(ctx, user)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "ошибка при создании пользователя")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO users (login, password_hash, referral_code)")).
+		WithArgs(user.Login, user.Password, user.ReferralCode).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "users_referral_code_key"})
+	mock.ExpectRollback()
+
+	err = store.CreateUser(ctx, user)
+	assert.ErrorIs(t, err, models.ErrReferralCodeTaken)
+
+	invited := &models.User{Login: "bob", Password: "hashedpassword", ReferralCode: "ABCD2345", ReferrerID: 1}
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO users (login, password_hash, referral_code)")).
+		WithArgs(invited.Login, invited.Password, invited.ReferralCode).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO referrals (referrer_id, referee_id, status, created_at)`)).
+		WithArgs(1, 2, models.ReferralPending).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO outbox (event_type, payload, created_at, available_at)`)).
+		WithArgs(models.EventUserRegistered, `{"user_id":2,"login":"bob"}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, store.CreateUser(ctx, invited))
+	assert.Equal(t, 2, invited.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 func TestGetUserByLogin(t *testing.T) {
 	mockDB, mock, _ := sqlmock.New()
@@ -87,9 +121,9 @@ func TestGetUserByLogin(t *testing.T) {
 	login := "testuser"
 
 	t.Run("Success", func(t *testing.T) {
-		mock.ExpectQuery("SELECT id, login, password_hash FROM users WHERE login = \\$1").
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, login, password_hash, role, blocked_at IS NOT NULL FROM users WHERE login = $1")).
 			WithArgs(login).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "login", "password_hash"}).AddRow(1, login, "hashedpassword"))
+			WillReturnRows(sqlmock.NewRows([]string{"id", "login", "password_hash", "role", "blocked"}).AddRow(1, login, "hashedpassword", "customer", false))
 
 		user, err := store.GetUserByLogin(ctx, login)
 
@@ -101,7 +135,7 @@ func TestGetUserByLogin(t *testing.T) {
 	})
 
 	t.Run("NoRows", func(t *testing.T) {
-		mock.ExpectQuery("SELECT id, login, password_hash FROM users WHERE login = \\$1").
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, login, password_hash, role, blocked_at IS NOT NULL FROM users WHERE login = $1")).
 			WithArgs(login).
 			WillReturnError(sql.ErrNoRows)
 
@@ -112,7 +146,7 @@ func TestGetUserByLogin(t *testing.T) {
 	})
 
 	t.Run("Error", func(t *testing.T) {
-		mock.ExpectQuery("SELECT id, login, password_hash FROM users WHERE login = \\$1").
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, login, password_hash, role, blocked_at IS NOT NULL FROM users WHERE login = $1")).
 			WithArgs(login).
 			WillReturnError(errors.New("db error"))
 
@@ -123,6 +157,32 @@ func TestGetUserByLogin(t *testing.T) {
 		assert.Contains(t, err.Error(), "db error")
 	})
 }
+func TestUpdatePasswordHash(t *testing.T) {
+	mockDB, mock, _ := sqlmock.New()
+	defer mockDB.Close()
+
+	store := PgStorage{DB: mockDB}
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET password_hash = $2 WHERE id = $1")).
+			WithArgs(1, "$argon2id$hash").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, store.UpdatePasswordHash(ctx, 1, "$argon2id$hash"))
+	})
+
+	t.Run("UserNotFound", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET password_hash = $2 WHERE id = $1")).
+			WithArgs(2, "$argon2id$hash").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, store.UpdatePasswordHash(ctx, 2, "$argon2id$hash"), models.ErrUserNotFound)
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetUserWithdrawals(t *testing.T) {
 	mockDB, mock, _ := sqlmock.New()
 	defer mockDB.Close()
@@ -293,45 +353,120 @@ func TestUpdateOrder(t *testing.T) {
 		Status:  "PROCESSED",
 		Accrual: 15.75,
 	}
+	policy := models.CreditPolicy{LifetimeMonths: 12, TierWindowMonths: 12}
+
+	expectStatus := func(order, status string) *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery(regexp.QuoteMeta(`
+			UPDATE orders
+			SET status = $2, accrual = NULL,
+				processed_at = CASE WHEN $2 = 'PROCESSED' THEN NOW() END
+			WHERE number = $1 AND status NOT IN ('PROCESSED', 'INVALID')
+			RETURNING user_id;
+		`)).WithArgs(order, status)
+	}
+	expectMerchant := func(order string, merchantID any) {
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT merchant_id, COALESCE(accrual, 0) FROM orders WHERE number = $1;`)).
+			WithArgs(order).
+			WillReturnRows(sqlmock.NewRows([]string{"merchant_id", "accrual"}).AddRow(merchantID, 0.0))
+	}
+	expectTier := func(multiplier float64) {
+		mock.ExpectQuery(regexp.QuoteMeta(`UPDATE users u SET tier_id = t.id`)).
+			WithArgs(42, policy.TierWindowMonths).
+			WillReturnRows(sqlmock.NewRows([]string{"multiplier"}).AddRow(multiplier))
+	}
 
 	t.Run("SuccessUpdate", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectStatus(accrual.Order, accrual.Status).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(42))
+		expectTier(1.5)
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE orders SET accrual = $2, base_accrual = $3 WHERE number = $1;`)).
+			WithArgs(accrual.Order, 23.63, accrual.Accrual).
+			WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectExec(regexp.QuoteMeta(`
-			WITH updated_order AS (
-				UPDATE orders
-				SET status = $2, accrual = $3
-				WHERE number = $1
-				RETURNING user_id, accrual
-			)
 			UPDATE users
-			SET current_balance = current_balance + uo.accrual
-			FROM updated_order uo
-			WHERE users.id = uo.user_id AND uo.accrual > 0;
+			SET current_balance = current_balance + $1
+			WHERE id = $2;
 		`)).
-			WithArgs(accrual.Order, accrual.Status, sql.NullFloat64{Float64: accrual.Accrual, Valid: accrual.Accrual > 0}).
+			WithArgs(23.63, 42).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO accrual_lots`)).
+			WithArgs(42, accrual.Order, models.LotSourceOrder, 23.63, policy.LifetimeMonths).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		expectTier(1.5)
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM campaigns WHERE active`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM referrals WHERE referee_id = $1 AND status = $2`)).
+			WithArgs(42, models.ReferralPending).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "referrer_id"}))
+		expectMerchant(accrual.Order, nil)
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO outbox (event_type, payload, created_at, available_at)`)).
+			WithArgs(models.EventOrderProcessed, `{"user_id":42,"order":"123456789","accrual":0}`).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
 
-		err := store.UpdateOrder(ctx, accrual)
+		err := store.UpdateOrder(ctx, accrual, policy)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("AlreadyProcessed", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectStatus(accrual.Order, accrual.Status).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		err := store.UpdateOrder(ctx, accrual, policy)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NoAccrual", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectStatus(accrual.Order, models.OrderInvalid).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(42))
+		expectMerchant(accrual.Order, nil)
+		mock.ExpectCommit()
+
+		err := store.UpdateOrder(ctx, &models.AccrualResponse{Order: accrual.Order, Status: models.OrderInvalid}, policy)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("MerchantEvent", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectStatus(accrual.Order, models.OrderInvalid).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(42))
+		expectMerchant(accrual.Order, 3)
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO webhook_events (merchant_id, event_type, payload, created_at)`)).
+			WithArgs(3, models.WebhookOrderInvalid, `{"order":"123456789","status":"INVALID"}`, models.DeliveryPending).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		err := store.UpdateOrder(ctx, &models.AccrualResponse{Order: accrual.Order, Status: models.OrderInvalid}, policy)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("OrderNotFound", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectStatus(accrual.Order, accrual.Status).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+		mock.ExpectRollback()
+
+		err := store.UpdateOrder(ctx, accrual, policy)
 
 		assert.NoError(t, err)
 	})
 
 	t.Run("DatabaseError", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`
-			WITH updated_order AS (
-				UPDATE orders
-				SET status = $2, accrual = $3
-				WHERE number = $1
-				RETURNING user_id, accrual
-			)
-			UPDATE users
-			SET current_balance = current_balance + uo.accrual
-			FROM updated_order uo
-			WHERE users.id = uo.user_id AND uo.accrual > 0;
-		`)).
-			WithArgs(accrual.Order, accrual.Status, sql.NullFloat64{Float64: accrual.Accrual, Valid: accrual.Accrual > 0}).
+		mock.ExpectBegin()
+		expectStatus(accrual.Order, accrual.Status).
 			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
 
-		err := store.UpdateOrder(ctx, accrual)
+		err := store.UpdateOrder(ctx, accrual, policy)
 
 		assert.Error(t, err)
 		assert.Equal(t, sql.ErrConnDone, err)
@@ -423,6 +558,10 @@ func TestWithdraw(t *testing.T) {
 			WithArgs(userID, orderNum, amount).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
+		mock.ExpectQuery(regexp.QuoteMeta(`UPDATE accrual_lots l SET remaining = l.remaining - c.take`)).
+			WithArgs(userID, amount).
+			WillReturnRows(sqlmock.NewRows([]string{"take", "expires_at"}).AddRow(amount, nil))
+
 		mock.ExpectExec(regexp.QuoteMeta(`
 			UPDATE users
 			SET current_balance = current_balance - $1,
@@ -432,6 +571,10 @@ func TestWithdraw(t *testing.T) {
 			WithArgs(amount, userID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO outbox (event_type, payload, created_at, available_at)`)).
+			WithArgs(models.EventPointsWithdrawn, `{"user_id":1,"order":"123456789","sum":100}`).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
 		mock.ExpectCommit()
 
 		err := store.Withdraw(ctx, userID, orderNum, amount)
@@ -498,9 +641,10 @@ func TestGetPendingOrders(t *testing.T) {
 			SELECT number
 			FROM orders
 			WHERE status IN ('NEW', 'PROCESSING')
-		`)).WillReturnRows(rows)
+			  AND uploaded_at <= NOW() - $1 * INTERVAL '1 second'
+		`)).WithArgs(300.0).WillReturnRows(rows)
 
-		result, err := store.GetPendingOrders(ctx)
+		result, err := store.GetPendingOrders(ctx, 5*time.Minute)
 		require.NoError(t, err)
 		require.Equal(t, []string{"ORD001", "ORD002", "ORD003"}, result)
 	})
@@ -512,7 +656,7 @@ func TestGetPendingOrders(t *testing.T) {
 			WHERE status IN ('NEW', 'PROCESSING')
 		`)).WillReturnError(errors.New("query failed"))
 
-		result, err := store.GetPendingOrders(ctx)
+		result, err := store.GetPendingOrders(ctx, 0)
 		require.Error(t, err)
 		require.Nil(t, result)
 	})
@@ -527,7 +671,7 @@ func TestGetPendingOrders(t *testing.T) {
 			WHERE status IN ('NEW', 'PROCESSING')
 		`)).WillReturnRows(rows)
 
-		result, err := store.GetPendingOrders(ctx)
+		result, err := store.GetPendingOrders(ctx, 0)
 		require.Error(t, err)
 		require.Nil(t, result)
 	})
@@ -0,0 +1,230 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// expectedBalances — баланс и сумма списаний каждого пользователя по
+// истории операций.
+var expectedBalances = `
+        WITH ledger AS (` + ledgerEntries("") + `
+        ), expected AS (
+            SELECT user_id, SUM(amount) AS balance,
+                COALESCE(SUM(-amount) FILTER (WHERE kind = 'WITHDRAWAL'), 0) AS withdrawn
+            FROM ledger
+            GROUP BY user_id
+        )`
+
+// FindBalanceDrifts сверяет сохранённые балансы всех пользователей с
+// историей операций. Возвращает число проверенных пользователей и
+// найденные расхождения.
+func (db *PgStorage) FindBalanceDrifts(ctx context.Context) (int, []models.BalanceDrift, error) {
+	var checked int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users;`).Scan(&checked); err != nil {
+		logger.Log.Error(err.Error())
+		return 0, nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, expectedBalances+`
+        SELECT u.id, u.login, COALESCE(u.current_balance, 0), COALESCE(e.balance, 0),
+            COALESCE(u.withdrawn, 0), COALESCE(e.withdrawn, 0)
+        FROM users u
+        LEFT JOIN expected e ON e.user_id = u.id
+        WHERE COALESCE(u.current_balance, 0) <> COALESCE(e.balance, 0)
+            OR COALESCE(u.withdrawn, 0) <> COALESCE(e.withdrawn, 0)
+        ORDER BY u.id;
+    `)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	drifts := []models.BalanceDrift{}
+	for rows.Next() {
+		var d models.BalanceDrift
+		if err := rows.Scan(&d.UserID, &d.Login, &d.Balance, &d.ExpectedBalance, &d.Withdrawn, &d.ExpectedWithdrawn); err != nil {
+			logger.Log.Error(err.Error())
+			return 0, nil, err
+		}
+		drifts = append(drifts, d)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, nil, err
+	}
+	return checked, drifts, nil
+}
+
+// CorrectBalance приводит баланс пользователя к истории операций в рамках
+// сверки runID. Расхождение заново проверяется под блокировкой
+// пользователя: если оно исчезло или изменилось, пока шла сверка,
+// исправляется актуальное; история читается только по этому пользователю.
+// Возвращает false, если исправлять нечего. Баланс не опускается ниже
+// нуля: если история даёт отрицательный баланс, он исправляется до нуля.
+// Исправление записывается в balance_corrections и попадает в историю
+// операций суммой, которая сводит её с новым балансом, поэтому видно в
+// выписке. Партии баллов подгоняются под новый баланс: недостача
+// списывается по FIFO, излишек заводится бессрочной партией CORRECTION.
+func (db *PgStorage) CorrectBalance(ctx context.Context, runID, userID int) (models.BalanceDrift, bool, error) {
+	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return models.BalanceDrift{}, false, err
+	}
+	defer tx.Rollback()
+
+	d := models.BalanceDrift{UserID: userID}
+	err = tx.QueryRowContext(ctx, `
+        SELECT login, COALESCE(current_balance, 0), COALESCE(withdrawn, 0)
+        FROM users WHERE id = $1 FOR UPDATE;
+    `, userID).Scan(&d.Login, &d.Balance, &d.Withdrawn)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return d, false, models.ErrUserNotFound
+		}
+		logger.Log.Error(err.Error())
+		return d, false, err
+	}
+	err = tx.QueryRowContext(ctx, `
+        WITH ledger AS (`+ledgerEntries("$1")+`
+        )
+        SELECT COALESCE(SUM(amount), 0), COALESCE(SUM(-amount) FILTER (WHERE kind = 'WITHDRAWAL'), 0)
+        FROM ledger;
+    `, userID).Scan(&d.ExpectedBalance, &d.ExpectedWithdrawn)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return d, false, err
+	}
+	if d.Balance == d.ExpectedBalance && d.Withdrawn == d.ExpectedWithdrawn {
+		return d, false, nil
+	}
+
+	balance, reason := d.ExpectedBalance, models.CorrectionReasonDrift
+	if balance < 0 {
+		balance, reason = 0, models.CorrectionReasonNegative
+	}
+	if delta := balance - d.Balance; delta < 0 {
+		if _, err := consumeLots(ctx, tx, userID, -delta); err != nil {
+			return d, false, err
+		}
+	} else if delta > 0 {
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO accrual_lots (user_id, source, amount, remaining, accrued_at)
+            VALUES ($1, $2, $3, $3, NOW());
+        `, userID, models.LotSourceCorrection, delta)
+		if err != nil {
+			return d, false, err
+		}
+	}
+	_, err = tx.ExecContext(ctx, `
+        UPDATE users SET current_balance = $2, withdrawn = $3 WHERE id = $1;
+    `, userID, balance, d.ExpectedWithdrawn)
+	if err != nil {
+		return d, false, err
+	}
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO balance_corrections (run_id, user_id, amount, reason, balance_before, balance_after, withdrawn_before, withdrawn_after, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW());
+    `, runID, userID, balance-d.ExpectedBalance, reason, d.Balance, balance, d.Withdrawn, d.ExpectedWithdrawn)
+	if err != nil {
+		return d, false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return d, false, err
+	}
+	d.Corrected = true
+	return d, true, nil
+}
+
+// StartReconciliationRun регистрирует начатую сверку, чтобы исправления
+// балансов ссылались на неё.
+func (db *PgStorage) StartReconciliationRun(ctx context.Context, run *models.ReconciliationRun) error {
+	err := db.QueryRowContext(ctx, `
+        INSERT INTO reconciliation_runs (started_at, auto_correct)
+        VALUES ($1, $2)
+        RETURNING id;
+    `, run.StartedAt, run.AutoCorrect).Scan(&run.ID)
+	if err != nil {
+		logger.Log.Error(err.Error())
+	}
+	return err
+}
+
+// FinishReconciliationRun сохраняет итог сверки вместе с найденными
+// расхождениями.
+func (db *PgStorage) FinishReconciliationRun(ctx context.Context, run models.ReconciliationRun) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE reconciliation_runs
+        SET finished_at = $2, users_checked = $3, error = $4
+        WHERE id = $1;
+    `, run.ID, run.FinishedAt, run.UsersChecked, run.Error)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	for _, d := range run.Drifts {
+		_, err := tx.ExecContext(ctx, `
+            INSERT INTO balance_drifts (run_id, user_id, balance, expected_balance, withdrawn, expected_withdrawn, corrected)
+            VALUES ($1, $2, $3, $4, $5, $6, $7);
+        `, run.ID, d.UserID, d.Balance, d.ExpectedBalance, d.Withdrawn, d.ExpectedWithdrawn, d.Corrected)
+		if err != nil {
+			logger.Log.Error(err.Error())
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetLastReconciliationRun возвращает последнюю завершённую сверку с
+// найденными расхождениями.
+func (db *PgStorage) GetLastReconciliationRun(ctx context.Context) (models.ReconciliationRun, error) {
+	var run models.ReconciliationRun
+	err := db.QueryRowContext(ctx, `
+        SELECT id, started_at, finished_at, auto_correct, users_checked, error
+        FROM reconciliation_runs
+        WHERE finished_at IS NOT NULL
+        ORDER BY id DESC
+        LIMIT 1;
+    `).Scan(&run.ID, &run.StartedAt, &run.FinishedAt, &run.AutoCorrect, &run.UsersChecked, &run.Error)
+	if errors.Is(err, sql.ErrNoRows) {
+		return run, models.ErrNoReconciliation
+	}
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return run, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+        SELECT d.user_id, u.login, d.balance, d.expected_balance, d.withdrawn, d.expected_withdrawn, d.corrected
+        FROM balance_drifts d
+        JOIN users u ON u.id = d.user_id
+        WHERE d.run_id = $1
+        ORDER BY d.user_id;
+    `, run.ID)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return run, err
+	}
+	defer rows.Close()
+
+	run.Drifts = []models.BalanceDrift{}
+	for rows.Next() {
+		var d models.BalanceDrift
+		if err := rows.Scan(&d.UserID, &d.Login, &d.Balance, &d.ExpectedBalance, &d.Withdrawn, &d.ExpectedWithdrawn, &d.Corrected); err != nil {
+			logger.Log.Error(err.Error())
+			return run, err
+		}
+		run.Drifts = append(run.Drifts, d)
+	}
+	return run, rows.Err()
+}
@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestFindBalanceDrifts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM users;`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE COALESCE(u.current_balance, 0) <> COALESCE(e.balance, 0)`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "login", "balance", "expected_balance", "withdrawn", "expected_withdrawn"}).
+			AddRow(3, "alice", 1000.0, 500.0, 0.0, 0.0))
+
+	checked, drifts, err := store.FindBalanceDrifts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 42, checked)
+	assert.Equal(t, []models.BalanceDrift{{UserID: 3, Login: "alice", Balance: 1000, ExpectedBalance: 500}}, drifts)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCorrectBalance(t *testing.T) {
+	lock := regexp.QuoteMeta(`FROM users WHERE id = $1 FOR UPDATE;`)
+	expected := regexp.QuoteMeta(`WHERE l.user_id = $1 AND l.expired_amount > 0 ) SELECT COALESCE(SUM(amount), 0), COALESCE(SUM(-amount) FILTER (WHERE kind = 'WITHDRAWAL'), 0) FROM ledger;`)
+
+	t.Run("двойное начисление списывается", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		store := &PgStorage{DB: db}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lock).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"login", "current_balance", "withdrawn"}).AddRow("alice", 1000.0, 100.0))
+		mock.ExpectQuery(expected).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"balance", "withdrawn"}).AddRow(500.0, 100.0))
+		mock.ExpectQuery(regexp.QuoteMeta(`UPDATE accrual_lots l SET remaining = l.remaining - c.take`)).
+			WithArgs(3, 500.0).
+			WillReturnRows(sqlmock.NewRows([]string{"take", "expires_at"}).AddRow(500.0, nil))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET current_balance = $2, withdrawn = $3 WHERE id = $1;`)).
+			WithArgs(3, 500.0, 100.0).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO balance_corrections`)).
+			WithArgs(7, 3, 0.0, models.CorrectionReasonDrift, 1000.0, 500.0, 100.0, 100.0).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		d, corrected, err := store.CorrectBalance(context.Background(), 7, 3)
+		require.NoError(t, err)
+		assert.True(t, corrected)
+		assert.Equal(t, models.BalanceDrift{
+			UserID: 3, Login: "alice", Balance: 1000, ExpectedBalance: 500, Withdrawn: 100, ExpectedWithdrawn: 100, Corrected: true,
+		}, d)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("недостача зачисляется партией", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		store := &PgStorage{DB: db}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lock).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"login", "current_balance", "withdrawn"}).AddRow("alice", 400.0, 100.0))
+		mock.ExpectQuery(expected).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"balance", "withdrawn"}).AddRow(500.0, 0.0))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO accrual_lots (user_id, source, amount, remaining, accrued_at)`)).
+			WithArgs(3, models.LotSourceCorrection, 100.0).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET current_balance = $2, withdrawn = $3 WHERE id = $1;`)).
+			WithArgs(3, 500.0, 0.0).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO balance_corrections`)).
+			WithArgs(7, 3, 0.0, models.CorrectionReasonDrift, 400.0, 500.0, 100.0, 0.0).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		_, corrected, err := store.CorrectBalance(context.Background(), 7, 3)
+		require.NoError(t, err)
+		assert.True(t, corrected)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("отрицательная история исправляется до нуля", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		store := &PgStorage{DB: db}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lock).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"login", "current_balance", "withdrawn"}).AddRow("alice", 100.0, 300.0))
+		mock.ExpectQuery(expected).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"balance", "withdrawn"}).AddRow(-50.0, 300.0))
+		mock.ExpectQuery(regexp.QuoteMeta(`UPDATE accrual_lots l SET remaining = l.remaining - c.take`)).
+			WithArgs(3, 100.0).
+			WillReturnRows(sqlmock.NewRows([]string{"take", "expires_at"}).AddRow(100.0, nil))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET current_balance = $2, withdrawn = $3 WHERE id = $1;`)).
+			WithArgs(3, 0.0, 300.0).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO balance_corrections (run_id, user_id, amount, reason,`)).
+			WithArgs(7, 3, 50.0, models.CorrectionReasonNegative, 100.0, 0.0, 300.0, 300.0).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		d, corrected, err := store.CorrectBalance(context.Background(), 7, 3)
+		require.NoError(t, err)
+		assert.True(t, corrected)
+		assert.Equal(t, -50.0, d.ExpectedBalance)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("расхождение исчезло", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		store := &PgStorage{DB: db}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lock).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"login", "current_balance", "withdrawn"}).AddRow("alice", 500.0, 0.0))
+		mock.ExpectQuery(expected).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"balance", "withdrawn"}).AddRow(500.0, 0.0))
+		mock.ExpectRollback()
+
+		_, corrected, err := store.CorrectBalance(context.Background(), 7, 3)
+		require.NoError(t, err)
+		assert.False(t, corrected)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReconciliationRuns(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	started := time.Now()
+	finished := started.Add(time.Second)
+	drift := models.BalanceDrift{UserID: 3, Login: "alice", Balance: 1000, ExpectedBalance: 500, Corrected: true}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO reconciliation_runs (started_at, auto_correct)`)).
+		WithArgs(started, true).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	run := models.ReconciliationRun{StartedAt: started, AutoCorrect: true}
+	require.NoError(t, store.StartReconciliationRun(context.Background(), &run))
+	assert.Equal(t, 7, run.ID)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE reconciliation_runs SET finished_at = $2, users_checked = $3, error = $4 WHERE id = $1;`)).
+		WithArgs(7, finished, 42, "").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO balance_drifts`)).
+		WithArgs(7, 3, 1000.0, 500.0, 0.0, 0.0, true).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	run.FinishedAt = finished
+	run.UsersChecked = 42
+	run.Drifts = []models.BalanceDrift{drift}
+	require.NoError(t, store.FinishReconciliationRun(context.Background(), run))
+
+	last := regexp.QuoteMeta(`FROM reconciliation_runs WHERE finished_at IS NOT NULL ORDER BY id DESC LIMIT 1;`)
+	mock.ExpectQuery(last).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "started_at", "finished_at", "auto_correct", "users_checked", "error"}).
+			AddRow(7, started, finished, true, 42, ""))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM balance_drifts d JOIN users u ON u.id = d.user_id`)).WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "login", "balance", "expected_balance", "withdrawn", "expected_withdrawn", "corrected"}).
+			AddRow(3, "alice", 1000.0, 500.0, 0.0, 0.0, true))
+	got, err := store.GetLastReconciliationRun(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, run, got)
+
+	mock.ExpectQuery(last).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "started_at", "finished_at", "auto_correct", "users_checked", "error"}))
+	_, err = store.GetLastReconciliationRun(context.Background())
+	assert.ErrorIs(t, err, models.ErrNoReconciliation)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
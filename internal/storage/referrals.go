@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+func (db *PgStorage) GetUserByReferralCode(ctx context.Context, code string) (*models.User, error) {
+	var user models.User
+
+	query := "SELECT id, login, referral_code FROM users WHERE referral_code = $1"
+	err := db.QueryRowContext(ctx, query, code).Scan(&user.ID, &user.Login, &user.ReferralCode)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	return &user, nil
+}
+
+// createReferral связывает приглашённого с пригласившим в транзакции
+// регистрации, чтобы пользователь не появился без своего приглашения.
+func createReferral(ctx context.Context, tx *sql.Tx, referrerID, refereeID int) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO referrals (referrer_id, referee_id, status, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (referee_id) DO NOTHING
+	`, referrerID, refereeID, models.ReferralPending)
+	return err
+}
+
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == constraint
+}
+
+// applyReferral вознаграждает обе стороны приглашения, когда первый заказ
+// приглашённого переходит в статус PROCESSED. Сверх лимита на пригласившего
+// приглашение закрывается без начисления.
+func applyReferral(ctx context.Context, tx *sql.Tx, refereeID int, policy models.CreditPolicy) error {
+	var referralID, referrerID int
+	err := tx.QueryRowContext(ctx, `
+        SELECT id, referrer_id FROM referrals WHERE referee_id = $1 AND status = $2 FOR UPDATE;
+    `, refereeID, models.ReferralPending).Scan(&referralID, &referrerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	// Блокировка строки пригласившего сериализует параллельные начисления
+	// по его приглашениям, чтобы лимит нельзя было превысить гонкой.
+	var rewarded int
+	err = tx.QueryRowContext(ctx, `
+        SELECT (SELECT COUNT(*) FROM referrals WHERE referrer_id = u.id AND status = $2)
+        FROM users u WHERE u.id = $1 FOR UPDATE;
+    `, referrerID, models.ReferralRewarded).Scan(&rewarded)
+	if err != nil {
+		return err
+	}
+
+	rp := policy.Referral
+	if rp.MaxPerUser > 0 && rewarded >= rp.MaxPerUser {
+		_, err = tx.ExecContext(ctx, `
+            UPDATE referrals SET status = $2, rewarded_at = NOW() WHERE id = $1;
+        `, referralID, models.ReferralCapped)
+		return err
+	}
+
+	if rp.ReferrerBonus > 0 {
+		if err := creditPoints(ctx, tx, referrerID, "", models.LotSourceReferral, rp.ReferrerBonus, policy.LifetimeMonths); err != nil {
+			return err
+		}
+	}
+	if rp.RefereeBonus > 0 {
+		if err := creditPoints(ctx, tx, refereeID, "", models.LotSourceReferral, rp.RefereeBonus, policy.LifetimeMonths); err != nil {
+			return err
+		}
+	}
+	_, err = tx.ExecContext(ctx, `
+        UPDATE referrals
+        SET status = $2, referrer_bonus = $3, referee_bonus = $4, rewarded_at = NOW()
+        WHERE id = $1;
+    `, referralID, models.ReferralRewarded, rp.ReferrerBonus, rp.RefereeBonus)
+	return err
+}
+
+func (db *PgStorage) GetUserReferrals(ctx context.Context, userID int) (models.ReferralSummary, error) {
+	summary := models.ReferralSummary{Invitees: []models.Referral{}}
+
+	var code sql.NullString
+	err := db.QueryRowContext(ctx, "SELECT referral_code FROM users WHERE id = $1", userID).Scan(&code)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return summary, err
+	}
+	summary.Code = code.String
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT u.login, r.status, r.referrer_bonus, r.created_at
+		FROM referrals r
+		JOIN users u ON u.id = r.referee_id
+		WHERE r.referrer_id = $1
+		ORDER BY r.created_at DESC
+	`, userID)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return summary, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var referral models.Referral
+		if err := rows.Scan(&referral.Login, &referral.Status, &referral.Bonus, &referral.RegisteredAt); err != nil {
+			logger.Log.Error(err.Error())
+			return summary, err
+		}
+		summary.Earned += referral.Bonus
+		summary.Invitees = append(summary.Invitees, referral)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Log.Error(err.Error())
+		return summary, err
+	}
+
+	return summary, nil
+}
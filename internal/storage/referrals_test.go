@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestApplyReferral(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	policy := models.CreditPolicy{
+		LifetimeMonths: 12,
+		Referral:       models.ReferralPolicy{ReferrerBonus: 100, RefereeBonus: 50, MaxPerUser: 2},
+	}
+
+	expectPending := func(referrerRewarded int) {
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, referrer_id FROM referrals WHERE referee_id = $1 AND status = $2 FOR UPDATE;`)).
+			WithArgs(7, models.ReferralPending).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "referrer_id"}).AddRow(3, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM users u WHERE u.id = $1 FOR UPDATE;`)).
+			WithArgs(1, models.ReferralRewarded).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(referrerRewarded))
+	}
+
+	t.Run("Rewarded", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectPending(1)
+		mock.ExpectExec(regexp.QuoteMeta(`SET current_balance = current_balance + $1`)).
+			WithArgs(100.0, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO accrual_lots`)).
+			WithArgs(1, "", models.LotSourceReferral, 100.0, 12).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SET current_balance = current_balance + $1`)).
+			WithArgs(50.0, 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO accrual_lots`)).
+			WithArgs(7, "", models.LotSourceReferral, 50.0, 12).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SET status = $2, referrer_bonus = $3, referee_bonus = $4, rewarded_at = NOW()`)).
+			WithArgs(3, models.ReferralRewarded, 100.0, 50.0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		require.NoError(t, applyReferral(ctx, tx, 7, policy))
+		require.NoError(t, tx.Commit())
+	})
+
+	t.Run("Capped", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectPending(2)
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE referrals SET status = $2, rewarded_at = NOW() WHERE id = $1;`)).
+			WithArgs(3, models.ReferralCapped).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		require.NoError(t, applyReferral(ctx, tx, 7, policy))
+		require.NoError(t, tx.Commit())
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestGetUserReferrals(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT referral_code FROM users WHERE id = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"referral_code"}).AddRow("ABCD2345"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT u.login, r.status, r.referrer_bonus, r.created_at FROM referrals r`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"login", "status", "referrer_bonus", "created_at"}).
+			AddRow("bob", models.ReferralRewarded, 100.0, now).
+			AddRow("carol", models.ReferralPending, 0.0, now))
+
+	summary, err := store.GetUserReferrals(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "ABCD2345", summary.Code)
+	assert.Equal(t, 100.0, summary.Earned)
+	assert.Len(t, summary.Invitees, 2)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
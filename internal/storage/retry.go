@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// serializableAttempts — сколько раз повторяется транзакция уровня
+// SERIALIZABLE, которую Postgres отменил из-за конфликта с параллельной.
+const serializableAttempts = 3
+
+// retrySerializable выполняет fn заново, пока она завершается ошибкой
+// сериализации (SQLSTATE 40001), но не больше serializableAttempts раз.
+func retrySerializable(fn func() error) error {
+	var err error
+	for attempt := 0; attempt < serializableAttempts; attempt++ {
+		if err = fn(); !isSerializationFailure(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func isSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "40001"
+}
@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+func (db *PgStorage) CreateSession(ctx context.Context, userID int, tokenHash string, meta models.SessionMeta, ttl time.Duration) (int, error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var sessionID int
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO sessions (user_id, user_agent, ip, created_at, last_used_at, expires_at)
+        VALUES ($1, $2, $3, NOW(), NOW(), NOW() + make_interval(secs => $4::float8))
+        RETURNING id;
+    `, userID, meta.UserAgent, meta.IP, ttl.Seconds()).Scan(&sessionID)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return 0, err
+	}
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO refresh_tokens (session_id, token_hash, created_at)
+        VALUES ($1, $2, NOW());
+    `, sessionID, tokenHash)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return 0, err
+	}
+
+	return sessionID, tx.Commit()
+}
+
+// RotateRefreshToken помечает предъявленный refresh-токен использованным и
+// выдаёт вместо него новый. Повторное предъявление уже использованного
+// токена означает его утечку — сессия отзывается целиком.
+func (db *PgStorage) RotateRefreshToken(ctx context.Context, oldHash, newHash string, ttl time.Duration) (*models.User, error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var (
+		tokenID      int
+		used, active bool
+		user         models.User
+	)
+	err = tx.QueryRowContext(ctx, `
+        SELECT rt.id, rt.used_at IS NOT NULL,
+               s.revoked_at IS NULL AND s.expires_at > NOW(),
+               s.id, u.id, u.role, u.blocked_at IS NOT NULL
+        FROM refresh_tokens rt
+        JOIN sessions s ON s.id = rt.session_id
+        JOIN users u ON u.id = s.user_id
+        WHERE rt.token_hash = $1
+        FOR UPDATE OF rt, s;
+    `, oldHash).Scan(&tokenID, &used, &active, &user.SessionID, &user.ID, &user.Role, &user.Blocked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrInvalidRefreshToken
+		}
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+
+	if used {
+		_, err = tx.ExecContext(ctx, `
+            UPDATE sessions SET revoked_at = COALESCE(revoked_at, NOW()) WHERE id = $1;
+        `, user.SessionID)
+		if err != nil {
+			logger.Log.Error(err.Error())
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		return nil, models.ErrRefreshTokenReused
+	}
+	if !active {
+		return nil, models.ErrInvalidRefreshToken
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE refresh_tokens SET used_at = NOW() WHERE id = $1;
+    `, tokenID)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO refresh_tokens (session_id, token_hash, created_at)
+        VALUES ($1, $2, NOW());
+    `, user.SessionID, newHash)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `
+        UPDATE sessions
+        SET last_used_at = NOW(), expires_at = NOW() + make_interval(secs => $2::float8)
+        WHERE id = $1;
+    `, user.SessionID, ttl.Seconds())
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+
+	return &user, tx.Commit()
+}
+
+func (db *PgStorage) GetUserSessions(ctx context.Context, userID int) ([]models.Session, error) {
+	rows, err := db.QueryContext(ctx, `
+        SELECT id, user_agent, ip, created_at, last_used_at, expires_at
+        FROM sessions
+        WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+        ORDER BY last_used_at DESC
+    `, userID)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []models.Session
+	for rows.Next() {
+		var s models.Session
+		if err := rows.Scan(&s.ID, &s.UserAgent, &s.IP, &s.CreatedAt, &s.LastUsedAt, &s.ExpiresAt); err != nil {
+			logger.Log.Error(err.Error())
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (db *PgStorage) RevokeSession(ctx context.Context, userID, sessionID int) error {
+	res, err := db.ExecContext(ctx, `
+        UPDATE sessions SET revoked_at = NOW()
+        WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+    `, sessionID, userID)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return models.ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeOtherSessions отзывает все сессии пользователя, кроме keepSessionID.
+func (db *PgStorage) RevokeOtherSessions(ctx context.Context, userID, keepSessionID int) error {
+	_, err := db.ExecContext(ctx, `
+        UPDATE sessions SET revoked_at = NOW()
+        WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL;
+    `, userID, keepSessionID)
+	if err != nil {
+		logger.Log.Error(err.Error())
+	}
+	return err
+}
+
+// RevokeToken заносит jti в стоп-лист до истечения срока действия токена,
+// заодно вычищая записи, которые уже не нужны.
+func (db *PgStorage) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	_, err := db.ExecContext(ctx, `
+        WITH purged AS (
+            DELETE FROM revoked_tokens WHERE expires_at < NOW()
+        )
+        INSERT INTO revoked_tokens (jti, expires_at)
+        VALUES ($1, $2)
+        ON CONFLICT (jti) DO NOTHING;
+    `, jti, expiresAt)
+	if err != nil {
+		logger.Log.Error(err.Error())
+	}
+	return err
+}
+
+func (db *PgStorage) IsTokenRevoked(ctx context.Context, jti string, sessionID int) (bool, error) {
+	var revoked bool
+	err := db.QueryRowContext(ctx, `
+        SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)
+            OR EXISTS (SELECT 1 FROM sessions WHERE id = $2 AND revoked_at IS NOT NULL)
+    `, jti, sessionID).Scan(&revoked)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return false, err
+	}
+	return revoked, nil
+}
@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestCreateSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`NOW() + make_interval(secs => $4::float8)`)).
+		WithArgs(1, "curl", "10.0.0.1", float64(3600)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO refresh_tokens`)).
+		WithArgs(11, "hash").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	id, err := store.CreateSession(context.Background(), 1, "hash", models.SessionMeta{UserAgent: "curl", IP: "10.0.0.1"}, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 11, id)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestRotateRefreshToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	ctx := context.Background()
+	selectToken := regexp.QuoteMeta(`FROM refresh_tokens rt JOIN sessions s ON s.id = rt.session_id`)
+	columns := []string{"id", "used", "active", "session_id", "user_id", "role", "blocked"}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectToken).
+			WithArgs("old").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(3, false, true, 11, 1, models.RoleCustomer, false))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE refresh_tokens SET used_at = NOW() WHERE id = $1;`)).
+			WithArgs(3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO refresh_tokens`)).
+			WithArgs(11, "new").
+			WillReturnResult(sqlmock.NewResult(4, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SET last_used_at = NOW(), expires_at = NOW() + make_interval(secs => $2::float8)`)).
+			WithArgs(11, float64(3600)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		user, err := store.RotateRefreshToken(ctx, "old", "new", time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, 1, user.ID)
+		assert.Equal(t, 11, user.SessionID)
+	})
+
+	t.Run("Reuse", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectToken).
+			WithArgs("old").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(3, true, true, 11, 1, models.RoleCustomer, false))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE sessions SET revoked_at = COALESCE(revoked_at, NOW()) WHERE id = $1;`)).
+			WithArgs(11).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		_, err := store.RotateRefreshToken(ctx, "old", "new", time.Hour)
+		assert.ErrorIs(t, err, models.ErrRefreshTokenReused)
+	})
+
+	t.Run("RevokedSession", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectToken).
+			WithArgs("old").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(3, false, false, 11, 1, models.RoleCustomer, false))
+		mock.ExpectRollback()
+
+		_, err := store.RotateRefreshToken(ctx, "old", "new", time.Hour)
+		assert.ErrorIs(t, err, models.ErrInvalidRefreshToken)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectToken).
+			WithArgs("old").
+			WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectRollback()
+
+		_, err := store.RotateRefreshToken(ctx, "old", "new", time.Hour)
+		assert.ErrorIs(t, err, models.ErrInvalidRefreshToken)
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestRevokeSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	query := regexp.QuoteMeta(`UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND user_id = $2`)
+
+	mock.ExpectExec(query).WithArgs(11, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, store.RevokeSession(context.Background(), 1, 11))
+
+	mock.ExpectExec(query).WithArgs(12, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, store.RevokeSession(context.Background(), 1, 12), models.ErrSessionNotFound)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestIsTokenRevoked(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)`)).
+		WithArgs("jti", 11).
+		WillReturnRows(sqlmock.NewRows([]string{"revoked"}).AddRow(true))
+
+	revoked, err := store.IsTokenRevoked(context.Background(), "jti", 11)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// ledgerEntries — операции по счетам пользователей, восстановленные из
+// первичных таблиц. Начисление за заказ датируется заведением первой
+// партии ORDER — заказ с несколькими партиями учитывается один раз, — а для
+// заказов, обработанных до появления партий, — загрузкой заказа.
+// Партии LEGACY, TRANSFER, ADJUSTMENT и CORRECTION не учитываются: они
+// дублируют заказы, переводы, корректировки и исправления сверки.
+// user — параметр запроса с id пользователя, условие на него ставится
+// в каждую ветку, чтобы читались только его строки; пустая строка —
+// операции всех пользователей.
+func ledgerEntries(user string) string {
+	owner := func(alias string) string {
+		if user == "" {
+			return "TRUE"
+		}
+		return alias + ".user_id = " + user
+	}
+	return `
+        SELECT o.user_id, 'ACCRUAL' AS kind, o.number AS order_number, o.accrual AS amount,
+            COALESCE((
+                SELECT MIN(l.accrued_at) FROM accrual_lots l
+                WHERE l.order_number = o.number AND l.source = 'ORDER'
+            ), o.uploaded_at) AS at, o.id AS ref
+        FROM orders o
+        WHERE ` + owner("o") + ` AND o.status = 'PROCESSED' AND o.accrual > 0
+        UNION ALL
+        SELECT l.user_id, l.source, COALESCE(l.order_number, ''), l.amount, l.accrued_at, l.id
+        FROM accrual_lots l
+        WHERE ` + owner("l") + ` AND l.source IN ('CAMPAIGN', 'REFERRAL')
+        UNION ALL
+        SELECT t.user_id, 'TRANSFER_' || t.direction, '', CASE WHEN t.direction = 'IN' THEN t.sum ELSE -t.sum END, t.created_at, t.id
+        FROM transfers t
+        WHERE ` + owner("t") + `
+        UNION ALL
+        SELECT a.user_id, 'ADJUSTMENT', '', a.amount, a.created_at, a.id
+        FROM balance_adjustments a
+        WHERE ` + owner("a") + `
+        UNION ALL
+        SELECT c.user_id, 'CORRECTION', '', c.amount, c.created_at, c.id
+        FROM balance_corrections c
+        WHERE ` + owner("c") + `
+        UNION ALL
+        SELECT w.user_id, 'WITHDRAWAL', w.order_number, -w.sum, w.uploaded_at, w.id
+        FROM withdrawals w
+        WHERE ` + owner("w") + `
+        UNION ALL
+        SELECT l.user_id, 'EXPIRY', COALESCE(l.order_number, ''), -l.expired_amount, l.expired_at, l.id
+        FROM accrual_lots l
+        WHERE ` + owner("l") + ` AND l.expired_amount > 0`
+}
+
+// StreamStatement передаёт в fn операции за [from, to) в хронологическом
+// порядке вместе с балансом после каждой из них. Строки читаются из
+// курсора по одной, поэтому выписка за большой период не собирается в
+// памяти; ошибка fn прерывает чтение. Границы передаются как timestamptz,
+// чтобы смещение из запроса (RFC 3339) не отбрасывалось.
+func (db *PgStorage) StreamStatement(ctx context.Context, userID int, from, to time.Time, fn func(models.StatementEntry) error) error {
+	rows, err := db.QueryContext(ctx, `
+        WITH ledger AS (`+ledgerEntries("$1")+`
+        ), balances AS (
+            SELECT kind, order_number, amount, at, ref,
+                SUM(amount) OVER (ORDER BY at, kind, ref) AS balance
+            FROM ledger
+            WHERE at < $3::timestamptz
+        )
+        SELECT kind, order_number, amount, balance, at
+        FROM balances
+        WHERE at >= $2::timestamptz
+        ORDER BY at, kind, ref;
+    `, userID, from, to)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e models.StatementEntry
+		if err := rows.Scan(&e.Type, &e.Order, &e.Amount, &e.Balance, &e.ProcessedAt); err != nil {
+			logger.Log.Error(err.Error())
+			return err
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// GetBalanceAt восстанавливает баланс и сумму списаний пользователя по
+// операциям, совершённым до момента at.
+func (db *PgStorage) GetBalanceAt(ctx context.Context, userID int, at time.Time) (models.Balance, error) {
+	var balance models.Balance
+	err := db.QueryRowContext(ctx, `
+        WITH ledger AS (`+ledgerEntries("$1")+`
+        )
+        SELECT COALESCE(SUM(amount), 0), COALESCE(SUM(-amount) FILTER (WHERE kind = 'WITHDRAWAL'), 0)
+        FROM ledger
+        WHERE at < $2::timestamptz;
+    `, userID, at).Scan(&balance.Current, &balance.Withdrawn)
+	if err != nil {
+		logger.Log.Error(err.Error())
+	}
+	return balance, err
+}
@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestStreamStatement(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	msk := time.FixedZone("MSK", 3*60*60)
+	from := time.Date(2025, 3, 1, 3, 0, 0, 0, msk)
+	to := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta(`WHERE w.user_id = $1 UNION ALL SELECT l.user_id, 'EXPIRY', COALESCE(l.order_number, ''), -l.expired_amount, l.expired_at, l.id FROM accrual_lots l WHERE l.user_id = $1 AND l.expired_amount > 0 ), balances AS ( SELECT kind, order_number, amount, at, ref, SUM(amount) OVER (ORDER BY at, kind, ref) AS balance FROM ledger WHERE at < $3::timestamptz ) SELECT kind, order_number, amount, balance, at FROM balances WHERE at >= $2::timestamptz`)
+	columns := []string{"kind", "order_number", "amount", "balance", "at"}
+
+	mock.ExpectQuery(query).WithArgs(1, from, to).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(models.StatementAccrual, "12345678903", 500.0, 700.0, from.Add(time.Hour)).
+			AddRow(models.StatementWithdrawal, "2377225624", -100.0, 600.0, from.Add(2*time.Hour)))
+
+	var entries []models.StatementEntry
+	err = store.StreamStatement(context.Background(), 1, from, to, func(e models.StatementEntry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []models.StatementEntry{
+		{Type: models.StatementAccrual, Order: "12345678903", Amount: 500, Balance: 700, ProcessedAt: from.Add(time.Hour)},
+		{Type: models.StatementWithdrawal, Order: "2377225624", Amount: -100, Balance: 600, ProcessedAt: from.Add(2 * time.Hour)},
+	}, entries)
+
+	stop := errors.New("клиент отключился")
+	mock.ExpectQuery(query).WithArgs(1, from, to).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(models.StatementAccrual, "12345678903", 500.0, 700.0, from).
+			AddRow(models.StatementAccrual, "2377225624", 100.0, 800.0, from))
+	calls := 0
+	err = store.StreamStatement(context.Background(), 1, from, to, func(e models.StatementEntry) error {
+		calls++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBalanceAt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	at := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM ledger WHERE at < $2::timestamptz;`)).WithArgs(1, at).
+		WillReturnRows(sqlmock.NewRows([]string{"current", "withdrawn"}).AddRow(600.0, 100.0))
+
+	balance, err := store.GetBalanceAt(context.Background(), 1, at)
+	require.NoError(t, err)
+	assert.Equal(t, models.Balance{Current: 600, Withdrawn: 100}, balance)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// Партий ORDER у заказа может быть несколько (например, после повторного
+// начисления), а начисление за заказ должно попасть в выписку один раз.
+func TestLedgerCountsOrderOnce(t *testing.T) {
+	assert.NotContains(t, ledgerEntries(""), "LEFT JOIN accrual_lots")
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	at := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT MIN(l.accrued_at) FROM accrual_lots l WHERE l.order_number = o.number AND l.source = 'ORDER'`)).
+		WithArgs(1, at).
+		WillReturnRows(sqlmock.NewRows([]string{"current", "withdrawn"}).AddRow(500.0, 0.0))
+
+	balance, err := store.GetBalanceAt(context.Background(), 1, at)
+	require.NoError(t, err)
+	assert.Equal(t, models.Balance{Current: 500}, balance)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// Выписка и исправление баланса читают историю одного пользователя:
+// условие на него стоит в каждой ветке, а не поверх всех операций.
+func TestLedgerFiltersEveryBranch(t *testing.T) {
+	query := ledgerEntries("$1")
+	assert.Equal(t, strings.Count(query, "UNION ALL")+1, strings.Count(query, ".user_id = $1"))
+	assert.NotContains(t, ledgerEntries(""), ".user_id =")
+}
+
+// Исправление сверки — операция по счёту: без неё история разошлась бы с
+// исправленным балансом, а пользователь не увидел бы исправления в выписке.
+func TestLedgerIncludesCorrections(t *testing.T) {
+	query := strings.Join(strings.Fields(ledgerEntries("$1")), " ")
+	assert.Contains(t, query, "SELECT c.user_id, 'CORRECTION', '', c.amount, c.created_at, c.id FROM balance_corrections c")
+}
@@ -0,0 +1,201 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+func (db *PgStorage) GetLoginThrottles(ctx context.Context, login, ip string) ([]models.LoginThrottle, error) {
+	rows, err := db.QueryContext(ctx, `
+        SELECT scope, key, failures, last_failure_at, COALESCE(locked_until, 'epoch'::timestamp)
+        FROM login_throttle
+        WHERE (scope = $1 AND key = $2) OR (scope = $3 AND key = $4)
+    `, models.ThrottleScopeLogin, login, models.ThrottleScopeIP, ip)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var throttles []models.LoginThrottle
+	for rows.Next() {
+		var t models.LoginThrottle
+		if err := rows.Scan(&t.Scope, &t.Key, &t.Failures, &t.LastFailureAt, &t.LockedUntil); err != nil {
+			logger.Log.Error(err.Error())
+			return nil, err
+		}
+		throttles = append(throttles, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+
+	return throttles, nil
+}
+
+// getThrottle возвращает счётчик одного ключа; отсутствующий счётчик — это
+// нулевое значение.
+func (db *PgStorage) getThrottle(ctx context.Context, scope, key string) (models.LoginThrottle, error) {
+	t := models.LoginThrottle{Scope: scope, Key: key}
+	err := db.QueryRowContext(ctx, `
+        SELECT failures, last_failure_at, COALESCE(locked_until, 'epoch'::timestamp)
+        FROM login_throttle
+        WHERE scope = $1 AND key = $2;
+    `, scope, key).Scan(&t.Failures, &t.LastFailureAt, &t.LockedUntil)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error(err.Error())
+		return t, err
+	}
+	return t, nil
+}
+
+func (db *PgStorage) GetPasswordResetThrottle(ctx context.Context, login string) (models.LoginThrottle, error) {
+	return db.getThrottle(ctx, models.ThrottleScopeReset, login)
+}
+
+// RecordPasswordResetRequest учитывает запрос сброса пароля для логина,
+// в том числе несуществующего, чтобы ограничение не выдавало, какие логины
+// зарегистрированы.
+func (db *PgStorage) RecordPasswordResetRequest(ctx context.Context, login string, policy models.LoginPolicy) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = registerFailure(ctx, tx, models.ThrottleScopeReset, login, policy.MaxFailures, policy.Lockout, policy.Window)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func auditLogin(ctx context.Context, tx *sql.Tx, login, ip string, success bool, reason string) error {
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO login_audit (login, ip, success, reason, created_at)
+        VALUES ($1, $2, $3, $4, NOW());
+    `, login, ip, success, reason)
+	return err
+}
+
+// registerFailure увеличивает счётчик неудач; счётчик, не обновлявшийся
+// дольше окна, начинается заново. Нулевое окно счётчик не сбрасывает. При
+// достижении порога ставится блокировка.
+func registerFailure(ctx context.Context, tx *sql.Tx, scope, key string, maxFailures int, lockout, window time.Duration) error {
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO login_throttle AS t (scope, key, failures, last_failure_at, locked_until)
+        VALUES ($1, $2, 1, NOW(), CASE WHEN $3::int > 0 AND $3::int <= 1 THEN NOW() + make_interval(secs => $4::float8) END)
+        ON CONFLICT (scope, key) DO UPDATE SET
+            failures = CASE WHEN $5::float8 > 0 AND t.last_failure_at < NOW() - make_interval(secs => $5::float8) THEN 1 ELSE t.failures + 1 END,
+            last_failure_at = NOW(),
+            locked_until = CASE
+                WHEN $3::int > 0 AND (CASE WHEN $5::float8 > 0 AND t.last_failure_at < NOW() - make_interval(secs => $5::float8) THEN 1 ELSE t.failures + 1 END) >= $3::int
+                THEN NOW() + make_interval(secs => $4::float8)
+                ELSE t.locked_until
+            END;
+    `, scope, key, maxFailures, lockout.Seconds(), window.Seconds())
+	return err
+}
+
+// RecordLoginFailure учитывает неудачную попытку входа по логину и по IP и
+// записывает её в журнал.
+func (db *PgStorage) RecordLoginFailure(ctx context.Context, login, ip, reason string, policy models.LoginPolicy) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = registerFailure(ctx, tx, models.ThrottleScopeLogin, login, policy.MaxFailures, policy.Lockout, policy.Window)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	if ip != "" {
+		err = registerFailure(ctx, tx, models.ThrottleScopeIP, ip, policy.IPMaxFailures, policy.Lockout, policy.Window)
+		if err != nil {
+			logger.Log.Error(err.Error())
+			return err
+		}
+	}
+	if err := auditLogin(ctx, tx, login, ip, false, reason); err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RecordLoginSuccess сбрасывает счётчик неудач логина. Счётчик IP не
+// сбрасывается: иначе перебор по многим логинам прятался бы за одним
+// известным паролем.
+func (db *PgStorage) RecordLoginSuccess(ctx context.Context, login, ip string) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM login_throttle WHERE scope = $1 AND key = $2;
+    `, models.ThrottleScopeLogin, login)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	if err := auditLogin(ctx, tx, login, ip, true, ""); err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *PgStorage) UnlockLogin(ctx context.Context, adminID, userID int) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var login string
+	err = tx.QueryRowContext(ctx, `SELECT login FROM users WHERE id = $1`, userID).Scan(&login)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ErrUserNotFound
+		}
+		logger.Log.Error(err.Error())
+		return err
+	}
+	// Вместе с логином снимается блокировка адресов, с которых в него
+	// входили неудачно, иначе пользователь остаётся заблокирован по IP.
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM login_throttle
+        WHERE (scope = $1 AND key = $2)
+           OR (scope = $3 AND key IN (SELECT ip FROM login_audit WHERE login = $2 AND NOT success AND ip <> ''));
+    `, models.ThrottleScopeLogin, login, models.ThrottleScopeIP)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	err = logAdminAction(ctx, tx, models.AdminAction{
+		AdminID:      adminID,
+		Action:       models.AdminActionUnlock,
+		TargetUserID: userID,
+	})
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestRecordLoginFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	policy := models.LoginPolicy{MaxFailures: 5, IPMaxFailures: 50, Lockout: 15 * time.Minute, Window: time.Hour}
+	upsert := regexp.QuoteMeta(`WHEN $5::float8 > 0 AND t.last_failure_at < NOW() - make_interval(secs => $5::float8) THEN 1`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(upsert).
+		WithArgs(models.ThrottleScopeLogin, "alice", 5, float64(900), float64(3600)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(upsert).
+		WithArgs(models.ThrottleScopeIP, "10.0.0.1", 50, float64(900), float64(3600)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO login_audit`)).
+		WithArgs("alice", "10.0.0.1", false, models.LoginReasonInvalidPassword).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = store.RecordLoginFailure(context.Background(), "alice", "10.0.0.1", models.LoginReasonInvalidPassword, policy)
+	assert.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestRecordLoginSuccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM login_throttle WHERE scope = $1 AND key = $2;`)).
+		WithArgs(models.ThrottleScopeLogin, "alice").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO login_audit`)).
+		WithArgs("alice", "10.0.0.1", true, "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, store.RecordLoginSuccess(context.Background(), "alice", "10.0.0.1"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestUnlockLogin(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT login FROM users WHERE id = $1`)).
+			WithArgs(5).
+			WillReturnRows(sqlmock.NewRows([]string{"login"}).AddRow("alice"))
+		mock.ExpectExec(regexp.QuoteMeta(`OR (scope = $3 AND key IN (SELECT ip FROM login_audit WHERE login = $2 AND NOT success`)).
+			WithArgs(models.ThrottleScopeLogin, "alice", models.ThrottleScopeIP).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO admin_actions`)).
+			WithArgs(1, models.AdminActionUnlock, 5, "", "").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, store.UnlockLogin(ctx, 1, 5))
+	})
+
+	t.Run("UserNotFound", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT login FROM users WHERE id = $1`)).
+			WithArgs(6).
+			WillReturnRows(sqlmock.NewRows([]string{"login"}))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, store.UnlockLogin(ctx, 1, 6), models.ErrUserNotFound)
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestPasswordResetThrottle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	ctx := context.Background()
+	policy := models.LoginPolicy{MaxFailures: 3, Lockout: time.Hour, Window: time.Hour}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM login_throttle WHERE scope = $1 AND key = $2;`)).
+		WithArgs(models.ThrottleScopeReset, "alice").
+		WillReturnRows(sqlmock.NewRows([]string{"failures", "last_failure_at", "locked_until"}))
+	throttle, err := store.GetPasswordResetThrottle(ctx, "alice")
+	require.NoError(t, err)
+	assert.Zero(t, throttle.Failures)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO login_throttle AS t`)).
+		WithArgs(models.ThrottleScopeReset, "alice", 3, float64(3600), float64(3600)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, store.RecordPasswordResetRequest(ctx, "alice", policy))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// rollingAccrualQuery — сумма начислений за заказы за скользящее окно до
+// применения множителя уровня, чтобы множители не накладывались друг на
+// друга.
+const rollingAccrualQuery = `
+    SELECT COALESCE(SUM(base_accrual), 0)
+    FROM orders
+    WHERE user_id = $1 AND status = 'PROCESSED' AND processed_at >= NOW() - make_interval(months => $2::int)
+`
+
+// recomputeTier пересчитывает уровень пользователя по сумме начислений за
+// скользящее окно и возвращает множитель нового уровня.
+func recomputeTier(ctx context.Context, tx *sql.Tx, userID int, windowMonths int) (float64, error) {
+	var multiplier float64
+	err := tx.QueryRowContext(ctx, `
+        UPDATE users u
+        SET tier_id = t.id
+        FROM (
+            SELECT id, multiplier
+            FROM tiers
+            WHERE min_points <= (`+rollingAccrualQuery+`)
+            ORDER BY min_points DESC
+            LIMIT 1
+        ) t
+        WHERE u.id = $1
+        RETURNING t.multiplier;
+    `, userID, windowMonths).Scan(&multiplier)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 1, nil
+		}
+		return 0, err
+	}
+	return multiplier, nil
+}
+
+func (db *PgStorage) GetTiers(ctx context.Context) ([]models.Tier, error) {
+	var tiers []models.Tier
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT name, min_points, multiplier
+		FROM tiers
+		ORDER BY min_points
+	`)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tier models.Tier
+		if err := rows.Scan(&tier.Name, &tier.MinPoints, &tier.Multiplier); err != nil {
+			logger.Log.Error(err.Error())
+			return nil, err
+		}
+		tiers = append(tiers, tier)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+
+	return tiers, nil
+}
+
+func (db *PgStorage) GetRollingAccrual(ctx context.Context, userID int, windowMonths int) (float64, error) {
+	var accrued float64
+	err := db.QueryRowContext(ctx, rollingAccrualQuery, userID, windowMonths).Scan(&accrued)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return 0, err
+	}
+	return accrued, nil
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTiers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT name, min_points, multiplier FROM tiers ORDER BY min_points`)).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "min_points", "multiplier"}).
+			AddRow("Bronze", 0.0, 1.0).
+			AddRow("Silver", 1000.0, 1.25))
+
+	tiers, err := store.GetTiers(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tiers, 2)
+	assert.Equal(t, "Silver", tiers[1].Name)
+	assert.Equal(t, 1.25, tiers[1].Multiplier)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestGetRollingAccrual(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	query := regexp.QuoteMeta(`SELECT COALESCE(SUM(base_accrual), 0) FROM orders`)
+
+	mock.ExpectQuery(query).
+		WithArgs(1, 12).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(1500.0))
+
+	accrued, err := store.GetRollingAccrual(context.Background(), 1, 12)
+	require.NoError(t, err)
+	assert.Equal(t, 1500.0, accrued)
+
+	mock.ExpectQuery(query).
+		WithArgs(1, 12).
+		WillReturnError(errors.New("db error"))
+
+	_, err = store.GetRollingAccrual(context.Background(), 1, 12)
+	assert.Error(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// Transfer переводит баллы другому пользователю. Перевод, отменённый
+// из-за конфликта с параллельной транзакцией, повторяется.
+func (db *PgStorage) Transfer(ctx context.Context, senderID int, recipientLogin string, sum float64, limits models.TransferLimits) error {
+	return retrySerializable(func() error {
+		return db.transfer(ctx, senderID, recipientLogin, sum, limits)
+	})
+}
+
+func (db *PgStorage) transfer(ctx context.Context, senderID int, recipientLogin string, sum float64, limits models.TransferLimits) error {
+	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Заблокированным, удалённым и служебным учётным записям перевести
+	// нельзя: баллы на них никто не потратит.
+	var recipientID int
+	err = tx.QueryRowContext(ctx, `
+        SELECT id FROM users
+        WHERE login = $1 AND role = $2 AND deleted_at IS NULL AND blocked_at IS NULL;
+    `, recipientLogin, models.RoleCustomer).Scan(&recipientID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ErrUserNotFound
+		}
+		return err
+	}
+	if recipientID == senderID {
+		return models.ErrSelfTransfer
+	}
+
+	// Блокируем обе строки в порядке возрастания id, чтобы встречные
+	// переводы не приводили к взаимной блокировке.
+	rows, err := tx.QueryContext(ctx, `
+        SELECT id, current_balance FROM users WHERE id IN ($1, $2) ORDER BY id FOR UPDATE;
+    `, senderID, recipientID)
+	if err != nil {
+		return err
+	}
+	var senderBalance float64
+	for rows.Next() {
+		var id int
+		var balance float64
+		if err := rows.Scan(&id, &balance); err != nil {
+			rows.Close()
+			return err
+		}
+		if id == senderID {
+			senderBalance = balance
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if limits.DailySum > 0 || limits.DailyCount > 0 {
+		var sentSum float64
+		var sentCount int
+		err = tx.QueryRowContext(ctx, `
+            SELECT COALESCE(SUM(sum), 0), COUNT(*)
+            FROM transfers
+            WHERE user_id = $1 AND direction = $2 AND created_at >= date_trunc('day', NOW());
+        `, senderID, models.TransferOut).Scan(&sentSum, &sentCount)
+		if err != nil {
+			return err
+		}
+		if limits.DailySum > 0 && sentSum+sum > limits.DailySum {
+			return models.ErrTransferLimitExceeded
+		}
+		if limits.DailyCount > 0 && sentCount+1 > limits.DailyCount {
+			return models.ErrTransferLimitExceeded
+		}
+	}
+
+	if senderBalance < sum {
+		return models.ErrInsufficientFunds
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO transfers (user_id, counterparty_id, direction, sum, created_at)
+        VALUES ($1, $2, $3, $5, NOW()), ($2, $1, $4, $5, NOW());
+    `, senderID, recipientID, models.TransferOut, models.TransferIn, sum)
+	if err != nil {
+		return err
+	}
+	consumed, err := consumeLots(ctx, tx, senderID, sum)
+	if err != nil {
+		return err
+	}
+	if err := moveLots(ctx, tx, recipientID, consumed, sum); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+        UPDATE users
+        SET current_balance = current_balance - $1
+        WHERE id = $2;
+    `, sum, senderID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+        UPDATE users
+        SET current_balance = current_balance + $1
+        WHERE id = $2;
+    `, sum, recipientID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *PgStorage) GetUserTransfers(ctx context.Context, userID int) ([]models.Transfer, error) {
+	var transfers []models.Transfer
+
+	query := `
+		SELECT t.direction, u.login, t.sum, t.created_at
+		FROM transfers t
+		JOIN users u ON u.id = t.counterparty_id
+		WHERE t.user_id = $1
+		ORDER BY t.created_at DESC
+	`
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transfer models.Transfer
+		if err := rows.Scan(&transfer.Direction, &transfer.Counterparty, &transfer.Sum, &transfer.ProcessedAt); err != nil {
+			logger.Log.Error(err.Error())
+			return nil, err
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+
+	return transfers, nil
+}
+
+// moveLots зачисляет получателю партии с теми же сроками сгорания, что и у
+// списанных у отправителя. Непокрытый партиями остаток считается бессрочным.
+func moveLots(ctx context.Context, tx *sql.Tx, recipientID int, consumed []consumedLot, sum float64) error {
+	covered := 0.0
+	for _, lot := range consumed {
+		covered += lot.amount
+	}
+	if rest := sum - covered; rest >= 0.005 {
+		consumed = append(consumed, consumedLot{amount: rest})
+	}
+	for _, lot := range consumed {
+		_, err := tx.ExecContext(ctx, `
+            INSERT INTO accrual_lots (user_id, source, amount, remaining, accrued_at, expires_at)
+            VALUES ($1, $2, $3, $3, NOW(), $4);
+        `, recipientID, models.LotSourceTransfer, lot.amount, lot.expiresAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestTransfer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	ctx := context.Background()
+	limits := models.TransferLimits{DailySum: 1000, DailyCount: 5}
+	expiresAt := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	recipient := regexp.QuoteMeta(`WHERE login = $1 AND role = $2 AND deleted_at IS NULL AND blocked_at IS NULL;`)
+	expectLock := func(senderBalance float64) {
+		mock.ExpectQuery(recipient).
+			WithArgs("bob", models.RoleCustomer).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, current_balance FROM users WHERE id IN ($1, $2) ORDER BY id FOR UPDATE;`)).
+			WithArgs(1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "current_balance"}).AddRow(1, senderBalance).AddRow(2, 0.0))
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectLock(200)
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(SUM(sum), 0), COUNT(*)`)).
+			WithArgs(1, models.TransferOut).
+			WillReturnRows(sqlmock.NewRows([]string{"sum", "count"}).AddRow(0.0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO transfers`)).
+			WithArgs(1, 2, models.TransferOut, models.TransferIn, 50.0).
+			WillReturnResult(sqlmock.NewResult(1, 2))
+		mock.ExpectQuery(regexp.QuoteMeta(`UPDATE accrual_lots l SET remaining = l.remaining - c.take`)).
+			WithArgs(1, 50.0).
+			WillReturnRows(sqlmock.NewRows([]string{"take", "expires_at"}).
+				AddRow(30.0, expiresAt).
+				AddRow(15.0, nil))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO accrual_lots`)).
+			WithArgs(2, models.LotSourceTransfer, 30.0, sql.NullTime{Time: expiresAt, Valid: true}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO accrual_lots`)).
+			WithArgs(2, models.LotSourceTransfer, 15.0, sql.NullTime{}).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO accrual_lots`)).
+			WithArgs(2, models.LotSourceTransfer, 5.0, sql.NullTime{}).
+			WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SET current_balance = current_balance - $1`)).
+			WithArgs(50.0, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SET current_balance = current_balance + $1`)).
+			WithArgs(50.0, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, store.Transfer(ctx, 1, "bob", 50, limits))
+	})
+
+	t.Run("RecipientNotFound", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(recipient).
+			WithArgs("bob", models.RoleCustomer).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, store.Transfer(ctx, 1, "bob", 50, limits), models.ErrUserNotFound)
+	})
+
+	// Логин удалённой учётной записи предсказуем, но перевод на неё не
+	// проходит: строка отсекается условием на deleted_at.
+	t.Run("DeletedRecipient", func(t *testing.T) {
+		deleted := models.DeletedLoginPrefix + "7"
+		mock.ExpectBegin()
+		mock.ExpectQuery(recipient).
+			WithArgs(deleted, models.RoleCustomer).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, store.Transfer(ctx, 1, deleted, 50, limits), models.ErrUserNotFound)
+	})
+
+	t.Run("SerializationFailureRetried", func(t *testing.T) {
+		conflict := &pgconn.PgError{Code: "40001"}
+		mock.ExpectBegin()
+		mock.ExpectQuery(recipient).
+			WithArgs("bob", models.RoleCustomer).
+			WillReturnError(conflict)
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectQuery(recipient).
+			WithArgs("bob", models.RoleCustomer).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, store.Transfer(ctx, 2, "bob", 50, limits), models.ErrSelfTransfer)
+	})
+
+	t.Run("SerializationFailureGivesUp", func(t *testing.T) {
+		conflict := &pgconn.PgError{Code: "40001"}
+		for i := 0; i < serializableAttempts; i++ {
+			mock.ExpectBegin()
+			mock.ExpectQuery(recipient).
+				WithArgs("bob", models.RoleCustomer).
+				WillReturnError(conflict)
+			mock.ExpectRollback()
+		}
+
+		assert.ErrorIs(t, store.Transfer(ctx, 1, "bob", 50, limits), conflict)
+	})
+
+	t.Run("SelfTransfer", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(recipient).
+			WithArgs("bob", models.RoleCustomer).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, store.Transfer(ctx, 2, "bob", 50, limits), models.ErrSelfTransfer)
+	})
+
+	t.Run("DailyLimitExceeded", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectLock(5000)
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(SUM(sum), 0), COUNT(*)`)).
+			WithArgs(1, models.TransferOut).
+			WillReturnRows(sqlmock.NewRows([]string{"sum", "count"}).AddRow(980.0, 2))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, store.Transfer(ctx, 1, "bob", 50, limits), models.ErrTransferLimitExceeded)
+	})
+
+	t.Run("InsufficientFunds", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectLock(10)
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(SUM(sum), 0), COUNT(*)`)).
+			WithArgs(1, models.TransferOut).
+			WillReturnRows(sqlmock.NewRows([]string{"sum", "count"}).AddRow(0.0, 0))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, store.Transfer(ctx, 1, "bob", 50, limits), models.ErrInsufficientFunds)
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestGetUserTransfers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT t.direction, u.login, t.sum, t.created_at FROM transfers t`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"direction", "login", "sum", "created_at"}).
+			AddRow(models.TransferOut, "bob", 50.0, now).
+			AddRow(models.TransferIn, "carol", 20.0, now))
+
+	transfers, err := store.GetUserTransfers(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Len(t, transfers, 2)
+	assert.Equal(t, "bob", transfers[0].Counterparty)
+	assert.Equal(t, models.TransferIn, transfers[1].Direction)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
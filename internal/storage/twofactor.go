@@ -0,0 +1,282 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+// GetTOTP возвращает настройки 2FA; если пользователь их не заводил,
+// возвращается пустая структура.
+func (db *PgStorage) GetTOTP(ctx context.Context, userID int) (models.TOTP, error) {
+	var t models.TOTP
+	err := db.QueryRowContext(ctx, `
+        SELECT secret, enabled_at IS NOT NULL, last_step, COALESCE(withdraw_threshold, 0)
+        FROM user_totp
+        WHERE user_id = $1;
+    `, userID).Scan(&t.Secret, &t.Enabled, &t.LastStep, &t.WithdrawThreshold)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.TOTP{}, nil
+		}
+		logger.Log.Error(err.Error())
+		return t, err
+	}
+	return t, nil
+}
+
+// SaveTOTPSecret начинает подключение 2FA. Неподтверждённый секрет
+// перезаписывается, подтверждённый — нет.
+func (db *PgStorage) SaveTOTPSecret(ctx context.Context, userID int, secret string) error {
+	res, err := db.ExecContext(ctx, `
+        INSERT INTO user_totp (user_id, secret, created_at)
+        VALUES ($1, $2, NOW())
+        ON CONFLICT (user_id) DO UPDATE
+        SET secret = EXCLUDED.secret, last_step = 0, created_at = NOW()
+        WHERE user_totp.enabled_at IS NULL;
+    `, userID, secret)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		if err == nil {
+			err = models.ErrTOTPAlreadyEnabled
+		}
+		return err
+	}
+	return nil
+}
+
+// EnableTOTP подтверждает подключение 2FA и сохраняет хеши кодов
+// восстановления, заменяя выданные ранее.
+func (db *PgStorage) EnableTOTP(ctx context.Context, userID int, step int64, codeHashes []string) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+        UPDATE user_totp SET enabled_at = NOW(), last_step = $2
+        WHERE user_id = $1 AND enabled_at IS NULL;
+    `, userID, step)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		if err == nil {
+			err = models.ErrTOTPAlreadyEnabled
+		}
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM totp_recovery_codes WHERE user_id = $1;
+    `, userID)
+	if err != nil {
+		return err
+	}
+	for _, hash := range codeHashes {
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO totp_recovery_codes (user_id, code_hash) VALUES ($1, $2);
+        `, userID, hash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (db *PgStorage) DisableTOTP(ctx context.Context, userID int) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM totp_recovery_codes WHERE user_id = $1;
+    `, userID)
+	if err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, `
+        DELETE FROM user_totp WHERE user_id = $1;
+    `, userID)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		if err == nil {
+			err = models.ErrTOTPNotEnabled
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UseTOTPStep запоминает принятый шаг TOTP. Возвращает false, если этот
+// или более поздний шаг уже был использован.
+func (db *PgStorage) UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error) {
+	res, err := db.ExecContext(ctx, `
+        UPDATE user_totp SET last_step = $2
+        WHERE user_id = $1 AND last_step < $2;
+    `, userID, step)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	return affected > 0, err
+}
+
+func (db *PgStorage) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	res, err := db.ExecContext(ctx, `
+        UPDATE totp_recovery_codes SET used_at = NOW()
+        WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+    `, userID, codeHash)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	return affected > 0, err
+}
+
+func (db *PgStorage) SetWithdrawThreshold(ctx context.Context, userID int, threshold float64) error {
+	res, err := db.ExecContext(ctx, `
+        UPDATE user_totp SET withdraw_threshold = NULLIF($2, 0)
+        WHERE user_id = $1 AND enabled_at IS NOT NULL;
+    `, userID, threshold)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		if err == nil {
+			err = models.ErrTOTPNotEnabled
+		}
+		return err
+	}
+	return nil
+}
+
+// CreateLoginChallenge выпускает токен второго шага входа. Действующих
+// токенов у пользователя остаётся не больше maxActive: самые старые
+// гасятся, чтобы повторный ввод пароля не давал новых попыток подбора кода.
+func (db *PgStorage) CreateLoginChallenge(ctx context.Context, userID int, tokenHash string, ttl time.Duration, maxActive int) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE login_challenges SET used_at = NOW()
+        WHERE id IN (
+            SELECT id FROM login_challenges
+            WHERE user_id = $1 AND used_at IS NULL AND expires_at > NOW()
+            ORDER BY created_at DESC, id DESC
+            OFFSET GREATEST($2::int - 1, 0)
+        );
+    `, userID, maxActive)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO login_challenges (user_id, token_hash, created_at, expires_at)
+        VALUES ($1, $2, NOW(), NOW() + make_interval(secs => $3::float8));
+    `, userID, tokenHash, ttl.Seconds())
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetLoginChallenge возвращает пользователя действующего токена входа.
+func (db *PgStorage) GetLoginChallenge(ctx context.Context, tokenHash string) (*models.User, error) {
+	var user models.User
+	err := db.QueryRowContext(ctx, `
+        SELECT u.id, u.login FROM login_challenges c
+        JOIN users u ON u.id = c.user_id
+        WHERE c.token_hash = $1 AND c.used_at IS NULL AND c.expires_at > NOW();
+    `, tokenHash).Scan(&user.ID, &user.Login)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrInvalidChallenge
+		}
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FailLoginChallenge учитывает неверный код; после maxAttempts ошибок токен
+// входа гасится.
+func (db *PgStorage) FailLoginChallenge(ctx context.Context, tokenHash string, maxAttempts int) error {
+	_, err := db.ExecContext(ctx, `
+        UPDATE login_challenges
+        SET attempts = attempts + 1,
+            used_at = CASE WHEN attempts + 1 >= $2::int THEN NOW() END
+        WHERE token_hash = $1 AND used_at IS NULL;
+    `, tokenHash, maxAttempts)
+	if err != nil {
+		logger.Log.Error(err.Error())
+	}
+	return err
+}
+
+// ConsumeLoginChallenge гасит токен входа и возвращает пользователя, для
+// которого выпускается сессия.
+func (db *PgStorage) ConsumeLoginChallenge(ctx context.Context, tokenHash string) (*models.User, error) {
+	var user models.User
+	err := db.QueryRowContext(ctx, `
+        UPDATE login_challenges c SET used_at = NOW()
+        FROM users u
+        WHERE c.token_hash = $1 AND c.used_at IS NULL AND c.expires_at > NOW() AND u.id = c.user_id
+        RETURNING u.id, u.login, u.role, u.blocked_at IS NOT NULL;
+    `, tokenHash).Scan(&user.ID, &user.Login, &user.Role, &user.Blocked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrInvalidChallenge
+		}
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (db *PgStorage) GetTOTPThrottle(ctx context.Context, userID int) (models.LoginThrottle, error) {
+	return db.getThrottle(ctx, models.ThrottleScopeTOTP, strconv.Itoa(userID))
+}
+
+// RecordTOTPFailure учитывает неверный код 2FA, введённый в уже открытой
+// сессии. Как и для кодов оплаты, удачный ввод счётчик не сбрасывает.
+func (db *PgStorage) RecordTOTPFailure(ctx context.Context, userID int, policy models.LoginPolicy) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = registerFailure(ctx, tx, models.ThrottleScopeTOTP, strconv.Itoa(userID), policy.MaxFailures, policy.Lockout, policy.Window)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+
+	return tx.Commit()
+}
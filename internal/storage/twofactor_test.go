@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestGetTOTP(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	query := regexp.QuoteMeta(`SELECT secret, enabled_at IS NOT NULL, last_step, COALESCE(withdraw_threshold, 0)`)
+
+	mock.ExpectQuery(query).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"secret", "enabled", "last_step", "threshold"}).AddRow("SECRET", true, 100, 500.0))
+	got, err := store.GetTOTP(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, models.TOTP{Secret: "SECRET", Enabled: true, LastStep: 100, WithdrawThreshold: 500}, got)
+
+	mock.ExpectQuery(query).WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"secret", "enabled", "last_step", "threshold"}))
+	got, err = store.GetTOTP(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, models.TOTP{}, got)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestSaveTOTPSecret(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	query := regexp.QuoteMeta(`INSERT INTO user_totp (user_id, secret, created_at)`)
+
+	mock.ExpectExec(query).WithArgs(1, "SECRET").WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, store.SaveTOTPSecret(context.Background(), 1, "SECRET"))
+
+	mock.ExpectExec(query).WithArgs(1, "OTHER").WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, store.SaveTOTPSecret(context.Background(), 1, "OTHER"), models.ErrTOTPAlreadyEnabled)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestEnableTOTP(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE user_totp SET enabled_at = NOW(), last_step = $2`)).
+		WithArgs(1, int64(42)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM totp_recovery_codes WHERE user_id = $1;`)).
+		WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+	for _, hash := range []string{"h1", "h2"} {
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO totp_recovery_codes (user_id, code_hash) VALUES ($1, $2);`)).
+			WithArgs(1, hash).WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	mock.ExpectCommit()
+
+	assert.NoError(t, store.EnableTOTP(context.Background(), 1, 42, []string{"h1", "h2"}))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestUseTOTPStep(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	query := regexp.QuoteMeta(`UPDATE user_totp SET last_step = $2 WHERE user_id = $1 AND last_step < $2;`)
+
+	mock.ExpectExec(query).WithArgs(1, int64(43)).WillReturnResult(sqlmock.NewResult(0, 1))
+	used, err := store.UseTOTPStep(context.Background(), 1, 43)
+	assert.NoError(t, err)
+	assert.True(t, used)
+
+	mock.ExpectExec(query).WithArgs(1, int64(43)).WillReturnResult(sqlmock.NewResult(0, 0))
+	used, err = store.UseTOTPStep(context.Background(), 1, 43)
+	assert.NoError(t, err)
+	assert.False(t, used)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestCreateLoginChallenge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`OFFSET GREATEST($2::int - 1, 0)`)).
+		WithArgs(1, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO login_challenges`)).
+		WithArgs(1, "hash", float64(300)).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, store.CreateLoginChallenge(context.Background(), 1, "hash", 5*time.Minute, 3))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestFailLoginChallenge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+
+	mock.ExpectExec(regexp.QuoteMeta(`used_at = CASE WHEN attempts + 1 >= $2::int THEN NOW() END`)).
+		WithArgs("hash", 5).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, store.FailLoginChallenge(context.Background(), "hash", 5))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestConsumeLoginChallenge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	query := regexp.QuoteMeta(`UPDATE login_challenges c SET used_at = NOW()`)
+
+	mock.ExpectQuery(query).WithArgs("hash").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "login", "role", "blocked"}).AddRow(3, "alice", models.RoleCustomer, false))
+	user, err := store.ConsumeLoginChallenge(context.Background(), "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, &models.User{ID: 3, Login: "alice", Role: models.RoleCustomer}, user)
+
+	mock.ExpectQuery(query).WithArgs("used").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "login", "role", "blocked"}))
+	_, err = store.ConsumeLoginChallenge(context.Background(), "used")
+	assert.ErrorIs(t, err, models.ErrInvalidChallenge)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+func TestTOTPThrottle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM login_throttle`)).WithArgs(models.ThrottleScopeTOTP, "1").
+		WillReturnRows(sqlmock.NewRows([]string{"failures", "last_failure_at", "locked_until"}).AddRow(5, now, now))
+	got, err := store.GetTOTPThrottle(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, models.LoginThrottle{Scope: models.ThrottleScopeTOTP, Key: "1", Failures: 5, LastFailureAt: now, LockedUntil: now}, got)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO login_throttle AS t`)).
+		WithArgs(models.ThrottleScopeTOTP, "1", 5, 900.0, 3600.0).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, store.RecordTOTPFailure(context.Background(), 1,
+		models.LoginPolicy{MaxFailures: 5, Lockout: 15 * time.Minute, Window: time.Hour}))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,251 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/scoring-service/pkg/logger"
+	"github.com/scoring-service/pkg/models"
+)
+
+func (db *PgStorage) CreateWebhookSubscription(ctx context.Context, merchantID int, sub *models.WebhookSubscription) error {
+	err := db.QueryRowContext(ctx, `
+        INSERT INTO webhook_subscriptions (merchant_id, url, events, secret, created_at)
+        VALUES ($1, $2, $3, $4, NOW())
+        RETURNING id, created_at;
+    `, merchantID, sub.URL, strings.Join(sub.Events, ","), sub.Secret).Scan(&sub.ID, &sub.CreatedAt)
+	if err != nil {
+		logger.Log.Error(err.Error())
+	}
+	return err
+}
+
+func (db *PgStorage) GetWebhookSubscriptions(ctx context.Context, merchantID int) ([]models.WebhookSubscription, error) {
+	rows, err := db.QueryContext(ctx, `
+        SELECT id, url, events, created_at
+        FROM webhook_subscriptions
+        WHERE merchant_id = $1 AND active
+        ORDER BY id;
+    `, merchantID)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := []models.WebhookSubscription{}
+	for rows.Next() {
+		var sub models.WebhookSubscription
+		var events string
+		if err := rows.Scan(&sub.ID, &sub.URL, &events, &sub.CreatedAt); err != nil {
+			logger.Log.Error(err.Error())
+			return nil, err
+		}
+		sub.Events = strings.Split(events, ",")
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+// DeleteWebhookSubscription отключает подписку. Журнал её доставок
+// сохраняется, недоставленные события больше не отправляются.
+func (db *PgStorage) DeleteWebhookSubscription(ctx context.Context, merchantID, subscriptionID int) error {
+	res, err := db.ExecContext(ctx, `
+        UPDATE webhook_subscriptions SET active = FALSE
+        WHERE id = $1 AND merchant_id = $2 AND active;
+    `, subscriptionID, merchantID)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return models.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// GetWebhookDeliveries возвращает журнал доставок мерчанта, новые первыми.
+// Пустой status — доставки в любом состоянии.
+func (db *PgStorage) GetWebhookDeliveries(ctx context.Context, merchantID int, status string, limit int) ([]models.WebhookDelivery, error) {
+	rows, err := db.QueryContext(ctx, `
+        SELECT d.id, d.subscription_id, d.event_id, e.event_type, d.status, d.attempts,
+            COALESCE(d.last_status_code, 0), COALESCE(d.last_error, ''),
+            d.next_attempt_at, d.delivered_at, d.created_at
+        FROM webhook_deliveries d
+        JOIN webhook_events e ON e.id = d.event_id
+        WHERE e.merchant_id = $1 AND ($2 = '' OR d.status = $2)
+        ORDER BY d.id DESC
+        LIMIT $3;
+    `, merchantID, status, limit)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var d models.WebhookDelivery
+		var next, delivered sql.NullTime
+		err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.Event, &d.Status, &d.Attempts,
+			&d.LastStatusCode, &d.LastError, &next, &delivered, &d.CreatedAt)
+		if err != nil {
+			logger.Log.Error(err.Error())
+			return nil, err
+		}
+		if next.Valid && d.Status == models.DeliveryPending {
+			d.NextAttemptAt = &next.Time
+		}
+		if delivered.Valid {
+			d.DeliveredAt = &delivered.Time
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// RedeliverWebhook ставит доставку в очередь заново с полным набором
+// попыток. Доставки отключённых подписок не повторяются.
+func (db *PgStorage) RedeliverWebhook(ctx context.Context, merchantID, deliveryID int) error {
+	res, err := db.ExecContext(ctx, `
+        UPDATE webhook_deliveries d
+        SET status = $3, attempts = 0, next_attempt_at = NOW(), delivered_at = NULL
+        FROM webhook_subscriptions s
+        WHERE d.id = $1 AND s.id = d.subscription_id AND s.merchant_id = $2 AND s.active;
+    `, deliveryID, merchantID, models.DeliveryPending)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return models.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// ClaimWebhookDeliveries выбирает доставки, которым пора отправляться, и
+// откладывает их следующую попытку на lease, чтобы параллельный обработчик
+// не отправил их второй раз. Итог попытки записывает RecordWebhookAttempt.
+func (db *PgStorage) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	rows, err := db.QueryContext(ctx, `
+        WITH due AS (
+            SELECT d.id
+            FROM webhook_deliveries d
+            JOIN webhook_subscriptions s ON s.id = d.subscription_id
+            WHERE d.status = $1 AND d.next_attempt_at <= NOW() AND s.active
+            ORDER BY d.next_attempt_at
+            LIMIT $2
+            FOR UPDATE OF d SKIP LOCKED
+        )
+        UPDATE webhook_deliveries d
+        SET next_attempt_at = NOW() + $3 * INTERVAL '1 second'
+        FROM due, webhook_subscriptions s, webhook_events e
+        WHERE d.id = due.id AND s.id = d.subscription_id AND e.id = d.event_id
+        RETURNING d.id, d.subscription_id, d.event_id, e.event_type, e.payload, e.created_at,
+            d.attempts, s.url, s.secret;
+    `, models.DeliveryPending, limit, lease.Seconds())
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		d := models.WebhookDelivery{Status: models.DeliveryPending}
+		err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.Event, &d.Payload, &d.EventCreatedAt,
+			&d.Attempts, &d.URL, &d.Secret)
+		if err != nil {
+			logger.Log.Error(err.Error())
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (db *PgStorage) RecordWebhookAttempt(ctx context.Context, attempt models.WebhookAttempt) error {
+	_, err := db.ExecContext(ctx, `
+        UPDATE webhook_deliveries
+        SET status = $2, attempts = attempts + 1,
+            last_status_code = NULLIF($3, 0), last_error = NULLIF($4, ''),
+            next_attempt_at = CASE WHEN $2 = 'PENDING' THEN $5::timestamp END,
+            delivered_at = CASE WHEN $2 = 'DELIVERED' THEN NOW() END
+        WHERE id = $1;
+    `, attempt.DeliveryID, attempt.Status, attempt.StatusCode, attempt.Error, attempt.NextAttemptAt)
+	if err != nil {
+		logger.Log.Error(err.Error())
+	}
+	return err
+}
+
+// recordMerchantEvent сохраняет событие мерчанта и ставит его доставку
+// каждой активной подписке на этот тип события. Вызывается в транзакции,
+// меняющей данные, о которых событие сообщает.
+func recordMerchantEvent(ctx context.Context, tx *sql.Tx, merchantID int, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+        WITH event AS (
+            INSERT INTO webhook_events (merchant_id, event_type, payload, created_at)
+            VALUES ($1, $2, $3, NOW())
+            RETURNING id
+        )
+        INSERT INTO webhook_deliveries (event_id, subscription_id, status, next_attempt_at, created_at)
+        SELECT event.id, s.id, $4, NOW(), NOW()
+        FROM event, webhook_subscriptions s
+        WHERE s.merchant_id = $1 AND s.active AND $2 = ANY(string_to_array(s.events, ','));
+    `, merchantID, eventType, string(data), models.DeliveryPending)
+	if err != nil {
+		logger.Log.Error(err.Error())
+	}
+	return err
+}
+
+// recordOrderEvents пишет события об окончательном статусе заказа: в outbox
+// для обработанного заказа и мерчанту, зарегистрировавшему заказ, — в любом
+// случае. В событиях передаётся итоговое начисление.
+func recordOrderEvents(ctx context.Context, tx *sql.Tx, userID int, orderNum, status string) error {
+	var merchantID sql.NullInt64
+	var accrual float64
+	err := tx.QueryRowContext(ctx, `
+        SELECT merchant_id, COALESCE(accrual, 0) FROM orders WHERE number = $1;
+    `, orderNum).Scan(&merchantID, &accrual)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return err
+	}
+	if status == models.OrderProcessed {
+		event := models.OrderProcessedEvent{UserID: userID, Order: orderNum, Accrual: accrual, MerchantID: int(merchantID.Int64)}
+		if err := writeOutbox(ctx, tx, event); err != nil {
+			return err
+		}
+	}
+	if !merchantID.Valid {
+		return nil
+	}
+	eventType := models.WebhookOrderProcessed
+	if status == models.OrderInvalid {
+		eventType = models.WebhookOrderInvalid
+	}
+	return recordMerchantEvent(ctx, tx, int(merchantID.Int64), eventType,
+		models.WebhookOrderEvent{Order: orderNum, Status: status, Accrual: accrual})
+}
+
+// isFinalOrderStatus сообщает, что после статуса заказ больше не меняется.
+func isFinalOrderStatus(status string) bool {
+	return status == models.OrderProcessed || status == models.OrderInvalid
+}
@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestWebhookSubscriptions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO webhook_subscriptions (merchant_id, url, events, secret, created_at)`)).
+		WithArgs(3, "https://shop.example/hooks", "order.processed,points.withdrawn", "secret").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, now))
+	sub := models.WebhookSubscription{
+		URL:    "https://shop.example/hooks",
+		Events: []string{models.WebhookOrderProcessed, models.WebhookPointsWithdrawn},
+		Secret: "secret",
+	}
+	require.NoError(t, store.CreateWebhookSubscription(context.Background(), 3, &sub))
+	assert.Equal(t, 5, sub.ID)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM webhook_subscriptions WHERE merchant_id = $1 AND active`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "events", "created_at"}).
+			AddRow(5, "https://shop.example/hooks", "order.processed,points.withdrawn", now))
+	subs, err := store.GetWebhookSubscriptions(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, []models.WebhookSubscription{{
+		ID:        5,
+		URL:       "https://shop.example/hooks",
+		Events:    []string{models.WebhookOrderProcessed, models.WebhookPointsWithdrawn},
+		CreatedAt: now,
+	}}, subs)
+
+	deleteQuery := regexp.QuoteMeta(`UPDATE webhook_subscriptions SET active = FALSE`)
+	mock.ExpectExec(deleteQuery).WithArgs(5, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, store.DeleteWebhookSubscription(context.Background(), 3, 5))
+	mock.ExpectExec(deleteQuery).WithArgs(5, 4).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, store.DeleteWebhookSubscription(context.Background(), 4, 5), models.ErrWebhookNotFound)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetWebhookDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM webhook_deliveries d JOIN webhook_events e ON e.id = d.event_id`)).
+		WithArgs(3, "", 50).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "subscription_id", "event_id", "event_type", "status", "attempts",
+			"last_status_code", "last_error", "next_attempt_at", "delivered_at", "created_at",
+		}).
+			AddRow(2, 5, 9, models.WebhookPointsWithdrawn, models.DeliveryPending, 1, 503, "", now, nil, now).
+			AddRow(1, 5, 8, models.WebhookOrderProcessed, models.DeliveryDelivered, 1, 200, "", nil, now, now))
+
+	deliveries, err := store.GetWebhookDeliveries(context.Background(), 3, "", 50)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, 503, deliveries[0].LastStatusCode)
+	assert.Equal(t, now, *deliveries[0].NextAttemptAt)
+	assert.Nil(t, deliveries[1].NextAttemptAt)
+	assert.Equal(t, now, *deliveries[1].DeliveredAt)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRedeliverWebhook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	query := regexp.QuoteMeta(`SET status = $3, attempts = 0, next_attempt_at = NOW(), delivered_at = NULL`)
+
+	mock.ExpectExec(query).WithArgs(7, 3, models.DeliveryPending).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, store.RedeliverWebhook(context.Background(), 3, 7))
+
+	mock.ExpectExec(query).WithArgs(7, 4, models.DeliveryPending).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, store.RedeliverWebhook(context.Background(), 4, 7), models.ErrWebhookNotFound)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimWebhookDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FOR UPDATE OF d SKIP LOCKED`)).
+		WithArgs(models.DeliveryPending, 20, 120.0).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "subscription_id", "event_id", "event_type", "payload", "created_at", "attempts", "url", "secret",
+		}).AddRow(1, 5, 8, models.WebhookOrderProcessed, []byte(`{"order":"12345678903"}`), now, 2,
+			"https://shop.example/hooks", "secret"))
+
+	deliveries, err := store.ClaimWebhookDeliveries(context.Background(), 20, 2*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, []models.WebhookDelivery{{
+		ID:             1,
+		SubscriptionID: 5,
+		EventID:        8,
+		Event:          models.WebhookOrderProcessed,
+		Status:         models.DeliveryPending,
+		Attempts:       2,
+		URL:            "https://shop.example/hooks",
+		Secret:         "secret",
+		Payload:        []byte(`{"order":"12345678903"}`),
+		EventCreatedAt: now,
+	}}, deliveries)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecordWebhookAttempt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+	next := time.Now().Add(time.Minute)
+
+	mock.ExpectExec(regexp.QuoteMeta(`SET status = $2, attempts = attempts + 1`)).
+		WithArgs(1, models.DeliveryPending, 503, "неожиданный ответ 503", next).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err = store.RecordWebhookAttempt(context.Background(), models.WebhookAttempt{
+		DeliveryID:    1,
+		Status:        models.DeliveryPending,
+		StatusCode:    503,
+		Error:         "неожиданный ответ 503",
+		NextAttemptAt: next,
+	})
+	assert.NoError(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithdrawAtMerchant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := &PgStorage{DB: db}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT current_balance FROM users WHERE id = $1 FOR UPDATE;`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"current_balance"}).AddRow(200.0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO withdrawals (user_id, order_number, sum, uploaded_at)`)).
+		WithArgs(1, "12345678903", 100.0).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE accrual_lots l SET remaining = l.remaining - c.take`)).
+		WithArgs(1, 100.0).
+		WillReturnRows(sqlmock.NewRows([]string{"take", "expires_at"}).AddRow(100.0, nil))
+	mock.ExpectExec(regexp.QuoteMeta(`SET current_balance = current_balance - $1`)).
+		WithArgs(100.0, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO outbox (event_type, payload, created_at, available_at)`)).
+		WithArgs(models.EventPointsWithdrawn, `{"user_id":1,"order":"12345678903","sum":100,"merchant_id":3}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO webhook_events (merchant_id, event_type, payload, created_at)`)).
+		WithArgs(3, models.WebhookPointsWithdrawn, `{"order":"12345678903","sum":100}`, models.DeliveryPending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, store.WithdrawAtMerchant(context.Background(), 3, 1, "12345678903", 100))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress — адрес получателя во внутренней сети сервиса.
+var ErrForbiddenAddress = errors.New("адрес получателя во внутренней сети")
+
+// Resolver разрешает имя хоста; net.DefaultResolver ему удовлетворяет.
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// reservedPrefixes — диапазоны, которые не отсекают методы netip.Addr:
+// «этот» сеть, CGNAT, служебные IETF и сеть для тестов производительности.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// PublicAddr сообщает, можно ли отправлять вебхуки на addr: частные,
+// loopback, link-local и прочие служебные адреса запрещены.
+func PublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckHost разрешает host и возвращает ErrForbiddenAddress, если хотя бы
+// один из его адресов не публичный.
+func CheckHost(ctx context.Context, resolver Resolver, host string) error {
+	addrs := []netip.Addr{}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = append(addrs, addr)
+	} else {
+		addrs, err = resolver.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			return err
+		}
+	}
+	for _, addr := range addrs {
+		if !PublicAddr(addr) {
+			return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr)
+		}
+	}
+	return nil
+}
+
+// dialPublic не даёт установить соединение с внутренним адресом. Проверяется
+// адрес, к которому идёт подключение, поэтому её не обойти сменой DNS-записи
+// после регистрации подписки или перенаправлением.
+func dialPublic(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !PublicAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addrPort.Addr())
+	}
+	return nil
+}
+
+// newTransport — транспорт без прокси, подключающийся только к публичным
+// адресам.
+func newTransport(timeout time.Duration) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{Timeout: timeout, Control: dialPublic}).DialContext
+	return transport
+}
@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/pkg/models"
+)
+
+type fakeResolver map[string][]netip.Addr
+
+func (f fakeResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	return f[host], nil
+}
+
+func TestPublicAddr(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"93.184.216.34":        true,
+		"2606:4700::1111":      true,
+		"127.0.0.1":            false,
+		"10.1.2.3":             false,
+		"172.16.0.1":           false,
+		"192.168.1.1":          false,
+		"169.254.169.254":      false,
+		"100.64.0.1":           false,
+		"0.0.0.0":              false,
+		"::1":                  false,
+		"fe80::1":              false,
+		"fd00::1":              false,
+		"::ffff:127.0.0.1":     false,
+		"64:ff9b::a00:1":       false,
+		"224.0.0.1":            false,
+		"255.255.255.255":      false,
+		"::ffff:93.184.216.34": true,
+	} {
+		require.Equal(t, want, PublicAddr(netip.MustParseAddr(addr)), addr)
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	resolver := fakeResolver{
+		"shop.example":  {netip.MustParseAddr("93.184.216.34")},
+		"mixed.example": {netip.MustParseAddr("93.184.216.34"), netip.MustParseAddr("10.0.0.1")},
+	}
+	require.NoError(t, CheckHost(context.Background(), resolver, "shop.example"))
+	require.NoError(t, CheckHost(context.Background(), resolver, "93.184.216.34"))
+	require.ErrorIs(t, CheckHost(context.Background(), resolver, "mixed.example"), ErrForbiddenAddress)
+	require.ErrorIs(t, CheckHost(context.Background(), resolver, "169.254.169.254"), ErrForbiddenAddress)
+}
+
+func TestSendRejectsPrivateAddress(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	_, err := NewSender(time.Second).Send(context.Background(), models.WebhookDelivery{URL: srv.URL, Secret: "secret"})
+	require.ErrorIs(t, err, ErrForbiddenAddress)
+	require.False(t, called)
+}
@@ -0,0 +1,85 @@
+// Package webhook отправляет события мерчантам на адреса их подписок.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+// Envelope — тело запроса с событием. ID события одинаков во всех попытках
+// доставки, по нему получатель отбрасывает повторы.
+type Envelope struct {
+	ID        int             `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Sender подписывает событие секретом подписки так же, как мерчант
+// подписывает свои запросы: HMAC-SHA256 от метки времени, X-Nonce, метода,
+// пути и тела в заголовке X-Signature (см. auth.SignRequest).
+type Sender struct {
+	Client *http.Client
+}
+
+// NewSender создаёт отправителя, который подключается только к публичным
+// адресам.
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{Client: &http.Client{Timeout: timeout, Transport: newTransport(timeout)}}
+}
+
+// Send возвращает код ответа получателя; ответ вне 2xx считается ошибкой.
+func (s *Sender) Send(ctx context.Context, d models.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(Envelope{ID: d.EventID, Type: d.Event, CreatedAt: d.EventCreatedAt, Data: d.Payload})
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	nonce, err := auth.GenerateNonce()
+	if err != nil {
+		return 0, err
+	}
+	// Пустой путь клиент отправляет как "/", подписывается то, что увидит
+	// получатель.
+	path := req.URL.Path
+	if path == "" {
+		path = "/"
+	}
+	signed := auth.SignedRequest{
+		Method:    req.Method,
+		Path:      path,
+		Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+		Nonce:     nonce,
+		Body:      body,
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", d.Event)
+	req.Header.Set("X-Webhook-ID", strconv.Itoa(d.EventID))
+	req.Header.Set("X-Timestamp", signed.Timestamp)
+	req.Header.Set("X-Nonce", nonce)
+	req.Header.Set("X-Signature", auth.SignRequest([]byte(d.Secret), signed))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("неожиданный ответ %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
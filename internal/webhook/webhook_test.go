@@ -0,0 +1,62 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/scoring-service/internal/auth"
+	"github.com/scoring-service/pkg/models"
+)
+
+func TestSend(t *testing.T) {
+	created := time.Date(2025, 7, 26, 12, 0, 0, 0, time.UTC)
+	status := http.StatusNoContent
+	var got Envelope
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		signed := auth.SignedRequest{
+			Method:    r.Method,
+			Path:      r.URL.Path,
+			Timestamp: r.Header.Get("X-Timestamp"),
+			Nonce:     r.Header.Get("X-Nonce"),
+			Body:      body,
+		}
+		require.NoError(t, auth.VerifyRequestSignature([]byte("secret"), signed, r.Header.Get("X-Signature"), time.Now()))
+		require.Equal(t, models.WebhookOrderProcessed, r.Header.Get("X-Webhook-Event"))
+		require.Equal(t, "8", r.Header.Get("X-Webhook-ID"))
+		require.NoError(t, json.Unmarshal(body, &got))
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	// Тестовый сервер слушает loopback, поэтому клиент без проверки адреса.
+	sender := &Sender{Client: srv.Client()}
+	delivery := models.WebhookDelivery{
+		EventID:        8,
+		Event:          models.WebhookOrderProcessed,
+		URL:            srv.URL,
+		Secret:         "secret",
+		Payload:        []byte(`{"order":"12345678903","status":"PROCESSED","accrual":10}`),
+		EventCreatedAt: created,
+	}
+
+	code, err := sender.Send(context.Background(), delivery)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, code)
+	require.Equal(t, 8, got.ID)
+	require.Equal(t, created, got.CreatedAt)
+	require.JSONEq(t, string(delivery.Payload), string(got.Data))
+
+	status = http.StatusServiceUnavailable
+	code, err = sender.Send(context.Background(), delivery)
+	require.Error(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+}
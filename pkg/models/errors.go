@@ -0,0 +1,48 @@
+package models
+
+import "errors"
+
+var (
+	ErrInsufficientFunds     = errors.New("недостаточно средств")
+	ErrUserNotFound          = errors.New("пользователь не найден")
+	ErrSelfTransfer          = errors.New("перевод самому себе невозможен")
+	ErrTransferLimitExceeded = errors.New("превышен дневной лимит переводов")
+	ErrCampaignNotFound      = errors.New("акция не найдена")
+	ErrInvalidReferralCode   = errors.New("неверный реферальный код")
+	ErrReferralCodeTaken     = errors.New("реферальный код уже занят")
+	ErrUserBlocked           = errors.New("учётная запись заблокирована")
+	ErrOrderNotFound         = errors.New("заказ не найден")
+	ErrOrderExists           = errors.New("заказ уже загружен")
+	ErrOrderConflict         = errors.New("заказ загружен другим пользователем")
+	ErrInvalidRefreshToken   = errors.New("недействительный refresh-токен")
+	ErrRefreshTokenReused    = errors.New("повторное использование refresh-токена")
+	ErrSessionNotFound       = errors.New("сессия не найдена")
+	ErrInvalidCredentials    = errors.New("неверная пара логин/пароль")
+	ErrWeakPassword          = errors.New("пароль не соответствует требованиям")
+	ErrInvalidResetToken     = errors.New("недействительный или просроченный токен сброса пароля")
+	ErrInvalidTOTP           = errors.New("неверный код подтверждения")
+	ErrTOTPNotEnabled        = errors.New("двухфакторная аутентификация не подключена")
+	ErrTOTPAlreadyEnabled    = errors.New("двухфакторная аутентификация уже подключена")
+	ErrInvalidChallenge      = errors.New("недействительный или просроченный токен входа")
+	ErrMerchantNotFound      = errors.New("мерчант не найден")
+	ErrMerchantNameTaken     = errors.New("название мерчанта уже занято")
+	ErrInvalidAPIKey         = errors.New("недействительный API-ключ")
+	ErrLoginTaken            = errors.New("логин уже занят")
+	ErrReservedLogin         = errors.New("логин зарезервирован")
+	ErrInvalidCheckoutCode   = errors.New("недействительный или просроченный код оплаты")
+	ErrCheckoutCodeTaken     = errors.New("код оплаты уже используется")
+	ErrInvalidCustomerToken  = errors.New("недействительный или просроченный токен покупателя")
+	ErrRequestReplayed       = errors.New("подписанный запрос уже принят")
+	ErrBatchTooLarge         = errors.New("слишком много заказов в одной загрузке")
+	ErrAlreadyIngested       = errors.New("файл уже загружен")
+	ErrCallbackReplayed      = errors.New("уведомление о начислении уже принято")
+	ErrCallbackDisabled      = errors.New("приём уведомлений о начислениях выключен")
+	ErrInvalidAccrual        = errors.New("неверные данные начисления")
+	ErrWebhookNotFound       = errors.New("подписка или доставка не найдена")
+	ErrInvalidWebhook        = errors.New("неверные параметры подписки")
+	ErrInvalidPreferences    = errors.New("неверный адрес почты или язык уведомлений")
+	ErrInvalidEmailToken     = errors.New("недействительный или просроченный токен подтверждения почты")
+	ErrInvalidPeriod         = errors.New("неверный период выписки")
+	ErrNoReconciliation      = errors.New("сверка балансов ещё не проводилась")
+	ErrExpiryOverdraft       = errors.New("сгорание баллов уводит баланс ниже нуля")
+)
@@ -7,6 +7,9 @@ const (
 	OrderProcessing = "PROCESSING"
 	OrderInvalid    = "INVALID"
 	OrderProcessed  = "PROCESSED"
+	// AccrualRegistered — заказ принят системой начислений, но ещё не
+	// обработан; у нас он остаётся в статусе PROCESSING.
+	AccrualRegistered = "REGISTERED"
 )
 
 type Order struct {
@@ -21,14 +24,26 @@ type Withdrawal struct {
 	ProcessedAt time.Time `json:"processed_at"`
 }
 type Balance struct {
-	Current   float64 `json:"current"`
-	Withdrawn float64 `json:"withdrawn"`
+	Current   float64          `json:"current"`
+	Withdrawn float64          `json:"withdrawn"`
+	Expiring  []ExpiringPoints `json:"expiring,omitempty"`
+}
+type ExpiringPoints struct {
+	Date   string  `json:"date"`
+	Amount float64 `json:"amount"`
 }
 type User struct {
 	Balance
-	ID       int    `json:"id"`
-	Login    string `json:"login"`
-	Password string `json:"password"`
+	ID           int      `json:"id"`
+	Login        string   `json:"login"`
+	Password     string   `json:"password"`
+	ReferralCode string   `json:"-"`
+	InvitedWith  string   `json:"referral_code,omitempty"`
+	ReferrerID   int      `json:"-"`
+	Role         string   `json:"-"`
+	Permissions  []string `json:"-"`
+	Blocked      bool     `json:"-"`
+	SessionID    int      `json:"-"`
 }
 type AccrualResponse struct {
 	Order   string  `json:"order"`
@@ -38,4 +53,667 @@ type AccrualResponse struct {
 type Withdraw struct {
 	Order string  `json:"order"`
 	Sum   float64 `json:"sum"`
+	TOTP  string  `json:"totp_code,omitempty"`
+}
+
+const (
+	LotSourceOrder      = "ORDER"
+	LotSourceTransfer   = "TRANSFER"
+	LotSourceLegacy     = "LEGACY" // баланс до учёта партий, бессрочный
+	LotSourceCampaign   = "CAMPAIGN"
+	LotSourceReferral   = "REFERRAL"
+	LotSourceAdjustment = "ADJUSTMENT"
+	LotSourceCorrection = "CORRECTION"
+)
+
+type CreditPolicy struct {
+	LifetimeMonths   int
+	TierWindowMonths int
+	Referral         ReferralPolicy
+}
+
+type ReferralPolicy struct {
+	ReferrerBonus float64
+	RefereeBonus  float64
+	MaxPerUser    int
+}
+
+type Tier struct {
+	Name       string  `json:"name"`
+	MinPoints  float64 `json:"min_points"`
+	Multiplier float64 `json:"multiplier"`
+}
+type TierStatus struct {
+	Tier         string  `json:"tier"`
+	Multiplier   float64 `json:"multiplier"`
+	Accrued      float64 `json:"accrued"`
+	NextTier     string  `json:"next_tier,omitempty"`
+	PointsToNext float64 `json:"points_to_next,omitempty"`
+	Progress     float64 `json:"progress"`
+}
+
+const (
+	TransferOut = "OUT"
+	TransferIn  = "IN"
+)
+
+type TransferRequest struct {
+	Recipient string  `json:"recipient"`
+	Sum       float64 `json:"sum"`
+	TOTP      string  `json:"totp_code,omitempty"`
+}
+type Transfer struct {
+	Direction    string    `json:"direction"`
+	Counterparty string    `json:"counterparty"`
+	Sum          float64   `json:"sum"`
+	ProcessedAt  time.Time `json:"processed_at"`
+}
+
+// Виды операций в выписке по счёту.
+const (
+	StatementAccrual     = "ACCRUAL"
+	StatementCampaign    = "CAMPAIGN"
+	StatementReferral    = "REFERRAL"
+	StatementTransferIn  = "TRANSFER_IN"
+	StatementTransferOut = "TRANSFER_OUT"
+	StatementAdjustment  = "ADJUSTMENT"
+	StatementCorrection  = "CORRECTION"
+	StatementWithdrawal  = "WITHDRAWAL"
+	StatementExpiry      = "EXPIRY"
+)
+
+// StatementEntry — операция по счёту в выписке. Amount положителен для
+// зачислений и отрицателен для списаний; Balance — баланс после операции.
+type StatementEntry struct {
+	Type        string    `json:"type"`
+	Order       string    `json:"order,omitempty"`
+	Amount      float64   `json:"amount"`
+	Balance     float64   `json:"balance"`
+	ProcessedAt time.Time `json:"processed_at"`
+}
+
+// BalanceDrift — расхождение сохранённого баланса пользователя с балансом,
+// восстановленным по истории операций.
+type BalanceDrift struct {
+	UserID            int     `json:"user_id"`
+	Login             string  `json:"login"`
+	Balance           float64 `json:"balance"`
+	ExpectedBalance   float64 `json:"expected_balance"`
+	Withdrawn         float64 `json:"withdrawn"`
+	ExpectedWithdrawn float64 `json:"expected_withdrawn"`
+	Corrected         bool    `json:"corrected"`
+}
+
+// Причины исправления баланса сверкой. Если история операций даёт
+// отрицательный баланс, он исправляется до нуля, а не ниже.
+const (
+	CorrectionReasonDrift    = "LEDGER_DRIFT"
+	CorrectionReasonNegative = "NEGATIVE_LEDGER"
+)
+
+// ReconciliationRun — итог сверки балансов.
+type ReconciliationRun struct {
+	ID           int            `json:"id"`
+	StartedAt    time.Time      `json:"started_at"`
+	FinishedAt   time.Time      `json:"finished_at"`
+	AutoCorrect  bool           `json:"auto_correct"`
+	UsersChecked int            `json:"users_checked"`
+	Drifts       []BalanceDrift `json:"drifts"`
+	Error        string         `json:"error,omitempty"`
+}
+
+type TransferLimits struct {
+	DailySum   float64
+	DailyCount int
+}
+
+type Campaign struct {
+	ID             int        `json:"id"`
+	Name           string     `json:"name"`
+	Merchant       string     `json:"merchant,omitempty"`
+	StartsAt       time.Time  `json:"starts_at"`
+	EndsAt         *time.Time `json:"ends_at,omitempty"`
+	FirstOrderOnly bool       `json:"first_order_only"`
+	OrderPrefix    string     `json:"order_prefix,omitempty"`
+	BonusPoints    float64    `json:"bonus_points"`
+	Multiplier     float64    `json:"multiplier"`
+	Active         bool       `json:"active"`
+}
+type CampaignBonus struct {
+	CampaignID int     `json:"campaign_id"`
+	UserID     int     `json:"user_id"`
+	Order      string  `json:"order"`
+	Amount     float64 `json:"amount"`
+}
+type CampaignDryRun struct {
+	CampaignID int             `json:"campaign_id"`
+	Evaluated  int             `json:"orders_evaluated"`
+	Bonuses    []CampaignBonus `json:"bonuses"`
+	Total      float64         `json:"total"`
+}
+
+const (
+	ReferralPending  = "PENDING"
+	ReferralRewarded = "REWARDED"
+	ReferralCapped   = "CAPPED"
+)
+
+type Referral struct {
+	Login        string    `json:"login"`
+	Status       string    `json:"status"`
+	Bonus        float64   `json:"bonus"`
+	RegisteredAt time.Time `json:"registered_at"`
+}
+type ReferralSummary struct {
+	Code     string     `json:"code"`
+	Earned   float64    `json:"earned"`
+	Invitees []Referral `json:"invitees"`
+}
+
+// DeletedLoginPrefix начинает служебный логин удалённой учётной записи.
+// Логины с этим префиксом зарегистрировать нельзя, поэтому освобождённый
+// логин не совпадёт со служебным.
+const DeletedLoginPrefix = "#deleted-"
+
+const (
+	RoleCustomer = "customer"
+	RoleSupport  = "support"
+	RoleAdmin    = "admin"
+	RoleMerchant = "merchant"
+)
+
+const (
+	PermUsersRead          = "users:read"
+	PermUsersBlock         = "users:block"
+	PermUsersManage        = "users:manage"
+	PermOrdersRepoll       = "orders:repoll"
+	PermOrdersSubmit       = "orders:submit"
+	PermBalanceAdjust      = "balance:adjust"
+	PermCampaignsManage    = "campaigns:manage"
+	PermMerchantsManage    = "merchants:manage"
+	PermWebhooksManage     = "webhooks:manage"
+	PermReconciliationRead = "reconciliation:read"
+)
+
+var (
+	Roles       = []string{RoleCustomer, RoleSupport, RoleAdmin, RoleMerchant}
+	Permissions = []string{
+		PermUsersRead, PermUsersBlock, PermUsersManage, PermOrdersRepoll,
+		PermOrdersSubmit, PermBalanceAdjust, PermCampaignsManage, PermMerchantsManage,
+		PermWebhooksManage, PermReconciliationRead,
+	}
+)
+
+const (
+	AdminActionAdjust  = "BALANCE_ADJUST"
+	AdminActionBlock   = "BLOCK"
+	AdminActionUnblock = "UNBLOCK"
+	AdminActionRepoll  = "ORDER_REPOLL"
+	AdminActionAccess  = "ACCESS_CHANGE"
+	AdminActionUnlock  = "LOGIN_UNLOCK"
+
+	AdminActionMerchantCreate = "MERCHANT_CREATE"
+	AdminActionMerchantKey    = "MERCHANT_KEY_ISSUE"
+	AdminActionMerchantRevoke = "MERCHANT_KEY_REVOKE"
+)
+
+type AdminUser struct {
+	Balance
+	ID      int    `json:"id"`
+	Login   string `json:"login"`
+	Role    string `json:"role"`
+	Blocked bool   `json:"blocked"`
+}
+type UserAccess struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+type AdminUserView struct {
+	User        AdminUser    `json:"user"`
+	Permissions []string     `json:"permissions"`
+	Orders      []Order      `json:"orders"`
+	Withdrawals []Withdrawal `json:"withdrawals"`
+}
+type AdminAction struct {
+	AdminID      int
+	Action       string
+	TargetUserID int
+	TargetOrder  string
+	Details      string
+}
+type Adjustment struct {
+	Amount float64 `json:"amount"`
+	Reason string  `json:"reason"`
+}
+type BlockRequest struct {
+	Reason string `json:"reason"`
+}
+
+type SessionMeta struct {
+	UserAgent string
+	IP        string
+}
+type Session struct {
+	ID         int       `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+const (
+	ThrottleScopeLogin = "LOGIN"
+	ThrottleScopeIP    = "IP"
+	// ThrottleScopeReset считает запросы сброса пароля для логина.
+	ThrottleScopeReset = "RESET"
+	// ThrottleScopeCheckout считает неверные коды оплаты, введённые мерчантом.
+	ThrottleScopeCheckout = "CHECKOUT"
+	// ThrottleScopeTOTP считает неверные коды 2FA вне входа: при списании,
+	// переводе и смене настроек 2FA. Ключ — id пользователя.
+	ThrottleScopeTOTP = "TOTP"
+)
+
+const (
+	LoginReasonInvalidPassword = "invalid_password"
+	LoginReasonUnknownLogin    = "unknown_login"
+	LoginReasonBlocked         = "blocked"
+	LoginReasonInvalidTOTP     = "invalid_totp"
+)
+
+// LoginPolicy задаёт защиту входа от перебора: задержка между неудачными
+// попытками растёт экспоненциально от BaseDelay до MaxDelay, после
+// MaxFailures неудач подряд логин блокируется на Lockout. Счётчик
+// сбрасывается, если неудач не было дольше Window; при нулевом Window — только
+// удачным входом или администратором.
+type LoginPolicy struct {
+	MaxFailures   int
+	IPMaxFailures int
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	Lockout       time.Duration
+	Window        time.Duration
+}
+type LoginThrottle struct {
+	Scope         string
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   time.Time
+}
+
+// PasswordPolicy задаёт требования к новым паролям. Длина считается в
+// символах, MaxBytes — в байтах UTF-8. Нулевые значения снимают
+// соответствующее ограничение; Denylist хранит распространённые пароли в
+// нижнем регистре.
+type PasswordPolicy struct {
+	MinLength int
+	MaxLength int
+	MaxBytes  int
+	Denylist  map[string]struct{}
+}
+
+type PasswordChange struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+type PasswordResetRequest struct {
+	Login string `json:"login"`
+}
+type PasswordReset struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+type AccountDeletion struct {
+	Password string `json:"password"`
+}
+
+const (
+	NotificationPasswordReset     = "PASSWORD_RESET"
+	NotificationEmailVerification = "EMAIL_VERIFICATION"
+	NotificationPointsCredited    = "POINTS_CREDITED"
+	NotificationPointsWithdrawn   = "POINTS_WITHDRAWN"
+)
+
+// Notification — служебное сообщение пользователю, которое доставляет
+// Notifier. Email и Locale берутся из настроек уведомлений пользователя.
+type Notification struct {
+	Kind      string    `json:"kind"`
+	UserID    int       `json:"user_id"`
+	Login     string    `json:"login"`
+	Email     string    `json:"email,omitempty"`
+	Locale    string    `json:"locale,omitempty"`
+	Token     string    `json:"token,omitempty"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	Order     string    `json:"order,omitempty"`
+	Amount    float64   `json:"amount,omitempty"`
+}
+
+const (
+	LocaleRU = "ru"
+	LocaleEN = "en"
+)
+
+// NotificationPreferences — куда и о чём уведомлять пользователя. Без
+// сохранённых настроек уведомления включены, язык — русский, адреса нет.
+// Письма уходят только на подтверждённый Email; PendingEmail ждёт
+// подтверждения токеном из письма.
+type NotificationPreferences struct {
+	Login           string `json:"-"`
+	Email           string `json:"email"`
+	PendingEmail    string `json:"pending_email,omitempty"`
+	Locale          string `json:"locale"`
+	PointsCredited  bool   `json:"points_credited"`
+	PointsWithdrawn bool   `json:"points_withdrawn"`
+}
+
+type EmailConfirmation struct {
+	Token string `json:"token"`
+}
+
+// TOTP — настройки двухфакторной аутентификации пользователя. Секрет
+// без Enabled означает, что подключение начато, но не подтверждено.
+// LastStep — последний принятый шаг TOTP, повторно он не принимается.
+type TOTP struct {
+	Secret            string
+	Enabled           bool
+	LastStep          int64
+	WithdrawThreshold float64
+}
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+type TOTPCode struct {
+	Code string `json:"code"`
+}
+type RecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+// TwoFactorSettings задаёт сумму списания или перевода, начиная с которой
+// требуется свежий код TOTP; ноль отключает проверку. Изменение
+// подтверждается кодом TOTP в Code.
+type TwoFactorSettings struct {
+	WithdrawThreshold float64 `json:"withdraw_threshold"`
+	Code              string  `json:"code"`
+}
+type LoginChallenge struct {
+	ChallengeToken string `json:"challenge_token"`
+	ExpiresIn      int    `json:"expires_in"`
+}
+type TwoFactorLogin struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
+// Merchant — партнёрский магазин, регистрирующий заказы покупателей через
+// API. RateLimit — запросов в минуту, ноль снимает ограничение. Если задан
+// HMACSecret, запросы мерчанта должны быть подписаны.
+type Merchant struct {
+	ID          int       `json:"id"`
+	UserID      int       `json:"user_id"`
+	Name        string    `json:"name"`
+	RateLimit   int       `json:"rate_limit"`
+	HMACSecret  string    `json:"-"`
+	Permissions []string  `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+type MerchantCreate struct {
+	Name         string `json:"name"`
+	Login        string `json:"login"`
+	RateLimit    int    `json:"rate_limit"`
+	SignRequests bool   `json:"sign_requests"`
+}
+
+// MerchantCredentials показываются один раз при выпуске; в базе хранится
+// только хеш ключа.
+type MerchantCredentials struct {
+	MerchantID int    `json:"merchant_id"`
+	KeyID      int    `json:"key_id"`
+	APIKey     string `json:"api_key"`
+	HMACSecret string `json:"hmac_secret,omitempty"`
+}
+
+// MerchantOrder привязывает заказ к покупателю по логину или по
+// одноразовому токену, который покупатель выпустил и передал магазину.
+type MerchantOrder struct {
+	Order         string      `json:"order"`
+	CustomerLogin string      `json:"customer_login,omitempty"`
+	CustomerToken string      `json:"customer_token,omitempty"`
+	Items         []OrderItem `json:"items,omitempty"`
+}
+
+// CustomerToken — одноразовый токен, которым покупатель разрешает магазину
+// загрузить заказ на свой счёт.
+type CustomerToken struct {
+	Token     string    `json:"customer_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CheckoutCode — одноразовый числовой код, которым покупатель подтверждает
+// покупку на кассе вместо логина.
+type CheckoutCode struct {
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CheckoutRedeem погашает код покупателя: при ненулевой Sum списывает баллы
+// в счёт заказа Order, иначе привязывает заказ к покупателю.
+type CheckoutRedeem struct {
+	Code  string  `json:"code"`
+	Order string  `json:"order"`
+	Sum   float64 `json:"sum,omitempty"`
+	TOTP  string  `json:"totp_code,omitempty"`
+	// Items — позиции чека привязываемого заказа.
+	Items []OrderItem `json:"items,omitempty"`
+}
+
+// Результаты обработки номера в пакетной загрузке заказов.
+const (
+	BatchOrderAccepted        = "ACCEPTED"
+	BatchOrderAlreadyUploaded = "ALREADY_UPLOADED"
+	BatchOrderConflict        = "CONFLICT"
+	BatchOrderInvalid         = "INVALID"
+	BatchOrderDuplicate       = "DUPLICATE"
+)
+
+type BatchOrderResult struct {
+	Order  string `json:"order"`
+	Status string `json:"status"`
+}
+
+const (
+	IngestionRunning = "RUNNING"
+	IngestionDone    = "DONE"
+	IngestionFailed  = "FAILED"
+)
+
+// IngestionRun — загрузка одного файла заказов из каталога приёма. Файл
+// узнаётся по контрольной сумме, поэтому повторно доставленный файл не
+// загружается второй раз.
+type IngestionRun struct {
+	ID         int       `json:"id"`
+	FileName   string    `json:"file_name"`
+	Checksum   string    `json:"checksum"`
+	Status     string    `json:"status"`
+	Lines      int       `json:"lines"`
+	Imported   int       `json:"imported"`
+	Skipped    int       `json:"skipped"`
+	Failed     int       `json:"failed"`
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+}
+
+// IngestionLine — строка файла заказов. Status принимает значения
+// BatchOrder*; Error заполняется для строк, которые не удалось загрузить.
+type IngestionLine struct {
+	Line   int
+	Login  string
+	Order  string
+	Status string
+	Error  string
+}
+
+// OrderItem — позиция чека. Используется локальным расчётом начислений
+// вместо внешней системы.
+type OrderItem struct {
+	Name     string  `json:"name"`
+	Category string  `json:"category,omitempty"`
+	Brand    string  `json:"brand,omitempty"`
+	Price    float64 `json:"price"`
+	Quantity int     `json:"quantity,omitempty"`
+}
+
+// Amount — стоимость позиции; количество по умолчанию равно единице.
+func (i OrderItem) Amount() float64 {
+	if i.Quantity <= 0 {
+		return i.Price
+	}
+	return i.Price * float64(i.Quantity)
+}
+
+// OrderSubmission — заказ покупателя в JSON. Позиции чека от покупателя не
+// принимаются: цены в них задавал бы он сам. Их передаёт только мерчант.
+type OrderSubmission struct {
+	Order string `json:"order"`
+}
+
+// AccrualRule — правило локального расчёта начислений. Category и Brand
+// сравниваются без учёта регистра, пустое поле подходит к любой позиции.
+// Percent начисляется от стоимости подходящих позиций, Bonus — один раз
+// за заказ, в котором есть хотя бы одна подходящая позиция.
+type AccrualRule struct {
+	Name     string  `json:"name"`
+	Category string  `json:"category,omitempty"`
+	Brand    string  `json:"brand,omitempty"`
+	Percent  float64 `json:"percent,omitempty"`
+	Bonus    float64 `json:"bonus,omitempty"`
+}
+
+// События, о которых мерчант может получать вебхуки.
+const (
+	WebhookOrderProcessed  = "order.processed"
+	WebhookOrderInvalid    = "order.invalid"
+	WebhookPointsWithdrawn = "points.withdrawn"
+)
+
+var WebhookEvents = []string{WebhookOrderProcessed, WebhookOrderInvalid, WebhookPointsWithdrawn}
+
+// Состояния доставки вебхука. DEAD — попытки исчерпаны, доставку можно
+// только повторить вручную.
+const (
+	DeliveryPending   = "PENDING"
+	DeliveryDelivered = "DELIVERED"
+	DeliveryDead      = "DEAD"
+)
+
+// WebhookSubscription — адрес мерчанта для событий из Events. Secret
+// подписывает доставки и показывается только при создании подписки.
+type WebhookSubscription struct {
+	ID        int       `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookDelivery — доставка одного события одной подписке; она же запись
+// журнала доставок.
+type WebhookDelivery struct {
+	ID             int        `json:"id"`
+	SubscriptionID int        `json:"subscription_id"`
+	EventID        int        `json:"event_id"`
+	Event          string     `json:"event"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+
+	// Заполняются при выборке на отправку.
+	URL            string    `json:"-"`
+	Secret         string    `json:"-"`
+	Payload        []byte    `json:"-"`
+	EventCreatedAt time.Time `json:"-"`
+}
+
+// WebhookAttempt — итог очередной попытки доставки.
+type WebhookAttempt struct {
+	DeliveryID    int
+	Status        string
+	StatusCode    int
+	Error         string
+	NextAttemptAt time.Time
+}
+
+type WebhookOrderEvent struct {
+	Order   string  `json:"order"`
+	Status  string  `json:"status"`
+	Accrual float64 `json:"accrual,omitempty"`
+}
+
+type WebhookWithdrawEvent struct {
+	Order string  `json:"order"`
+	Sum   float64 `json:"sum"`
+}
+
+// DomainEvent — событие предметной области. Оно записывается в outbox в той
+// же транзакции, что и изменение, о котором сообщает.
+type DomainEvent interface {
+	EventType() string
+}
+
+const (
+	EventOrderProcessed  = "order.processed"
+	EventPointsWithdrawn = "points.withdrawn"
+	EventUserRegistered  = "user.registered"
+)
+
+type OrderProcessedEvent struct {
+	UserID     int     `json:"user_id"`
+	Order      string  `json:"order"`
+	Accrual    float64 `json:"accrual"`
+	MerchantID int     `json:"merchant_id,omitempty"`
+}
+
+func (OrderProcessedEvent) EventType() string { return EventOrderProcessed }
+
+type PointsWithdrawnEvent struct {
+	UserID     int     `json:"user_id"`
+	Order      string  `json:"order"`
+	Sum        float64 `json:"sum"`
+	MerchantID int     `json:"merchant_id,omitempty"`
+}
+
+func (PointsWithdrawnEvent) EventType() string { return EventPointsWithdrawn }
+
+type UserRegisteredEvent struct {
+	UserID int    `json:"user_id"`
+	Login  string `json:"login"`
+}
+
+func (UserRegisteredEvent) EventType() string { return EventUserRegistered }
+
+// OutboxEvent — запись outbox, ожидающая публикации. Delivered — получатели,
+// уже принявшие событие в прошлых попытках.
+type OutboxEvent struct {
+	ID        int64
+	Type      string
+	Payload   []byte
+	CreatedAt time.Time
+	Attempts  int
+	Delivered []string
 }